@@ -4,8 +4,11 @@
 // Author: Joseph Kehoe (Joseph.Kehoe@setu.ie)
 // Created on 30/9/2024
 // Modified by: Ronan Green
+// Description:
+// Ten goroutines meet at a barrier between part A and part B, using the
+// reusable barrier in the barrier package.
 // Issues:
-// The barrier is not implemented!
+// None
 //--------------------------------------------
 
 package main
@@ -14,25 +17,17 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"Barrier/barrier"
 )
 
-// Place a barrier in this function --use Mutex's and Semaphores
-func doStuff(goNum int, arrived *int, max int, wg *sync.WaitGroup, sharedLock *sync.Mutex, theChan chan bool) bool {
+// doStuff does part A, waits at the barrier until every goroutine has done part A, then does part B.
+func doStuff(goNum int, wg *sync.WaitGroup, theBarrier *barrier.Barrier) bool {
 
 	time.Sleep(time.Second)
 	fmt.Println("Part A", goNum)
 	//we wait here until everyone has completed part A
-	sharedLock.Lock()
-	*arrived++
-	if *arrived == 10 { //last to arrive -signal others to go
-		sharedLock.Unlock() //unlock before any potentially blocking code
-		theChan <- true
-		<-theChan
-	} else { //not all here yet we wait until signal
-		sharedLock.Unlock() //unlock before any potentially blocking code
-		<-theChan
-		theChan <- true //once we get through send signal to next routine to continue
-	} //end of if-else
+	theBarrier.Wait()
 	fmt.Println("PartB", goNum)
 
 	wg.Done()
@@ -41,15 +36,11 @@ func doStuff(goNum int, arrived *int, max int, wg *sync.WaitGroup, sharedLock *s
 
 func main() {
 	totalRoutines := 10
-	arrived := 0
 	var wg sync.WaitGroup
 	wg.Add(totalRoutines)
-	//we will need some of these
-	var theLock sync.Mutex
-	theChan := make(chan bool)     //use unbuffered channel in place of semaphore
+	theBarrier := barrier.New(totalRoutines)
 	for i := range totalRoutines { //create the go Routines here
-		go doStuff(i, &arrived, totalRoutines, &wg, &theLock, theChan)
+		go doStuff(i, &wg, theBarrier)
 	}
 	wg.Wait() //wait for everyone to finish before exiting
 } //end-main
-
@@ -0,0 +1,237 @@
+// Package barrier provides a reusable (cyclic) barrier: a meeting point where a fixed number of goroutines wait for
+// each other before any of them goes on. For example:
+//
+//	b := barrier.New(3)
+//	for range 3 {
+//		go func() {
+//			partA()
+//			b.Wait() // No one starts part B until everyone has finished part A.
+//			partB()
+//		}()
+//	}
+//
+// It replaces the barriers written for the labs, none of which could be imported and each of which had a bug:
+//   - Lab 3 passed a token round an unbuffered channel, starting with the last goroutine to arrive, which then waits
+//     for the token back. Nothing stops it taking the token back before every other goroutine has had it, leaving
+//     the rest blocked. It also counted to a hard-coded 10 and never reset, so could be used once only.
+//   - Lab 4 added a second turnstile to reuse it, but with the same token race in both turnstiles and the same 10.
+//   - BarrierStruct had the last goroutine to arrive receive from each of the others, which works once, but never
+//     reset its count, so a second Wait never released anyone.
+package barrier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBroken is returned by Wait once a barrier is broken: a waiting party gave up, the barrier action panicked, or
+// Reset was called while parties were waiting. Every party waiting then, and every party that calls Wait after, gets
+// ErrBroken until Reset is called, as they can no longer all meet.
+var ErrBroken = errors.New("barrier: broken")
+
+// Waiter is a reusable barrier for a fixed number of goroutines, its parties: each call to Wait blocks until every
+// party has called it, then they all return and can meet again. Barrier implements it, as do the SenseReversing,
+// Tree and Turnstile barriers, which are here to compare the ways a barrier can be built and cannot break.
+type Waiter interface {
+	Wait() error
+}
+
+// Barrier blocks the goroutines that call Wait until a number of them, its parties, are waiting, then releases them
+// all together. It then resets, so the same goroutines can meet at it again: each time they do is a generation.
+// Parties can join and leave between generations, or during one, with Register and Unregister. A Barrier must be
+// created with New or NewWithAction.
+//
+// As with Java's CyclicBarrier, a barrier breaks if a party gives up waiting or the barrier action panics, and stays
+// broken until Reset. A party that crashes or hangs before reaching the barrier cannot break it, so wait with
+// WaitContext or WaitTimeout where that could happen.
+type Barrier struct {
+	action func() // Run by the last party to arrive before the others are released, or nil.
+
+	mu         sync.Mutex
+	parties    int    // Goroutines that must arrive to release a generation.
+	arrived    int    // Goroutines waiting in the current generation.
+	generation uint64 // Generations released so far.
+	round      *round // The current generation.
+	broken     bool   // Whether the barrier is broken, until Reset.
+}
+
+// round is one generation of a barrier, shared by the goroutines waiting in it.
+type round struct {
+	release chan struct{} // Closed once the generation is released or broken.
+	broken  bool          // Whether the generation was broken, set before release is closed.
+}
+
+func newRound() *round {
+	return &round{release: make(chan struct{})}
+}
+
+// New returns a barrier for parties goroutines. It panics if parties is less than 1.
+func New(parties int) *Barrier {
+	return NewWithAction(parties, nil)
+}
+
+// NewWithAction returns a barrier for parties goroutines whose last party to arrive runs action before the others
+// are released, such as to merge what they each did. The action holds the barrier's lock, so it must not call the
+// barrier's methods. If it panics, the barrier breaks and the panic goes on in that party. It panics if parties is
+// less than 1.
+func NewWithAction(parties int, action func()) *Barrier {
+	if parties < 1 {
+		panic(fmt.Sprintf("barrier: %d parties: there must be at least one", parties))
+	}
+	return &Barrier{parties: parties, action: action, round: newRound()}
+}
+
+// Wait blocks until all the barrier's parties have called Wait, then returns nil in each of them. The last to arrive
+// does not block, and starts the next generation before any of them returns, so a goroutine that calls Wait again at
+// once waits for the others to arrive again rather than passing straight through. It returns ErrBroken if the barrier
+// is broken, or breaks while it waits.
+func (b *Barrier) Wait() error {
+	return b.WaitContext(context.Background())
+}
+
+// WaitContext waits as Wait does, unless ctx is cancelled first, so a party that hangs does not leave the others
+// blocked forever. Cancelling ctx breaks the barrier: WaitContext returns ctx.Err(), and every other party waiting,
+// or that waits before Reset, gets ErrBroken.
+func (b *Barrier) WaitContext(ctx context.Context) error {
+	b.mu.Lock()
+	if b.broken {
+		b.mu.Unlock()
+		return ErrBroken
+	}
+	if err := ctx.Err(); err != nil {
+		b.breakRound()
+		b.mu.Unlock()
+		return err
+	}
+	r := b.round
+	b.arrived++
+	if b.arrived == b.parties {
+		defer b.mu.Unlock() // Deferred in case the action panics.
+		b.trip()
+		return nil
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-r.release:
+		return r.err()
+	case <-ctx.Done():
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if r != b.round { // Released or broken before we could give up.
+		return r.err()
+	}
+	b.breakRound()
+	return ctx.Err()
+}
+
+// WaitTimeout waits as WaitContext does, giving up once d has passed and returning context.DeadlineExceeded.
+func (b *Barrier) WaitTimeout(d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return b.WaitContext(ctx)
+}
+
+// Reset returns the barrier to its state when new, with no one waiting and not broken. Any parties waiting get
+// ErrBroken. The generation count is kept.
+func (b *Barrier) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.arrived > 0 {
+		b.breakRound()
+	}
+	b.broken = false
+}
+
+// IsBroken reports whether the barrier is broken, so Wait returns ErrBroken until Reset.
+func (b *Barrier) IsBroken() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.broken
+}
+
+// err returns what a party released from r returns: nil, or ErrBroken if r was broken.
+func (r *round) err() error {
+	if r.broken {
+		return ErrBroken
+	}
+	return nil
+}
+
+// trip runs the action, if any, then releases the current generation and starts the next. If the action panics, it
+// breaks the barrier instead and panics again. The caller holds b.mu.
+func (b *Barrier) trip() {
+	if b.action != nil {
+		defer func() {
+			if v := recover(); v != nil {
+				b.breakRound()
+				panic(v)
+			}
+		}()
+		b.action()
+	}
+	close(b.round.release)
+	b.round = newRound()
+	b.arrived = 0
+	b.generation++
+}
+
+// breakRound breaks the barrier, releasing the current generation with ErrBroken and starting a new one without
+// counting it as released. The caller holds b.mu.
+func (b *Barrier) breakRound() {
+	b.round.broken = true
+	close(b.round.release)
+	b.round = newRound()
+	b.arrived = 0
+	b.broken = true
+}
+
+// Register adds a party to the barrier, such as a goroutine started for a new partition of the work. The change takes
+// effect at once: a generation already being waited in is not released until the new party arrives too. So register
+// a goroutine before starting it, from a party that has not yet arrived in the current generation.
+func (b *Barrier) Register() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.parties++
+}
+
+// Unregister removes a party from the barrier, such as a goroutine whose partition of the work is finished. It is
+// called by that party in place of Wait, or by another party on its behalf before it would have arrived. The change
+// takes effect at once: if every remaining party is already waiting, Unregister releases the generation, running the
+// barrier action, as the last party to arrive would. It panics if the barrier would be left with no parties.
+func (b *Barrier) Unregister() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.parties == 1 {
+		panic("barrier: Unregister of the last party")
+	}
+	b.parties--
+	if !b.broken && b.arrived > 0 && b.arrived == b.parties {
+		b.trip()
+	}
+}
+
+// Parties returns the number of goroutines that must call Wait for the barrier to release them.
+func (b *Barrier) Parties() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.parties
+}
+
+// Waiting returns the number of goroutines waiting at the barrier now.
+func (b *Barrier) Waiting() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.arrived
+}
+
+// Generation returns the number of times the barrier has released its parties.
+func (b *Barrier) Generation() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.generation
+}
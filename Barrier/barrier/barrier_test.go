@@ -0,0 +1,348 @@
+package barrier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// TestWait checks that no goroutine passes the barrier until all have arrived, generation after generation, with
+// goroutines that call Wait again as soon as they are released.
+func TestWait(t *testing.T) {
+	const parties, generations = 8, 200
+	b := New(parties)
+	var arrived [generations]atomic.Int32
+	var wg sync.WaitGroup
+	wg.Add(parties)
+	for range parties {
+		go func() {
+			defer wg.Done()
+			for g := range generations {
+				arrived[g].Add(1)
+				if err := b.Wait(); err != nil {
+					t.Errorf("generation %d: Wait() = %v", g, err)
+				}
+				if n := arrived[g].Load(); n != parties {
+					t.Errorf("generation %d: released with %d of %d arrived", g, n, parties)
+				}
+			}
+		}()
+	}
+	testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+	if got := b.Generation(); got != generations {
+		t.Errorf("Generation() = %d, want %d", got, generations)
+	}
+	if got := b.Waiting(); got != 0 {
+		t.Errorf("Waiting() = %d after every generation was released", got)
+	}
+}
+
+// TestWaitBlocks checks that the barrier holds its parties until the last one arrives.
+func TestWaitBlocks(t *testing.T) {
+	b := New(3)
+	released := make(chan struct{}, 2)
+	for range 2 {
+		go func() {
+			b.Wait()
+			released <- struct{}{}
+		}()
+	}
+	for b.Waiting() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-released:
+		t.Fatal("a goroutine passed the barrier before the last party arrived")
+	case <-time.After(20 * time.Millisecond):
+	}
+	b.Wait()
+	for range 2 {
+		<-released
+	}
+	if got := b.Generation(); got != 1 {
+		t.Errorf("Generation() = %d, want 1", got)
+	}
+}
+
+// TestWaitContext checks that cancelling one party's wait releases the others with ErrBroken, that the barrier stays
+// broken, and that it can be used again once Reset.
+func TestWaitContext(t *testing.T) {
+	b := New(4)
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			errs <- b.Wait()
+		}()
+	}
+	for b.Waiting() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if err := b.WaitContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("WaitContext() = %v, want %v", err, context.Canceled)
+	}
+	for range 2 {
+		if err := <-errs; !errors.Is(err, ErrBroken) {
+			t.Errorf("a waiting party got %v, want ErrBroken", err)
+		}
+	}
+	if got := b.Waiting(); got != 0 {
+		t.Errorf("Waiting() = %d after the barrier broke, want 0", got)
+	}
+	if !b.IsBroken() {
+		t.Error("IsBroken() = false after a party gave up")
+	}
+	if err := b.Wait(); !errors.Is(err, ErrBroken) {
+		t.Errorf("Wait() at a broken barrier = %v, want ErrBroken", err)
+	}
+
+	b.Reset()
+	var wg sync.WaitGroup
+	wg.Add(4)
+	for range 4 {
+		go func() {
+			defer wg.Done()
+			if err := b.Wait(); err != nil {
+				t.Errorf("Wait() after Reset = %v", err)
+			}
+		}()
+	}
+	testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+	if got := b.Generation(); got != 1 {
+		t.Errorf("Generation() = %d, want 1, as a broken generation is not released", got)
+	}
+}
+
+func TestWaitContextDone(t *testing.T) {
+	b := New(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.WaitContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("WaitContext() with a cancelled context = %v, want %v", err, context.Canceled)
+	}
+	if got := b.Waiting(); got != 0 {
+		t.Errorf("Waiting() = %d, want 0", got)
+	}
+	if err := b.WaitContext(ctx); !errors.Is(err, ErrBroken) {
+		t.Errorf("WaitContext() at a broken barrier = %v, want ErrBroken before the context's error", err)
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	b := New(2)
+	if err := b.WaitTimeout(10 * time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitTimeout() alone = %v, want %v", err, context.DeadlineExceeded)
+	}
+	b.Reset()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- b.WaitTimeout(10 * time.Second)
+	}()
+	if err := b.Wait(); err != nil {
+		t.Errorf("Wait() = %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("WaitTimeout() with the other party arriving = %v", err)
+	}
+}
+
+// TestReset checks that resetting a barrier with parties waiting releases them with ErrBroken and leaves the barrier
+// ready for use, not broken.
+func TestReset(t *testing.T) {
+	b := New(3)
+	b.Reset() // With no one waiting, Reset changes nothing.
+	if b.IsBroken() {
+		t.Fatal("IsBroken() = true after Reset of a new barrier")
+	}
+
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			errs <- b.Wait()
+		}()
+	}
+	for b.Waiting() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	b.Reset()
+	for range 2 {
+		if err := <-errs; !errors.Is(err, ErrBroken) {
+			t.Errorf("a party waiting at Reset got %v, want ErrBroken", err)
+		}
+	}
+	if b.IsBroken() {
+		t.Error("IsBroken() = true after Reset")
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	for range 3 {
+		go func() {
+			defer wg.Done()
+			if err := b.Wait(); err != nil {
+				t.Errorf("Wait() after Reset = %v", err)
+			}
+		}()
+	}
+	testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+	if got := b.Generation(); got != 1 {
+		t.Errorf("Generation() = %d, want 1", got)
+	}
+}
+
+// TestAction checks that the action runs once a generation, after everyone has arrived and before anyone is released.
+func TestAction(t *testing.T) {
+	const parties, generations = 4, 50
+	var arrived, actions atomic.Int32
+	b := NewWithAction(parties, func() {
+		if n := arrived.Load(); n != parties {
+			t.Errorf("the action ran with %d of %d arrived", n, parties)
+		}
+		arrived.Store(0)
+		actions.Add(1)
+	})
+	var wg sync.WaitGroup
+	wg.Add(parties)
+	for range parties {
+		go func() {
+			defer wg.Done()
+			for range generations {
+				before := actions.Load()
+				arrived.Add(1)
+				if err := b.Wait(); err != nil {
+					t.Errorf("Wait() = %v", err)
+				}
+				if actions.Load() == before {
+					t.Error("a party was released before the action ran")
+				}
+			}
+		}()
+	}
+	testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+	if got := actions.Load(); got != generations {
+		t.Errorf("the action ran %d times, want %d", got, generations)
+	}
+}
+
+// TestActionPanics checks that a panicking action breaks the barrier, and that the panic goes on in the last party.
+func TestActionPanics(t *testing.T) {
+	b := NewWithAction(2, func() { panic("boom") })
+	waiting := make(chan error, 1)
+	go func() {
+		waiting <- b.Wait()
+	}()
+	for b.Waiting() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	func() {
+		defer func() {
+			if v := recover(); v != "boom" {
+				t.Errorf("the last party recovered %v, want the action's panic", v)
+			}
+		}()
+		b.Wait()
+	}()
+	if err := <-waiting; !errors.Is(err, ErrBroken) {
+		t.Errorf("the waiting party got %v, want ErrBroken", err)
+	}
+	if !b.IsBroken() {
+		t.Error("IsBroken() = false after the action panicked")
+	}
+	if err := b.Wait(); !errors.Is(err, ErrBroken) {
+		t.Errorf("Wait() = %v, want ErrBroken", err)
+	}
+	if got := b.Generation(); got != 0 {
+		t.Errorf("Generation() = %d, want 0, as the broken generation was not released", got)
+	}
+}
+
+func TestOneParty(t *testing.T) {
+	b := New(1)
+	for range 3 {
+		b.Wait() // Never blocks.
+	}
+	if got := b.Generation(); got != 3 {
+		t.Errorf("Generation() = %d, want 3", got)
+	}
+}
+
+func TestNewPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("New(0) did not panic")
+		}
+	}()
+	New(0)
+}
+
+// TestRegister checks that a party registered mid-generation is waited for, and one unregistered while the rest wait
+// releases them.
+func TestRegister(t *testing.T) {
+	b := New(2)
+	errs := make(chan error, 3)
+	go func() {
+		errs <- b.Wait()
+	}()
+	for b.Waiting() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	b.Register() // Started by the party yet to arrive.
+	go func() {
+		errs <- b.Wait()
+	}()
+	for b.Waiting() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	if got := b.Generation(); got != 0 {
+		t.Fatalf("Generation() = %d: released before the registered party arrived", got)
+	}
+	b.Wait()
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Errorf("Wait() = %v", err)
+		}
+	}
+	if got := b.Parties(); got != 3 {
+		t.Errorf("Parties() = %d, want 3", got)
+	}
+
+	for range 2 {
+		go func() {
+			errs <- b.Wait()
+		}()
+	}
+	for b.Waiting() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	b.Unregister() // The third party leaves rather than arriving.
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Errorf("Wait() = %v", err)
+		}
+	}
+	if got := b.Generation(); got != 2 {
+		t.Errorf("Generation() = %d, want 2", got)
+	}
+	if got := b.Parties(); got != 2 {
+		t.Errorf("Parties() = %d, want 2", got)
+	}
+}
+
+func TestUnregisterLastPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Unregister of the last party did not panic")
+		}
+	}()
+	New(1).Unregister()
+}
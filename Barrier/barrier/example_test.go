@@ -0,0 +1,103 @@
+package barrier_test
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"Barrier/barrier"
+)
+
+// A simulation step in three phases, as in Wa-Tor: every partition's creatures move, then breed, then the dead are
+// cleaned up, with no partition starting a phase until all have finished the one before. The main goroutine is a
+// party too, reporting each phase, and a partition whose creatures have all died leaves the phaser.
+func ExamplePhaser() {
+	const partitions, chronons = 4, 2
+	phases := []string{"move", "breed", "cleanup"}
+	phaser := barrier.NewPhaser(partitions + 1) // The partitions and the main goroutine.
+	var done [3]atomic.Int32                    // Partitions that have finished each phase this chronon.
+
+	var wg sync.WaitGroup
+	wg.Add(partitions)
+	for i := range partitions {
+		go func() {
+			defer wg.Done()
+			for chronon := range chronons {
+				if i == partitions-1 && chronon == 1 {
+					phaser.ArriveAndDeregister() // Nothing left alive in this partition.
+					return
+				}
+				for phase := range phases {
+					done[phase].Add(1) // Move, breed or clean up this partition's creatures.
+					phaser.ArriveAndAwait()
+				}
+			}
+			phaser.ArriveAndDeregister()
+		}()
+	}
+
+	for chronon := range chronons {
+		for phase, name := range phases {
+			phaser.ArriveAndAwait()
+			fmt.Printf("chronon %d: %s done in %d partitions\n", chronon, name, done[phase].Swap(0))
+		}
+	}
+	wg.Wait()
+	fmt.Println("parties left:", phaser.Parties())
+	// Output:
+	// chronon 0: move done in 4 partitions
+	// chronon 0: breed done in 4 partitions
+	// chronon 0: cleanup done in 4 partitions
+	// chronon 1: move done in 3 partitions
+	// chronon 1: breed done in 3 partitions
+	// chronon 1: cleanup done in 3 partitions
+	// parties left: 1
+}
+
+// The handshake of the signalling lab: each of two goroutines does part A, and neither starts part B until both have
+// finished part A.
+func ExampleRendezvous() {
+	var r barrier.Rendezvous
+	var partsA atomic.Int32
+	partB := make(chan string, 2)
+	for _, name := range []string{"StuffOne", "StuffTwo"} {
+		go func() {
+			partsA.Add(1) // Part A.
+			r.Meet()
+			partB <- fmt.Sprintf("%s - Part B, after %d parts A", name, partsA.Load())
+		}()
+	}
+	lines := []string{<-partB, <-partB}
+	slices.Sort(lines)
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	// Output:
+	// StuffOne - Part B, after 2 parts A
+	// StuffTwo - Part B, after 2 parts A
+}
+
+// A producer fills a buffer while the consumer empties another, and they swap buffers at the exchanger, so neither
+// waits for a lock on a shared one.
+func ExampleExchanger() {
+	var x barrier.Exchanger[[]int]
+	go func() {
+		buf := make([]int, 0, 3)
+		for batch := range 2 {
+			for i := range 3 {
+				buf = append(buf, batch*3+i)
+			}
+			buf = x.Exchange(buf)[:0] // Hand over the full buffer, and fill the empty one given back.
+		}
+	}()
+
+	buf := make([]int, 0, 3)
+	for range 2 {
+		buf = x.Exchange(buf[:0]) // Hand over the empty buffer for a full one.
+		fmt.Println(buf)
+	}
+	// Output:
+	// [0 1 2]
+	// [3 4 5]
+}
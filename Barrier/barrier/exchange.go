@@ -0,0 +1,77 @@
+package barrier
+
+import (
+	"context"
+	"sync"
+)
+
+// Exchanger is a meeting point where two goroutines swap values: each calls Exchange with its value and gets the
+// other's, once both have arrived. It is reusable, pairing callers in the order they arrive, so any number of
+// goroutines can exchange through it two at a time. The zero value is ready to use.
+//
+// It is the handshake the labs build by hand from a pair of channels, such as a producer handing a full buffer to a
+// consumer and getting an empty one back.
+type Exchanger[T any] struct {
+	mu      sync.Mutex
+	waiting *offer[T] // The goroutine waiting for a partner, or nil.
+}
+
+// offer is a value waiting in an Exchanger, and where its partner sends theirs.
+type offer[T any] struct {
+	value T
+	reply chan T // Buffered, so the partner never blocks.
+}
+
+// Exchange waits for another goroutine to call Exchange, then gives it v and returns its value.
+func (e *Exchanger[T]) Exchange(v T) T {
+	w, _ := e.ExchangeContext(context.Background(), v)
+	return w
+}
+
+// ExchangeContext exchanges as Exchange does, unless ctx is cancelled before a partner arrives, when it returns the
+// zero value and ctx.Err(). Once a partner has taken v, the exchange completes whatever happens to ctx.
+func (e *Exchanger[T]) ExchangeContext(ctx context.Context, v T) (T, error) {
+	e.mu.Lock()
+	if o := e.waiting; o != nil {
+		e.waiting = nil
+		e.mu.Unlock()
+		o.reply <- v
+		return o.value, nil
+	}
+	o := &offer[T]{value: v, reply: make(chan T, 1)}
+	e.waiting = o
+	e.mu.Unlock()
+
+	select {
+	case w := <-o.reply:
+		return w, nil
+	case <-ctx.Done():
+	}
+	e.mu.Lock()
+	if e.waiting == o {
+		e.waiting = nil
+		e.mu.Unlock()
+		var zero T
+		return zero, ctx.Err()
+	}
+	e.mu.Unlock()
+	return <-o.reply, nil // A partner took v just as ctx was cancelled.
+}
+
+// Rendezvous is a meeting point for two goroutines: each waits at Meet until the other arrives, so neither goes on
+// until both have reached it. It is a Barrier of two parties that needs no setup, and like an Exchanger pairs callers
+// in the order they arrive. The zero value is ready to use.
+type Rendezvous struct {
+	x Exchanger[struct{}]
+}
+
+// Meet waits until another goroutine calls Meet.
+func (r *Rendezvous) Meet() {
+	r.x.Exchange(struct{}{})
+}
+
+// MeetContext waits as Meet does, unless ctx is cancelled first, returning ctx.Err().
+func (r *Rendezvous) MeetContext(ctx context.Context) error {
+	_, err := r.x.ExchangeContext(ctx, struct{}{})
+	return err
+}
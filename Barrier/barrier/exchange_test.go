@@ -0,0 +1,97 @@
+package barrier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+func TestExchanger(t *testing.T) {
+	var x Exchanger[int]
+	got := make(chan int, 1)
+	go func() {
+		got <- x.Exchange(1)
+	}()
+	if v := x.Exchange(2); v != 1 {
+		t.Errorf("Exchange(2) = %d, want 1", v)
+	}
+	if v := <-got; v != 2 {
+		t.Errorf("Exchange(1) = %d, want 2", v)
+	}
+}
+
+// TestExchangerPairs checks that many goroutines exchanging at once are paired off, each value going to exactly one
+// other goroutine.
+func TestExchangerPairs(t *testing.T) {
+	const n = 1000
+	var x Exchanger[int]
+	partner := make([]int, n)
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := range n {
+		go func() {
+			defer wg.Done()
+			partner[i] = x.Exchange(i)
+		}()
+	}
+	testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+	for i, p := range partner {
+		if partner[p] != i {
+			t.Fatalf("%d got %d's value, but %d got %d's", i, p, p, partner[p])
+		}
+	}
+}
+
+func TestExchangeContext(t *testing.T) {
+	var x Exchanger[string]
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if v, err := x.ExchangeContext(ctx, "alone"); !errors.Is(err, context.DeadlineExceeded) || v != "" {
+		t.Errorf("ExchangeContext() with no partner = %q, %v, want \"\", %v", v, err, context.DeadlineExceeded)
+	}
+
+	// The value given up on is not passed to the next goroutine.
+	got := make(chan string, 1)
+	go func() {
+		got <- x.Exchange("a")
+	}()
+	if v := x.Exchange("b"); v != "a" {
+		t.Errorf("Exchange(\"b\") = %q, want \"a\"", v)
+	}
+	if v := <-got; v != "b" {
+		t.Errorf("Exchange(\"a\") = %q, want \"b\"", v)
+	}
+}
+
+func TestRendezvous(t *testing.T) {
+	var r Rendezvous
+	var arrived [2]bool
+	var mu sync.Mutex
+	done := make(chan struct{})
+	for i := range 2 {
+		go func() {
+			mu.Lock()
+			arrived[i] = true
+			mu.Unlock()
+			r.Meet()
+			mu.Lock()
+			if !arrived[1-i] {
+				t.Errorf("goroutine %d passed the rendezvous before the other arrived", i)
+			}
+			mu.Unlock()
+			done <- struct{}{}
+		}()
+	}
+	<-done
+	<-done
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := r.MeetContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("MeetContext() = %v, want %v", err, context.Canceled)
+	}
+}
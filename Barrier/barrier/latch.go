@@ -0,0 +1,73 @@
+package barrier
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// CountDownLatch lets goroutines wait until a count of events has happened, as Java's CountDownLatch does. The count
+// is set at creation and each CountDown takes one off; once it reaches zero every waiter is released, and Wait returns
+// at once from then on. Unlike a Barrier, the goroutines counting down do not wait, and a latch cannot be reused.
+//
+// It does the job of the mutex, counter and channel the labs use to start or finish goroutines together, and unlike a
+// sync.WaitGroup its count cannot go negative and it can be waited for with a context.
+type CountDownLatch struct {
+	mu    sync.Mutex
+	count int
+	done  chan struct{} // Closed once the count reaches zero.
+}
+
+// NewCountDownLatch returns a latch that opens once CountDown has been called count times. A latch with a count of
+// zero is already open. It panics if count is negative.
+func NewCountDownLatch(count int) *CountDownLatch {
+	if count < 0 {
+		panic(fmt.Sprintf("barrier: latch count %d cannot be negative", count))
+	}
+	l := &CountDownLatch{count: count, done: make(chan struct{})}
+	if count == 0 {
+		close(l.done)
+	}
+	return l
+}
+
+// CountDown takes one off the count, opening the latch if it reaches zero. Once the latch is open it does nothing.
+func (l *CountDownLatch) CountDown() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.count == 0 {
+		return
+	}
+	l.count--
+	if l.count == 0 {
+		close(l.done)
+	}
+}
+
+// Count returns the number of CountDown calls still needed to open the latch.
+func (l *CountDownLatch) Count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.count
+}
+
+// Wait blocks until the latch is open.
+func (l *CountDownLatch) Wait() {
+	<-l.done
+}
+
+// WaitContext blocks until the latch is open, returning nil, or until ctx is cancelled, returning ctx.Err(). A
+// goroutine giving up does not change the count.
+func (l *CountDownLatch) WaitContext(ctx context.Context) error {
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Done returns a channel closed once the latch is open, for waiting in a select.
+func (l *CountDownLatch) Done() <-chan struct{} {
+	return l.done
+}
@@ -0,0 +1,81 @@
+package barrier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+func TestCountDownLatch(t *testing.T) {
+	const count = 5
+	l := NewCountDownLatch(count)
+	released := make(chan struct{}, 3)
+	for range 3 {
+		go func() {
+			l.Wait()
+			released <- struct{}{}
+		}()
+	}
+	var wg sync.WaitGroup
+	wg.Add(count - 1)
+	for range count - 1 {
+		go func() {
+			defer wg.Done()
+			l.CountDown()
+		}()
+	}
+	testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+	if got := l.Count(); got != 1 {
+		t.Fatalf("Count() = %d, want 1", got)
+	}
+	select {
+	case <-released:
+		t.Fatal("a waiter was released before the count reached zero")
+	case <-time.After(20 * time.Millisecond):
+	}
+	l.CountDown()
+	for range 3 {
+		<-released
+	}
+	l.CountDown() // Does nothing once open.
+	if got := l.Count(); got != 0 {
+		t.Errorf("Count() = %d after counting down past zero, want 0", got)
+	}
+	l.Wait() // Returns at once.
+	select {
+	case <-l.Done():
+	default:
+		t.Error("Done() is not closed once the latch is open")
+	}
+}
+
+func TestCountDownLatchZero(t *testing.T) {
+	if err := NewCountDownLatch(0).WaitContext(context.Background()); err != nil {
+		t.Errorf("WaitContext() on a latch of zero = %v", err)
+	}
+}
+
+func TestCountDownLatchWaitContext(t *testing.T) {
+	l := NewCountDownLatch(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.WaitContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitContext() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if got := l.Count(); got != 1 {
+		t.Errorf("Count() = %d after a waiter gave up, want 1", got)
+	}
+}
+
+func TestNewCountDownLatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewCountDownLatch(-1) did not panic")
+		}
+	}()
+	NewCountDownLatch(-1)
+}
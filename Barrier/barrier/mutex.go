@@ -0,0 +1,113 @@
+package barrier
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Mutex is a sync.Mutex that measures how it is used: how often it is locked, how often a goroutine had to wait for
+// it, and how long goroutines waited for it and held it. It can replace a sync.Mutex wherever one is locked and
+// unlocked, such as the boundary mutexes of Wa-Tor or the forks of the dining philosophers, to find which locks are
+// fought over. Its zero value is an unlocked mutex.
+//
+// Measuring costs two reads of the clock each time the mutex is held, and a third when a goroutine has to wait, so
+// use it to analyse a program rather than in one already tuned. Stats can be called at any time, from any goroutine,
+// and a Mutex can be published with expvar.Publish to serve its statistics at /debug/vars.
+type Mutex struct {
+	mu     sync.Mutex
+	locked time.Time // When the holder locked mu; only used by the holder.
+
+	acquisitions atomic.Int64
+	contended    atomic.Int64
+	wait         atomic.Int64 // Nanoseconds.
+	maxWait      atomic.Int64
+	hold         atomic.Int64
+	maxHold      atomic.Int64
+}
+
+// MutexStats describes how a Mutex has been used since it was created.
+type MutexStats struct {
+	Acquisitions int64         // Times it was locked.
+	Contended    int64         // Acquisitions that had to wait because another goroutine held it.
+	Wait         time.Duration // Total time spent waiting for it.
+	MaxWait      time.Duration // Longest a goroutine waited for it.
+	Hold         time.Duration // Total time it was held, not counting a holder that has yet to unlock it.
+	MaxHold      time.Duration // Longest it was held.
+}
+
+// Lock locks m, waiting until it is free, as sync.Mutex.Lock does. A lock that is already free costs one TryLock; the
+// clock is only read for the wait when another goroutine holds it.
+func (m *Mutex) Lock() {
+	if m.mu.TryLock() {
+		m.acquired()
+		return
+	}
+	start := time.Now()
+	m.mu.Lock()
+	m.acquired()
+	wait := m.locked.Sub(start)
+	m.contended.Add(1)
+	m.wait.Add(int64(wait))
+	if int64(wait) > m.maxWait.Load() { // Only the holder updates it, so there is no race to raise it.
+		m.maxWait.Store(int64(wait))
+	}
+}
+
+// TryLock locks m if it is free and reports whether it did, as sync.Mutex.TryLock does. A failed TryLock does not
+// count as contention, as it did not wait.
+func (m *Mutex) TryLock() bool {
+	if !m.mu.TryLock() {
+		return false
+	}
+	m.acquired()
+	return true
+}
+
+// Unlock unlocks m, as sync.Mutex.Unlock does, adding the time since it was locked to the hold time.
+func (m *Mutex) Unlock() {
+	hold := time.Since(m.locked)
+	m.hold.Add(int64(hold))
+	if int64(hold) > m.maxHold.Load() {
+		m.maxHold.Store(int64(hold))
+	}
+	m.mu.Unlock()
+}
+
+// acquired counts an acquisition and starts timing the hold. The caller holds m.mu.
+func (m *Mutex) acquired() {
+	m.locked = time.Now()
+	m.acquisitions.Add(1)
+}
+
+// Stats returns how m has been used so far. Each count is read on its own while m may be in use, so they can be a
+// moment apart, but none is torn.
+func (m *Mutex) Stats() MutexStats {
+	return MutexStats{
+		Acquisitions: m.acquisitions.Load(),
+		Contended:    m.contended.Load(),
+		Wait:         time.Duration(m.wait.Load()),
+		MaxWait:      time.Duration(m.maxWait.Load()),
+		Hold:         time.Duration(m.hold.Load()),
+		MaxHold:      time.Duration(m.maxHold.Load()),
+	}
+}
+
+// String returns m's Stats as JSON, with the times in nanoseconds, so m is an expvar.Var.
+func (m *Mutex) String() string {
+	b, _ := json.Marshal(m.Stats()) // Cannot fail: MutexStats holds only numbers.
+	return string(b)
+}
+
+// Add returns the sum of s and t, with the larger of each maximum, to total the statistics of several mutexes.
+func (s MutexStats) Add(t MutexStats) MutexStats {
+	return MutexStats{
+		Acquisitions: s.Acquisitions + t.Acquisitions,
+		Contended:    s.Contended + t.Contended,
+		Wait:         s.Wait + t.Wait,
+		MaxWait:      max(s.MaxWait, t.MaxWait),
+		Hold:         s.Hold + t.Hold,
+		MaxHold:      max(s.MaxHold, t.MaxHold),
+	}
+}
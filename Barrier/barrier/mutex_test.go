@@ -0,0 +1,106 @@
+package barrier
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// TestMutex checks that the mutex excludes, and counts every acquisition, under the race detector.
+func TestMutex(t *testing.T) {
+	const goroutines, locks = 8, 1000
+	var m Mutex
+	counter := 0
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for range goroutines {
+		go func() {
+			defer wg.Done()
+			for range locks {
+				m.Lock()
+				counter++
+				m.Unlock()
+			}
+		}()
+	}
+	testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+	if counter != goroutines*locks {
+		t.Errorf("counter = %d, want %d", counter, goroutines*locks)
+	}
+	s := m.Stats()
+	if s.Acquisitions != goroutines*locks {
+		t.Errorf("Acquisitions = %d, want %d", s.Acquisitions, goroutines*locks)
+	}
+	if s.Contended > s.Acquisitions || s.MaxWait > s.Wait || s.MaxHold > s.Hold {
+		t.Errorf("Stats() = %+v: a part is larger than its whole", s)
+	}
+}
+
+// TestMutexTimes checks that the wait and hold times cover a goroutine kept waiting by a holder.
+func TestMutexTimes(t *testing.T) {
+	const hold = 20 * time.Millisecond
+	var m Mutex
+	m.Lock()
+	done := make(chan struct{})
+	go func() {
+		m.Lock()
+		m.Unlock()
+		close(done)
+	}()
+	time.Sleep(hold)
+	m.Unlock()
+	<-done
+
+	s := m.Stats()
+	if s.Acquisitions != 2 || s.Contended != 1 {
+		t.Errorf("Acquisitions, Contended = %d, %d, want 2, 1", s.Acquisitions, s.Contended)
+	}
+	if s.MaxHold < hold || s.Hold < hold {
+		t.Errorf("Hold, MaxHold = %v, %v, want at least %v", s.Hold, s.MaxHold, hold)
+	}
+	if s.Wait <= 0 || s.Wait != s.MaxWait {
+		t.Errorf("Wait, MaxWait = %v, %v, want the one wait", s.Wait, s.MaxWait)
+	}
+}
+
+func TestMutexTryLock(t *testing.T) {
+	var m Mutex
+	if !m.TryLock() {
+		t.Fatal("TryLock() of a free mutex = false")
+	}
+	if m.TryLock() {
+		t.Fatal("TryLock() of a held mutex = true")
+	}
+	m.Unlock()
+	if s := m.Stats(); s.Acquisitions != 1 || s.Contended != 0 {
+		t.Errorf("Acquisitions, Contended = %d, %d, want 1, 0: a failed TryLock does not wait", s.Acquisitions, s.Contended)
+	}
+}
+
+// TestMutexVar checks that a mutex can be published with expvar, as JSON.
+func TestMutexVar(t *testing.T) {
+	var m Mutex
+	m.Lock()
+	m.Unlock()
+	expvar.Publish("barrier_test_mutex", &m)
+	var s MutexStats
+	if err := json.Unmarshal([]byte(expvar.Get("barrier_test_mutex").String()), &s); err != nil {
+		t.Fatalf("the published value is not JSON: %v", err)
+	}
+	if s != m.Stats() {
+		t.Errorf("published %+v, want %+v", s, m.Stats())
+	}
+}
+
+func TestMutexStatsAdd(t *testing.T) {
+	a := MutexStats{Acquisitions: 3, Contended: 1, Wait: 2, MaxWait: 2, Hold: 9, MaxHold: 5}
+	b := MutexStats{Acquisitions: 2, Contended: 2, Wait: 6, MaxWait: 4, Hold: 4, MaxHold: 3}
+	want := MutexStats{Acquisitions: 5, Contended: 3, Wait: 8, MaxWait: 4, Hold: 13, MaxHold: 5}
+	if got := a.Add(b); got != want {
+		t.Errorf("Add() = %+v, want %+v", got, want)
+	}
+}
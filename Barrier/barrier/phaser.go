@@ -0,0 +1,127 @@
+package barrier
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Phaser is a barrier for pipelines of several stages, or phases, modelled on Java's Phaser. As with a Barrier, the
+// phase advances once every registered party has arrived, but a party can arrive without waiting, wait for a phase it
+// did not arrive in, and register or deregister at any phase, so the parties can change as the work does. Phases are
+// numbered from 0. A phaser never breaks.
+//
+// A phaser left with no parties does not advance until a party registers.
+type Phaser struct {
+	mu      sync.Mutex
+	parties int    // Parties that must arrive to advance the phase.
+	arrived int    // Parties that have arrived in this phase.
+	phase   int    // The current phase.
+	round   *round // Closed when the current phase advances.
+}
+
+// NewPhaser returns a phaser at phase 0 with parties registered. It panics if parties is negative.
+func NewPhaser(parties int) *Phaser {
+	if parties < 0 {
+		panic(fmt.Sprintf("barrier: %d parties: there cannot be fewer than none", parties))
+	}
+	return &Phaser{parties: parties, round: newRound()}
+}
+
+// Register adds a party, which must arrive in the current phase before it advances, and returns the phase.
+func (p *Phaser) Register() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.parties++
+	return p.phase
+}
+
+// Arrive records that a party has reached the end of the current phase, without waiting for the others, and returns
+// the phase it arrived in. The last party to arrive advances the phase. Use it for a party whose next phase does not
+// depend on the others, such as one that only produces what they will use.
+func (p *Phaser) Arrive() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	phase := p.phase
+	p.arrive()
+	return phase
+}
+
+// ArriveAndAwait arrives, as Arrive does, then waits for the others to arrive, and returns the phase begun, one past
+// the phase arrived in: the equivalent of Barrier.Wait.
+func (p *Phaser) ArriveAndAwait() int {
+	p.mu.Lock()
+	phase, r := p.phase, p.round
+	p.arrive()
+	p.mu.Unlock()
+	<-r.release
+	return phase + 1
+}
+
+// ArriveAndDeregister arrives, as Arrive does, and removes the party from the phaser, so no later phase waits for it,
+// returning the phase arrived in. If every remaining party has arrived, it advances the phase.
+func (p *Phaser) ArriveAndDeregister() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.parties == 0 {
+		panic("barrier: ArriveAndDeregister with no parties registered")
+	}
+	phase := p.phase
+	p.parties--
+	if p.parties > 0 && p.arrived == p.parties {
+		p.advance()
+	}
+	return phase
+}
+
+// AwaitAdvance waits until the phaser has moved past phase and returns the current phase, or returns at once if it
+// already has. It neither arrives nor needs to be called by a party, so it lets any goroutine follow the phases.
+func (p *Phaser) AwaitAdvance(phase int) int {
+	p.mu.Lock()
+	current, r := p.phase, p.round
+	p.mu.Unlock()
+	if current != phase {
+		return current
+	}
+	<-r.release
+	return phase + 1
+}
+
+// arrive counts a party as arrived, advancing the phase if it is the last. The caller holds p.mu.
+func (p *Phaser) arrive() {
+	if p.parties == 0 {
+		panic("barrier: arrival at a phaser with no parties registered")
+	}
+	p.arrived++
+	if p.arrived == p.parties {
+		p.advance()
+	}
+}
+
+// advance releases the parties waiting in the current phase and starts the next. The caller holds p.mu.
+func (p *Phaser) advance() {
+	close(p.round.release)
+	p.round = newRound()
+	p.arrived = 0
+	p.phase++
+}
+
+// Phase returns the current phase.
+func (p *Phaser) Phase() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.phase
+}
+
+// Parties returns the number of parties registered.
+func (p *Phaser) Parties() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.parties
+}
+
+// Arrived returns the number of parties that have arrived in the current phase.
+func (p *Phaser) Arrived() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.arrived
+}
@@ -0,0 +1,91 @@
+package barrier
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// TestPhaser checks that no party begins a phase until every party has finished the one before.
+func TestPhaser(t *testing.T) {
+	const parties, phases = 6, 100
+	p := NewPhaser(parties)
+	var done [phases]atomic.Int32
+	var wg sync.WaitGroup
+	wg.Add(parties)
+	for range parties {
+		go func() {
+			defer wg.Done()
+			for phase := range phases {
+				done[phase].Add(1)
+				if next := p.ArriveAndAwait(); next != phase+1 {
+					t.Errorf("ArriveAndAwait() in phase %d = %d, want %d", phase, next, phase+1)
+				}
+				if n := done[phase].Load(); n != parties {
+					t.Errorf("phase %d: advanced with %d of %d done", phase, n, parties)
+				}
+			}
+		}()
+	}
+	testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+	if got := p.Phase(); got != phases {
+		t.Errorf("Phase() = %d, want %d", got, phases)
+	}
+}
+
+// TestPhaserArrive checks that Arrive does not wait, that the last arrival advances the phase, and that AwaitAdvance
+// follows it.
+func TestPhaserArrive(t *testing.T) {
+	p := NewPhaser(2)
+	if got := p.Arrive(); got != 0 {
+		t.Errorf("Arrive() = %d, want 0", got)
+	}
+	if got := p.Arrived(); got != 1 {
+		t.Errorf("Arrived() = %d, want 1", got)
+	}
+	advanced := make(chan int, 1)
+	go func() {
+		advanced <- p.AwaitAdvance(0)
+	}()
+	select {
+	case <-advanced:
+		t.Fatal("AwaitAdvance(0) returned before the phase advanced")
+	case <-time.After(20 * time.Millisecond):
+	}
+	p.Arrive()
+	if got := <-advanced; got != 1 {
+		t.Errorf("AwaitAdvance(0) = %d, want 1", got)
+	}
+	if got := p.AwaitAdvance(0); got != 1 {
+		t.Errorf("AwaitAdvance of a past phase = %d, want 1 at once", got)
+	}
+}
+
+// TestPhaserRegister checks that parties can join and leave between and during phases.
+func TestPhaserRegister(t *testing.T) {
+	p := NewPhaser(0)
+	if got := p.Register(); got != 0 {
+		t.Errorf("Register() = %d, want 0", got)
+	}
+	p.Register()
+	next := make(chan int, 1)
+	go func() {
+		next <- p.ArriveAndAwait()
+	}()
+	for p.Arrived() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	if got := p.ArriveAndDeregister(); got != 0 {
+		t.Errorf("ArriveAndDeregister() = %d, want 0", got)
+	}
+	if got := <-next; got != 1 {
+		t.Errorf("ArriveAndAwait() = %d, want 1", got)
+	}
+	if got := p.Parties(); got != 1 {
+		t.Errorf("Parties() = %d, want 1", got)
+	}
+
+}
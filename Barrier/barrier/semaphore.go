@@ -0,0 +1,149 @@
+package barrier
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Semaphore is a counting semaphore: it holds a number of permits, Acquire takes some, waiting until enough are free,
+// and Release gives them back. It does the job of golang.org/x/sync/semaphore.Weighted in a form small enough to read
+// and instrument, with Available and Waiting to watch it.
+//
+// A semaphore made with NewSemaphore lets a goroutine take free permits even while others wait, and on a Release
+// hands permits to any waiter they are enough for, so a small request can overtake a large one. That keeps permits
+// busy but can starve a waiter. One made with NewFairSemaphore serves goroutines strictly in the order they asked:
+// none takes permits while an earlier one waits.
+type Semaphore struct {
+	size int
+	fair bool
+
+	mu      sync.Mutex
+	free    int       // Permits not held.
+	waiters list.List // The *semWaiters waiting, in the order they asked.
+}
+
+// semWaiter is a goroutine waiting in Acquire.
+type semWaiter struct {
+	n     int           // Permits wanted.
+	ready chan struct{} // Closed once they have been granted.
+}
+
+// NewSemaphore returns a semaphore with permits free, which lets goroutines overtake one another. It panics if permits
+// is less than 1.
+func NewSemaphore(permits int) *Semaphore {
+	if permits < 1 {
+		panic(fmt.Sprintf("barrier: %d permits: a semaphore needs at least one", permits))
+	}
+	return &Semaphore{size: permits, free: permits}
+}
+
+// NewFairSemaphore returns a semaphore with permits free, which serves goroutines first come, first served. It panics if
+// permits is less than 1.
+func NewFairSemaphore(permits int) *Semaphore {
+	s := NewSemaphore(permits)
+	s.fair = true
+	return s
+}
+
+// Acquire takes n permits, waiting until they are free or ctx is cancelled. It returns nil once it has them, or
+// ctx.Err() without them. It panics if n is negative or more than the semaphore holds, as it could never have them.
+func (s *Semaphore) Acquire(ctx context.Context, n int) error {
+	s.check(n)
+	s.mu.Lock()
+	if s.take(n) {
+		s.mu.Unlock()
+		return nil
+	}
+	w := &semWaiter{n: n, ready: make(chan struct{})}
+	e := s.waiters.PushBack(w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-w.ready:
+		return nil // Granted just as ctx was cancelled.
+	default:
+	}
+	s.waiters.Remove(e)
+	s.grant() // With a fair semaphore, those behind may now be served.
+	return ctx.Err()
+}
+
+// TryAcquire takes n permits if they are free now, without waiting, and reports whether it did. A fair semaphore
+// does not hand them out while anyone is waiting.
+func (s *Semaphore) TryAcquire(n int) bool {
+	s.check(n)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.take(n)
+}
+
+// Release gives back n permits, handing them on to waiting goroutines. It panics if n is negative, which would take
+// permits away without waiting for them, or if more permits are released than are held.
+func (s *Semaphore) Release(n int) {
+	if n < 0 {
+		panic(fmt.Sprintf("barrier: releasing %d permits to a semaphore", n))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.free+n > s.size {
+		panic("barrier: Release of more permits than are held")
+	}
+	s.free += n
+	s.grant()
+}
+
+// take takes n permits if they are free and no earlier waiter at a fair semaphore should have them first. The caller
+// holds s.mu.
+func (s *Semaphore) take(n int) bool {
+	if s.free < n || s.fair && s.waiters.Len() > 0 {
+		return false
+	}
+	s.free -= n
+	return true
+}
+
+// check panics if n permits could never be acquired.
+func (s *Semaphore) check(n int) {
+	if n < 0 || n > s.size {
+		panic(fmt.Sprintf("barrier: acquiring %d permits from a semaphore of %d", n, s.size))
+	}
+}
+
+// grant hands free permits to waiters in the order they asked, stopping at the first they are not enough for if the
+// semaphore is fair and skipping it otherwise. The caller holds s.mu.
+func (s *Semaphore) grant() {
+	for e := s.waiters.Front(); e != nil && s.free > 0; {
+		w, next := e.Value.(*semWaiter), e.Next()
+		if w.n <= s.free {
+			s.free -= w.n
+			s.waiters.Remove(e)
+			close(w.ready)
+		} else if s.fair {
+			return
+		}
+		e = next
+	}
+}
+
+// Available returns the number of permits free now.
+func (s *Semaphore) Available() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.free
+}
+
+// Waiting returns the number of goroutines waiting in Acquire.
+func (s *Semaphore) Waiting() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.waiters.Len()
+}
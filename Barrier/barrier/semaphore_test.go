@@ -0,0 +1,155 @@
+package barrier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// TestSemaphore checks that no more goroutines than there are permits hold one at once, and that all get one in the
+// end.
+func TestSemaphore(t *testing.T) {
+	for _, fair := range []bool{false, true} {
+		s := NewSemaphore(3)
+		if fair {
+			s = NewFairSemaphore(3)
+		}
+		var holding, most, done atomic.Int32
+		var wg sync.WaitGroup
+		wg.Add(50)
+		for range 50 {
+			go func() {
+				defer wg.Done()
+				if err := s.Acquire(context.Background(), 1); err != nil {
+					t.Error(err)
+					return
+				}
+				n := holding.Add(1)
+				for m := most.Load(); n > m && !most.CompareAndSwap(m, n); m = most.Load() {
+				}
+				time.Sleep(time.Millisecond)
+				holding.Add(-1)
+				done.Add(1)
+				s.Release(1)
+			}()
+		}
+		testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+		if m := most.Load(); m > 3 {
+			t.Errorf("fair %v: %d goroutines held a permit at once, with 3 permits", fair, m)
+		}
+		if d := done.Load(); d != 50 {
+			t.Errorf("fair %v: %d of 50 goroutines got a permit", fair, d)
+		}
+		if a := s.Available(); a != 3 {
+			t.Errorf("fair %v: Available() = %d at the end, want 3", fair, a)
+		}
+	}
+}
+
+// waitFor waits until n goroutines are waiting at s.
+func waitFor(s *Semaphore, n int) {
+	for s.Waiting() < n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestSemaphoreFairness checks that a fair semaphore serves a large request before later small ones, while an unfair
+// one lets the small ones overtake it.
+func TestSemaphoreFairness(t *testing.T) {
+	for _, fair := range []bool{false, true} {
+		s := NewSemaphore(2)
+		if fair {
+			s = NewFairSemaphore(2)
+		}
+		s.Acquire(context.Background(), 2)
+		order := make(chan string, 2)
+		go func() {
+			s.Acquire(context.Background(), 2)
+			order <- "large"
+			s.Release(2)
+		}()
+		waitFor(s, 1)
+		go func() {
+			s.Acquire(context.Background(), 1)
+			order <- "small"
+			s.Release(1)
+		}()
+		waitFor(s, 2)
+
+		s.Release(1) // Enough for the small request only.
+		if fair {
+			if s.TryAcquire(1) {
+				t.Error("fair: TryAcquire took a permit while others waited")
+			}
+			s.Release(1)
+		} else {
+			if got := <-order; got != "small" {
+				t.Errorf("unfair: %s request served first, want small", got)
+			}
+			s.Release(1)
+		}
+		first := <-order
+		if fair && first != "large" {
+			t.Errorf("fair: %s request served first, want large", first)
+		}
+		if fair {
+			<-order
+		}
+		for s.Available() != 2 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+// TestSemaphoreAcquireContext checks that giving up waiting leaves the permits alone, and lets those behind a large
+// request at a fair semaphore be served.
+func TestSemaphoreAcquireContext(t *testing.T) {
+	s := NewFairSemaphore(2)
+	s.Acquire(context.Background(), 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	large := make(chan error, 1)
+	go func() {
+		large <- s.Acquire(ctx, 2)
+	}()
+	waitFor(s, 1)
+	small := make(chan error, 1)
+	go func() {
+		small <- s.Acquire(context.Background(), 1)
+	}()
+	waitFor(s, 2)
+
+	cancel()
+	if err := <-large; !errors.Is(err, context.Canceled) {
+		t.Errorf("Acquire() = %v, want %v", err, context.Canceled)
+	}
+	if err := <-small; err != nil {
+		t.Errorf("Acquire() behind the cancelled request = %v", err)
+	}
+	if a := s.Available(); a != 0 {
+		t.Errorf("Available() = %d, want 0", a)
+	}
+}
+
+func TestSemaphorePanics(t *testing.T) {
+	for name, f := range map[string]func(){
+		"NewSemaphore(0)":           func() { NewSemaphore(0) },
+		"Acquire of too many":       func() { NewSemaphore(1).Acquire(context.Background(), 2) },
+		"Acquire of a negative":     func() { NewSemaphore(1).Acquire(context.Background(), -1) },
+		"Release of more than held": func() { NewSemaphore(1).Release(1) },
+		"Release of a negative":     func() { NewSemaphore(2).Release(-1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
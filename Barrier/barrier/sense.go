@@ -0,0 +1,44 @@
+package barrier
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+)
+
+// SenseReversing is a barrier that its parties spin at rather than block. Each party decrements a shared count as it
+// arrives and spins until a shared sense flag flips; the last to arrive resets the count and flips the sense, which
+// releases everyone. As the sense alternates, a party released from one generation can arrive at the next at once
+// without being mistaken for a waiter in the old one.
+//
+// Spinning avoids waking sleeping goroutines, so it is fast when every party has a CPU to itself, but it wastes CPU
+// otherwise: parties yield with runtime.Gosched while they spin.
+type SenseReversing struct {
+	parties int32
+	count   atomic.Int32 // Parties yet to arrive in this generation.
+	sense   atomic.Bool  // Flipped to release each generation.
+}
+
+// NewSenseReversing returns a sense-reversing barrier for parties goroutines. It panics if parties is less than 1.
+func NewSenseReversing(parties int) *SenseReversing {
+	if parties < 1 {
+		panic(fmt.Sprintf("barrier: %d parties: there must be at least one", parties))
+	}
+	b := &SenseReversing{parties: int32(parties)}
+	b.count.Store(b.parties)
+	return b
+}
+
+// Wait spins until all the barrier's parties have called Wait. It always returns nil.
+func (b *SenseReversing) Wait() error {
+	sense := !b.sense.Load() // The sense that will release this generation.
+	if b.count.Add(-1) == 0 {
+		b.count.Store(b.parties) // Before the flip, so no one arrives at the next generation early.
+		b.sense.Store(sense)
+		return nil
+	}
+	for b.sense.Load() != sense {
+		runtime.Gosched()
+	}
+	return nil
+}
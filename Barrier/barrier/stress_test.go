@@ -0,0 +1,84 @@
+package barrier
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// stress has parties goroutines meet at w cycles times, failing if any is released before every party has arrived in
+// its cycle, or if they have not all finished within timeout, as when a barrier deadlocks or leaves a party behind.
+func stress(t *testing.T, w Waiter, parties, cycles int, timeout time.Duration) {
+	t.Helper()
+	arrived := make([]atomic.Int32, cycles)
+	reached := make([]atomic.Int32, parties) // The cycle each party has reached, to report where they stopped.
+	var early atomic.Int32
+	var wg sync.WaitGroup
+	wg.Add(parties)
+	for i := range parties {
+		go func() {
+			defer wg.Done()
+			for c := range cycles {
+				reached[i].Store(int32(c))
+				arrived[c].Add(1)
+				w.Wait()
+				if arrived[c].Load() != int32(parties) {
+					early.Add(1)
+				}
+			}
+			reached[i].Store(int32(cycles))
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		var stuck []string
+		for i := range reached {
+			if c := reached[i].Load(); c < int32(cycles) {
+				stuck = append(stuck, fmt.Sprintf("%d at cycle %d", i, c))
+			}
+		}
+		t.Fatalf("after %v, %d of %d parties had not finished: %s\n\n%s", timeout, len(stuck), parties, stuck[:min(len(stuck), 10)], testutil.Stacks())
+	}
+	if n := early.Load(); n > 0 {
+		t.Errorf("parties were released early %d times", n)
+	}
+}
+
+// TestStress has thousands of goroutines meet at each kind of barrier, cycle after cycle, under the race detector
+// where it is on. It is the regression test for the Barrier2 lab's channel ping-pong, which deadlocked now and then:
+// run it with -count to try more schedules. With -short it uses fewer goroutines and cycles.
+func TestStress(t *testing.T) {
+	parties, cycles := 1000, 1000
+	if testing.Short() {
+		parties, cycles = 200, 100
+	}
+	for _, w := range waiters {
+		t.Run(w.name, func(t *testing.T) {
+			t.Parallel()
+			stress(t, w.new(parties), parties, cycles, 5*time.Minute)
+		})
+	}
+	t.Run("phaser", func(t *testing.T) {
+		t.Parallel()
+		stress(t, phaserWaiter{NewPhaser(parties)}, parties, cycles, 5*time.Minute)
+	})
+}
+
+// phaserWaiter has a Phaser stand in for a barrier, for the stress test.
+type phaserWaiter struct{ *Phaser }
+
+func (p phaserWaiter) Wait() error {
+	p.ArriveAndAwait()
+	return nil
+}
@@ -0,0 +1,82 @@
+package barrier
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+)
+
+// Tree is a combining-tree barrier. Rather than every party decrementing one shared count, parties arrive at the
+// leaves of a tree, a few to each, and only the last to arrive at a node goes on to its parent, so no count is shared
+// by more than fanIn goroutines. The last to arrive at the root flips a sense flag, as the SenseReversing barrier
+// does, and the parties spin until it flips.
+//
+// Each party is given a leaf by the order it arrives in a generation.
+type Tree struct {
+	parties uint64
+	fanIn   int
+	leaves  []*treeNode
+	tickets atomic.Uint64 // Arrivals so far, over every generation; ticket t arrives in generation t/parties.
+	sense   atomic.Bool   // Flipped to release each generation.
+}
+
+// treeNode is a node of a combining tree, counting the arrivals from its children.
+type treeNode struct {
+	count  atomic.Int32 // Children yet to arrive in this generation.
+	size   int32        // Children it has: parties at a leaf, nodes above.
+	parent *treeNode    // Nil at the root.
+}
+
+// NewTree returns a combining-tree barrier for parties goroutines, with fanIn children to each node. It panics if
+// parties is less than 1 or fanIn less than 2.
+func NewTree(parties, fanIn int) *Tree {
+	if parties < 1 {
+		panic(fmt.Sprintf("barrier: %d parties: there must be at least one", parties))
+	}
+	if fanIn < 2 {
+		panic(fmt.Sprintf("barrier: fan-in %d: there must be at least two children to a node", fanIn))
+	}
+	b := &Tree{parties: uint64(parties), fanIn: fanIn}
+	// Build the tree bottom up: a level of nodes for each fanIn children of the level below, until one is left.
+	level := make([]*treeNode, (parties+fanIn-1)/fanIn)
+	for i := range level {
+		level[i] = &treeNode{size: int32(min(fanIn, parties-i*fanIn))}
+	}
+	b.leaves = level
+	for len(level) > 1 {
+		above := make([]*treeNode, (len(level)+fanIn-1)/fanIn)
+		for i := range above {
+			above[i] = &treeNode{size: int32(min(fanIn, len(level)-i*fanIn))}
+			for _, child := range level[i*fanIn : i*fanIn+int(above[i].size)] {
+				child.parent = above[i]
+			}
+		}
+		level = above
+	}
+	for _, leaf := range b.leaves {
+		for n := leaf; n != nil; n = n.parent {
+			n.count.Store(n.size)
+		}
+	}
+	return b
+}
+
+// Wait spins until all the barrier's parties have called Wait. It always returns nil.
+func (b *Tree) Wait() error {
+	ticket := b.tickets.Add(1) - 1
+	sense := (ticket/b.parties)%2 == 0 // The sense that will release this generation: true for the first.
+	node := b.leaves[int(ticket%b.parties)/b.fanIn]
+	for node.count.Add(-1) == 0 {
+		// Last to arrive here: no one else arrives at this node until the release, so reset it and go on up.
+		node.count.Store(node.size)
+		if node.parent == nil {
+			b.sense.Store(sense)
+			return nil
+		}
+		node = node.parent
+	}
+	for b.sense.Load() != sense {
+		runtime.Gosched()
+	}
+	return nil
+}
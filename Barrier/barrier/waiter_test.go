@@ -0,0 +1,88 @@
+package barrier
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// waiters lists each kind of barrier, by name, for the tests and benchmarks to run against.
+var waiters = []struct {
+	name string
+	new  func(parties int) Waiter
+}{
+	{"channel", func(n int) Waiter { return New(n) }},
+	{"turnstile", func(n int) Waiter { return NewTurnstile(n) }},
+	{"sense", func(n int) Waiter { return NewSenseReversing(n) }},
+	{"tree", func(n int) Waiter { return NewTree(n, 4) }},
+}
+
+// meet has parties goroutines wait at w generations times each, and waits for them all.
+func meet(w Waiter, parties, generations int) {
+	var wg sync.WaitGroup
+	wg.Add(parties)
+	for range parties {
+		go func() {
+			defer wg.Done()
+			for range generations {
+				w.Wait()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestWaiters checks that no party passes any of the barriers until all have arrived, generation after generation,
+// with tables that do and do not fill the trees' leaves.
+func TestWaiters(t *testing.T) {
+	for _, w := range waiters {
+		for _, parties := range []int{1, 2, 5, 16, 37} {
+			t.Run(fmt.Sprintf("%s/%d", w.name, parties), func(t *testing.T) {
+				const generations = 100
+				var arrived [generations + 1]atomic.Int32
+				b := w.new(parties)
+				var wg sync.WaitGroup
+				wg.Add(parties)
+				for range parties {
+					go func() {
+						defer wg.Done()
+						for g := 1; g <= generations; g++ {
+							arrived[g].Add(1)
+							b.Wait()
+							if n := arrived[g].Load(); n != int32(parties) {
+								t.Errorf("generation %d: released with %d of %d arrived", g, n, parties)
+								return
+							}
+						}
+					}()
+				}
+				testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+			})
+		}
+	}
+}
+
+func TestNewTreePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewTree(4, 1) did not panic")
+		}
+	}()
+	NewTree(4, 1)
+}
+
+// BenchmarkWaiters times one generation of each barrier, with every party arriving and being released, at 10, 100 and
+// 1000 goroutines.
+func BenchmarkWaiters(b *testing.B) {
+	for _, parties := range []int{10, 100, 1000} {
+		for _, w := range waiters {
+			b.Run(fmt.Sprintf("%s/%d", w.name, parties), func(b *testing.B) {
+				meet(w.new(parties), parties, b.N)
+			})
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package pool
+
+import "sync"
+
+// deque is a worker's double-ended queue of tasks. Its worker pushes and pops at the bottom, newest first, which keeps
+// the work it is given last, and most likely still in its cache, for itself; thieves steal from the top, oldest first.
+type deque struct {
+	mu    sync.Mutex
+	tasks []func() // tasks[head:] are queued, the oldest first.
+	head  int
+}
+
+// push adds task to the bottom of d.
+func (d *deque) push(task func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.tasks = append(d.tasks, task)
+}
+
+// pop removes the task at the bottom of d, the newest, or returns nil if d is empty.
+func (d *deque) pop() func() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	last := len(d.tasks) - 1
+	if last < d.head {
+		return nil
+	}
+	task := d.tasks[last]
+	d.tasks[last] = nil // Do not keep the task alive once taken.
+	d.tasks = d.tasks[:last]
+	d.reset()
+	return task
+}
+
+// steal removes the task at the top of d, the oldest, or returns nil if d is empty.
+func (d *deque) steal() func() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.head == len(d.tasks) {
+		return nil
+	}
+	task := d.tasks[d.head]
+	d.tasks[d.head] = nil
+	d.head++
+	d.reset()
+	return task
+}
+
+// reset reuses the slice from the start once d is empty, so stealing from the top does not make it grow without end.
+// The caller holds d.mu.
+func (d *deque) reset() {
+	if d.head == len(d.tasks) {
+		d.tasks, d.head = d.tasks[:0], 0
+	}
+}
@@ -0,0 +1,172 @@
+// Package pool provides a work-stealing pool of worker goroutines. Tasks are submitted with Submit and the pool is
+// waited on with Wait:
+//
+//	p := pool.New(4)
+//	defer p.Close()
+//	for _, part := range parts {
+//		p.Submit(func() { process(part) })
+//	}
+//	p.Wait() // Every part has been processed.
+//
+// Each worker has its own deque of tasks. Submit deals tasks out to the deques in turn, and a worker takes the task it
+// was given most recently from the bottom of its own deque. A worker whose deque is empty steals the oldest task from
+// the top of another's, choosing where to start looking at random, so the workers keep busy when some tasks take
+// longer than others without all of them contending for one shared queue, as the workers of a channel do. Only a
+// worker with nothing to do touches another's deque.
+//
+// The deques are guarded by a mutex each rather than written lock-free, as in the Chase-Lev deque, to stay small
+// enough to read: a worker locks only its own deque until it runs out of work.
+package pool
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+)
+
+// Pool is a fixed number of worker goroutines running submitted tasks, each from its own deque, stealing from the
+// others when its own is empty. Create one with New and stop its workers with Close.
+type Pool struct {
+	deques []*deque
+	stats  []workerStats // Indexed like deques.
+	next   atomic.Uint64 // The deque the next Submit adds to, modulo the number of workers.
+	queued atomic.Int64  // Tasks in the deques, not yet taken by a worker.
+	idle   atomic.Int64  // Workers asleep or going to sleep.
+	tasks  sync.WaitGroup
+	done   sync.WaitGroup // The workers, for Close.
+
+	mu     sync.Mutex
+	wake   *sync.Cond  // Signalled when a task is submitted and a worker is idle, and broadcast by Close.
+	closed atomic.Bool // Set, holding mu, by Close.
+}
+
+// WorkerStats describes what a worker of a Pool has done.
+type WorkerStats struct {
+	Executed int64 // Tasks the worker ran.
+	Stolen   int64 // Tasks among those it stole from another worker's deque.
+}
+
+// workerStats is a WorkerStats that its worker can update while Stats reads it.
+type workerStats struct {
+	executed atomic.Int64
+	stolen   atomic.Int64
+}
+
+// New returns a pool of workers goroutines, started and waiting for tasks. It panics if workers is less than 1.
+func New(workers int) *Pool {
+	if workers < 1 {
+		panic(fmt.Sprintf("pool: %d workers: a pool needs at least one", workers))
+	}
+	p := &Pool{deques: make([]*deque, workers), stats: make([]workerStats, workers)}
+	p.wake = sync.NewCond(&p.mu)
+	for i := range p.deques {
+		p.deques[i] = &deque{}
+	}
+	p.done.Add(workers)
+	for i := range workers {
+		go p.work(i)
+	}
+	return p
+}
+
+// Submit adds task to the pool, to be run by one of its workers. It can be called from any goroutine, including
+// from a task. A task that panics crashes the program, as it would on a goroutine of its own. Submit panics if the
+// pool is closed.
+func (p *Pool) Submit(task func()) {
+	if p.closed.Load() {
+		panic("pool: Submit after Close")
+	}
+	p.tasks.Add(1)
+	p.queued.Add(1) // Counted before it is in a deque, so a worker that sees none queued cannot miss it.
+	p.deques[p.next.Add(1)%uint64(len(p.deques))].push(task)
+	if p.idle.Load() > 0 {
+		p.mu.Lock() // Wait until a worker going to sleep is waiting, so it cannot miss the signal.
+		p.wake.Signal()
+		p.mu.Unlock()
+	}
+}
+
+// Wait waits until every task submitted has run, including those submitted by tasks while it waits. As with a
+// sync.WaitGroup, a Submit from outside the pool must not race with Wait returning: submit the tasks, then wait.
+func (p *Pool) Wait() {
+	p.tasks.Wait()
+}
+
+// Close stops the workers once they have run every task already submitted, and returns when they have. Call it after
+// Wait, as a task that submits another once the pool is closed panics. Closing a closed pool does nothing.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	p.closed.Store(true)
+	p.wake.Broadcast()
+	p.mu.Unlock()
+	p.done.Wait()
+}
+
+// Workers returns the number of workers in the pool.
+func (p *Pool) Workers() int {
+	return len(p.deques)
+}
+
+// Stats returns what each worker has done so far, indexed by worker.
+func (p *Pool) Stats() []WorkerStats {
+	stats := make([]WorkerStats, len(p.stats))
+	for i := range p.stats {
+		stats[i] = WorkerStats{Executed: p.stats[i].executed.Load(), Stolen: p.stats[i].stolen.Load()}
+	}
+	return stats
+}
+
+// work runs tasks on worker id until the pool is closed and no tasks are left.
+func (p *Pool) work(id int) {
+	defer p.done.Done()
+	for {
+		task, stolen := p.find(id)
+		if task == nil {
+			if !p.sleep() {
+				return
+			}
+			continue
+		}
+		p.queued.Add(-1)
+		task()
+		p.stats[id].executed.Add(1)
+		if stolen {
+			p.stats[id].stolen.Add(1)
+		}
+		p.tasks.Done()
+	}
+}
+
+// find takes the newest task from worker id's own deque or, if it is empty, steals the oldest from another's,
+// reporting whether it stole it. It returns nil if every deque is empty.
+func (p *Pool) find(id int) (task func(), stolen bool) {
+	if task := p.deques[id].pop(); task != nil {
+		return task, false
+	}
+	n := len(p.deques)
+	start := rand.IntN(n) // A random victim first, so idle workers do not all rob the same one.
+	for i := range n {
+		if victim := (start + i) % n; victim != id {
+			if task := p.deques[victim].steal(); task != nil {
+				return task, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// sleep waits until a task is queued or the pool is closed, reporting false if the worker is to stop.
+//
+// A worker counts itself idle before checking for tasks, and Submit counts a task queued before checking for idle
+// workers, so at least one of the two sees the other: either the worker finds the task, or Submit signals it.
+func (p *Pool) sleep() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.idle.Add(1)
+	for p.queued.Load() == 0 && !p.closed.Load() {
+		p.wake.Wait()
+	}
+	p.idle.Add(-1)
+	return p.queued.Load() > 0 // Closed, but tasks submitted before Close are still run.
+}
@@ -0,0 +1,201 @@
+package pool
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// TestPool checks that every task runs exactly once, whatever the number of workers, under the race detector.
+func TestPool(t *testing.T) {
+	const tasks = 10000
+	for _, workers := range []int{1, 2, 8} {
+		p := New(workers)
+		runs := make([]atomic.Int32, tasks)
+		for i := range tasks {
+			p.Submit(func() { runs[i].Add(1) })
+		}
+		testutil.RequireCompletesWithin(t, 10*time.Second, p.Wait)
+		for i := range runs {
+			if n := runs[i].Load(); n != 1 {
+				t.Fatalf("%d workers: task %d ran %d times", workers, i, n)
+			}
+		}
+		var executed int64
+		for _, s := range p.Stats() {
+			executed += s.Executed
+		}
+		if executed != tasks {
+			t.Errorf("%d workers: Stats() counts %d tasks executed, want %d", workers, executed, tasks)
+		}
+		testutil.RequireCompletesWithin(t, 10*time.Second, p.Close)
+	}
+}
+
+// sum adds up the integers from lo to hi-1 by splitting the range in two until it is small, submitting each half as a
+// task, as a divide-and-conquer algorithm does.
+func sum(p *Pool, lo, hi int, total *atomic.Int64) {
+	if hi-lo <= 8 {
+		for i := lo; i < hi; i++ {
+			total.Add(int64(i))
+		}
+		return
+	}
+	mid := (lo + hi) / 2
+	p.Submit(func() { sum(p, lo, mid, total) })
+	p.Submit(func() { sum(p, mid, hi, total) })
+}
+
+// TestNested checks that Wait waits for tasks submitted by other tasks.
+func TestNested(t *testing.T) {
+	p := New(4)
+	defer p.Close()
+	var total atomic.Int64
+	p.Submit(func() { sum(p, 0, 10000, &total) })
+	testutil.RequireCompletesWithin(t, 10*time.Second, p.Wait)
+	if got, want := total.Load(), int64(10000*9999/2); got != want {
+		t.Errorf("sum = %d, want %d", got, want)
+	}
+}
+
+// TestStealing checks that idle workers steal from one kept busy: every fourth task is slow and Submit deals them all
+// to the same worker, so the others run out and must take its remaining tasks for the pool to finish quickly.
+func TestStealing(t *testing.T) {
+	const workers, tasks = 4, 40
+	p := New(workers)
+	defer p.Close()
+	start := time.Now()
+	for i := range tasks {
+		p.Submit(func() {
+			if i%workers == 0 {
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+	testutil.RequireCompletesWithin(t, 10*time.Second, p.Wait)
+	var stolen int64
+	for _, s := range p.Stats() {
+		stolen += s.Stolen
+	}
+	if stolen == 0 {
+		t.Errorf("no task was stolen: Stats() = %+v", p.Stats())
+	}
+	// Without stealing one worker would sleep through all ten slow tasks, for 100ms.
+	if elapsed := time.Since(start); elapsed >= 100*time.Millisecond {
+		t.Logf("took %v; the slow tasks were not shared out", elapsed) // Timing is only a hint on a loaded machine.
+	}
+}
+
+// TestClose checks that Close runs the tasks already submitted before stopping the workers, that a second Close does
+// nothing, and that Submit then panics.
+func TestClose(t *testing.T) {
+	p := New(2)
+	var ran atomic.Int32
+	for range 100 {
+		p.Submit(func() {
+			time.Sleep(time.Microsecond)
+			ran.Add(1)
+		})
+	}
+	testutil.RequireCompletesWithin(t, 10*time.Second, func() {
+		p.Close()
+		p.Close()
+	})
+	if n := ran.Load(); n != 100 {
+		t.Errorf("%d of 100 tasks ran before Close returned", n)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Submit after Close did not panic")
+		}
+	}()
+	p.Submit(func() {})
+}
+
+// TestWaitIdle checks that waiting on a pool with nothing submitted returns at once, and that workers asleep between
+// batches wake for the next.
+func TestWaitIdle(t *testing.T) {
+	p := New(4)
+	defer p.Close()
+	testutil.RequireCompletesWithin(t, 10*time.Second, p.Wait)
+	for batch := range 100 {
+		var ran atomic.Int32
+		for range batch % 5 {
+			p.Submit(func() { ran.Add(1) })
+		}
+		testutil.RequireCompletesWithin(t, 10*time.Second, p.Wait)
+		if n := ran.Load(); n != int32(batch%5) {
+			t.Fatalf("batch %d: %d of %d tasks ran", batch, n, batch%5)
+		}
+	}
+}
+
+func TestNewPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("New(0) did not panic")
+		}
+	}()
+	New(0)
+}
+
+// BenchmarkPool times running small tasks, all alike or every eighth a hundred times longer, on the pool and on
+// workers fed by one shared channel.
+func BenchmarkPool(b *testing.B) {
+	const workers = 4
+	task := func(i int) func() {
+		return func() {
+			n := 100
+			if i%8 == 0 {
+				n = 10000
+			}
+			x := 0
+			for j := range n {
+				x += j
+			}
+			_ = x
+		}
+	}
+	b.Run("pool", func(b *testing.B) {
+		p := New(workers)
+		defer p.Close()
+		for i := range b.N {
+			p.Submit(task(i))
+		}
+		p.Wait()
+	})
+	b.Run("channel", func(b *testing.B) {
+		tasks := make(chan func())
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for range workers {
+			go func() {
+				defer wg.Done()
+				for t := range tasks {
+					t()
+				}
+			}()
+		}
+		for i := range b.N {
+			tasks <- task(i)
+		}
+		close(tasks)
+		wg.Wait()
+	})
+}
+
+func ExamplePool() {
+	p := New(4)
+	defer p.Close()
+	squares := make([]int, 10)
+	for i := range squares {
+		p.Submit(func() { squares[i] = i * i }) // Each task writes only its own element.
+	}
+	p.Wait()
+	fmt.Println(squares)
+	// Output: [0 1 4 9 16 25 36 49 64 81]
+}
@@ -0,0 +1,45 @@
+// Package testutil helps test synchronisation code, whose bugs tend to show as a test that hangs rather than one that
+// fails. Left to itself such a test runs until go test's -timeout, ten minutes by default, then panics; a test that
+// sets its own deadline with time.After fails sooner but without saying where everyone was stuck.
+// RequireCompletesWithin does both: it fails the test soon, and with every goroutine's stack.
+//
+//	testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+package testutil
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// RequireCompletesWithin calls fn and waits for it to return, failing the test with t.Fatalf, and a dump of every
+// goroutine's stack, if it has not returned within d. It must be called from the test's own goroutine, as Fatalf must.
+// fn runs on a goroutine of its own, which a failed test leaves behind, still blocked.
+func RequireCompletesWithin(t testing.TB, d time.Duration, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		t.Fatalf("did not complete within %v, so is probably deadlocked; every goroutine's stack:\n\n%s", d, Stacks())
+	}
+}
+
+// Stacks returns the stack of every goroutine, as a panic that is not recovered prints them, for a test's own message
+// about where its goroutines are stuck.
+func Stacks() string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
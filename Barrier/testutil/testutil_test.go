@@ -0,0 +1,45 @@
+package testutil
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recorder is a testing.TB that records Fatalf rather than stopping the test.
+type recorder struct {
+	testing.TB
+	failed string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.failed = fmt.Sprintf(format, args...)
+}
+
+func TestRequireCompletesWithin(t *testing.T) {
+	r := &recorder{TB: t}
+	ran := false
+	RequireCompletesWithin(r, time.Second, func() { ran = true })
+	if !ran || r.failed != "" {
+		t.Errorf("a function that returns at once: ran %t, failed with %q", ran, r.failed)
+	}
+
+	hang := make(chan struct{})
+	defer close(hang) // Lets the goroutine left blocked return.
+	RequireCompletesWithin(r, 10*time.Millisecond, func() { <-hang })
+	if !strings.Contains(r.failed, "did not complete within 10ms") {
+		t.Errorf("a function that hangs failed with %q, want it to say so", r.failed)
+	}
+	if !strings.Contains(r.failed, "TestRequireCompletesWithin.func") {
+		t.Errorf("the failure has no stack of the hung goroutine:\n%s", r.failed)
+	}
+}
+
+func TestStacks(t *testing.T) {
+	if s := Stacks(); !strings.Contains(s, "testutil.TestStacks") {
+		t.Errorf("Stacks() does not include the calling goroutine:\n%s", s)
+	}
+}
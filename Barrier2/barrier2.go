@@ -5,11 +5,10 @@
 // Created on 30/9/2024
 // Modified by: Aaron Doyle, Ronan Green
 // Description:
-// A simple barrier implemented using mutex and unbuffered channel
+// A reusable barrier: ten goroutines meet at the same barrier after part A
+// and again after part B, round after round, using the barrier package.
 // Issues:
 // None I hope
-//1. Change mutex to atomic variable
-//2. Make it a reusable barrier
 //--------------------------------------------
 
 package main
@@ -18,41 +17,22 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"Barrier/barrier"
 )
 
-// Place a barrier in this function --use Mutex's and Semaphores
-func doStuff(goNum int, arrived *int, max int, wg *sync.WaitGroup, sharedLock *sync.Mutex, theChan chan bool) bool {
+// doStuff does part A and part B twice, with everyone meeting at the barrier after each part.
+func doStuff(goNum int, wg *sync.WaitGroup, theBarrier *barrier.Barrier) bool {
 	for i := 1; i < 3; i++ {
 		time.Sleep(time.Second)
 		fmt.Println("Part A", goNum)
 		//we wait here until everyone has completed part A
-		sharedLock.Lock()
-		*arrived++
-		if *arrived == 10 { //last to arrive -signal others to go
-			sharedLock.Unlock() //unlock before any potentially blocking code
-			theChan <- true
-			<-theChan
-		} else { //not all here yet we wait until signal
-			sharedLock.Unlock() //unlock before any potentially blocking code
-			<-theChan
-			theChan <- true //once we get through send signal to next routine to continue
-		} //end of if-else
+		theBarrier.Wait()
 
 		fmt.Println("Part B", goNum)
-		
-		// everything is waiting here until the threads are finished
-		sharedLock.Lock()
-		*arrived--
-		if *arrived == 0 { // checking if all have arrived
-			sharedLock.Unlock() // unlocking to prevent a deadlock
-			theChan <- true
-			<-theChan // Setting it to wait for a signal
-		} else {
-			sharedLock.Unlock() // unlocking to prevent a deadlock
-			<-theChan
-			theChan <- true // This is sending a signal to the next routine
-		}
-		
+
+		// everything is waiting here until the threads are finished, so no one starts the next part A early
+		theBarrier.Wait()
 	}
 	wg.Done()
 	return true
@@ -60,14 +40,11 @@ func doStuff(goNum int, arrived *int, max int, wg *sync.WaitGroup, sharedLock *s
 
 func main() {
 	totalRoutines := 10
-	arrived := 0
 	var wg sync.WaitGroup
 	wg.Add(totalRoutines)
-	//we will need some of these
-	var theLock sync.Mutex
-	theChan := make(chan bool)     //use unbuffered channel in place of semaphore
-	for i := range totalRoutines { //create the go Routines here
-		go doStuff(i, &arrived, totalRoutines, &wg, &theLock, theChan)
+	theBarrier := barrier.New(totalRoutines) // The same barrier is reused for every meeting
+	for i := range totalRoutines {           //create the go Routines here
+		go doStuff(i, &wg, theBarrier)
 	}
 	wg.Wait() //wait for everyone to finish before exiting
 } //end-main
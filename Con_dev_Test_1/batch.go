@@ -0,0 +1,194 @@
+// Ronan Green
+// C00270395
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+
+	"Con_dev_Test_1/roman"
+)
+
+// batchHeader is the first row of the batch results in CSV.
+var batchHeader = []string{"Input", "Value", "Numeral", "Valid", "Error"}
+
+// batchFormats names the formats the batch results can be written in.
+var batchFormats = []string{"csv", "json", "plain"}
+
+// errInvalid is wrapped by the error runBatch returns when a line could not be converted, as opposed to the input
+// or output failing.
+var errInvalid = errors.New("could not be converted")
+
+// batchResult is the result of converting one line, as written in JSON.
+type batchResult struct {
+	Input   string `json:"input"`
+	Value   int    `json:"value,omitempty"`
+	Numeral string `json:"numeral,omitempty"`
+	Valid   bool   `json:"valid"`
+	Error   string `json:"error,omitempty"`
+}
+
+// batchWriter writes batch results in one of batchFormats.
+type batchWriter interface {
+	write(r batchResult) error
+	flush() error
+}
+
+// newBatchWriter returns a writer for format, which must be one of batchFormats, writing to out.
+// The plain format shows the values as c asks with -words, -year and -explain. With -q, only the results are written
+// whatever the format.
+func newBatchWriter(format string, out io.Writer, c converter) (batchWriter, error) {
+	if c.quiet && slices.Contains(batchFormats, format) {
+		return quietBatchWriter{out, c}, nil
+	}
+	switch format {
+	case "csv":
+		w := csvBatchWriter{csv.NewWriter(out)}
+		return w, w.w.Write(batchHeader)
+	case "json":
+		return jsonBatchWriter{json.NewEncoder(out)}, nil
+	case "plain":
+		return plainBatchWriter{out, c}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q (want %s)", format, strings.Join(batchFormats, ", "))
+}
+
+// csvBatchWriter writes a row for each result, after batchHeader.
+type csvBatchWriter struct{ w *csv.Writer }
+
+func (c csvBatchWriter) write(r batchResult) error {
+	row := []string{r.Input, "", r.Numeral, strconv.FormatBool(r.Valid), r.Error}
+	if r.Valid {
+		row[1] = strconv.Itoa(r.Value)
+	}
+	return c.w.Write(row)
+}
+
+func (c csvBatchWriter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonBatchWriter writes each result as a JSON object on its own line, so the output can be streamed into tools
+// such as jq.
+type jsonBatchWriter struct{ e *json.Encoder }
+
+func (j jsonBatchWriter) write(r batchResult) error { return j.e.Encode(r) }
+
+func (j jsonBatchWriter) flush() error { return nil }
+
+// plainBatchWriter writes each result as the REPL prints it.
+type plainBatchWriter struct {
+	w io.Writer
+	c converter
+}
+
+func (p plainBatchWriter) write(r batchResult) error {
+	var err error
+	if r.Valid {
+		_, err = fmt.Fprintln(p.w, conversion{input: r.Input, value: r.Value, numeral: r.Numeral, words: p.c.words, year: p.c.year})
+		if p.c.explain && err == nil {
+			_, err = fmt.Fprintln(p.w, "  "+p.c.explanation(r.Input, r.Numeral))
+		}
+	} else {
+		_, err = fmt.Fprintf(p.w, "%s: Error: %s\n", r.Input, r.Error)
+	}
+	return err
+}
+
+func (p plainBatchWriter) flush() error { return nil }
+
+// quietBatchWriter writes only the result of each line, as convertArgs does with -q, and a blank line for a line that
+// could not be converted, so the results still line up with the input.
+type quietBatchWriter struct {
+	w io.Writer
+	c converter
+}
+
+func (q quietBatchWriter) write(r batchResult) error {
+	var err error
+	if r.Valid {
+		_, err = fmt.Fprintln(q.w, q.c.result(r.Value, r.Numeral))
+	} else {
+		_, err = fmt.Fprintln(q.w)
+	}
+	return err
+}
+
+func (q quietBatchWriter) flush() error { return nil }
+
+// runBatch converts every line of filename, or of standard input if filename is empty, and writes the results in
+// format to outFile, or to standard output if outFile is empty. A summary is printed to standard error unless c is
+// quiet, and an error is returned if any line could not be converted, so scripts can tell from the exit status.
+func runBatch(c converter, filename, outFile, format string) error {
+	in := io.Reader(os.Stdin)
+	if filename != "" {
+		f, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	out := io.Writer(os.Stdout)
+	if outFile != "" {
+		f, err := os.Create(outFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	w, err := newBatchWriter(format, out, c)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	converted, failed, err := convertBatch(ctx, c, in, w)
+	if err != nil {
+		return err
+	}
+	if !c.quiet {
+		fmt.Fprintf(os.Stderr, "%d converted, %d failed\n", converted, failed)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d lines %w", failed, converted+failed, errInvalid)
+	}
+	return nil
+}
+
+// convertBatch converts each line of in, several at once, and writes a result to w for each in order: the line, its
+// value and canonical numeral, or the error if it could not be converted. Blank lines are skipped.
+//
+// Input:
+//   - ctx (context.Context): Stops the conversion when cancelled, such as by Ctrl+C.
+//   - c (converter): Converts each line, with c.workers lines at once.
+//   - in (io.Reader): One numeral, or number with -to roman, per line.
+//   - w (batchWriter): Where the results are written.
+//
+// Output:
+//   - int: The number of lines converted.
+//   - int: The number of lines that could not be converted.
+//   - error: Returns an error if the conversion was cancelled, in cannot be read or w cannot be written; a line that
+//     cannot be converted is not an error, only a result that is not valid.
+func convertBatch(ctx context.Context, c converter, in io.Reader, w batchWriter) (converted, failed int, err error) {
+	converted, failed, err = roman.ConvertEach(ctx, in, c.options(), func(r roman.Result) error {
+		if r.Err != nil {
+			return w.write(batchResult{Input: r.Input, Error: r.Err.Error()})
+		}
+		return w.write(batchResult{Input: r.Input, Value: r.Value, Numeral: r.Numeral, Valid: true})
+	})
+	return converted, failed, errors.Join(err, w.flush())
+}
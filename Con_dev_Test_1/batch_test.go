@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"Con_dev_Test_1/roman"
+)
+
+// TestConvertBatch converts a list in both directions, checking that failures become rows rather than errors.
+func TestConvertBatch(t *testing.T) {
+	tests := []struct {
+		c                 converter
+		in                string
+		want              string
+		converted, failed int
+	}{
+		{
+			converter{}, "XIV\nIIII\n\nMCMXCIV\r\n",
+			"Input,Value,Numeral,Valid,Error\nXIV,14,XIV,true,\nIIII,,,false,\"roman: invalid Roman numeral combination at position 4 in \"\"IIII\"\"\"\nMCMXCIV,1994,MCMXCIV,true,\n",
+			2, 1,
+		},
+		{
+			converter{opts: roman.Lenient}, "iiii\n xc \n",
+			"Input,Value,Numeral,Valid,Error\niiii,4,IV,true,\n\" xc \",90,XC,true,\n",
+			2, 0,
+		},
+		{
+			converter{toRoman: true}, "1994\n4000\nten\n",
+			"Input,Value,Numeral,Valid,Error\n1994,1994,MCMXCIV,true,\n4000,,,false,roman: 4000 cannot be written as a Roman numeral; only 1 to 3999 can\nten,,,false,\"roman: \"\"ten\"\" is not a whole number\"\n",
+			1, 2,
+		},
+	}
+	for _, test := range tests {
+		var out strings.Builder
+		w, err := newBatchWriter("csv", &out, test.c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		converted, failed, err := convertBatch(context.Background(), test.c, strings.NewReader(test.in), w)
+		if err != nil || converted != test.converted || failed != test.failed || out.String() != test.want {
+			t.Errorf("convertBatch(%q) = %d, %d, %v writing\n%s\nwant %d, %d, nil writing\n%s",
+				test.in, converted, failed, err, out.String(), test.converted, test.failed, test.want)
+		}
+	}
+}
+
+// TestBatchFormats writes the same results in each format.
+func TestBatchFormats(t *testing.T) {
+	want := map[string]string{
+		"json": `{"input":"XIV","value":14,"numeral":"XIV","valid":true}` + "\n" +
+			`{"input":"iiii","value":4,"numeral":"IV","valid":true}` + "\n" +
+			`{"input":"bad","valid":false,"error":"roman: invalid character 'b' at position 1 in \"bad\"; only use the Roman numeral characters I, V, X, L, C, D, M"}` + "\n",
+		"plain": "XIV = 14\niiii = 4 (IV)\nbad: Error: roman: invalid character 'b' at position 1 in \"bad\"; only use the Roman numeral characters I, V, X, L, C, D, M\n",
+	}
+	for format, want := range want {
+		var out strings.Builder
+		w, err := newBatchWriter(format, &out, converter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := convertBatch(context.Background(), converter{opts: roman.Lenient}, strings.NewReader("XIV\niiii\nbad\n"), w); err != nil || out.String() != want {
+			t.Errorf("the %s results are\n%s(%v), want\n%s", format, out.String(), err, want)
+		}
+	}
+
+	// With -q, only the results are written, and a blank line for each failure, whatever the format.
+	var out strings.Builder
+	w, err := newBatchWriter("csv", &out, converter{quiet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := convertBatch(context.Background(), converter{opts: roman.Lenient}, strings.NewReader("XIV\nbad\niiii\n"), w); err != nil || out.String() != "14\n\n4\n" {
+		t.Errorf("the quiet results are %q (%v), want %q", out.String(), err, "14\n\n4\n")
+	}
+	if _, err := newBatchWriter("xml", &strings.Builder{}, converter{}); err == nil {
+		t.Error("newBatchWriter accepted an unknown format")
+	}
+}
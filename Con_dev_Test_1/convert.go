@@ -0,0 +1,94 @@
+// Ronan Green
+// C00270395
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"Con_dev_Test_1/input"
+	"Con_dev_Test_1/roman"
+)
+
+// converter converts input in the direction chosen with -to.
+type converter struct {
+	toRoman bool                // Whether the input is a number to write as a numeral, rather than a numeral to read.
+	opts    roman.ParseOptions  // How forgiving to be when reading numerals.
+	format  roman.FormatOptions // How to write numerals.
+	words   bool                // Whether to show values with thousands separators and in words.
+	year    bool                // Whether to show values as years, with their century.
+	explain bool                // Whether to show how each numeral adds up to its value.
+	quiet   bool                // Whether to write only the result of each conversion, as -q asks.
+	workers int                 // How many lines to convert at once in batch mode; as many as there are CPUs if 0.
+	steal   bool                // Whether the batch workers are a work-stealing pool, as -work-stealing asks.
+}
+
+// maxInput is the most characters converted from one input. Longer input is rejected before it is read, however
+// forgiving the options.
+const maxInput = 1000
+
+// validInput checks an input is worth converting: not blank, and not longer than maxInput.
+var validInput = input.All(input.NonEmpty(), input.MaxLength(maxInput))
+
+// convert converts one numeral or number and returns both its value and its canonical numeral.
+func (c converter) convert(in string) (int, string, error) {
+	if err := validInput(in); err != nil {
+		return 0, "", err
+	}
+	return roman.Convert(in, c.options())
+}
+
+// options returns the library options c converts with.
+func (c converter) options() roman.Options {
+	return roman.Options{ToRoman: c.toRoman, Parse: c.opts, Format: c.format, Workers: c.workers, WorkStealing: c.steal}
+}
+
+// explanation shows how the numeral for a successful conversion adds up, step by step: the numeral as typed when
+// reading one, and the canonical numeral when writing one.
+func (c converter) explanation(input, numeral string) string {
+	opts := c.opts
+	if c.toRoman {
+		input, opts = numeral, roman.ParseOptions{Unicode: true, Vinculum: c.format.Vinculum, Style: c.format.Style}
+	}
+	b, err := roman.ParseBreakdown(input, opts)
+	if err != nil {
+		return err.Error() // Unreachable, since the conversion succeeded.
+	}
+	return b.String()
+}
+
+// result is what a successful conversion is quietly written as: the value when reading a numeral, and the numeral
+// when writing one.
+func (c converter) result(value int, numeral string) string {
+	if c.toRoman {
+		return numeral
+	}
+	return strconv.Itoa(value)
+}
+
+// convertArgs converts each command-line argument, writing each result to out as the REPL prints it, or only the
+// result with -q, and each error to errOut unless quiet. It returns exitInvalid if any argument could not be
+// converted, and exitOK otherwise.
+func convertArgs(c converter, args []string, out, errOut io.Writer) int {
+	status := exitOK
+	for _, arg := range args {
+		value, numeral, err := c.convert(arg)
+		switch {
+		case err != nil:
+			status = exitInvalid
+			if !c.quiet {
+				fmt.Fprintln(errOut, "Error:", err)
+			}
+		case c.quiet:
+			fmt.Fprintln(out, c.result(value, numeral))
+		default:
+			fmt.Fprintln(out, conversion{input: arg, value: value, numeral: numeral, words: c.words, year: c.year})
+			if c.explain {
+				fmt.Fprintln(out, "  "+c.explanation(arg, numeral))
+			}
+		}
+	}
+	return status
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"Con_dev_Test_1/roman"
+)
+
+// TestConvertArgs converts command-line arguments, checking the output and exit status with and without -q.
+func TestConvertArgs(t *testing.T) {
+	tests := []struct {
+		c         converter
+		args      []string
+		out, errs string
+		status    int
+	}{
+		{converter{}, []string{"XIV", "MCMXCIV"}, "XIV = 14\nMCMXCIV = 1994\n", "", exitOK},
+		{converter{quiet: true}, []string{"XIV", "MCMXCIV"}, "14\n1994\n", "", exitOK},
+		{converter{toRoman: true, quiet: true}, []string{"14"}, "XIV\n", "", exitOK},
+		{converter{opts: roman.Lenient, quiet: true}, []string{"iiii"}, "4\n", "", exitOK},
+		{converter{}, []string{"IIII", "XIV"}, "XIV = 14\n", "Error: roman: invalid Roman numeral combination at position 4 in \"IIII\"\n", exitInvalid},
+		{converter{toRoman: true, quiet: true}, []string{"4000", "ten"}, "", "", exitInvalid},
+	}
+	for _, test := range tests {
+		var out, errs strings.Builder
+		status := convertArgs(test.c, test.args, &out, &errs)
+		if status != test.status || out.String() != test.out || errs.String() != test.errs {
+			t.Errorf("convertArgs(%q) = %d writing %q and %q, want %d writing %q and %q",
+				test.args, status, out.String(), errs.String(), test.status, test.out, test.errs)
+		}
+	}
+}
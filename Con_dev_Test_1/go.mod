@@ -1,3 +1,7 @@
 module Con_dev_Test_1
 
 go 1.23.1
+
+require Barrier v0.0.0
+
+replace Barrier => ../Barrier
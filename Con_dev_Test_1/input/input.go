@@ -0,0 +1,120 @@
+// Ronan Green
+// C00270395
+
+// Package input checks text typed by a user, such as a line read at a prompt or a flag value, before it is used.
+//
+// A Validator checks one rule, and validators compose with All, so each tool can state what it accepts:
+//
+//	numeral := input.All(input.NonEmpty(), input.MaxLength(15), input.Charset("Roman numeral characters", "IVXLCDM"))
+//	if err := numeral("MCMXCIV"); err != nil { ... }
+package input
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrEmpty is returned by NonEmpty for empty or blank input.
+var ErrEmpty = errors.New("input: nothing was entered")
+
+// Error describes why input was rejected, and where, if one character is to blame.
+type Error struct {
+	Input  string // The input being checked.
+	Index  int    // Byte offset of the character at fault, or -1 if the input as a whole is.
+	Reason string // What is wrong, such as "only use the characters I, V, X, L, C, D, M".
+}
+
+func (e *Error) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("input: %s: %s", quote(e.Input), e.Reason)
+	}
+	c, _ := utf8.DecodeRuneInString(e.Input[e.Index:])
+	return fmt.Sprintf("input: invalid character %q at position %d in %s; %s", c, e.Index+1, quote(e.Input), e.Reason)
+}
+
+// maxQuoted is the most characters of the input an Error repeats.
+const maxQuoted = 40
+
+// quote quotes s for an error message, shortened to its first maxQuoted characters and "..." if it is longer.
+func quote(s string) string {
+	if utf8.RuneCountInString(s) <= maxQuoted {
+		return strconv.Quote(s)
+	}
+	return strconv.Quote(string([]rune(s)[:maxQuoted])) + "..."
+}
+
+// Validator checks input, returning nil if it is acceptable and otherwise an error saying why not, usually an *Error.
+type Validator func(s string) error
+
+// All returns a validator that checks each of validators in turn and returns the first error.
+func All(validators ...Validator) Validator {
+	return func(s string) error {
+		for _, v := range validators {
+			if err := v(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// NonEmpty rejects input that is empty or only space, with ErrEmpty.
+func NonEmpty() Validator {
+	return func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return ErrEmpty
+		}
+		return nil
+	}
+}
+
+// MaxLength rejects input of more than n characters.
+func MaxLength(n int) Validator {
+	return func(s string) error {
+		if utf8.RuneCountInString(s) > n {
+			return &Error{Input: s, Index: -1, Reason: fmt.Sprintf("cannot be more than %d characters", n)}
+		}
+		return nil
+	}
+}
+
+// Charset rejects input with a character not in chars, naming the first one and describing the characters allowed
+// as name, such as "Roman numeral characters".
+func Charset(name, chars string) Validator {
+	return func(s string) error {
+		if i := strings.IndexFunc(s, func(c rune) bool { return !strings.ContainsRune(chars, c) }); i >= 0 {
+			return &Error{Input: s, Index: i, Reason: fmt.Sprintf("only use the %s %s", name, list(chars))}
+		}
+		return nil
+	}
+}
+
+// Pattern rejects input that does not match re, describing what it should be as name, such as "a whole number".
+func Pattern(name string, re *regexp.Regexp) Validator {
+	return func(s string) error {
+		if !re.MatchString(s) {
+			return &Error{Input: s, Index: -1, Reason: "must be " + name}
+		}
+		return nil
+	}
+}
+
+// OneOf rejects input other than one of values, such as a flag that takes a fixed set of names.
+func OneOf(values ...string) Validator {
+	return func(s string) error {
+		if !slices.Contains(values, s) {
+			return &Error{Input: s, Index: -1, Reason: "must be one of " + strings.Join(values, ", ")}
+		}
+		return nil
+	}
+}
+
+// list writes chars separated by commas, such as "I, V, X".
+func list(chars string) string {
+	return strings.Join(strings.Split(chars, ""), ", ")
+}
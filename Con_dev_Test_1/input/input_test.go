@@ -0,0 +1,55 @@
+package input
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestValidators checks each validator accepts and rejects what it should, with the message it gives.
+func TestValidators(t *testing.T) {
+	numeral := All(NonEmpty(), MaxLength(15), Charset("Roman numeral characters", "IVXLCDM"))
+	number := Pattern("a whole number", regexp.MustCompile(`^[0-9]+$`))
+	tests := []struct {
+		v     Validator
+		input string
+		want  string // The error message, or "" for none.
+	}{
+		{numeral, "MCMXCIV", ""},
+		{numeral, "", "input: nothing was entered"},
+		{numeral, " \t", "input: nothing was entered"},
+		{numeral, "MMMDCCCLXXXVIIII", `input: "MMMDCCCLXXXVIIII": cannot be more than 15 characters`},
+		{numeral, "XIB", `input: invalid character 'B' at position 3 in "XIB"; only use the Roman numeral characters I, V, X, L, C, D, M`},
+		{numeral, "Ⅻ", `input: invalid character 'Ⅻ' at position 1 in "Ⅻ"; only use the Roman numeral characters I, V, X, L, C, D, M`},
+		{MaxLength(3), "ⅫⅫⅫ", ""},
+		{number, "1994", ""},
+		{number, "19a4", `input: "19a4": must be a whole number`},
+		{OneOf("int", "roman"), "roman", ""},
+		{OneOf("int", "roman"), "Roman", `input: "Roman": must be one of int, roman`},
+		{MaxLength(50), strings.Repeat("x", 51), `input: "` + strings.Repeat("x", 40) + `"...: cannot be more than 50 characters`},
+	}
+	for _, test := range tests {
+		got := ""
+		if err := test.v(test.input); err != nil {
+			got = err.Error()
+		}
+		if got != test.want {
+			t.Errorf("validating %q returned %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+// TestErrors checks the errors can be told apart, and that All stops at the first.
+func TestErrors(t *testing.T) {
+	if err := All(NonEmpty(), MaxLength(1))(""); !errors.Is(err, ErrEmpty) {
+		t.Errorf("empty input returned %v, want ErrEmpty", err)
+	}
+	var inputErr *Error
+	if err := All(Charset("digits", "0123456789"), MaxLength(1))("1x"); !errors.As(err, &inputErr) || inputErr.Index != 1 {
+		t.Errorf("1x returned %v, want an Error at the x", err)
+	}
+	if err := All()("anything"); err != nil {
+		t.Errorf("All with no validators returned %v", err)
+	}
+}
@@ -5,99 +5,142 @@ package main
 
 import (
 	"errors"
+	"flag"
 	"fmt"
-	"regexp"
-)
+	"io"
+	"os"
 
-func main() {
+	"Con_dev_Test_1/input"
+	"Con_dev_Test_1/roman"
+)
 
-	var romanNumeral string //string of roman numerals input by user
+// The exit statuses, so scripts and Makefiles can tell a numeral that could not be converted from a command used
+// wrongly.
+const (
+	exitOK      = 0 // Every conversion succeeded.
+	exitInvalid = 1 // A numeral or number could not be converted, or the input or output failed.
+	exitUsage   = 2 // The flags were invalid, as the flag package also reports.
+)
 
-	convertion := map[string]int{
-		"I":  1,
-		"V":  5,
-		"X":  10,
-		"L":  50,
-		"C":  100,
-		"D":  500,
-		"M":  1000,
-		"IV": 4,
-		"IX": 9,
-		"XL": 40,
-		"XC": 90,
-		"CD": 400,
-		"CM": 900,
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "quiz" {
+		os.Exit(quizMain(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
 	}
-
-	fmt.Println("Enter Roman Numerials")
-	fmt.Scanln(&romanNumeral)
-
-	if len(romanNumeral) > 15 {
-		fmt.Println("Error: Input cannot be more than 15 characters.")
-		return
+	cmd, status, ok := parseFlags(os.Args[1:], os.Stderr)
+	if !ok {
+		os.Exit(status)
 	}
+	c := cmd.c
 
-	// Define allowed characters (Roman numerals: I, V, X, L, C, D, M),
-	// Return only boolean 'matched' then check if parameters were met
-	validInputPattern := "^[IVXLCDM]+$"
-	matched, _ := regexp.MatchString(validInputPattern, romanNumeral)
-
-	if !matched {
-		fmt.Println("Incorrect Input, please follow the following parameters:\nOnly use the following Roman numeral characters: I, V, X, L, C, D, M")
-		return
+	var err error
+	switch {
+	case cmd.addr != "":
+		err = serve(c, cmd.addr)
+	case len(cmd.args) > 0:
+		os.Exit(convertArgs(c, cmd.args, os.Stdout, os.Stderr))
+	case cmd.batch || cmd.file != "" || c.quiet:
+		err = runBatch(c, cmd.file, cmd.out, cmd.format)
+	default:
+		_, err = runREPL(c, os.Stdin, os.Stdout)
 	}
-
-	result, err := calculation(romanNumeral, convertion)
 	if err != nil {
-		fmt.Println("Error:", err)
-	} else {
-		fmt.Println(romanNumeral, "=", result)
+		if !c.quiet || !errors.Is(err, errInvalid) {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		os.Exit(exitInvalid)
 	}
 }
 
-func calculation(romanNumeral string, convertion map[string]int) (int, error) {
-	var total int //number of roman numerals entered
-	length := len(romanNumeral)
-	var count int = 1
-
-	//initial check of 2 characters to see if they match any entries in the map
-	for i := 0; i < length; i++ {
-		if i+1 < length {
-			chars := romanNumeral[i : i+2]
-			// value is assigned the same as chars and exists is a boolean for whether value exists in the map
-			// This is used to check for instances where the first didget subtracts from the second
-			if value, exists := convertion[chars]; exists {
-				total += value
-				i++
-				continue
-			}
+// command is what the flags and arguments ask the converter to do.
+type command struct {
+	c      converter // How to convert each numeral or number.
+	args   []string  // The numerals or numbers to convert, if any were given.
+	addr   string    // The address to serve conversions on, with -serve.
+	batch  bool      // Whether to convert standard input, as -batch asks.
+	file   string    // The file to convert every line of, with -file.
+	out    string    // The file to write the batch results to, with -out.
+	format string    // The format of the batch results.
+}
 
+// parseFlags parses the converter's flags and arguments from args.
+//
+// Input:
+//   - args ([]string): The command line, without the program name.
+//   - errOut (io.Writer): Where usage errors and the usage message are written.
+//
+// Output:
+//   - command: What to do, if ok.
+//   - int: The status to exit with if not ok: exitOK after -h, or exitUsage if a flag is unknown or its value invalid.
+//   - bool: Whether the command should be run.
+func parseFlags(args []string, errOut io.Writer) (command, int, bool) {
+	flags := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	to := flags.String("to", "int", "direction to convert: int reads a Roman numeral, roman reads a number from 1 to 3999")
+	lenient := flags.Bool("lenient", false, "accept lower case, surrounding spaces and non-canonical numerals such as IIII, and show their canonical form")
+	vinculum := flags.Bool("vinculum", false, "read and write numbers above 3999 with barred thousands, such as V̄ (or _V) for 5000")
+	underscores := flags.Bool("underscores", false, "with -vinculum, write the bars as underscores before the symbols, such as _V")
+	style := flags.String("style", "standard", "how numerals are written: standard, additive (IIII, as on clocks) or apostrophus (CIↃ for M)")
+	unicodeForms := flags.Bool("unicode", false, "write numerals with the Unicode Roman numeral code points, such as Ⅻ; they are always read")
+	words := flags.Bool("words", false, "show each value with thousands separators and in words, such as 1,984, one thousand nine hundred eighty-four")
+	year := flags.Bool("year", false, "read the numerals as years, such as the dates on buildings and films, and show their century")
+	explain := flags.Bool("explain", false, "show how each numeral adds up, such as CM=900 (1000-100), XC=90 (100-10), IV=4 (5-1) → 994")
+	max := flags.Int("max", 0, "the largest value to convert, at least 1, if below the largest that can be written")
+	maxLength := flags.Int("maxlength", 0, "the most symbols a numeral may have; by default as many as the longest numeral up to -max, or any number with -lenient")
+	batch := flags.Bool("batch", false, "convert every line of standard input and write the results in -format, instead of prompting for each")
+	file := flags.String("file", "", "convert every line of this file and write the results in -format (implies -batch)")
+	workers := flags.Int("workers", 0, "how many lines to convert at once with -batch; 0 for as many as there are CPUs")
+	steal := flags.Bool("work-stealing", false, "with -batch, convert the lines on a work-stealing pool, whose workers take from one another's queues, rather than sharing one")
+	out := flags.String("out", "", "write the batch results to this file instead of standard output")
+	format := flags.String("format", "csv", "format of the batch results: csv, json (one object per line) or plain")
+	addr := flags.String("serve", "", "serve conversions over HTTP on this address, such as :8080, instead of prompting")
+	quiet := flags.Bool("q", false, "print only the result of each conversion, with no prompts, summaries or errors; without arguments, convert standard input as with -batch")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s [flags] [numeral or number ...]\n", os.Args[0])
+		fmt.Fprintf(flags.Output(), "   or: %s quiz [-n questions] [-max value] [-to mixed|roman|int] [-profile file] [-seed n]\n", os.Args[0])
+		fmt.Fprintln(flags.Output(), "Converts each argument, or prompts for them if there are none. quiz asks random conversions and scores the answers.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return command{}, exitOK, false
 		}
+		return command{}, exitUsage, false // The flag package has reported the error and the usage.
+	}
+	// usage reports a usage error, such as an unknown flag value, for parseFlags to return.
+	usage := func(format string, args ...any) (command, int, bool) {
+		fmt.Fprintf(errOut, format+"\n", args...)
+		flags.Usage()
+		return command{}, exitUsage, false
+	}
 
-		char := string(romanNumeral[i])
-		// Add single characters with a check to make sure the character isn't higher than the next.
-		//This also checks for combinations like VV or DD or a letter shwoing up more than 4 times like IIII
-		if value, exists := convertion[char]; exists {
-			if i+1 < length && convertion[char] < convertion[string(romanNumeral[i+1])] {
-				return -1, errors.New("invalid Roman numeral combination")
-			}else if i+1 < length && char == "V" && string(romanNumeral[i + 1]) == "V"{
-				return -1, errors.New("invalid Roman numeral combination")
-			}else if i+1 < length && char == "L" && string(romanNumeral[i + 1]) == "L"{
-				return -1, errors.New("invalid Roman numeral combination")
-			}else if i+1 < length && char == "D" && string(romanNumeral[i+ 1]) == "D"{
-				return -1, errors.New("invalid Roman numeral combination")
-			}else if i+1 < length && char == string(romanNumeral[i + 1]){
-				count += 1
-				if count > 3{
-					return -1, errors.New("invalid Roman numeral combination")
-				}
-			}else if i+1 < length && char != string(romanNumeral[i + 1]){
-				count = 1
-			}
-			
-			total += value
-			
-		}
+	c := converter{toRoman: *to == "roman", words: *words, year: *year, explain: *explain, quiet: *quiet, workers: *workers, steal: *steal}
+	if err := input.OneOf("int", "roman")(*to); err != nil {
+		return usage("-to: %v", err)
+	}
+	if err := input.OneOf(batchFormats...)(*format); err != nil {
+		return usage("-format: %v", err)
+	}
+	given := false // Left at 0, -max is the largest that can be written; given, it must be a value that can be.
+	flags.Visit(func(f *flag.Flag) { given = given || f.Name == "max" })
+	if given && *max < 1 {
+		return usage("-max: the largest value must be at least 1, not %d", *max)
+	}
+	if *maxLength < 0 {
+		return usage("-maxlength: the most symbols a numeral may have must not be negative, not %d", *maxLength)
+	}
+	if *workers < 0 {
+		return usage("-workers: the number of workers must not be negative, not %d", *workers)
+	}
+	if *lenient {
+		c.opts = roman.Lenient
+	}
+	c.opts.Vinculum, c.opts.MaxValue, c.opts.Unicode = *vinculum, *max, true // Numerals copied from documents often use the Unicode forms.
+	c.opts.MaxLength = *maxLength
+	c.format = roman.FormatOptions{Vinculum: *vinculum, Underscores: *underscores, Unicode: *unicodeForms, MaxValue: *max}
+	var err error
+	if c.opts.Style, err = roman.ParseStyle(*style); err != nil {
+		return usage("%v", err)
 	}
-	return total, nil
+	c.format.Style = c.opts.Style
+	return command{c: c, args: flags.Args(), addr: *addr, batch: *batch, file: *file, out: *out, format: *format}, exitOK, true
 }
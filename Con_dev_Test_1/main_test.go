@@ -0,0 +1,30 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestParseFlags checks that the flags are parsed into a command, and that invalid values are usage errors.
+func TestParseFlags(t *testing.T) {
+	cmd, status, ok := parseFlags([]string{"-max", "10", "-to", "roman", "9"}, &strings.Builder{})
+	if !ok || status != exitOK || !cmd.c.toRoman || cmd.c.opts.MaxValue != 10 || !slices.Equal(cmd.args, []string{"9"}) {
+		t.Errorf("parseFlags returned %+v, %d, %t", cmd, status, ok)
+	}
+	if cmd, _, ok := parseFlags([]string{"-batch", "-workers", "0"}, &strings.Builder{}); !ok || !cmd.batch || cmd.c.workers != 0 {
+		t.Errorf("-workers 0, for as many as there are CPUs, returned %+v, %t", cmd, ok)
+	}
+	if cmd, _, ok := parseFlags([]string{"-maxlength", "0", "X"}, &strings.Builder{}); !ok || cmd.c.opts.MaxLength != 0 {
+		t.Errorf("-maxlength 0, for the default, returned %+v, %t", cmd, ok)
+	}
+	if _, status, ok := parseFlags([]string{"-h"}, &strings.Builder{}); ok || status != exitOK {
+		t.Errorf("-h returned %d, %t, want %d and not to run", status, ok, exitOK)
+	}
+	for _, bad := range [][]string{{"-max", "-5", "X"}, {"-max", "0", "X"}, {"-maxlength", "-1", "X"}, {"-lenient", "-maxlength", "-3", "X"}, {"-workers", "-1", "-batch"}, {"-to", "latin"}, {"-format", "xml"}, {"-style", "gothic"}, {"-bogus"}} {
+		var errs strings.Builder
+		if _, status, ok := parseFlags(bad, &errs); ok || status != exitUsage || !strings.Contains(errs.String(), "Usage:") {
+			t.Errorf("%q returned %d, %t and printed %q, want %d and the usage", bad, status, ok, errs.String(), exitUsage)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Ronan Green
+// C00270395
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"Con_dev_Test_1/input"
+	"Con_dev_Test_1/roman"
+)
+
+// quiz asks for random values to be converted one way or the other and scores the answers.
+type quiz struct {
+	rounds    int              // How many questions to ask.
+	max       int              // The largest value asked about.
+	direction string           // "roman" to ask for numerals, "int" to ask for values, or "mixed" for either.
+	rng       *rand.Rand       // Chooses the values and directions.
+	now       func() time.Time // The clock the answers are timed with.
+}
+
+// quizDirections names the directions a quiz can ask in.
+var quizDirections = []string{"mixed", "roman", "int"}
+
+// answerOptions is how forgiving the quiz is with numerals typed as answers: the case and surrounding space do not
+// matter, but the numeral must be canonical.
+var answerOptions = roman.ParseOptions{IgnoreCase: true, TrimSpace: true, Unicode: true}
+
+// quizScore is the outcome of one quiz, as kept in the profile.
+type quizScore struct {
+	Date       time.Time `json:"date"`
+	Questions  int       `json:"questions"`   // How many questions were answered.
+	Correct    int       `json:"correct"`     // How many answers were right.
+	BestStreak int       `json:"best_streak"` // The most right answers in a row.
+	Seconds    float64   `json:"seconds"`     // The time taken answering, in total.
+}
+
+// String summarises the score, such as "8 of 10 correct, best streak 5, 3.2s per answer".
+func (s quizScore) String() string {
+	if s.Questions == 0 {
+		return "No questions answered"
+	}
+	return fmt.Sprintf("%d of %d correct, best streak %d, %.1fs per answer", s.Correct, s.Questions, s.BestStreak, s.Seconds/float64(s.Questions))
+}
+
+// run asks the questions, reading the answers from in and writing the questions and feedback to out.
+//
+// Input:
+//   - in (io.Reader): The answers, one per line. "quit" or the end of input stops the quiz early.
+//   - out (io.Writer): Where the questions, feedback and final score are written.
+//
+// Output:
+//   - quizScore: The score for the questions answered, dated when the quiz started.
+//   - error: Returns an error if in cannot be read.
+//
+// Functionality:
+//  1. Draws each value from 1 to q.max, and asks for its numeral or, given its numeral, for the value, as q.direction
+//     says, choosing at random for mixed.
+//  2. Times each answer and marks it, keeping the current streak of right answers and the best one. Numerals must be
+//     canonical, but may be in lower case.
+//  3. Prints the score once the questions run out or the user quits.
+func (q quiz) run(in io.Reader, out io.Writer) (quizScore, error) {
+	score := quizScore{Date: q.now()}
+	fmt.Fprintf(out, "Answer %d questions, or quit to stop early\n", q.rounds)
+	scanner := bufio.NewScanner(in)
+	streak := 0
+	for i := 1; i <= q.rounds; i++ {
+		n := q.rng.IntN(q.max) + 1
+		numeral, err := roman.FromInt(n)
+		if err != nil {
+			return score, err // Unreachable, since q.max is at most 3999.
+		}
+		toRoman := q.direction == "roman" || q.direction == "mixed" && q.rng.IntN(2) == 0
+		if toRoman {
+			fmt.Fprintf(out, "%d. Write %d as a Roman numeral: ", i, n)
+		} else {
+			fmt.Fprintf(out, "%d. What is %s? ", i, numeral)
+		}
+
+		start := q.now()
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			break
+		}
+		answer := strings.TrimSpace(scanner.Text())
+		if strings.EqualFold(answer, "quit") {
+			break
+		}
+		elapsed := q.now().Sub(start)
+		score.Questions++
+		score.Seconds += elapsed.Seconds()
+
+		var right bool
+		if toRoman {
+			value, _, err := roman.Parse(answer, answerOptions)
+			right = err == nil && value == n
+		} else {
+			value, err := strconv.Atoi(answer)
+			right = err == nil && value == n
+		}
+		if !right {
+			streak = 0
+			fmt.Fprintf(out, "Wrong: %d is %s\n", n, numeral)
+			continue
+		}
+		score.Correct++
+		streak++
+		score.BestStreak = max(score.BestStreak, streak)
+		fmt.Fprintf(out, "Correct! Streak %d, in %.1fs\n", streak, elapsed.Seconds())
+	}
+	fmt.Fprintln(out, score)
+	return score, scanner.Err()
+}
+
+// profile is the record of every quiz taken, kept in a JSON file between quizzes.
+type profile struct {
+	Games []quizScore `json:"games"`
+}
+
+// String summarises every quiz in the profile, such as "All time: 38 of 50 correct over 5 quizzes, best streak 9".
+func (p profile) String() string {
+	var total quizScore
+	for _, g := range p.Games {
+		total.Questions += g.Questions
+		total.Correct += g.Correct
+		total.BestStreak = max(total.BestStreak, g.BestStreak)
+	}
+	return fmt.Sprintf("All time: %d of %d correct over %d quizzes, best streak %d", total.Correct, total.Questions, len(p.Games), total.BestStreak)
+}
+
+// loadProfile reads the profile in filename, or returns an empty one if the file does not exist yet.
+func loadProfile(filename string) (profile, error) {
+	var p profile
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return p, err
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return p, fmt.Errorf("reading the quiz profile %s: %w", filename, err)
+	}
+	return p, nil
+}
+
+// save writes the profile to filename.
+func (p profile) save(filename string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0o644)
+}
+
+// defaultProfile is where the profile is kept unless -profile says otherwise: in the home directory, or the current
+// directory if there is none.
+func defaultProfile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".roman-quiz.json"
+	}
+	return filepath.Join(home, ".roman-quiz.json")
+}
+
+// quizMain runs the quiz subcommand with args, the arguments after "quiz", and returns the exit status.
+func quizMain(args []string, in io.Reader, out, errOut io.Writer) int {
+	flags := flag.NewFlagSet("quiz", flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	rounds := flags.Int("n", 10, "how many questions to ask")
+	maxValue := flags.Int("max", roman.MaxValue, "the largest value to ask about, up to 3999")
+	direction := flags.String("to", "mixed", "what to ask for: roman for numerals, int for values, or mixed for either")
+	profileFile := flags.String("profile", defaultProfile(), "the JSON file the scores are kept in")
+	seed := flags.Uint64("seed", 0, "the seed for the questions, to ask the same ones again; 0 for new ones")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	switch {
+	case *rounds < 1:
+		fmt.Fprintf(errOut, "the number of questions must be at least 1, not %d\n", *rounds)
+		return exitUsage
+	case *maxValue < roman.MinValue || *maxValue > roman.MaxValue:
+		fmt.Fprintf(errOut, "the largest value must be from %d to %d, not %d\n", roman.MinValue, roman.MaxValue, *maxValue)
+		return exitUsage
+	}
+	if err := input.OneOf(quizDirections...)(*direction); err != nil {
+		fmt.Fprintln(errOut, "-to:", err)
+		return exitUsage
+	}
+
+	p, err := loadProfile(*profileFile)
+	if err != nil {
+		fmt.Fprintln(errOut, "Error:", err)
+		return exitInvalid
+	}
+	s1, s2 := *seed, *seed
+	if *seed == 0 {
+		s1, s2 = rand.Uint64(), rand.Uint64()
+	}
+	q := quiz{rounds: *rounds, max: *maxValue, direction: *direction, rng: rand.New(rand.NewPCG(s1, s2)), now: time.Now}
+	score, err := q.run(in, out)
+	if err != nil {
+		fmt.Fprintln(errOut, "Error:", err)
+		return exitInvalid
+	}
+	if score.Questions > 0 {
+		p.Games = append(p.Games, score)
+		if err := p.save(*profileFile); err != nil {
+			fmt.Fprintln(errOut, "Error:", err)
+			return exitInvalid
+		}
+	}
+	fmt.Fprintln(out, p)
+	return exitOK
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tick returns a clock that moves on two seconds each time it is read.
+func tick() func() time.Time {
+	t := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		t = t.Add(2 * time.Second)
+		return t
+	}
+}
+
+// TestQuiz answers a quiz whose only value is 1, checking the marking, the streaks and the timing.
+func TestQuiz(t *testing.T) {
+	q := quiz{rounds: 5, max: 1, direction: "roman", rng: rand.New(rand.NewPCG(1, 2)), now: tick()}
+	var out strings.Builder
+	score, err := q.run(strings.NewReader("I\n i \nIV\nⅠ\n"), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `Answer 5 questions, or quit to stop early
+1. Write 1 as a Roman numeral: Correct! Streak 1, in 2.0s
+2. Write 1 as a Roman numeral: Correct! Streak 2, in 2.0s
+3. Write 1 as a Roman numeral: Wrong: 1 is I
+4. Write 1 as a Roman numeral: Correct! Streak 1, in 2.0s
+5. Write 1 as a Roman numeral: 
+3 of 4 correct, best streak 2, 2.0s per answer
+`
+	if out.String() != want {
+		t.Errorf("the quiz printed\n%s\nwant\n%s", out.String(), want)
+	}
+	if score.Questions != 4 || score.Correct != 3 || score.BestStreak != 2 || score.Seconds != 8 {
+		t.Errorf("the score is %+v, want 3 of 4 correct, a best streak of 2 and 8 seconds", score)
+	}
+}
+
+// TestQuizToInt asks for values, checking quit stops the quiz without counting as an answer.
+func TestQuizToInt(t *testing.T) {
+	q := quiz{rounds: 3, max: 1, direction: "int", rng: rand.New(rand.NewPCG(1, 2)), now: tick()}
+	var out strings.Builder
+	score, err := q.run(strings.NewReader("1\nQUIT\n1\n"), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Answer 3 questions, or quit to stop early\n1. What is I? Correct! Streak 1, in 2.0s\n2. What is I? 1 of 1 correct, best streak 1, 2.0s per answer\n"
+	if out.String() != want || score.Questions != 1 {
+		t.Errorf("the quiz printed %q with %+v, want %q with one question", out.String(), score, want)
+	}
+}
+
+// TestQuizProfile runs the quiz subcommand twice, checking the profile keeps both scores.
+func TestQuizProfile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "profile.json")
+	args := []string{"-n", "2", "-max", "1", "-to", "int", "-profile", file}
+	for i, in := range []string{"1\n1\n", "1\n2\n"} {
+		var out, errs strings.Builder
+		if status := quizMain(args, strings.NewReader(in), &out, &errs); status != exitOK {
+			t.Fatalf("quiz %d exited with %d: %s", i+1, status, errs.String())
+		}
+	}
+	p, err := loadProfile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "All time: 3 of 4 correct over 2 quizzes, best streak 2"; p.String() != want {
+		t.Errorf("the profile is %q, want %q", p, want)
+	}
+
+	if err := os.WriteFile(file, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if status := quizMain(args, strings.NewReader("1\n"), &strings.Builder{}, &strings.Builder{}); status != exitInvalid {
+		t.Errorf("a quiz with a corrupt profile exited with %d, want %d", status, exitInvalid)
+	}
+	for _, bad := range [][]string{{"-to", "latin"}, {"-max", "4000"}, {"-n", "0"}, {"-bogus"}} {
+		if status := quizMain(bad, strings.NewReader(""), &strings.Builder{}, &strings.Builder{}); status != exitUsage {
+			t.Errorf("quiz %q exited with %d, want %d", bad, status, exitUsage)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Ronan Green
+// C00270395
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"Con_dev_Test_1/roman"
+)
+
+// conversion is one successful conversion kept in the REPL's history.
+type conversion struct {
+	input   string // What was typed.
+	value   int    // Its value.
+	numeral string // Its canonical numeral.
+	words   bool   // Whether to write the value with thousands separators and in words, as -words asks.
+	year    bool   // Whether to name the century of the value as a year, as -year asks.
+}
+
+// String formats the conversion as the REPL prints it, such as "XIV = 14", "iiii = 4 (IV)", with words
+// "MCMLXXXIV = 1,984, one thousand nine hundred eighty-four" or as a year "MCMLXXXIV = 1984, in the twentieth century".
+func (c conversion) String() string {
+	value := strconv.Itoa(c.value)
+	if c.words {
+		value = roman.Grouped(c.value)
+	}
+	var s string
+	switch {
+	case c.input == c.numeral:
+		s = fmt.Sprintf("%s = %s", c.input, value)
+	case strings.TrimSpace(c.input) == strconv.Itoa(c.value):
+		s = fmt.Sprintf("%s = %s", value, c.numeral)
+	default:
+		s = fmt.Sprintf("%s = %s (%s)", c.input, value, c.numeral)
+	}
+	if c.words {
+		s += ", " + roman.Words(c.value)
+	}
+	if century, err := roman.CenturyName(c.value); c.year && err == nil {
+		s += ", in the " + century
+	}
+	return s
+}
+
+// replHelp lists the REPL's commands.
+const replHelp = `Commands:
+  history   show every conversion so far
+  undo      remove the last conversion from the history
+  help      show this list
+  quit      exit (so does end of input)`
+
+// runREPL converts one line at a time from in until "quit" or the end of input, writing prompts and results to out.
+//
+// Input:
+//   - c (converter): Converts each line that is not a command.
+//   - in (io.Reader): The user's input, one numeral, number or command per line.
+//   - out (io.Writer): Where the prompts, results and errors are written.
+//
+// Output:
+//   - []conversion: The history when the REPL ended, oldest first.
+//   - error: Returns an error if in cannot be read.
+//
+// Functionality:
+//  1. Prompts for a line and treats history, undo, help and quit as commands, in any case.
+//  2. Converts any other non-blank line, printing the result and adding it to the history, or printing the error.
+//     Failed conversions are not kept.
+func runREPL(c converter, in io.Reader, out io.Writer) ([]conversion, error) {
+	switch {
+	case c.toRoman && c.year:
+		fmt.Fprintf(out, "Enter years from 1 to %d, or help for the commands\n", c.format.Max())
+	case c.toRoman:
+		fmt.Fprintf(out, "Enter numbers from 1 to %d, or help for the commands\n", c.format.Max())
+	case c.year:
+		fmt.Fprintln(out, "Enter years in Roman Numerials, or help for the commands")
+	default:
+		fmt.Fprintln(out, "Enter Roman Numerials, or help for the commands")
+	}
+
+	var history []conversion
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return history, scanner.Err()
+		}
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "":
+		case "quit", "exit":
+			return history, nil
+		case "help":
+			fmt.Fprintln(out, replHelp)
+		case "history":
+			if len(history) == 0 {
+				fmt.Fprintln(out, "No conversions yet")
+			}
+			for i, h := range history {
+				fmt.Fprintf(out, "%d. %s\n", i+1, h)
+			}
+		case "undo":
+			if len(history) == 0 {
+				fmt.Fprintln(out, "Nothing to undo")
+				break
+			}
+			fmt.Fprintln(out, "Removed", history[len(history)-1])
+			history = history[:len(history)-1]
+		default:
+			value, numeral, err := c.convert(line)
+			if err != nil {
+				fmt.Fprintln(out, "Error:", err)
+				break
+			}
+			h := conversion{input: line, value: value, numeral: numeral, words: c.words, year: c.year}
+			history = append(history, h)
+			fmt.Fprintln(out, h)
+			if c.explain {
+				fmt.Fprintln(out, "  "+c.explanation(line, numeral))
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"Con_dev_Test_1/roman"
+)
+
+// TestREPL runs a session of conversions and commands, checking the output and the history left at the end.
+func TestREPL(t *testing.T) {
+	in := "XIV\nundo\nhistory\niiii\n\nbad\nMCMXCIV\nHISTORY\nquit\nX\n"
+	var out strings.Builder
+	history, err := runREPL(converter{opts: roman.Lenient}, strings.NewReader(in), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `Enter Roman Numerials, or help for the commands
+> XIV = 14
+> Removed XIV = 14
+> No conversions yet
+> iiii = 4 (IV)
+> > Error: roman: invalid character 'b' at position 1 in "bad"; only use the Roman numeral characters I, V, X, L, C, D, M
+> MCMXCIV = 1994
+> 1. iiii = 4 (IV)
+2. MCMXCIV = 1994
+> `
+	if out.String() != want {
+		t.Errorf("the session printed\n%s\nwant\n%s", out.String(), want)
+	}
+	if len(history) != 2 || history[1].value != 1994 {
+		t.Errorf("the history is %v, want iiii and MCMXCIV; the line after quit must not be read", history)
+	}
+}
+
+// TestREPLToRoman checks the numbers are printed first when converting to numerals, and that the end of input quits.
+func TestREPLToRoman(t *testing.T) {
+	var out strings.Builder
+	history, err := runREPL(converter{toRoman: true}, strings.NewReader("1994\nundo\nundo"), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Enter numbers from 1 to 3999, or help for the commands\n> 1994 = MCMXCIV\n> Removed 1994 = MCMXCIV\n> Nothing to undo\n> \n"
+	if out.String() != want || len(history) != 0 {
+		t.Errorf("the session printed %q with history %v, want %q with none", out.String(), history, want)
+	}
+}
+
+// TestREPLWords checks -words adds the grouped value and its words, in both directions.
+func TestREPLWords(t *testing.T) {
+	var out strings.Builder
+	c := converter{opts: roman.ParseOptions{Vinculum: true}, format: roman.FormatOptions{Vinculum: true, Underscores: true}, words: true}
+	if _, err := runREPL(c, strings.NewReader("MCMLXXXIV\n_X_I_ICCCXLV\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+	c.toRoman = true
+	if _, err := runREPL(c, strings.NewReader("12345\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"> MCMLXXXIV = 1,984, one thousand nine hundred eighty-four\n",
+		"> _X_I_ICCCXLV = 12,345, twelve thousand three hundred forty-five\n",
+		"> 12,345 = _X_I_ICCCXLV, twelve thousand three hundred forty-five\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("the session printed\n%s\nwithout %q", out.String(), want)
+		}
+	}
+}
+
+// TestREPLYear checks -year names the century of each value in both directions.
+func TestREPLYear(t *testing.T) {
+	var out strings.Builder
+	if _, err := runREPL(converter{year: true}, strings.NewReader("MCMLXXXIV\nMM\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runREPL(converter{toRoman: true, year: true}, strings.NewReader("2001\n0\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"> MCMLXXXIV = 1984, in the twentieth century\n",
+		"> MM = 2000, in the twentieth century\n",
+		"> 2001 = MMI, in the twenty-first century\n",
+		"> Error: roman: 0 cannot be written as a Roman numeral; only 1 to 3999 can\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("the session printed\n%s\nwithout %q", out.String(), want)
+		}
+	}
+}
+
+// TestREPLExplain checks -explain breaks down the numeral read or written.
+func TestREPLExplain(t *testing.T) {
+	var out strings.Builder
+	if _, err := runREPL(converter{opts: roman.Lenient, explain: true}, strings.NewReader("cmxciv\niiii\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runREPL(converter{toRoman: true, explain: true}, strings.NewReader("1994\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"> cmxciv = 994 (CMXCIV)\n  CM=900 (1000-100), XC=90 (100-10), IV=4 (5-1) → 994\n",
+		"> iiii = 4 (IV)\n  I=1, I=1, I=1, I=1 → 4\n",
+		"> 1994 = MCMXCIV\n  M=1000, CM=900 (1000-100), XC=90 (100-10), IV=4 (5-1) → 1994\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("the session printed\n%s\nwithout %q", out.String(), want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package roman
+
+import "testing"
+
+// benchNumerals is a mix of short and long canonical numerals.
+var benchNumerals = []string{"I", "IV", "XLII", "MCMXCIV", "MMXXIV", "MMMDCCCLXXXVIII", "CDXLIV", "MMMCMXCIX"}
+
+func BenchmarkToInt(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ToInt(benchNumerals[i%len(benchNumerals)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFromInt(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := FromInt(MinValue + i%MaxValue); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseLenient(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := Parse(benchNumerals[i%len(benchNumerals)], Lenient); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseNonCanonical(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := Parse("mdcccclxxxxiiii", Lenient); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestConversionsDoNotAllocate checks the usual conversions, canonical numerals in either direction, allocate nothing.
+func TestConversionsDoNotAllocate(t *testing.T) {
+	for _, numeral := range benchNumerals {
+		if allocs := testing.AllocsPerRun(100, func() { ToInt(numeral) }); allocs != 0 {
+			t.Errorf("ToInt(%q) made %v allocations", numeral, allocs)
+		}
+		if allocs := testing.AllocsPerRun(100, func() { Parse(numeral, Lenient) }); allocs != 0 {
+			t.Errorf("Parse(%q, Lenient) made %v allocations", numeral, allocs)
+		}
+	}
+	if allocs := testing.AllocsPerRun(100, func() { FromInt(1994) }); allocs != 0 {
+		t.Errorf("FromInt(1994) made %v allocations", allocs)
+	}
+}
@@ -0,0 +1,235 @@
+// Ronan Green
+// C00270395
+
+package roman
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"Barrier/pool"
+)
+
+// Options controls Convert, ConvertEach and ConvertAll.
+type Options struct {
+	ToRoman bool          // Read each input as a number and write its numeral, rather than reading a numeral.
+	Parse   ParseOptions  // How to read numerals. TrimSpace also trims numbers.
+	Format  FormatOptions // How to write numerals.
+	Workers int           // How many lines ConvertEach and ConvertAll convert at once; runtime.GOMAXPROCS(0) if 0 or less.
+	// WorkStealing has ConvertEach and ConvertAll submit each chunk of lines to a work-stealing pool from Barrier/pool,
+	// whose workers each keep their own queue of chunks and take from the others once theirs is empty, rather than
+	// have the workers share one channel of chunks.
+	WorkStealing bool
+}
+
+// Convert converts one numeral, or number if opts.ToRoman is set, and returns both its value and its canonical
+// numeral.
+func Convert(input string, opts Options) (int, string, error) {
+	if !opts.ToRoman {
+		return Parse(input, opts.Parse)
+	}
+	if opts.Parse.TrimSpace {
+		input = strings.TrimSpace(input)
+	}
+	n, err := strconv.Atoi(input)
+	if err != nil {
+		return 0, "", fmt.Errorf("roman: %q is not a whole number", input)
+	}
+	numeral, err := Format(n, opts.Format)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, numeral, nil
+}
+
+// Result is the conversion of one line of input by ConvertEach.
+type Result struct {
+	Line    int    // The line number, from 1.
+	Input   string // The line, without its line ending.
+	Value   int    // The value, if the line was converted.
+	Numeral string // The canonical numeral, if the line was converted.
+	Err     error  // Why the line could not be converted, or nil.
+}
+
+// chunkSize is how many lines ConvertEach hands to a worker at once, so the cost of passing them between goroutines,
+// which is more than that of converting a line, is shared between them.
+const chunkSize = 256
+
+// chunk is a run of lines for a worker to convert.
+type chunk struct {
+	results []Result      // The lines, converted in place.
+	done    chan struct{} // Closed once every line is converted.
+}
+
+// ConvertEach converts every line of r concurrently and calls fn with each result in the order of the lines.
+//
+// Input:
+//   - ctx (context.Context): Stops the conversion when cancelled.
+//   - r (io.Reader): One numeral, or number if opts.ToRoman is set, per line. Blank lines are skipped, and lines may
+//     end in \r\n.
+//   - opts (Options): How to convert each line, and how many workers to convert them with.
+//   - fn (func(Result) error): Called from the calling goroutine with each result in turn. An error stops the
+//     conversion and is returned.
+//
+// Output:
+//   - int: The number of lines converted.
+//   - int: The number of lines that could not be converted.
+//   - error: Returns ctx.Err() if ctx was cancelled, or an error if r cannot be read or fn fails; a line that cannot
+//     be converted is not an error, only a Result with Err set.
+//
+// Functionality:
+//  1. Reads the lines in one goroutine, in chunks of chunkSize, and queues each chunk both for opts.Workers workers
+//     and for fn in the order they were read. The workers share one channel of chunks or, with opts.WorkStealing,
+//     are those of a pool.Pool, to which one goroutine submits each chunk as it is read. Either way the results are
+//     passed on a chunk at a time, once the chunk is full or the input ends, rather than as soon as each line is read.
+//  2. Waits on each chunk in turn, so the results come out in order however fast each chunk is converted, while at
+//     most a few chunks per worker are held in memory however large the input.
+//  3. On cancellation or an error, stops reading and waits for the goroutines to finish before returning. Chunks
+//     already queued are closed without being converted, and a read already waiting on r cannot be interrupted, so
+//     it must return first.
+func ConvertEach(ctx context.Context, r io.Reader, opts Options, fn func(Result) error) (converted, failed int, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	jobs := make(chan chunk)
+	order := make(chan chunk, 4*workers)
+	var readErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		defer close(order)
+		readErr = readChunks(ctx, r, jobs, order)
+	}()
+	if opts.WorkStealing {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p := pool.New(workers)
+			defer p.Close()
+			for c := range jobs {
+				p.Submit(func() { convertChunk(ctx, c, opts) })
+			}
+			p.Wait()
+		}()
+	} else {
+		wg.Add(workers)
+		for range workers {
+			go func() {
+				defer wg.Done()
+				for c := range jobs {
+					convertChunk(ctx, c, opts)
+				}
+			}()
+		}
+	}
+
+	err = func() error {
+		for c := range order {
+			select {
+			case <-c.done:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			for _, r := range c.results {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				if r.Err != nil {
+					failed++
+				} else {
+					converted++
+				}
+				if err := fn(r); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}()
+	cancel()
+	wg.Wait()
+	if err == nil {
+		err = readErr
+	}
+	return converted, failed, err
+}
+
+// convertChunk converts every line of c in place and then closes c.done. Once ctx is cancelled no one reads the
+// results, so it closes c.done without converting the rest.
+func convertChunk(ctx context.Context, c chunk, opts Options) {
+	defer close(c.done)
+	for i := range c.results {
+		if i%64 == 0 && ctx.Err() != nil {
+			return
+		}
+		c.results[i].Value, c.results[i].Numeral, c.results[i].Err = Convert(c.results[i].Input, opts)
+	}
+}
+
+// readChunks reads the lines of r into chunks and sends each to order and then jobs, until the input ends or ctx is
+// cancelled, returning ctx.Err() or any error reading r.
+func readChunks(ctx context.Context, r io.Reader, jobs, order chan<- chunk) error {
+	send := func(c chunk) error {
+		for _, ch := range []chan<- chunk{order, jobs} {
+			select {
+			case ch <- c:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+	scanner := bufio.NewScanner(r)
+	c := chunk{done: make(chan struct{})}
+	for line := 1; scanner.Scan(); line++ {
+		input := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(input) == "" {
+			continue
+		}
+		c.results = append(c.results, Result{Line: line, Input: input})
+		if len(c.results) == chunkSize {
+			if err := send(c); err != nil {
+				return err
+			}
+			c = chunk{done: make(chan struct{})}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if len(c.results) > 0 {
+		return send(c)
+	}
+	return ctx.Err()
+}
+
+// ConvertAll converts every line of r concurrently, as ConvertEach does, and writes a line to w for each in the
+// order of the input, with tab-separated columns: the input, then its value and canonical numeral, or two empty
+// columns and the error if it could not be converted, such as "MCMXCIV\t1994\tMCMXCIV".
+func ConvertAll(ctx context.Context, r io.Reader, w io.Writer, opts Options) (converted, failed int, err error) {
+	bw := bufio.NewWriter(w)
+	converted, failed, err = ConvertEach(ctx, r, opts, func(r Result) error {
+		if r.Err != nil {
+			_, err := fmt.Fprintf(bw, "%s\t\t\t%v\n", r.Input, r.Err)
+			return err
+		}
+		_, err := fmt.Fprintf(bw, "%s\t%d\t%s\n", r.Input, r.Value, r.Numeral)
+		return err
+	})
+	if flushErr := bw.Flush(); err == nil {
+		err = flushErr
+	}
+	return converted, failed, err
+}
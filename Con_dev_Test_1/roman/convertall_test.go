@@ -0,0 +1,161 @@
+package roman
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestConvertAll converts a list in both directions, checking blank lines are skipped and failures become lines.
+func TestConvertAll(t *testing.T) {
+	tests := []struct {
+		in                string
+		opts              Options
+		want              string
+		converted, failed int
+	}{
+		{
+			"XIV\n\nIIII\r\nMCMXCIV", Options{},
+			"XIV\t14\tXIV\nIIII\t\t\troman: invalid Roman numeral combination at position 4 in \"IIII\"\nMCMXCIV\t1994\tMCMXCIV\n",
+			2, 1,
+		},
+		{
+			" 1994 \nten\n5000\n", Options{ToRoman: true, Parse: Lenient, Format: FormatOptions{Vinculum: true}},
+			" 1994 \t1994\tMCMXCIV\nten\t\t\troman: \"ten\" is not a whole number\n5000\t5000\tV̅\n",
+			2, 1,
+		},
+	}
+	for _, test := range tests {
+		var out strings.Builder
+		converted, failed, err := ConvertAll(context.Background(), strings.NewReader(test.in), &out, test.opts)
+		if err != nil || converted != test.converted || failed != test.failed || out.String() != test.want {
+			t.Errorf("ConvertAll(%q) = %d, %d, %v writing %q, want %d, %d, nil writing %q",
+				test.in, converted, failed, err, out.String(), test.converted, test.failed, test.want)
+		}
+	}
+}
+
+// TestConvertEachOrder converts every value with different numbers of workers, sharing a channel and stealing from
+// one another, checking the results come out in the order of the lines.
+func TestConvertEachOrder(t *testing.T) {
+	var in strings.Builder
+	for n := MaxValue; n >= MinValue; n-- {
+		fmt.Fprintln(&in, plain(n))
+	}
+	for _, stealing := range []bool{false, true} {
+		for _, workers := range []int{0, 1, 3, 16} {
+			next := MaxValue
+			opts := Options{Workers: workers, WorkStealing: stealing}
+			_, _, err := ConvertEach(context.Background(), strings.NewReader(in.String()), opts, func(r Result) error {
+				if r.Value != next || r.Line != MaxValue-next+1 || r.Err != nil {
+					return fmt.Errorf("line %d is %s = %d, %v, want %d", r.Line, r.Input, r.Value, r.Err, next)
+				}
+				next--
+				return nil
+			})
+			if err != nil || next != 0 {
+				t.Errorf("with %d workers, work stealing %t: %v, stopping before %d", workers, stealing, err, next)
+			}
+		}
+	}
+}
+
+// TestConvertAllEngines converts the same list, with lines that fail among those that do not, on workers sharing a
+// channel and on a work-stealing pool, checking both write the same results in the same order.
+func TestConvertAllEngines(t *testing.T) {
+	var in strings.Builder
+	for n := range 3000 {
+		if n%7 == 0 {
+			fmt.Fprintln(&in, "IIII")
+		} else {
+			fmt.Fprintln(&in, plain(MinValue+n%MaxValue))
+		}
+	}
+	for _, workers := range []int{1, 3, 16} {
+		var outs [2]string
+		for i, stealing := range []bool{false, true} {
+			var out strings.Builder
+			opts := Options{Workers: workers, WorkStealing: stealing}
+			if _, _, err := ConvertAll(context.Background(), strings.NewReader(in.String()), &out, opts); err != nil {
+				t.Fatalf("with %d workers, work stealing %t: %v", workers, stealing, err)
+			}
+			outs[i] = out.String()
+		}
+		if outs[0] != outs[1] {
+			t.Errorf("with %d workers, the pool wrote different results from the channel", workers)
+		}
+	}
+}
+
+// endless is a reader of the same numeral forever.
+type endless struct{}
+
+func (endless) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = "XIV\n"[i%4]
+	}
+	return len(p) / 4 * 4, nil
+}
+
+// TestConvertEachStops checks cancellation and an error from fn stop an endless conversion, on workers sharing a
+// channel and on a work-stealing pool.
+func TestConvertEachStops(t *testing.T) {
+	for _, stealing := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		converted, _, err := ConvertEach(ctx, endless{}, Options{Workers: 4, WorkStealing: stealing}, func(r Result) error {
+			if calls++; calls == 100 {
+				cancel()
+			}
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) || converted != 100 {
+			t.Errorf("work stealing %t: a cancelled conversion returned %v after %d lines, want context.Canceled after 100",
+				stealing, err, converted)
+		}
+		converted, _, err = ConvertEach(ctx, endless{}, Options{Workers: 4, WorkStealing: stealing}, func(r Result) error { return nil })
+		if !errors.Is(err, context.Canceled) || converted != 0 {
+			t.Errorf("work stealing %t: a conversion with a cancelled context returned %v after %d lines, want context.Canceled after none",
+				stealing, err, converted)
+		}
+		if _, _, err := ConvertEach(ctx, strings.NewReader("X\n"), Options{WorkStealing: stealing}, func(Result) error { return nil }); !errors.Is(err, context.Canceled) {
+			t.Errorf("work stealing %t: a short conversion with a cancelled context returned %v, want context.Canceled", stealing, err)
+		}
+
+		stop := errors.New("stop")
+		_, _, err = ConvertEach(context.Background(), endless{}, Options{WorkStealing: stealing}, func(r Result) error {
+			if r.Line == 10 {
+				return stop
+			}
+			return nil
+		})
+		if !errors.Is(err, stop) {
+			t.Errorf("work stealing %t: a conversion stopped by fn returned %v, want its error", stealing, err)
+		}
+	}
+}
+
+// BenchmarkConvertAll converts a large list with one worker and with as many as there are CPUs, sharing a channel
+// and on a work-stealing pool.
+func BenchmarkConvertAll(b *testing.B) {
+	var in strings.Builder
+	for n := range 100000 {
+		fmt.Fprintln(&in, strconv.Itoa(MinValue+n%MaxValue))
+	}
+	for _, stealing := range []bool{false, true} {
+		for _, workers := range []int{1, 0} {
+			b.Run(fmt.Sprintf("stealing=%t/workers=%d", stealing, workers), func(b *testing.B) {
+				opts := Options{ToRoman: true, Workers: workers, WorkStealing: stealing}
+				for range b.N {
+					if _, _, err := ConvertAll(context.Background(), strings.NewReader(in.String()), io.Discard, opts); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Ronan Green
+// C00270395
+
+package roman
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Breakdown is how a numeral was read: the tokens whose values add up to its value.
+type Breakdown struct {
+	Tokens    []Token // The symbols and subtractive pairs of the numeral, in order.
+	Value     int     // The sum of the tokens' values.
+	Canonical string  // The canonical form of the numeral.
+}
+
+// String explains the breakdown step by step for teaching, such as
+// "M=1000, CM=900 (1000-100), XC=90 (100-10), IV=4 (5-1) → 1994", showing which symbols were added and which pairs
+// subtracted.
+func (b Breakdown) String() string {
+	steps := make([]string, len(b.Tokens))
+	for i, tok := range b.Tokens {
+		steps[i] = fmt.Sprintf("%s=%d", tok.Symbol, tok.Value)
+		if tok.Subtractive() {
+			steps[i] += fmt.Sprintf(" (%d-%d)", tok.symbols[1].value(), tok.symbols[0].value())
+		}
+	}
+	return strings.Join(steps, ", ") + " → " + strconv.Itoa(b.Value)
+}
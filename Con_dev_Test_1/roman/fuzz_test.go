@@ -0,0 +1,81 @@
+package roman
+
+import (
+	"errors"
+	"testing"
+)
+
+// FuzzToInt feeds arbitrary strings to ToInt and checks that anything it accepts is exactly the canonical numeral
+// FromInt writes for the value, so no malformed numeral is accepted, and that anything it rejects gets one of the
+// package's errors. Run it with go test ./roman -run x -fuzz FuzzToInt.
+func FuzzToInt(f *testing.F) {
+	for _, seed := range []string{"", "I", "IV", "IIII", "VV", "IC", "MCMXCIV", "MMMCMXCIX", "MMMM", "xiv", " X", "Ⅻ", "_V", "V̅"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, numeral string) {
+		value, err := ToInt(numeral)
+		if err != nil {
+			checkError(t, numeral, err)
+			return
+		}
+		if numeral2, err := FromInt(value); numeral2 != numeral || err != nil {
+			t.Fatalf("ToInt(%q) = %d, but FromInt(%d) = %q, %v", numeral, value, value, numeral2, err)
+		}
+	})
+}
+
+// FuzzParse parses arbitrary strings with every combination of options and checks the canonical form returned is
+// accepted strictly, with the same value, by the notation it is written in.
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{"iiii", " mcmxciv ", "_v_i", "V̅Ⅻ", "ⅫⅤ", "̅", "_", "MMMMMMMMMMMMMMMM"} {
+		for flags := range byte(64) {
+			f.Add(seed, flags)
+		}
+	}
+	f.Fuzz(func(t *testing.T, numeral string, flags byte) {
+		opts := ParseOptions{
+			IgnoreCase:        flags&1 != 0,
+			TrimSpace:         flags&2 != 0,
+			AllowNonCanonical: flags&4 != 0,
+			Unicode:           flags&8 != 0,
+			Vinculum:          flags&16 != 0,
+		}
+		if flags&32 != 0 {
+			opts.MaxValue = 5000
+		}
+		value, canonical, err := Parse(numeral, opts)
+		if err != nil {
+			checkError(t, numeral, err)
+			return
+		}
+		if value < MinValue || value > maxValue(opts.Vinculum, opts.MaxValue) {
+			t.Fatalf("Parse(%q, %+v) = %d, outside the range allowed", numeral, opts, value)
+		}
+		strict := ParseOptions{Unicode: opts.Unicode, Vinculum: opts.Vinculum, MaxValue: opts.MaxValue}
+		if value2, canonical2, err := Parse(canonical, strict); value2 != value || canonical2 != canonical || err != nil {
+			t.Fatalf("Parse(%q, %+v) = %d, %q, but the canonical form parses to %d, %q, %v",
+				numeral, opts, value, canonical, value2, canonical2, err)
+		}
+	})
+}
+
+// checkError fails the test unless err is one of the errors the package documents.
+func checkError(t *testing.T, numeral string, err error) {
+	t.Helper()
+	var charErr *CharError
+	var combination *CombinationError
+	var rangeErr *RangeError
+	switch {
+	case errors.Is(err, ErrEmpty), errors.Is(err, ErrTooLong), errors.As(err, &rangeErr):
+	case errors.As(err, &charErr):
+		if charErr.Index < 0 || charErr.Index >= len(numeral) {
+			t.Fatalf("the CharError for %q is at %d, outside the numeral", numeral, charErr.Index)
+		}
+	case errors.As(err, &combination):
+		if combination.Index < 0 || combination.Index >= len(numeral) {
+			t.Fatalf("the CombinationError for %q is at %d, outside the numeral", numeral, combination.Index)
+		}
+	default:
+		t.Fatalf("%q returned an unexpected error: %v", numeral, err)
+	}
+}
@@ -0,0 +1,65 @@
+// Ronan Green
+// C00270395
+
+package roman
+
+import "fmt"
+
+// LengthError is returned for a numeral with more symbols than the limit set by ParseOptions.MaxLength, or than the
+// longest canonical numeral for a value in range. It wraps ErrTooLong.
+type LengthError struct {
+	Numeral string // The numeral being converted.
+	Max     int    // The most symbols allowed.
+}
+
+func (e *LengthError) Error() string {
+	return fmt.Sprintf("roman: %q has more than %d symbols", e.Numeral, e.Max)
+}
+
+func (e *LengthError) Unwrap() error {
+	return ErrTooLong
+}
+
+// Longest returns the most symbols Parse accepts with these options, or 0 if there is no limit: MaxLength if it is
+// set, and otherwise, unless AllowNonCanonical is set, the length of the longest canonical numeral in the style for
+// a value up to the maximum. A barred symbol counts as one, and so does a D or M written in apostrophus, while a code
+// point such as Ⅻ counts as the symbols it stands for.
+func (o ParseOptions) Longest() int {
+	if o.MaxLength > 0 || o.AllowNonCanonical {
+		return o.MaxLength
+	}
+	return longestNumeral(maxValue(o.Vinculum, o.MaxValue), o.Style)
+}
+
+// longestNumeral returns the number of symbols in the longest numeral in the style for a value from 1 to limit, which
+// is at most MaxVinculumValue. Above MaxValue, a value is its barred thousands, from 4 up, followed by the rest.
+func longestNumeral(limit int, style Style) int {
+	if limit <= MaxValue {
+		return int(longest[style][limit])
+	}
+	thousands, t := style.thousands(), limit/1000
+	n := int(longest[style][MaxValue])
+	if t > 4 {
+		n = max(n, int(longestBarred[thousands][t-1])+int(longest[style][999]))
+	}
+	return max(n, int(symbolCounts[thousands][t])+int(longest[style][limit%1000]))
+}
+
+// symbolCounts holds the number of symbols in the numeral for each value from 0 to MaxValue in each style, longest the
+// most in the numeral for any value up to each, and longestBarred the most for any value from 4 up to each, as the
+// barred thousands are.
+var symbolCounts, longest, longestBarred = func() (counts, upTo, barred [len(styleNames)][MaxValue + 1]uint8) {
+	for style := range counts {
+		for n := range counts[style] {
+			counts[style][n] = uint8(len(Style(style).spelling(n)))
+			upTo[style][n] = counts[style][n]
+			if n > 0 {
+				upTo[style][n] = max(upTo[style][n], upTo[style][n-1])
+			}
+			if n >= 4 {
+				barred[style][n] = max(counts[style][n], barred[style][n-1])
+			}
+		}
+	}
+	return counts, upTo, barred
+}()
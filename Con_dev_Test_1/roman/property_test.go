@@ -0,0 +1,132 @@
+package roman
+
+import (
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// formats lists the notations every value should round trip through.
+var formats = []struct {
+	name   string
+	format FormatOptions
+	parse  ParseOptions
+}{
+	{"ASCII", FormatOptions{}, ParseOptions{}},
+	{"Unicode", FormatOptions{Unicode: true}, ParseOptions{Unicode: true}},
+	{"additive", FormatOptions{Style: Additive, Unicode: true}, ParseOptions{Style: Additive, Unicode: true}},
+	{"apostrophus", FormatOptions{Style: Apostrophus}, ParseOptions{Style: Apostrophus}},
+	{"vinculum", FormatOptions{Vinculum: true}, ParseOptions{Vinculum: true}},
+	{"underscores", FormatOptions{Vinculum: true, Underscores: true}, ParseOptions{Vinculum: true}},
+	{"additive vinculum", FormatOptions{Vinculum: true, Style: Additive}, ParseOptions{Vinculum: true, Style: Additive}},
+}
+
+// TestRoundTrip formats every value from 1 to 3999 in each notation and parses it back, both strictly and leniently.
+func TestRoundTrip(t *testing.T) {
+	for _, f := range formats {
+		for n := MinValue; n <= MaxValue; n++ {
+			checkRoundTrip(t, f.name, n, f.format, f.parse)
+		}
+	}
+}
+
+// TestRoundTripVinculum does the same for random values up to MaxVinculumValue.
+func TestRoundTripVinculum(t *testing.T) {
+	for _, f := range formats[4:] {
+		property := func(n uint32) bool {
+			checkRoundTrip(t, f.name, MinValue+int(n%MaxVinculumValue), f.format, f.parse)
+			return !t.Failed()
+		}
+		if err := quick.Check(property, &quick.Config{MaxCount: 20000}); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+// checkRoundTrip formats n and checks it parses back to n, giving the same canonical numeral, with opts and leniently.
+func checkRoundTrip(t *testing.T, name string, n int, format FormatOptions, opts ParseOptions) {
+	t.Helper()
+	numeral, err := Format(n, format)
+	if err != nil {
+		t.Fatalf("%s: Format(%d) returned %v", name, n, err)
+	}
+	lenient := Lenient
+	lenient.Vinculum, lenient.Style = opts.Vinculum, opts.Style
+	for _, opts := range []ParseOptions{opts, lenient} {
+		if value, canonical, err := Parse(numeral, opts); value != n || canonical != numeral || err != nil {
+			t.Fatalf("%s: Parse(Format(%d) = %q, %+v) = %d, %q, %v", name, n, numeral, opts, value, canonical, err)
+		}
+	}
+}
+
+// mutations returns every string one edit away from numeral, made with the symbols: each symbol replaced by another,
+// removed, doubled or swapped with the next, and each symbol inserted at every position.
+func mutations(numeral string) []string {
+	const symbols = "IVXLCDM"
+	var out []string
+	for i := 0; i < len(numeral); i++ {
+		for _, s := range symbols {
+			if byte(s) != numeral[i] {
+				out = append(out, numeral[:i]+string(s)+numeral[i+1:])
+			}
+		}
+		out = append(out, numeral[:i]+numeral[i+1:], numeral[:i+1]+numeral[i:])
+		if i+1 < len(numeral) && numeral[i] != numeral[i+1] {
+			out = append(out, numeral[:i]+numeral[i+1:i+2]+numeral[i:i+1]+numeral[i+2:])
+		}
+	}
+	for i := 0; i <= len(numeral); i++ {
+		for _, s := range symbols {
+			out = append(out, numeral[:i]+string(s)+numeral[i:])
+		}
+	}
+	return out
+}
+
+// TestMutations corrupts every canonical numeral by one edit and checks the result is rejected unless it is itself
+// the canonical numeral of another value. A parser that accepts anything it should not is caught here.
+func TestMutations(t *testing.T) {
+	canonical := make(map[string]int, MaxValue)
+	for n := MinValue; n <= MaxValue; n++ {
+		numeral, _ := FromInt(n)
+		canonical[numeral] = n
+	}
+
+	rejected := 0
+	for n := MinValue; n <= MaxValue; n++ {
+		numeral, _ := FromInt(n)
+		for _, mutant := range mutations(numeral) {
+			value, err := ToInt(mutant)
+			want, isCanonical := canonical[mutant]
+			switch {
+			case isCanonical && (value != want || err != nil):
+				t.Fatalf("ToInt(%q), a mutation of %q, = %d, %v, want %d", mutant, numeral, value, err, want)
+			case !isCanonical && err == nil:
+				t.Fatalf("ToInt(%q), a mutation of %q, accepted a non-canonical numeral as %d", mutant, numeral, value)
+			case !isCanonical:
+				rejected++
+			}
+		}
+	}
+	if rejected == 0 {
+		t.Fatal("no mutation was rejected")
+	}
+	t.Logf("%d non-canonical mutations rejected", rejected)
+}
+
+// TestMutationsLenient checks that the non-canonical mutations lenient parsing accepts are normalized to a numeral
+// strict parsing accepts, with the same value.
+func TestMutationsLenient(t *testing.T) {
+	for n := MinValue; n <= MaxValue; n += 7 {
+		numeral, _ := FromInt(n)
+		for _, mutant := range mutations(numeral) {
+			value, normalized, err := Parse(strings.ToLower(mutant), Lenient)
+			if err != nil {
+				continue // Out of range.
+			}
+			if strict, err := ToInt(normalized); strict != value || err != nil {
+				t.Fatalf("Parse(%q, Lenient) = %d, %q, but ToInt(%q) = %d, %v", mutant, value, normalized, normalized, strict, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,294 @@
+// Ronan Green
+// C00270395
+
+// Package roman converts between Roman numerals and integers.
+//
+// Only canonical numerals are accepted: the symbols I, V, X, L, C, D and M in descending order, with the
+// subtractive pairs IV, IX, XL, XC, CD and CM, no symbol repeated more than three times in a row, and V, L and D
+// never repeated. That covers every whole number from 1 to 3999. Larger numbers can be written with a vinculum, a bar
+// over the symbols of the thousands, when ParseOptions.Vinculum or FormatOptions.Vinculum is set.
+package roman
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MinValue and MaxValue are the smallest and largest values a Roman numeral can hold.
+const (
+	MinValue = 1
+	MaxValue = 3999
+)
+
+// MaxLength is the length of the longest canonical numeral, MMMDCCCLXXXVIII (3888). Parse allows longer numerals
+// when they can be canonical, such as with a vinculum, and ParseOptions.MaxLength sets another limit.
+const MaxLength = 15
+
+var (
+	// ErrEmpty is returned for an empty numeral.
+	ErrEmpty = errors.New("roman: empty numeral")
+	// ErrTooLong is wrapped by the *LengthError returned for a numeral with more symbols than allowed, such as ToInt
+	// given more than MaxLength.
+	ErrTooLong = errors.New("roman: numeral too long")
+)
+
+// CharError is returned when a numeral contains a character that is not a Roman numeral symbol.
+type CharError struct {
+	Numeral string // The numeral being converted.
+	Index   int    // Byte offset of the character in Numeral.
+	Char    rune   // The character.
+}
+
+func (e *CharError) Error() string {
+	return fmt.Sprintf("roman: invalid character %q at position %d in %q; only use the Roman numeral characters I, V, X, L, C, D, M",
+		e.Char, e.Index+1, e.Numeral)
+}
+
+// CombinationError is returned when the symbols of a numeral are valid but their order is not, as in IIII, VV or IC.
+type CombinationError struct {
+	Numeral string // The numeral being converted.
+	Index   int    // Byte offset of the first symbol that cannot follow the ones before it.
+}
+
+func (e *CombinationError) Error() string {
+	return fmt.Sprintf("roman: invalid Roman numeral combination at position %d in %q", e.Index+1, e.Numeral)
+}
+
+// RangeError is returned when converting a value that cannot be written as a Roman numeral, or is above the maximum
+// asked for.
+type RangeError struct {
+	Value int // The value being converted.
+	Max   int // The largest value allowed.
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("roman: %d cannot be written as a Roman numeral; only %d to %d can", e.Value, MinValue, e.Max)
+}
+
+// places holds the numeral for every digit of each decimal place, from the thousands down to the ones.
+// The thousands stop at 3, since 4000 would need a symbol for 5000.
+var places = [...]struct {
+	value  int
+	digits []string
+}{
+	{1000, []string{"", "M", "MM", "MMM"}},
+	{100, []string{"", "C", "CC", "CCC", "CD", "D", "DC", "DCC", "DCCC", "CM"}},
+	{10, []string{"", "X", "XX", "XXX", "XL", "L", "LX", "LXX", "LXXX", "XC"}},
+	{1, []string{"", "I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX"}},
+}
+
+// ParseOptions controls how forgiving Parse is. The zero value accepts only canonical numerals, as ToInt does.
+type ParseOptions struct {
+	IgnoreCase        bool  // Accept lower case symbols, such as "xiv".
+	TrimSpace         bool  // Ignore space before and after the numeral.
+	AllowNonCanonical bool  // Accept numerals such as IIII, VV or IC, reading them by adding up their tokens, so a symbol before a larger one is subtracted from it.
+	Unicode           bool  // Accept the Roman numerals of the Unicode Number Forms block, such as Ⅻ, as the symbols they stand for.
+	Vinculum          bool  // Accept barred symbols worth a thousand times as much, written "V̄" or "_V", up to MaxVinculumValue.
+	Style             Style // How canonical numerals are written; the zero value is Standard.
+	MaxValue          int   // The largest value accepted, if above 0 and below the largest that can be written.
+	MaxLength         int   // The most symbols accepted, if above 0, even with AllowNonCanonical; see Longest for the default.
+}
+
+// Lenient accepts anything that can be read as a numeral from 1 to 3999.
+var Lenient = ParseOptions{IgnoreCase: true, TrimSpace: true, AllowNonCanonical: true, Unicode: true}
+
+// ToInt converts a canonical Roman numeral, in upper case and without surrounding space such as "MCMXCIV", to its
+// value. It is Parse with the zero ParseOptions.
+func ToInt(numeral string) (int, error) {
+	value, _, err := Parse(numeral, ParseOptions{})
+	return value, err
+}
+
+// Parse converts a Roman numeral to its value, as forgivingly as opts allows.
+//
+// Input:
+//   - numeral (string): The numeral, such as "MCMXCIV", or with Lenient " mcmlxxxxiiii ".
+//   - opts (ParseOptions): What to accept besides canonical numerals.
+//
+// Output:
+//   - int: The value of the numeral, from 1 to 3999, or to MaxVinculumValue with a vinculum.
+//   - string: The canonical form of the numeral, such as "MCMXCIV", so lenient input can be normalized.
+//     Barred symbols are written with underscores if the numeral used them, and with overlines otherwise, and the
+//     symbols are written as Unicode code points if the numeral used any.
+//   - error: ErrEmpty, a *LengthError if the numeral has more symbols than opts.Longest allows, a *CharError naming the first character that is not a Roman numeral symbol,
+//     a *CombinationError naming the first symbol out of place, or a *RangeError if the value is above the maximum.
+//
+// Functionality:
+//  1. Splits the numeral into tokens with a Tokenizer and adds up their values, stopping once there are more symbols
+//     than opts.Longest allows. A canonical numeral of ASCII symbols,
+//     the usual case, is instead read directly without allocating.
+//  2. Unless AllowNonCanonical is set, checks the symbols are in canonical order: the barred symbols, then the rest,
+//     each read one decimal place at a time, taking the longest numeral of each place the input starts with, so "XC"
+//     is read as 90 rather than 10 followed by an out of place C. Anything left once the ones have been read is out
+//     of order, as is a barred symbol after an unbarred one. In the other styles, the symbols must instead be exactly
+//     those of the style's numeral for their value, so IIII is canonical when additive.
+//  3. Checks the value against the maximum and writes its canonical form in the style.
+func Parse(numeral string, opts ParseOptions) (int, string, error) {
+	if value, ok := parseFast(numeral); ok && opts.Style == Standard && value <= maxValue(opts.Vinculum, opts.MaxValue) &&
+		(opts.MaxLength == 0 || len(numeral) <= opts.MaxLength) {
+		return value, numeral, nil
+	}
+	b, err := ParseBreakdown(numeral, opts)
+	if err != nil {
+		return 0, "", err
+	}
+	return b.Value, b.Canonical, nil
+}
+
+// ParseBreakdown is Parse, also returning the tokens the value was added up from, so the conversion can be explained.
+func ParseBreakdown(numeral string, opts ParseOptions) (Breakdown, error) {
+	t := NewTokenizer(numeral, opts)
+	maxLength := opts.Longest()
+	var b Breakdown
+	var symbols []symbol
+	for t.Next() {
+		b.Tokens = append(b.Tokens, t.Token())
+		b.Value += t.Token().Value
+		symbols = append(symbols, t.Token().symbols...)
+		if maxLength > 0 && len(symbols) > maxLength {
+			return Breakdown{}, &LengthError{Numeral: numeral, Max: maxLength} // Stop early, however long the input.
+		}
+	}
+	if err := t.Err(); err != nil {
+		return Breakdown{}, err
+	}
+	if len(symbols) == 0 {
+		return Breakdown{}, ErrEmpty
+	}
+
+	if opts.Style == Standard && !opts.AllowNonCanonical {
+		if bad := outOfPlace(symbols); bad >= 0 {
+			return Breakdown{}, &CombinationError{Numeral: numeral, Index: symbols[bad].index}
+		}
+	}
+	limit := maxValue(opts.Vinculum, opts.MaxValue)
+	if b.Value < MinValue || b.Value > limit {
+		return Breakdown{}, &RangeError{Value: b.Value, Max: limit}
+	}
+	if bad := outOfStyle(symbols, b.Value, opts.Style); bad >= 0 && opts.Style != Standard && !opts.AllowNonCanonical {
+		return Breakdown{}, &CombinationError{Numeral: numeral, Index: symbols[bad].index}
+	}
+	style := t.style
+	style.Vinculum, style.MaxValue, style.Style = opts.Vinculum, limit, opts.Style
+	canonical, err := Format(b.Value, style)
+	if err != nil {
+		return Breakdown{}, err
+	}
+	b.Canonical = canonical
+	return b, nil
+}
+
+// outOfPlace checks symbols form a canonical numeral: barred symbols for the thousands from 4000 up, then unbarred
+// symbols for the rest. It returns the position in symbols of the first symbol out of order, or -1 if none is.
+func outOfPlace(symbols []symbol) int {
+	barred := 0
+	for barred < len(symbols) && symbols[barred].barred {
+		barred++
+	}
+	for i := barred; i < len(symbols); i++ {
+		if symbols[i].barred {
+			return i
+		}
+	}
+
+	high, read := parseCanonical(letters(symbols[:barred]))
+	if read < barred {
+		return read
+	}
+	low, read := parseCanonical(letters(symbols[barred:]))
+	if read < len(symbols)-barred {
+		return barred + read
+	}
+	if barred > 0 && high <= MaxValue/1000 {
+		return 0 // Thousands up to 3999 are written with M.
+	}
+	if barred > 0 && low >= 1000 {
+		return barred // Once the thousands are barred, M cannot follow.
+	}
+	return -1
+}
+
+// letters returns the letters of symbols, without their bars.
+func letters(symbols []symbol) string {
+	b := make([]byte, len(symbols))
+	for i, s := range symbols {
+		b[i] = s.letter
+	}
+	return string(b)
+}
+
+// parseCanonical reads a numeral of valid symbols one decimal place at a time, taking the longest numeral of each
+// place the input starts with, and returns its value and how much of it was read. Anything left is out of order.
+func parseCanonical(numeral string) (value, read int) {
+	for _, place := range places {
+		digit := 0
+		for d, digits := range place.digits {
+			if len(digits) > len(place.digits[digit]) && strings.HasPrefix(numeral[read:], digits) {
+				digit = d
+			}
+		}
+		value += digit * place.value
+		read += len(place.digits[digit])
+	}
+	return value, read
+}
+
+// symbolValues holds the value of each Roman numeral symbol, indexed by its letter, and 0 for any other byte.
+var symbolValues = [256]int{'I': 1, 'V': 5, 'X': 10, 'L': 50, 'C': 100, 'D': 500, 'M': 1000}
+
+// value returns what s is worth on its own.
+func (s symbol) value() int {
+	if s.barred {
+		return symbolValues[s.letter] * 1000
+	}
+	return symbolValues[s.letter]
+}
+
+// FromInt converts a value to its canonical Roman numeral, using the subtractive pairs IV, IX, XL, XC, CD and CM
+// rather than four repeated symbols, so FromInt(1994) is "MCMXCIV".
+// It returns a *RangeError if n is outside MinValue to MaxValue. Use Format for larger values.
+func FromInt(n int) (string, error) {
+	return Format(n, FormatOptions{})
+}
+
+// numerals holds the canonical numeral for every value from 1 to MaxValue, and "" for 0, so converting to and
+// checking numerals needs no allocation.
+var numerals = func() (table [MaxValue + 1]string) {
+	for n := range table {
+		m := n
+		for _, place := range places {
+			table[n] += place.digits[m/place.value]
+			m %= place.value
+		}
+	}
+	return table
+}()
+
+// plain returns the numeral for n, from 0 to MaxValue, without a vinculum.
+func plain(n int) string {
+	return numerals[n]
+}
+
+// parseFast reads numeral if it is a canonical numeral of plain ASCII symbols, which is what almost every
+// conversion is, without allocating: it adds up the symbols, subtracting each one before a larger one as the
+// Tokenizer pairs them, and checks the numeral is the canonical one for the total. Anything else is left to the
+// Tokenizer, which also reports why a numeral is rejected.
+func parseFast(numeral string) (int, bool) {
+	if len(numeral) == 0 || len(numeral) > MaxLength {
+		return 0, false
+	}
+	value := 0
+	for i := 0; i < len(numeral); i++ {
+		v := symbolValues[numeral[i]]
+		if v == 0 {
+			return 0, false
+		}
+		if i+1 < len(numeral) && v < symbolValues[numeral[i+1]] {
+			v = symbolValues[numeral[i+1]] - v
+			i++
+		}
+		value += v
+	}
+	return value, value <= MaxValue && numerals[value] == numeral
+}
@@ -0,0 +1,385 @@
+package roman
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestToInt converts canonical numerals covering every symbol and subtractive pair.
+func TestToInt(t *testing.T) {
+	tests := []struct {
+		numeral string
+		want    int
+	}{
+		{"I", 1},
+		{"III", 3},
+		{"IV", 4},
+		{"IX", 9},
+		{"XIV", 14},
+		{"XL", 40},
+		{"XC", 90},
+		{"CD", 400},
+		{"CM", 900},
+		{"MCMXCIV", 1994},
+		{"MMXXIV", 2024},
+		{"MMMDCCCLXXXVIII", 3888},
+		{"MMMCMXCIX", 3999},
+	}
+	for _, test := range tests {
+		got, err := ToInt(test.numeral)
+		if got != test.want || err != nil {
+			t.Errorf("ToInt(%q) = %d, %v, want %d, nil", test.numeral, got, err, test.want)
+		}
+	}
+}
+
+// TestToIntErrors checks that each kind of invalid numeral returns its error type.
+func TestToIntErrors(t *testing.T) {
+	if _, err := ToInt(""); !errors.Is(err, ErrEmpty) {
+		t.Errorf(`ToInt("") returned %v, want ErrEmpty`, err)
+	}
+	if _, err := ToInt("MMMDCCCLXXXVIIII"); !errors.Is(err, ErrTooLong) {
+		t.Errorf("ToInt of 16 characters returned %v, want ErrTooLong", err)
+	}
+
+	var charErr *CharError
+	if _, err := ToInt("XIZ"); !errors.As(err, &charErr) || charErr.Index != 2 || charErr.Char != 'Z' {
+		t.Errorf(`ToInt("XIZ") returned %v, want a CharError at index 2`, err)
+	}
+	if _, err := ToInt("xiv"); !errors.As(err, &charErr) || charErr.Index != 0 {
+		t.Errorf(`ToInt("xiv") returned %v, want a CharError at index 0`, err)
+	}
+
+	for numeral, index := range map[string]int{
+		"IIII": 3, "VV": 1, "LL": 1, "DD": 1, "IC": 1, "IL": 1, "XM": 1, "VX": 1, "IXI": 2, "XCX": 2, "MMMM": 3, "CMD": 2,
+	} {
+		var combination *CombinationError
+		if _, err := ToInt(numeral); !errors.As(err, &combination) || combination.Index != index {
+			t.Errorf("ToInt(%q) returned %v, want a CombinationError at index %d", numeral, err, index)
+		}
+	}
+}
+
+// TestFromInt converts every value and back, checking the round trip and a few canonical forms.
+func TestFromInt(t *testing.T) {
+	for n, want := range map[int]string{4: "IV", 9: "IX", 14: "XIV", 40: "XL", 400: "CD", 1994: "MCMXCIV", 3999: "MMMCMXCIX"} {
+		if got, err := FromInt(n); got != want || err != nil {
+			t.Errorf("FromInt(%d) = %q, %v, want %q, nil", n, got, err, want)
+		}
+	}
+	for n := MinValue; n <= MaxValue; n++ {
+		numeral, err := FromInt(n)
+		if err != nil {
+			t.Fatalf("FromInt(%d) returned %v", n, err)
+		}
+		if got, err := ToInt(numeral); got != n || err != nil {
+			t.Fatalf("ToInt(FromInt(%d)) = ToInt(%q) = %d, %v", n, numeral, got, err)
+		}
+	}
+
+	var rangeErr *RangeError
+	for _, n := range []int{0, -1, 4000} {
+		if _, err := FromInt(n); !errors.As(err, &rangeErr) || rangeErr.Value != n {
+			t.Errorf("FromInt(%d) returned %v, want a RangeError", n, err)
+		}
+	}
+}
+
+// TestParseLenient checks that lenient parsing accepts and normalizes what strict parsing rejects.
+func TestParseLenient(t *testing.T) {
+	tests := []struct {
+		numeral   string
+		want      int
+		canonical string
+	}{
+		{"xiv", 14, "XIV"},
+		{"  MCMXCIV\n", 1994, "MCMXCIV"},
+		{"IIII", 4, "IV"},
+		{"VV", 10, "X"},
+		{"IC", 99, "XCIX"},
+		{"MDCCCCX", 1910, "MCMX"},
+		{"mmmi", 3001, "MMMI"},
+	}
+	for _, test := range tests {
+		got, canonical, err := Parse(test.numeral, Lenient)
+		if got != test.want || canonical != test.canonical || err != nil {
+			t.Errorf("Parse(%q, Lenient) = %d, %q, %v, want %d, %q, nil", test.numeral, got, canonical, err, test.want, test.canonical)
+		}
+		if _, err := ToInt(test.numeral); err == nil && test.numeral != test.canonical {
+			t.Errorf("ToInt(%q) accepted a non-canonical numeral", test.numeral)
+		}
+	}
+
+	var rangeErr *RangeError
+	if _, _, err := Parse("MMMM", Lenient); !errors.As(err, &rangeErr) || rangeErr.Value != 4000 {
+		t.Errorf(`Parse("MMMM", Lenient) returned %v, want a RangeError`, err)
+	}
+	var charErr *CharError
+	if _, _, err := Parse("  x1v", Lenient); !errors.As(err, &charErr) || charErr.Index != 3 || charErr.Char != '1' {
+		t.Errorf(`Parse("  x1v", Lenient) returned %v, want a CharError at index 3`, err)
+	}
+	if _, _, err := Parse(" \t", Lenient); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Parse of spaces returned %v, want ErrEmpty", err)
+	}
+	if _, _, err := Parse("xiv", ParseOptions{TrimSpace: true}); !errors.As(err, &charErr) {
+		t.Errorf(`Parse("xiv") without IgnoreCase returned %v, want a CharError`, err)
+	}
+}
+
+// TestVinculum parses and formats numerals with barred thousands, in both notations.
+func TestVinculum(t *testing.T) {
+	tests := []struct {
+		n           int
+		overline    string
+		underscores string
+	}{
+		{4000, "I\u0305V\u0305", "_I_V"},
+		{5000, "V\u0305", "_V"},
+		{12345, "X\u0305I\u0305I\u0305CCCXLV", "_X_I_ICCCXLV"},
+		{1994000, "M\u0305C\u0305M\u0305X\u0305C\u0305I\u0305V\u0305", "_M_C_M_X_C_I_V"},
+		{MaxVinculumValue, "M\u0305M\u0305M\u0305C\u0305M\u0305X\u0305C\u0305I\u0305X\u0305CMXCIX", "_M_M_M_C_M_X_C_I_XCMXCIX"},
+	}
+	for _, test := range tests {
+		if got, err := Format(test.n, FormatOptions{Vinculum: true}); got != test.overline || err != nil {
+			t.Errorf("Format(%d) = %q, %v, want %q", test.n, got, err, test.overline)
+		}
+		if got, err := Format(test.n, FormatOptions{Vinculum: true, Underscores: true}); got != test.underscores || err != nil {
+			t.Errorf("Format(%d) with underscores = %q, %v, want %q", test.n, got, err, test.underscores)
+		}
+		for _, numeral := range []string{test.overline, test.underscores} {
+			if got, canonical, err := Parse(numeral, ParseOptions{Vinculum: true}); got != test.n || canonical != numeral || err != nil {
+				t.Errorf("Parse(%q) = %d, %q, %v, want %d", numeral, got, canonical, err, test.n)
+			}
+		}
+	}
+
+	// A macron is accepted for the bar, and Format still writes 3999 and below without one.
+	if got, canonical, err := Parse("V\u0304I", ParseOptions{Vinculum: true}); got != 5001 || canonical != "V\u0305I" || err != nil {
+		t.Errorf("Parse of V with a macron = %d, %q, %v, want 5001", got, canonical, err)
+	}
+	if got, _ := Format(3999, FormatOptions{Vinculum: true}); got != "MMMCMXCIX" {
+		t.Errorf("Format(3999) with a vinculum = %q, want MMMCMXCIX", got)
+	}
+
+	var combination *CombinationError
+	for numeral, index := range map[string]int{"X_V": 1, "_I": 0, "_VM": 2, "_V_V": 2, "V\u0305V\u0305": 3} {
+		if _, _, err := Parse(numeral, ParseOptions{Vinculum: true}); !errors.As(err, &combination) || combination.Index != index {
+			t.Errorf("Parse(%q) returned %v, want a CombinationError at index %d", numeral, err, index)
+		}
+	}
+	var charErr *CharError
+	if _, err := ToInt("_V"); !errors.As(err, &charErr) || charErr.Char != '_' {
+		t.Errorf(`ToInt("_V") without a vinculum returned %v, want a CharError`, err)
+	}
+	if _, _, err := Parse("\u0305V", ParseOptions{Vinculum: true}); !errors.As(err, &charErr) || charErr.Char != overline {
+		t.Errorf("Parse of a bar before any symbol returned %v, want a CharError", err)
+	}
+
+	var rangeErr *RangeError
+	if _, _, err := Parse("_X", ParseOptions{Vinculum: true, MaxValue: 5000}); !errors.As(err, &rangeErr) || rangeErr.Max != 5000 {
+		t.Errorf("Parse of 10000 with a maximum of 5000 returned %v, want a RangeError", err)
+	}
+	if _, err := Format(MaxVinculumValue+1, FormatOptions{Vinculum: true}); !errors.As(err, &rangeErr) || rangeErr.Max != MaxVinculumValue {
+		t.Errorf("Format(%d) returned %v, want a RangeError", MaxVinculumValue+1, err)
+	}
+	if got, canonical, err := Parse("_v_v", ParseOptions{Vinculum: true, AllowNonCanonical: true, IgnoreCase: true}); got != 10000 || canonical != "_X" || err != nil {
+		t.Errorf("lenient Parse of _v_v = %d, %q, %v, want 10000, _X", got, canonical, err)
+	}
+}
+
+// TestUnicode parses numerals written with the Number Forms code points and writes them back.
+func TestUnicode(t *testing.T) {
+	opts := ParseOptions{Unicode: true}
+	tests := []struct {
+		numeral   string
+		want      int
+		canonical string
+	}{
+		{"Ⅻ", 12, "Ⅻ"},
+		{"ⅩⅡ", 12, "Ⅻ"},
+		{"Ⅳ", 4, "Ⅳ"},
+		{"ⅯⅭⅯⅩⅭⅣ", 1994, "ⅯⅭⅯⅩⅭⅠⅤ"},
+		{"ⅯⅯⅩⅩⅣ", 2024, "ⅯⅯⅩⅩⅠⅤ"},
+		{"MCMⅩⅭⅣ", 1994, "ⅯⅭⅯⅩⅭⅠⅤ"},
+		{"XIV", 14, "XIV"},
+	}
+	for _, test := range tests {
+		got, canonical, err := Parse(test.numeral, opts)
+		if got != test.want || canonical != test.canonical || err != nil {
+			t.Errorf("Parse(%q) = %d, %q, %v, want %d, %q, nil", test.numeral, got, canonical, err, test.want, test.canonical)
+		}
+	}
+	if got, err := Format(1994, FormatOptions{Unicode: true}); got != "ⅯⅭⅯⅩⅭⅠⅤ" || err != nil {
+		t.Errorf("Format(1994) in Unicode = %q, %v", got, err)
+	}
+	if got, err := Format(5012, FormatOptions{Unicode: true, Vinculum: true}); got != "Ⅴ\u0305ⅩⅠⅠ" || err != nil {
+		t.Errorf("Format(5012) in Unicode with a vinculum = %q, %v", got, err)
+	}
+	if got, _, err := Parse("Ⅴ\u0305Ⅻ", ParseOptions{Unicode: true, Vinculum: true}); got != 5012 || err != nil {
+		t.Errorf("Parse of V barred and XII in Unicode = %d, %v, want 5012", got, err)
+	}
+
+	var combination *CombinationError
+	if _, _, err := Parse("ⅫⅤ", opts); !errors.As(err, &combination) || combination.Index != 3 {
+		t.Errorf("Parse(ⅫⅤ) returned %v, want a CombinationError at the second code point", err)
+	}
+	var charErr *CharError
+	if _, err := ToInt("Ⅻ"); !errors.As(err, &charErr) || charErr.Char != 'Ⅻ' {
+		t.Errorf("ToInt(Ⅻ) without Unicode returned %v, want a CharError", err)
+	}
+	if _, _, err := Parse("ⅻ", opts); !errors.As(err, &charErr) {
+		t.Errorf("Parse(ⅻ) without IgnoreCase returned %v, want a CharError", err)
+	}
+	if got, canonical, err := Parse(" ⅻ ", Lenient); got != 12 || canonical != "Ⅻ" || err != nil {
+		t.Errorf("lenient Parse(ⅻ) = %d, %q, %v, want 12", got, canonical, err)
+	}
+}
+
+// TestStyle writes and reads numerals in the additive and apostrophus styles.
+func TestStyle(t *testing.T) {
+	tests := []struct {
+		n       int
+		style   Style
+		format  FormatOptions
+		numeral string
+	}{
+		{4, Additive, FormatOptions{}, "IIII"},
+		{9, Additive, FormatOptions{}, "VIIII"},
+		{1994, Additive, FormatOptions{}, "MDCCCCLXXXXIIII"},
+		{3999, Additive, FormatOptions{}, "MMMDCCCCLXXXXVIIII"},
+		{4, Additive, FormatOptions{Unicode: true}, "ⅠⅠⅠⅠ"},
+		{8, Additive, FormatOptions{Unicode: true}, "Ⅷ"},
+		{9000, Additive, FormatOptions{Vinculum: true, Underscores: true}, "_V_I_I_I_I"},
+		{1994, Apostrophus, FormatOptions{}, "CIↃCCIↃXCIV"},
+		{1500, Apostrophus, FormatOptions{}, "CIↃIↃ"},
+		{1500, Apostrophus, FormatOptions{Unicode: true}, "ⅭⅠↃⅠↃ"},
+		{6500, Apostrophus, FormatOptions{Vinculum: true, Underscores: true}, "_V_IIↃ"},
+		{14, Apostrophus, FormatOptions{}, "XIV"},
+		{400, Apostrophus, FormatOptions{}, "CCCC"},
+		{900, Apostrophus, FormatOptions{}, "CCIↃ"},
+	}
+	for _, test := range tests {
+		test.format.Style = test.style
+		if got, err := Format(test.n, test.format); got != test.numeral || err != nil {
+			t.Errorf("Format(%d, %+v) = %q, %v, want %q", test.n, test.format, got, err, test.numeral)
+		}
+		opts := ParseOptions{Style: test.style, Unicode: test.format.Unicode, Vinculum: test.format.Vinculum}
+		if got, canonical, err := Parse(test.numeral, opts); got != test.n || canonical != test.numeral || err != nil {
+			t.Errorf("Parse(%q, %+v) = %d, %q, %v, want %d", test.numeral, opts, got, canonical, err, test.n)
+		}
+	}
+
+	// The apostrophus is read from a closing parenthesis too, and in lower case with IgnoreCase.
+	for _, numeral := range []string{"CI)I)", "ci)i)", "ciↄiↄ"} {
+		opts := ParseOptions{Style: Apostrophus, IgnoreCase: true}
+		if got, canonical, err := Parse(numeral, opts); got != 1500 || canonical != "CIↃIↃ" || err != nil {
+			t.Errorf("Parse(%q) in apostrophus = %d, %q, %v, want 1500, CIↃIↃ", numeral, got, canonical, err)
+		}
+	}
+
+	var combination *CombinationError
+	errorTests := []struct {
+		numeral string
+		style   Style
+		index   int
+	}{
+		{"IV", Additive, 1},
+		{"XIIIII", Additive, 1},
+		{"VIIIII", Additive, 0},
+		{"MCM", Additive, 1},
+		{"IIII", Standard, 3},
+		{"MD", Apostrophus, 0},
+		{"CIↃD", Apostrophus, 5},
+		{"IↃIↃ", Apostrophus, 0},
+		{"CD", Apostrophus, 1},
+	}
+	for _, test := range errorTests {
+		if _, _, err := Parse(test.numeral, ParseOptions{Style: test.style}); !errors.As(err, &combination) || combination.Index != test.index {
+			t.Errorf("Parse(%q) in %v returned %v, want a CombinationError at index %d", test.numeral, test.style, err, test.index)
+		}
+	}
+	if got, canonical, err := Parse("iv", ParseOptions{Style: Additive, AllowNonCanonical: true, IgnoreCase: true}); got != 4 || canonical != "IIII" || err != nil {
+		t.Errorf("lenient Parse(iv) in additive = %d, %q, %v, want 4, IIII", got, canonical, err)
+	}
+	var rangeErr *RangeError
+	if _, _, err := Parse("MMMM", ParseOptions{Style: Additive}); !errors.As(err, &rangeErr) {
+		t.Errorf("Parse(MMMM) in additive returned %v, want a RangeError", err)
+	}
+
+	for _, style := range []Style{Standard, Additive, Apostrophus} {
+		if got, err := ParseStyle(style.String()); got != style || err != nil {
+			t.Errorf("ParseStyle(%q) = %v, %v, want %v", style.String(), got, err, style)
+		}
+	}
+	if _, err := ParseStyle("etruscan"); err == nil {
+		t.Error("ParseStyle(etruscan) returned no error")
+	}
+}
+
+// TestLength checks the default length limit is the longest numeral in range, and that MaxLength overrides it.
+func TestLength(t *testing.T) {
+	tests := []struct {
+		opts ParseOptions
+		want int
+	}{
+		{ParseOptions{}, MaxLength},
+		{ParseOptions{MaxValue: 100}, 8},   // LXXXVIII
+		{ParseOptions{MaxValue: 3000}, 14}, // MMDCCCLXXXVIII
+		{ParseOptions{Vinculum: true}, 27}, // MMMDCCCLXXXVIII barred, then DCCCLXXXVIII
+		{ParseOptions{Vinculum: true, MaxValue: 5000}, MaxLength},
+		{ParseOptions{Vinculum: true, MaxValue: 9000}, 16}, // VIII barred, then DCCCLXXXVIII
+		{ParseOptions{Style: Additive}, 18},                // MMMDCCCCLXXXXVIIII
+		{ParseOptions{Style: Apostrophus}, MaxLength},
+		{ParseOptions{MaxLength: 5}, 5},
+		{Lenient, 0},
+		{ParseOptions{AllowNonCanonical: true, MaxLength: 20}, 20},
+	}
+	for _, test := range tests {
+		if got := test.opts.Longest(); got != test.want {
+			t.Errorf("%+v.Longest() = %d, want %d", test.opts, got, test.want)
+		}
+	}
+
+	// Check the limit for every value up to 20000 against the numerals Format writes.
+	for _, style := range []Style{Standard, Additive} {
+		longest := 0
+		for n := MinValue; n <= 20000; n++ {
+			numeral, err := Format(n, FormatOptions{Vinculum: true, Underscores: true, Style: style})
+			if err != nil {
+				t.Fatal(err)
+			}
+			longest = max(longest, len(numeral)-strings.Count(numeral, "_"))
+			if got := (ParseOptions{Vinculum: true, MaxValue: n, Style: style}).Longest(); got != longest {
+				t.Fatalf("the longest %v numeral up to %d has %d symbols, but Longest returned %d", style, n, longest, got)
+			}
+		}
+	}
+
+	var lengthErr *LengthError
+	errorTests := []struct {
+		numeral string
+		opts    ParseOptions
+		max     int
+	}{
+		{"MMXXIV", ParseOptions{MaxLength: 5}, 5},
+		{"LXXXVIIII", ParseOptions{MaxValue: 100}, 8},
+		{strings.Repeat("I", 1000), Lenient, 0},
+		{strings.Repeat("I", 1000), ParseOptions{AllowNonCanonical: true, MaxLength: 30}, 30},
+	}
+	for _, test := range errorTests {
+		_, _, err := Parse(test.numeral, test.opts)
+		if test.max == 0 {
+			if errors.As(err, &lengthErr) {
+				t.Errorf("Parse of %d symbols with %+v returned %v, want no length limit", len(test.numeral), test.opts, err)
+			}
+			continue
+		}
+		if !errors.As(err, &lengthErr) || lengthErr.Max != test.max || !errors.Is(err, ErrTooLong) {
+			t.Errorf("Parse(%.20q, %+v) returned %v, want a LengthError with a limit of %d", test.numeral, test.opts, err, test.max)
+		}
+	}
+	if got, _, err := Parse("MMMDCCCLXXXVIII", ParseOptions{MaxLength: MaxLength}); got != 3888 || err != nil {
+		t.Errorf("Parse of the longest numeral with a limit of its length = %d, %v, want 3888", got, err)
+	}
+}
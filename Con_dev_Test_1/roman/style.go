@@ -0,0 +1,158 @@
+// Ronan Green
+// C00270395
+
+package roman
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Style chooses which of the historical ways of writing numerals Parse and Format use.
+type Style int
+
+// The numeral styles.
+const (
+	Standard    Style = iota // The modern canonical numerals, with the subtractive pairs IV, IX, XL, XC, CD and CM.
+	Additive                 // Every symbol added, as on clock faces and in older inscriptions: IIII, VIIII, XXXX, LXXXX, CCCC and DCCCC.
+	Apostrophus              // The standard numerals with D written IↃ and M written CIↃ, as in early printed books.
+)
+
+// styleNames lists the names accepted by ParseStyle, indexed by Style.
+var styleNames = [...]string{"standard", "additive", "apostrophus"}
+
+// String returns the name of the style, as accepted by ParseStyle.
+func (s Style) String() string {
+	if s >= 0 && int(s) < len(styleNames) {
+		return styleNames[s]
+	}
+	return fmt.Sprintf("Style(%d)", int(s))
+}
+
+// ParseStyle returns the style with the given name.
+func ParseStyle(name string) (Style, error) {
+	for i, n := range styleNames {
+		if n == name {
+			return Style(i), nil
+		}
+	}
+	return 0, fmt.Errorf("roman: unknown style %q (want one of %s)", name, strings.Join(styleNames[:], ", "))
+}
+
+// The reversed C of apostrophus notation. Parse also accepts a closing parenthesis, as it is often typed.
+const (
+	reversedC      = 'Ↄ' // ROMAN NUMERAL REVERSED ONE HUNDRED
+	smallReversedC = 'ↄ' // LATIN SMALL LETTER REVERSED C, accepted with IgnoreCase.
+)
+
+// apostrophusForms holds the symbols written in apostrophus, longest first so CIↃ is not read as C followed by IↃ.
+var apostrophusForms = [...]struct {
+	letter byte
+	text   string
+}{
+	{'M', "CIↃ"},
+	{'D', "IↃ"},
+}
+
+// apostrophusReplacer writes the D and M of a numeral in apostrophus.
+var apostrophusReplacer = strings.NewReplacer("M", "CIↃ", "D", "IↃ")
+
+// spelling returns the symbols of the numeral for n, from 0 to MaxValue, in the style and without a vinculum, with
+// D and M as letters. Apostrophus writes 400 as CCCC, since CD would be written CIↃ, the same as M.
+func (s Style) spelling(n int) string {
+	switch s {
+	case Additive:
+		return additive(n)
+	case Apostrophus:
+		return strings.Replace(plain(n), "CD", "CCCC", 1)
+	}
+	return plain(n)
+}
+
+// numeral returns the numeral for n, from 0 to MaxValue, in the style and without a vinculum.
+func (s Style) numeral(n int) string {
+	if s == Apostrophus {
+		return apostrophusReplacer.Replace(s.spelling(n))
+	}
+	return s.spelling(n)
+}
+
+// thousands returns the style the barred thousands of a numeral are written in: Apostrophus only writes the D and M
+// below the bar.
+func (s Style) thousands() Style {
+	if s == Apostrophus {
+		return Standard
+	}
+	return s
+}
+
+// additive returns the numeral for n, from 0 to MaxValue, without subtractive pairs: each decimal place is written as
+// its five, if the digit is 5 or more, followed by up to four ones.
+func additive(n int) string {
+	var b strings.Builder
+	for _, place := range places {
+		digit := n / place.value % 10
+		if digit >= 5 {
+			b.WriteString(place.digits[5])
+			digit -= 5
+		}
+		b.WriteString(strings.Repeat(place.digits[1], digit))
+	}
+	return b.String()
+}
+
+// apostrophus reads a D or M written in apostrophus at i of the input, such as CIↃ, CI) or, with Unicode, ⅭⅠↃ.
+// It returns the letter and the byte offset just after it, or false if there is none.
+func (t *Tokenizer) apostrophus(i int) (byte, int, bool) {
+	for _, form := range apostrophusForms {
+		if end, ok := t.match(i, form.text); ok {
+			return form.letter, end, true
+		}
+	}
+	return 0, 0, false
+}
+
+// match reports whether text, a numeral in upper case with reversed Cs, is written at i of the input as the tokenizer
+// accepts it, and returns the byte offset just after it.
+func (t *Tokenizer) match(i int, text string) (int, bool) {
+	for _, want := range text {
+		if i >= len(t.input) {
+			return 0, false
+		}
+		if want == reversedC {
+			c, size := utf8.DecodeRuneInString(t.input[i:])
+			if c != reversedC && c != ')' && !(t.opts.IgnoreCase && c == smallReversedC) {
+				return 0, false
+			}
+			i += size
+			continue
+		}
+		l, size, ok := t.letters(i)
+		if !ok || l != string(want) {
+			return 0, false
+		}
+		i += size
+	}
+	return i, true
+}
+
+// outOfStyle checks symbols are written as the style writes the numeral for value, from MinValue to
+// MaxVinculumValue, with every unbarred D and M in apostrophus if the style is Apostrophus. It returns the position in
+// symbols of the first symbol written otherwise, or -1 if none is.
+func outOfStyle(symbols []symbol, value int, style Style) int {
+	want, barred := "", 0
+	if value > MaxValue {
+		want = style.thousands().spelling(value / 1000)
+		barred = len(want)
+		value %= 1000
+	}
+	want += style.spelling(value)
+	for i, s := range symbols {
+		apostrophus := style == Apostrophus && !s.barred && (s.letter == 'D' || s.letter == 'M')
+		if i >= len(want) || s.letter != want[i] || s.barred != (i < barred) || s.apostrophus != apostrophus {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,163 @@
+// Ronan Green
+// C00270395
+
+package roman
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Token is one term of a numeral: a symbol, or a subtractive pair of a symbol before a larger one such as CM.
+// The value of a numeral is the sum of its tokens.
+type Token struct {
+	Symbol string // The symbols in upper case, such as "CM", each barred symbol preceded by "_", such as "_I_V".
+	Value  int    // What the token adds to the numeral, such as 900 for CM.
+	Pos    int    // Byte offset of the token in the numeral.
+	Text   string // The token as written, such as "cm", "V̅" or "Ⅻ". A code point standing for several symbols is the
+	// text of every token it is part of.
+
+	symbols []symbol // The one or two symbols of the token.
+}
+
+// Subtractive reports whether the token is a pair whose first symbol is subtracted from the second.
+func (t Token) Subtractive() bool {
+	return len(t.symbols) == 2
+}
+
+// Tokenizer splits a numeral into tokens one at a time, so other tools, such as syntax highlighters or step-by-step
+// explanations, can work through a numeral as Parse does. Use it like a bufio.Scanner:
+//
+//	t := roman.NewTokenizer("MCMXCIV", roman.ParseOptions{})
+//	for t.Next() {
+//		fmt.Println(t.Token().Symbol, t.Token().Value) // M 1000, CM 900, XC 90, IV 4
+//	}
+//	if err := t.Err(); err != nil { ... }
+//
+// The tokenizer only checks that every character is a symbol opts accepts; whether the tokens are in a canonical
+// order is up to Parse.
+type Tokenizer struct {
+	numeral string       // The numeral passed to NewTokenizer.
+	opts    ParseOptions // What symbols to accept.
+	input   string       // The numeral, trimmed if opts says to.
+	offset  int          // Byte offset of input in numeral.
+	pos     int          // Byte offset in input of the next character to read.
+	pending []symbol     // Symbols read but not yet returned in a token.
+	style   FormatOptions
+	token   Token
+	err     error
+}
+
+// symbol is one symbol of a numeral being parsed.
+type symbol struct {
+	letter      byte // The symbol in upper case, such as 'X'.
+	barred      bool // Whether it has a vinculum, multiplying it by a thousand.
+	apostrophus bool // Whether it is a D or M written in apostrophus, such as CIↃ.
+	index       int  // Byte offset of the symbol in the numeral passed to Parse.
+	end         int  // Byte offset just after the symbol and its bar.
+}
+
+// NewTokenizer returns a tokenizer for numeral, accepting the symbols opts does and trimming it if opts says to.
+func NewTokenizer(numeral string, opts ParseOptions) *Tokenizer {
+	t := &Tokenizer{numeral: numeral, opts: opts, input: numeral}
+	if opts.TrimSpace {
+		t.input = strings.TrimLeftFunc(numeral, unicode.IsSpace)
+		t.offset = len(numeral) - len(t.input)
+		t.input = strings.TrimRightFunc(t.input, unicode.IsSpace)
+	}
+	return t
+}
+
+// Next advances to the next token, which is then available from Token. It returns false at the end of the numeral
+// or, once the tokens before it have been returned, at the first character that is not a symbol, which Err then
+// returns as a *CharError.
+func (t *Tokenizer) Next() bool {
+	for len(t.pending) < 2 && t.pos < len(t.input) && t.err == nil {
+		t.err = t.read()
+	}
+	if len(t.pending) == 0 {
+		return false
+	}
+
+	n := 1
+	if len(t.pending) > 1 && t.pending[0].value() < t.pending[1].value() {
+		n = 2
+	}
+	symbols := t.pending[:n:n]
+	t.pending = t.pending[n:]
+
+	t.token = Token{Value: symbols[n-1].value(), Pos: symbols[0].index, Text: t.numeral[symbols[0].index:symbols[n-1].end], symbols: symbols}
+	if n == 2 {
+		t.token.Value -= symbols[0].value()
+	}
+	for _, s := range symbols {
+		if s.barred {
+			t.token.Symbol += "_"
+		}
+		t.token.Symbol += string(s.letter)
+	}
+	return true
+}
+
+// Token returns the token Next advanced to.
+func (t *Tokenizer) Token() Token {
+	return t.token
+}
+
+// Err returns the *CharError that stopped the tokenizer, or nil if it reached the end of the numeral.
+func (t *Tokenizer) Err() error {
+	return t.err
+}
+
+// letters returns the symbols written by the character at i of the input, in upper case, and the character's size.
+// A Unicode code point may stand for several symbols.
+func (t *Tokenizer) letters(i int) (string, int, bool) {
+	c, size := utf8.DecodeRuneInString(t.input[i:])
+	if t.opts.IgnoreCase && 'a' <= c && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	if c < utf8.RuneSelf && symbolValues[c] > 0 {
+		return string(c), size, true
+	}
+	if form, ok := unicodeForm(c, t.opts.IgnoreCase); ok && t.opts.Unicode {
+		t.style.Unicode = true
+		return form, size, true
+	}
+	return "", size, false
+}
+
+// read reads the symbols written by the next character, along with its bar, into pending. With Apostrophus, a D or
+// M written in apostrophus is read as one symbol.
+func (t *Tokenizer) read() error {
+	i := t.pos
+	c, _ := utf8.DecodeRuneInString(t.input[i:])
+	barred := false
+	if c == '_' && t.opts.Vinculum && i+1 < len(t.input) {
+		if l, _, ok := t.letters(i + 1); ok && len(l) == 1 {
+			barred = true
+			t.style.Underscores = true
+			t.pos++
+		}
+	}
+	if t.opts.Style == Apostrophus && !barred {
+		if letter, end, ok := t.apostrophus(t.pos); ok {
+			t.pos = end
+			t.pending = append(t.pending, symbol{letter: letter, apostrophus: true, index: t.offset + i, end: t.offset + t.pos})
+			return nil
+		}
+	}
+	l, size, ok := t.letters(t.pos)
+	if !ok {
+		return &CharError{Numeral: t.numeral, Index: t.offset + i, Char: c}
+	}
+	t.pos += size
+	if bar, size := utf8.DecodeRuneInString(t.input[t.pos:]); (bar == overline || bar == macron) && t.opts.Vinculum && !barred {
+		barred = true // A bar over Ⅻ bars all three symbols.
+		t.pos += size
+	}
+	for j := 0; j < len(l); j++ {
+		t.pending = append(t.pending, symbol{letter: l[j], barred: barred, index: t.offset + i, end: t.offset + t.pos})
+	}
+	return nil
+}
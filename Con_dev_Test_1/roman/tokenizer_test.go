@@ -0,0 +1,108 @@
+package roman
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// tokens returns the tokens of numeral, without their symbols, and the tokenizer's error.
+func tokens(numeral string, opts ParseOptions) ([]Token, error) {
+	var out []Token
+	t := NewTokenizer(numeral, opts)
+	for t.Next() {
+		tok := t.Token()
+		tok.symbols = nil
+		out = append(out, tok)
+	}
+	return out, t.Err()
+}
+
+// TestTokenizer splits numerals in each notation into tokens.
+func TestTokenizer(t *testing.T) {
+	tests := []struct {
+		numeral string
+		opts    ParseOptions
+		want    []Token
+	}{
+		{"MCMXCIV", ParseOptions{}, []Token{
+			{Symbol: "M", Value: 1000, Pos: 0, Text: "M"},
+			{Symbol: "CM", Value: 900, Pos: 1, Text: "CM"},
+			{Symbol: "XC", Value: 90, Pos: 3, Text: "XC"},
+			{Symbol: "IV", Value: 4, Pos: 5, Text: "IV"},
+		}},
+		{" xiv ", Lenient, []Token{
+			{Symbol: "X", Value: 10, Pos: 1, Text: "x"},
+			{Symbol: "IV", Value: 4, Pos: 2, Text: "iv"},
+		}},
+		{"Ⅻ", ParseOptions{Unicode: true}, []Token{
+			{Symbol: "X", Value: 10, Pos: 0, Text: "Ⅻ"},
+			{Symbol: "I", Value: 1, Pos: 0, Text: "Ⅻ"},
+			{Symbol: "I", Value: 1, Pos: 0, Text: "Ⅻ"},
+		}},
+		{"_I_VX", ParseOptions{Vinculum: true}, []Token{
+			{Symbol: "_I_V", Value: 4000, Pos: 0, Text: "_I_V"},
+			{Symbol: "X", Value: 10, Pos: 4, Text: "X"},
+		}},
+		{"V̅IX", ParseOptions{Vinculum: true}, []Token{
+			{Symbol: "_V", Value: 5000, Pos: 0, Text: "V̅"},
+			{Symbol: "IX", Value: 9, Pos: 3, Text: "IX"},
+		}},
+		{"IIII", ParseOptions{}, []Token{
+			{Symbol: "I", Value: 1, Pos: 0, Text: "I"},
+			{Symbol: "I", Value: 1, Pos: 1, Text: "I"},
+			{Symbol: "I", Value: 1, Pos: 2, Text: "I"},
+			{Symbol: "I", Value: 1, Pos: 3, Text: "I"},
+		}},
+	}
+	for _, test := range tests {
+		got, err := tokens(test.numeral, test.opts)
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("the tokens of %q are %+v, %v, want %+v", test.numeral, got, err, test.want)
+		}
+	}
+
+	// The tokens before a bad character are still returned.
+	got, err := tokens("XIZ", ParseOptions{})
+	var charErr *CharError
+	if len(got) != 2 || !errors.As(err, &charErr) || charErr.Index != 2 {
+		t.Errorf(`the tokens of "XIZ" are %+v, %v, want X, I and a CharError at index 2`, got, err)
+	}
+}
+
+// TestParseAddsTokens checks Parse's value is the sum of the tokens for every canonical numeral.
+func TestParseAddsTokens(t *testing.T) {
+	for n := MinValue; n <= MaxValue; n++ {
+		numeral, _ := FromInt(n)
+		got, _ := tokens(numeral, ParseOptions{})
+		sum := 0
+		for _, tok := range got {
+			sum += tok.Value
+		}
+		if sum != n {
+			t.Fatalf("the tokens of %q add up to %d, want %d", numeral, sum, n)
+		}
+	}
+}
+
+// TestBreakdown checks the explanation of numerals with and without subtractive pairs.
+func TestBreakdown(t *testing.T) {
+	tests := map[string]string{
+		"MCMXCIV": "M=1000, CM=900 (1000-100), XC=90 (100-10), IV=4 (5-1) → 1994",
+		"CMXCIV":  "CM=900 (1000-100), XC=90 (100-10), IV=4 (5-1) → 994",
+		"XVIII":   "X=10, V=5, I=1, I=1, I=1 → 18",
+		"_I_VX":   "_I_V=4000 (5000-1000), X=10 → 4010",
+	}
+	for numeral, want := range tests {
+		b, err := ParseBreakdown(numeral, ParseOptions{Vinculum: true})
+		if got := b.String(); got != want || err != nil {
+			t.Errorf("the breakdown of %q is %q, %v, want %q", numeral, got, err, want)
+		}
+	}
+	if b, err := ParseBreakdown("iiii", Lenient); b.String() != "I=1, I=1, I=1, I=1 → 4" || b.Canonical != "IV" || err != nil {
+		t.Errorf(`the lenient breakdown of "iiii" is %q, %q, %v`, b, b.Canonical, err)
+	}
+	if _, err := ParseBreakdown("IIII", ParseOptions{}); err == nil {
+		t.Error(`ParseBreakdown("IIII") accepted a non-canonical numeral`)
+	}
+}
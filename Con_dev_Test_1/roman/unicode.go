@@ -0,0 +1,45 @@
+// Ronan Green
+// C00270395
+
+package roman
+
+import "strings"
+
+// The first code points of the Roman numerals in the Unicode Number Forms block, in upper and lower case.
+const (
+	upperForms = 'Ⅰ' // ROMAN NUMERAL ONE
+	lowerForms = 'ⅰ' // SMALL ROMAN NUMERAL ONE
+)
+
+// unicodeForms holds the symbols each Roman numeral code point stands for, from upperForms or lowerForms on.
+// The first twelve are the numbers one to twelve, as on a clock face, and the rest the symbols L, C, D and M.
+var unicodeForms = [...]string{"I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX", "X", "XI", "XII", "L", "C", "D", "M"}
+
+// unicodeForm returns the symbols c stands for, if it is one of the Roman numerals of the Number Forms block.
+// The lower case numerals are only accepted with ignoreCase.
+func unicodeForm(c rune, ignoreCase bool) (string, bool) {
+	switch {
+	case c >= upperForms && c < upperForms+rune(len(unicodeForms)):
+		return unicodeForms[c-upperForms], true
+	case ignoreCase && c >= lowerForms && c < lowerForms+rune(len(unicodeForms)):
+		return unicodeForms[c-lowerForms], true
+	}
+	return "", false
+}
+
+// toUnicode writes numeral, the numeral for n, with the code points of the Number Forms block: one code point for
+// 1 to 12 written as usual, and one for each symbol otherwise, so the additive IIII is ⅠⅠⅠⅠ. Bars and the reversed C
+// of apostrophus are left as they are.
+func toUnicode(numeral string, n int) string {
+	if n <= 12 && numeral == plain(n) {
+		return string(upperForms + rune(n-1))
+	}
+	return strings.Map(func(c rune) rune {
+		for i, form := range unicodeForms {
+			if form == string(c) {
+				return upperForms + rune(i)
+			}
+		}
+		return c
+	}, numeral)
+}
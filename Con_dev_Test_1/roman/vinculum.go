@@ -0,0 +1,77 @@
+// Ronan Green
+// C00270395
+
+package roman
+
+import "strings"
+
+// MaxVinculumValue is the largest value that can be written with a vinculum: 3999 thousands, barred, and 999.
+const MaxVinculumValue = MaxValue*1000 + 999
+
+// The combining characters that draw a bar over the symbol before them. Format writes overline; Parse accepts both.
+const (
+	overline = '\u0305' // COMBINING OVERLINE
+	macron   = '\u0304' // COMBINING MACRON
+)
+
+// FormatOptions controls how Format writes a numeral. The zero value writes canonical numerals up to 3999, as
+// FromInt does.
+type FormatOptions struct {
+	Vinculum    bool  // Write values from 4000 up to MaxVinculumValue with their thousands barred, such as V̄ for 5000.
+	Underscores bool  // Write a bar as an underscore before the symbol, such as "_V", instead of an overline over it.
+	Unicode     bool  // Write the symbols as Unicode code points, such as ⅯⅭⅯⅩⅭⅠⅤ, and 1 to 12 as one code point, such as Ⅻ.
+	MaxValue    int   // The largest value allowed, if above 0 and below the largest that can be written.
+	Style       Style // How to write the numeral; the zero value is Standard.
+}
+
+// Format converts a value to its canonical Roman numeral. With a vinculum, values from 4000 up are written as the
+// numeral for their thousands, barred, followed by the numeral for the rest, so 1994000 is M̄C̄M̄X̄C̄ĪV̄, and values
+// below 4000 are written as without one. The numeral is written in opts.Style, so 1994 is MDCCCCLXXXXIIII with
+// Additive and CIↃCCIↃXCIV with Apostrophus.
+// It returns a *RangeError if n is below MinValue or above the maximum.
+func Format(n int, opts FormatOptions) (string, error) {
+	limit := maxValue(opts.Vinculum, opts.MaxValue)
+	if n < MinValue || n > limit {
+		return "", &RangeError{Value: n, Max: limit}
+	}
+	if n <= MaxValue {
+		if opts.Unicode {
+			return toUnicode(opts.Style.numeral(n), n), nil
+		}
+		return opts.Style.numeral(n), nil
+	}
+
+	var b strings.Builder
+	for _, c := range opts.Style.thousands().numeral(n / 1000) {
+		if opts.Underscores {
+			b.WriteByte('_')
+			b.WriteRune(c)
+		} else {
+			b.WriteRune(c)
+			b.WriteRune(overline)
+		}
+	}
+	b.WriteString(opts.Style.numeral(n % 1000))
+	if opts.Unicode {
+		return toUnicode(b.String(), n), nil
+	}
+	return b.String(), nil
+}
+
+// Max returns the largest value Format accepts with these options.
+func (o FormatOptions) Max() int {
+	return maxValue(o.Vinculum, o.MaxValue)
+}
+
+// maxValue returns the largest value allowed: max if it is set and can be written, and otherwise the largest value
+// that can be written with or without a vinculum.
+func maxValue(vinculum bool, max int) int {
+	limit := MaxValue
+	if vinculum {
+		limit = MaxVinculumValue
+	}
+	if max > 0 && max < limit {
+		return max
+	}
+	return limit
+}
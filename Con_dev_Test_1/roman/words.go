@@ -0,0 +1,107 @@
+// Ronan Green
+// C00270395
+
+package roman
+
+import (
+	"strconv"
+	"strings"
+)
+
+// smallWords holds the words for the numbers below twenty.
+var smallWords = [...]string{
+	"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+	"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+}
+
+// tensWords holds the words for the tens, from twenty up.
+var tensWords = [...]string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+
+// scaleWords holds the words for each power of a thousand.
+var scaleWords = [...]string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion"}
+
+// Words writes n in English words, such as "one thousand nine hundred eighty-four" for 1984, so the value of a
+// numeral can be read out as well as written in digits.
+func Words(n int) string {
+	if n < 0 {
+		return "minus " + unsignedWords(uint64(-(n+1))+1) // Negating n + 1 cannot overflow, even for the smallest int.
+	}
+	return unsignedWords(uint64(n))
+}
+
+// unsignedWords writes n in words.
+func unsignedWords(n uint64) string {
+	if n == 0 {
+		return smallWords[0]
+	}
+	var groups []string
+	for scale := 0; n > 0; scale++ {
+		if group := int(n % 1000); group > 0 {
+			words := hundredsWords(group)
+			if scale > 0 {
+				words += " " + scaleWords[scale]
+			}
+			groups = append([]string{words}, groups...)
+		}
+		n /= 1000
+	}
+	return strings.Join(groups, " ")
+}
+
+// hundredsWords writes n, from 1 to 999, in words.
+func hundredsWords(n int) string {
+	var words []string
+	if n >= 100 {
+		words = append(words, smallWords[n/100], "hundred")
+		n %= 100
+	}
+	switch {
+	case n == 0:
+	case n < 20:
+		words = append(words, smallWords[n])
+	case n%10 == 0:
+		words = append(words, tensWords[n/10])
+	default:
+		words = append(words, tensWords[n/10]+"-"+smallWords[n%10])
+	}
+	return strings.Join(words, " ")
+}
+
+// irregularOrdinals holds the ordinals of the number words that do not just take "th".
+var irregularOrdinals = map[string]string{
+	"one": "first", "two": "second", "three": "third", "five": "fifth", "eight": "eighth", "nine": "ninth", "twelve": "twelfth",
+}
+
+// Ordinal writes n as an ordinal in English words, such as "twentieth" for 20 or "one thousand nine hundred
+// eighty-fourth" for 1984.
+func Ordinal(n int) string {
+	words := Words(n)
+	last := strings.LastIndexAny(words, " -") + 1
+	word := words[last:]
+	switch ordinal, ok := irregularOrdinals[word]; {
+	case ok:
+		word = ordinal
+	case strings.HasSuffix(word, "y"):
+		word = strings.TrimSuffix(word, "y") + "ieth"
+	default:
+		word += "th"
+	}
+	return words[:last] + word
+}
+
+// Grouped writes n in digits with a comma between each group of three, such as "1,984" for 1984.
+func Grouped(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return sign + b.String()
+}
@@ -0,0 +1,64 @@
+package roman
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestWords checks the words, ordinals and grouped digits for numbers around each irregular case and scale.
+func TestWords(t *testing.T) {
+	tests := []struct {
+		n       int
+		words   string
+		ordinal string
+		grouped string
+	}{
+		{0, "zero", "zeroth", "0"},
+		{1, "one", "first", "1"},
+		{12, "twelve", "twelfth", "12"},
+		{15, "fifteen", "fifteenth", "15"},
+		{20, "twenty", "twentieth", "20"},
+		{42, "forty-two", "forty-second", "42"},
+		{100, "one hundred", "one hundredth", "100"},
+		{999, "nine hundred ninety-nine", "nine hundred ninety-ninth", "999"},
+		{1984, "one thousand nine hundred eighty-four", "one thousand nine hundred eighty-fourth", "1,984"},
+		{2003, "two thousand three", "two thousand third", "2,003"},
+		{1000000, "one million", "one millionth", "1,000,000"},
+		{3999999, "three million nine hundred ninety-nine thousand nine hundred ninety-nine",
+			"three million nine hundred ninety-nine thousand nine hundred ninety-ninth", "3,999,999"},
+		{-1234, "minus one thousand two hundred thirty-four", "minus one thousand two hundred thirty-fourth", "-1,234"},
+	}
+	for _, test := range tests {
+		if got := Words(test.n); got != test.words {
+			t.Errorf("Words(%d) = %q, want %q", test.n, got, test.words)
+		}
+		if got := Ordinal(test.n); got != test.ordinal {
+			t.Errorf("Ordinal(%d) = %q, want %q", test.n, got, test.ordinal)
+		}
+		if got := Grouped(test.n); got != test.grouped {
+			t.Errorf("Grouped(%d) = %q, want %q", test.n, got, test.grouped)
+		}
+	}
+	if got := Words(math.MinInt); !strings.HasPrefix(got, "minus nine quintillion") {
+		t.Errorf("Words(math.MinInt) = %q", got)
+	}
+}
+
+// TestCentury checks the centuries either side of their boundaries.
+func TestCentury(t *testing.T) {
+	for year, want := range map[int]int{1: 1, 100: 1, 101: 2, 1900: 19, 1901: 20, 1984: 20, 2000: 20, 2001: 21, 3999: 40} {
+		if got, err := Century(year); got != want || err != nil {
+			t.Errorf("Century(%d) = %d, %v, want %d", year, got, err, want)
+		}
+	}
+	if _, err := Century(0); err == nil {
+		t.Error("Century(0) returned no error")
+	}
+	if got, err := CenturyName(1984); got != "twentieth century" || err != nil {
+		t.Errorf("CenturyName(1984) = %q, %v", got, err)
+	}
+	if got, _ := CenturyName(2001); got != "twenty-first century" {
+		t.Errorf("CenturyName(2001) = %q", got)
+	}
+}
@@ -0,0 +1,26 @@
+// Ronan Green
+// C00270395
+
+package roman
+
+import "fmt"
+
+// Century returns the century year falls in, counting from the first century AD as the years 1 to 100, so 1984
+// and 2000 are in the 20th century and 2001 in the 21st. Year 0 does not exist, so it returns an error for years
+// below 1.
+func Century(year int) (int, error) {
+	if year < 1 {
+		return 0, fmt.Errorf("roman: there is no year %d; years AD start at 1", year)
+	}
+	return (year + 99) / 100, nil
+}
+
+// CenturyName names the century year falls in, such as "twentieth century" for 1984, as a Roman numeral date on a
+// building or in the credits of a film is often read.
+func CenturyName(year int) (string, error) {
+	century, err := Century(year)
+	if err != nil {
+		return "", err
+	}
+	return Ordinal(century) + " century", nil
+}
@@ -0,0 +1,105 @@
+// Ronan Green
+// C00270395
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// result is the JSON body of a successful conversion.
+type result struct {
+	Input   string `json:"input"`
+	Value   int    `json:"value"`
+	Numeral string `json:"numeral"`
+}
+
+// handler returns the service's routes, converting with c's options in both directions whatever its direction:
+//
+//	GET /roman/{numeral}   the value of a Roman numeral, such as /roman/MCMXCIV
+//	GET /arabic/{number}   the Roman numeral for a number, such as /arabic/1994
+//
+// Both return {"input": ..., "value": ..., "numeral": ...}, or {"error": "..."} with status 400 if the input cannot
+// be converted.
+func handler(c converter) http.Handler {
+	toInt, toRoman := c, c
+	toInt.toRoman, toRoman.toRoman = false, true
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /roman/{numeral}", func(w http.ResponseWriter, r *http.Request) {
+		writeConversion(w, toInt, r.PathValue("numeral"))
+	})
+	mux.HandleFunc("GET /arabic/{number}", func(w http.ResponseWriter, r *http.Request) {
+		writeConversion(w, toRoman, r.PathValue("number"))
+	})
+	return mux
+}
+
+// writeConversion converts input with c and writes the result or error as JSON.
+func writeConversion(w http.ResponseWriter, c converter, input string) {
+	w.Header().Set("Content-Type", "application/json")
+	value, numeral, err := c.convert(input)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(result{Input: input, Value: value, Numeral: numeral})
+}
+
+// statusRecorder remembers the status written through it, for the request log.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests logs the method, path, status and duration of every request to next.
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		log.Printf("%s %s %d %v", r.Method, r.URL.Path, rec.status, time.Since(start))
+	})
+}
+
+// serve runs the conversion service on addr until Ctrl+C or SIGTERM, then waits up to five seconds for the requests
+// being answered to finish before returning.
+func serve(c converter, addr string) error {
+	server := &http.Server{Addr: addr, Handler: logRequests(handler(c))}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.ListenAndServe() }()
+	log.Printf("serving conversions on %s", addr)
+
+	select {
+	case err := <-errs:
+		return err // The address could not be listened on.
+	case <-ctx.Done():
+	}
+	log.Print("shutting down")
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdown); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
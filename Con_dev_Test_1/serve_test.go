@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Con_dev_Test_1/roman"
+)
+
+// TestHandler converts in both directions over HTTP, checking the JSON and status of each response.
+func TestHandler(t *testing.T) {
+	server := httptest.NewServer(handler(converter{opts: roman.ParseOptions{Unicode: true}}))
+	defer server.Close()
+
+	tests := []struct {
+		path   string
+		status int
+		want   result
+	}{
+		{"/roman/MCMXCIV", http.StatusOK, result{Input: "MCMXCIV", Value: 1994, Numeral: "MCMXCIV"}},
+		{"/roman/%E2%85%AB", http.StatusOK, result{Input: "Ⅻ", Value: 12, Numeral: "Ⅻ"}},
+		{"/arabic/1994", http.StatusOK, result{Input: "1994", Value: 1994, Numeral: "MCMXCIV"}},
+		{"/roman/IIII", http.StatusBadRequest, result{}},
+		{"/arabic/4000", http.StatusBadRequest, result{}},
+		{"/arabic/ten", http.StatusBadRequest, result{}},
+	}
+	for _, test := range tests {
+		resp, err := server.Client().Get(server.URL + test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body struct {
+			result
+			Error string `json:"error"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != test.status || body.result != test.want || (body.Error == "") != (test.status == http.StatusOK) {
+			t.Errorf("GET %s returned %d and %+v (%v), want %d and %+v", test.path, resp.StatusCode, body, err, test.status, test.want)
+		}
+	}
+
+	resp, err := server.Client().Post(server.URL+"/roman/X", "text/plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST /roman/X returned %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
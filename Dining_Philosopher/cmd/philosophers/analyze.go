@@ -0,0 +1,59 @@
+//--------------------------------------------
+// Author: Ronan Green
+// Created on 14/10/2024
+// Modified by: Ronan Green
+// Description:
+// The analyze subcommand: replays a trace written with -trace and reports
+// on it as a table or a Graphviz contention graph.
+//--------------------------------------------
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"dining_philosopher/philosophers"
+)
+
+// analyzeMain runs the analyze subcommand with args, the arguments after "analyze", and returns the exit status.
+func analyzeMain(args []string, out, errOut io.Writer) int {
+	flags := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	format := flags.String("format", "text", "text for tables, or dot for a Graphviz contention graph")
+	flags.Usage = func() {
+		fmt.Fprintln(errOut, "Usage: philosophers analyze [-format text|dot] trace.jsonl")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() != 1 || *format != "text" && *format != "dot" {
+		flags.Usage()
+		return 2
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(errOut, "Error:", err)
+		return 1
+	}
+	defer file.Close()
+	a, err := philosophers.AnalyzeTrace(file)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %s: %v\n", flags.Arg(0), err)
+		return 1
+	}
+	if *format == "dot" {
+		a.WriteDot(out)
+	} else {
+		a.WriteText(out)
+	}
+	return 0
+}
@@ -0,0 +1,158 @@
+//--------------------------------------------
+// Author: Ronan Green
+// Created on 14/10/2024
+// Modified by: Ronan Green
+// Description:
+// A live dashboard in the terminal, drawn with ANSI escape sequences as
+// the Wa-Tor terminal view is: a row for each philosopher with what they
+// are doing, their meals and how long they have been waiting for forks.
+//--------------------------------------------
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"dining_philosopher/philosophers"
+)
+
+// ANSI escape sequences used to manage the screen.
+const (
+	enterAltScreen = "\x1b[?1049h" // Switch to the alternate screen, keeping the shell's output underneath.
+	leaveAltScreen = "\x1b[?1049l" // Return to the shell's screen.
+	hideCursor     = "\x1b[?25l"
+	showCursor     = "\x1b[?25h"
+	cursorHome     = "\x1b[H"  // Move to the top-left corner, so each frame overwrites the last without flicker.
+	clearScreen    = "\x1b[2J" // Blank the screen.
+	clearLine      = "\x1b[K"  // Blank the rest of the line, in case it was longer last frame.
+	resetColours   = "\x1b[0m"
+)
+
+// Terminal size used when it cannot be read from the terminal or the COLUMNS and LINES variables.
+const (
+	defaultColumns = 80
+	defaultLines   = 24
+)
+
+// dashboardFPS is how many times a second the dashboard is redrawn.
+const dashboardFPS = 10
+
+// dashboardFrame returns a frame of the dashboard for snap: a header, a row for each philosopher with their state in
+// its window colour, meals, current wait and forks held, and status on the bottom row, in a terminal cols characters
+// wide and rows tall. Philosophers who do not fit are counted on the last row above the status.
+func dashboardFrame(snap philosophers.Snapshot, status string, cols, rows int) string {
+	var frame bytes.Buffer
+	frame.WriteString(cursorHome)
+	line := func(text string) {
+		frame.WriteString(text + clearLine + "\r\n")
+	}
+	line(fmt.Sprintf("%-11s  %-8s  %5s  %8s  %s", "Philosopher", "State", "Meals", "Waiting", "Forks"))
+
+	shown := len(snap.States)
+	if room := rows - 2; shown > room { // The header and the status take a row each.
+		shown = max(room-1, 0)
+	}
+	for i := range shown {
+		s := snap.States[i]
+		waiting := ""
+		if s == philosophers.Hungry {
+			waiting = snap.Time.Sub(snap.Since[i]).Round(100 * time.Millisecond).String()
+		}
+		var forks []string
+		for f, holder := range snap.Holders {
+			if holder == i+1 {
+				forks = append(forks, strconv.Itoa(f))
+			}
+		}
+		c := stateColors[s]
+		line(fmt.Sprintf("%11d  \x1b[38;2;%d;%d;%dm%-8s%s  %5d  %8s  %s", i+1, c.R, c.G, c.B, s, resetColours, snap.Meals[i], waiting, strings.Join(forks, " ")))
+	}
+	if hidden := len(snap.States) - shown; hidden > 0 {
+		line(fmt.Sprintf("... and %d more", hidden))
+	}
+	frame.WriteString("\x1b[J") // Blank anything below, in case the terminal grew.
+
+	if len(status) > cols {
+		status = status[:cols]
+	}
+	fmt.Fprintf(&frame, "\x1b[%d;1H%s%s", rows, status, clearLine)
+	return frame.String()
+}
+
+// showDashboard runs the dinner under ctx while drawing the dashboard to stdout, until the dinner returns.
+//
+// Input:
+//   - ctx (context.Context): Cancelled by Ctrl+C or after -duration, asking everyone to leave.
+//   - cfg (*Config): The settings of the dinner. What each philosopher says they are doing is discarded, since it
+//     would scroll the dashboard away.
+//   - watchdog (*Watchdog): Follows the philosophers. Its reports are held back until the terminal is restored.
+//
+// Output:
+//   - error: What dinner returned, joined with any error drawing to the terminal.
+func showDashboard(ctx context.Context, cfg *philosophers.Config, watchdog *philosophers.Watchdog) error {
+	dashboardCfg := *cfg
+	dashboardCfg.Out = io.Discard
+	var reports bytes.Buffer
+	reportsOut := watchdog.Out
+	watchdog.Out = &reports
+	defer func() {
+		reportsOut.Write(reports.Bytes())
+		watchdog.Out = reportsOut
+	}()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- dinner(ctx, &dashboardCfg, watchdog)
+	}()
+
+	cols, rows := terminalSize()
+	if _, err := io.WriteString(os.Stdout, enterAltScreen+hideCursor+clearScreen); err != nil {
+		return errors.Join(err, <-result)
+	}
+	started := time.Now()
+	ticker := time.NewTicker(time.Second / dashboardFPS)
+	defer ticker.Stop()
+	var drawErr, err error
+	for done := false; !done; {
+		select {
+		case err = <-result:
+			done = true
+		case <-ticker.C:
+		}
+		snap := watchdog.Snapshot()
+		meals := 0
+		for _, m := range snap.Meals {
+			meals += m
+		}
+		status := fmt.Sprintf("%s  %d philosophers  %d meals  %v  Ctrl+C stops", cfg.Strategy, len(snap.States), meals, time.Since(started).Round(100*time.Millisecond))
+		if drawErr == nil {
+			_, drawErr = io.WriteString(os.Stdout, dashboardFrame(snap, status, cols, rows))
+		}
+	}
+	_, stopErr := io.WriteString(os.Stdout, resetColours+showCursor+leaveAltScreen)
+	return errors.Join(err, drawErr, stopErr)
+}
+
+// terminalSize returns the size of the terminal on stdout in characters, falling back to the COLUMNS and LINES
+// environment variables and then to 80x24.
+func terminalSize() (cols, rows int) {
+	if cols, rows, err := stdoutSize(); err == nil && cols > 0 && rows > 0 {
+		return cols, rows
+	}
+	cols, rows = defaultColumns, defaultLines
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		cols = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+		rows = n
+	}
+	return cols, rows
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"dining_philosopher/philosophers"
+)
+
+// plain strips the escape sequences from a dashboard frame, leaving one string per line.
+func plain(frame string) []string {
+	text := regexp.MustCompile("\x1b\\[[0-9;?]*[a-zA-Z]").ReplaceAllString(frame, "")
+	var lines []string
+	for _, line := range strings.Split(text, "\r\n") {
+		if line = strings.TrimRight(line, " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestDashboardFrame(t *testing.T) {
+	now := time.Date(2024, 10, 14, 12, 0, 10, 0, time.UTC)
+	snap := philosophers.Snapshot{
+		Time:    now,
+		States:  []philosophers.State{philosophers.Eating, philosophers.Hungry, philosophers.Thinking, philosophers.Finished},
+		Since:   []time.Time{now, now.Add(-2500 * time.Millisecond), now, now},
+		Meals:   []int{3, 1, 2, 4},
+		Holders: []int{1, 1, 0, 0},
+	}
+
+	frame := dashboardFrame(snap, "hierarchy  status", 80, 24)
+	if !strings.Contains(frame, "\x1b[38;2;60;200;60meating") {
+		t.Error("eating is not drawn in the window's eating colour")
+	}
+	got := strings.Join(plain(frame), "\n")
+	for _, want := range []string{
+		"          1  eating        3            0 1",
+		"          2  hungry        1      2.5s",
+		"          4  finished      4",
+		"hierarchy  status",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("frame does not contain %q:\n%s", want, got)
+		}
+	}
+
+	got = strings.Join(plain(dashboardFrame(snap, "status", 80, 5)), "\n") // Room for two philosophers and a note.
+	if !strings.Contains(got, "... and 2 more") || strings.Contains(got, "          3  ") {
+		t.Errorf("a short terminal should show two philosophers and count the rest:\n%s", got)
+	}
+}
@@ -0,0 +1,129 @@
+//--------------------------------------------
+// Author: Ronan Green
+// Created on 14/10/2024
+// Modified by: Ronan Green
+// Description:
+// Runs the philosophers package from the command line, with the size of
+// the table, the number of meals and the think and eat times set by flags.
+// Issues:
+//
+//
+//--------------------------------------------
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"dining_philosopher/philosophers"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "analyze" {
+		os.Exit(analyzeMain(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	var cfg philosophers.Config
+	flag.IntVar(&cfg.Philosophers, "n", 5, "number of philosophers, and forks, at the table")
+	flag.IntVar(&cfg.Meals, "meals", 3, "meals each philosopher eats before leaving; 0 to dine forever")
+	flag.DurationVar(&cfg.MaxThink, "think", 3*time.Second, "longest a philosopher thinks before each meal")
+	flag.DurationVar(&cfg.MaxEat, "eat", 3*time.Second, "longest a meal lasts")
+	flag.StringVar(&cfg.Strategy, "strategy", "hierarchy", "how philosophers pick up forks: "+strings.Join(philosophers.StrategyNames, ", "))
+	flag.DurationVar(&cfg.Watchdog, "watchdog", 10*time.Second, "report a deadlock once no one has eaten for this long, or a philosopher as starving once they have waited this long; 0 to never report")
+	flag.DurationVar(&cfg.Duration, "duration", 0, "how long the dinner lasts before everyone is asked to leave; 0 for no limit")
+	flag.Int64Var(&cfg.Seed, "seed", 0, "seed for the think and eat times, to repeat a run's times; 0 for new ones")
+	statsFile := flag.String("stats", "philosopher_stats.csv", "CSV file each philosopher's meals, think and wait times are appended to; empty for none")
+	traceFile := flag.String("trace", "", "JSON lines file every fork picked up or put down and every wait and meal is written to, for the analyze subcommand; empty for none")
+	flag.BoolVar(&cfg.InstrumentForks, "fork-stats", false, "time how long each fork is held and waited for, and write the times with the watchdog's reports when the dinner ends")
+	render := flag.String("render", "text", "how to show the dinner: text prints what each philosopher does, tui shows a live dashboard in the terminal, ebiten also draws the table in a window")
+	flag.Parse()
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(2)
+	}
+	if *render != "text" && *render != "tui" && *render != "ebiten" {
+		fmt.Fprintf(os.Stderr, "Error: unknown renderer %q (want text, tui or ebiten)\n", *render)
+		os.Exit(2)
+	}
+
+	// Ctrl+C asks everyone to leave the table, and once it has, a second Ctrl+C kills the program as usual, in case the
+	// philosophers are deadlocked and cannot.
+	interrupt, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-interrupt.Done()
+		stop()
+	}()
+	ctx := interrupt
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(interrupt, cfg.Duration)
+		defer cancel()
+	}
+
+	started := time.Now()
+	watchdog := philosophers.NewWatchdog(cfg.Philosophers, cfg.Watchdog, os.Stderr)
+	if *traceFile != "" {
+		file, err := os.Create(*traceFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		watchdog.TraceTo(file, &cfg)
+	}
+	finished, err := true, error(nil)
+	switch *render {
+	case "ebiten":
+		finished, err = showDinner(ctx, interrupt, &cfg, watchdog)
+	case "tui":
+		err = showDashboard(ctx, &cfg, watchdog)
+	default:
+		err = dinner(ctx, &cfg, watchdog)
+	}
+	if err := watchdog.FlushTrace(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error: writing the trace:", err)
+	}
+	if *statsFile != "" {
+		// Written even after a deadlock, to show how long each philosopher was stuck.
+		if err := writeStats(*statsFile, &cfg, watchdog.Stats(), started); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	switch {
+	case !finished:
+	case interrupt.Err() != nil:
+		fmt.Println("The dinner was interrupted.")
+	case ctx.Err() != nil:
+		fmt.Printf("The dinner ended after %v.\n", cfg.Duration)
+	default:
+		fmt.Println("All philosophers have finished dining.")
+	}
+	watchdog.WriteSummary(os.Stdout)
+}
+
+// dinner seats the philosophers at a table as cfg says and holds the dinner, followed by watchdog, returning once it
+// ends, as Table.Dine does. With cfg.InstrumentForks it then writes how the forks were used to the watchdog's Out,
+// which the dashboard holds back until the terminal is restored.
+func dinner(ctx context.Context, cfg *philosophers.Config, watchdog *philosophers.Watchdog) error {
+	table, err := philosophers.NewTable(cfg, watchdog)
+	if err != nil {
+		return err
+	}
+	err = table.Dine(ctx)
+	if cfg.InstrumentForks {
+		fmt.Fprintln(watchdog.Out, "Fork use:")
+		table.WriteForkStats(watchdog.Out)
+	}
+	return err
+}
@@ -0,0 +1,80 @@
+//--------------------------------------------
+// Author: Ronan Green
+// Created on 14/10/2024
+// Modified by: Ronan Green
+// Description:
+// Writes what each philosopher did to a CSV file at the end of a run, in
+// the same format as the Wa-Tor results, so the fairness of the strategies
+// can be compared.
+//--------------------------------------------
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	"dining_philosopher/philosophers"
+)
+
+// statsHeader lists the columns of the statistics CSV file, one row per philosopher per run.
+var statsHeader = []string{"Strategy", "Philosophers", "Philosopher", "Meals", "Think Time (ms)", "Wait Time (ms)", "Max Wait (ms)"}
+
+// writeStats appends a row for each philosopher to the CSV file filename.
+//
+// Input:
+//   - filename (string): The CSV file, created if it does not exist.
+//   - cfg (*philosophers.Config): The settings of the dinner, written with each row and in the metadata.
+//   - stats ([]philosophers.Stats): What each philosopher did.
+//   - started (time.Time): When the dinner started.
+//
+// Output:
+//   - error: Returns an error if the file cannot be opened or written.
+//
+// Functionality:
+// As with the Wa-Tor results files, rows from every run are appended to the same file, and a new file starts with the
+// settings of its first run as a "#" comment (read it with pandas.read_csv(filename, comment="#")), followed by the
+// header row. Times are in milliseconds to three decimal places.
+func writeStats(filename string, cfg *philosophers.Config, stats []philosophers.Stats, started time.Time) error {
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to get file stats: %w", err)
+	}
+	writer := csv.NewWriter(file)
+	if stat.Size() == 0 {
+		fmt.Fprintf(file, "# dining philosophers=%d meals=%d think=%v eat=%v strategy=%s started=%s go=%s\n",
+			cfg.Philosophers, cfg.Meals, cfg.MaxThink, cfg.MaxEat, cfg.Strategy, started.Format(time.RFC3339), runtime.Version())
+		writer.Write(statsHeader)
+	}
+	for _, s := range stats {
+		writer.Write([]string{
+			cfg.Strategy,
+			strconv.Itoa(cfg.Philosophers),
+			strconv.Itoa(s.Id),
+			strconv.Itoa(s.Meals),
+			strconv.FormatFloat(durationToMS(s.Thought), 'f', 3, 64),
+			strconv.FormatFloat(durationToMS(s.Waited), 'f', 3, 64),
+			strconv.FormatFloat(durationToMS(s.LongestWait), 'f', 3, 64),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write to %s: %w", filename, err)
+	}
+	return nil
+}
+
+// durationToMS converts a duration to milliseconds.
+func durationToMS(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
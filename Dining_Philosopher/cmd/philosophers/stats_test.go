@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"dining_philosopher/philosophers"
+)
+
+func TestWriteStats(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "stats.csv")
+	stats := []philosophers.Stats{{Id: 1, Meals: 3, Thought: 1500 * time.Microsecond, Waited: 2 * time.Millisecond, LongestWait: time.Millisecond}}
+	for _, strategy := range []string{"waiter", "hierarchy"} {
+		cfg := &philosophers.Config{Philosophers: 1, Meals: 3, Strategy: strategy}
+		if err := writeStats(filename, cfg, stats, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "# dining philosophers=1 meals=3") {
+		t.Fatalf("the file does not have a metadata comment, a header and two rows:\n%s", data)
+	}
+	if want := strings.Join(statsHeader, ","); lines[1] != want {
+		t.Errorf("header = %q, want %q", lines[1], want)
+	}
+	if want := "hierarchy,1,1,3,1.500,2.000,1.000"; lines[3] != want {
+		t.Errorf("row = %q, want %q", lines[3], want)
+	}
+}
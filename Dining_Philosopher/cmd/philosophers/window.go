@@ -0,0 +1,172 @@
+//--------------------------------------------
+// Author: Ronan Green
+// Created on 14/10/2024
+// Modified by: Ronan Green
+// Description:
+// Shows the dinner in an Ebiten window, as the Wa-Tor simulation does:
+// the philosophers round the table coloured by what they are doing, and
+// the forks between them, drawn beside whoever holds them.
+//--------------------------------------------
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"math"
+
+	"dining_philosopher/philosophers"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// windowSize is the width and height of the window in pixels.
+const windowSize = 600
+
+// Table layout, in pixels from the centre of the window.
+const (
+	seatRadius = 220 // Distance of each philosopher from the centre.
+	tableSize  = 170 // Radius of the table.
+	forkInner  = 120 // Distance of the inner end of each fork from the centre.
+	forkOuter  = 165 // Distance of the outer end.
+)
+
+// Colors used for each state of a philosopher and of a fork.
+var (
+	ThinkingColor = color.RGBA{0, 221, 255, 255}   // Light blue for thinking.
+	HungryColor   = color.RGBA{255, 80, 60, 255}   // Red for waiting for forks.
+	EatingColor   = color.RGBA{60, 200, 60, 255}   // Green for eating.
+	FinishedColor = color.RGBA{110, 110, 110, 255} // Grey for having left the table.
+	TableColor    = color.RGBA{120, 90, 40, 255}   // Brown for the table.
+	FreeForkColor = color.RGBA{230, 230, 230, 255} // White for a fork on the table.
+	HeldForkColor = color.RGBA{255, 200, 0, 255}   // Gold for a fork in someone's hand.
+)
+
+// stateColors holds the colour of each state, indexed by state.
+var stateColors = [...]color.RGBA{philosophers.Thinking: ThinkingColor, philosophers.Hungry: HungryColor, philosophers.Eating: EatingColor, philosophers.Finished: FinishedColor}
+
+// seatAngle returns the angle, in radians clockwise from the top of the table, of position pos round a table of n
+// seats. Philosopher i sits at position i, and fork f, between philosophers f-1 and f, at f-0.5.
+func seatAngle(pos float64, n int) float64 {
+	return 2 * math.Pi * pos / float64(n)
+}
+
+// forkPosition returns the position of fork f round a table of n: midway between the two philosophers who share it,
+// or, if one of them holds it, moved most of the way toward them.
+func forkPosition(f, holder, n int) float64 {
+	pos := float64(f) - 0.5
+	switch {
+	case holder == f+1: // Philosopher f, on its right, holds it as their left fork.
+		pos += 0.3
+	case holder == (f+n-1)%n+1: // Philosopher f-1, on its left, holds it as their right fork.
+		pos -= 0.3
+	}
+	return pos
+}
+
+// polar returns the screen coordinates of the point at distance r from the centre of the window, at angle a.
+func polar(r, a float64) (float32, float32) {
+	return float32(windowSize/2 + r*math.Sin(a)), float32(windowSize/2 - r*math.Cos(a))
+}
+
+// window is an ebiten.Game showing a dinner as it happens, while dinner runs in other goroutines.
+type window struct {
+	cfg       *philosophers.Config
+	ctx       context.Context        // The dinner's context, cancelled when everyone is asked to leave.
+	interrupt context.Context        // Cancelled by Ctrl+C, which closes the window once everyone has left the table.
+	watchdog  *philosophers.Watchdog // Follows what the philosophers do.
+	result    chan error             // Receives what dinner returns once it does.
+	finished  bool                   // Whether dinner has returned.
+	err       error                  // What dinner returned.
+}
+
+// showDinner runs the dinner under ctx while showing it in a window, and waits for the window to be closed, or for
+// everyone to leave the table after interrupt, which ctx is derived from, is cancelled. It reports whether the
+// dinner had finished by then, and any error from it or from Ebiten. Closing the window first asks everyone to leave.
+func showDinner(ctx, interrupt context.Context, cfg *philosophers.Config, watchdog *philosophers.Watchdog) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w := &window{cfg: cfg, ctx: ctx, interrupt: interrupt, watchdog: watchdog, result: make(chan error, 1)}
+	go func() {
+		w.result <- dinner(ctx, cfg, watchdog)
+	}()
+	ebiten.SetWindowSize(windowSize, windowSize)
+	ebiten.SetWindowTitle("Ebiten Dining Philosophers")
+	if err := ebiten.RunGame(w); err != nil {
+		return w.finished, err
+	}
+	return w.finished, w.err
+}
+
+// Update checks whether the dinner has finished, leaving the final table on screen once it has, unless it was
+// interrupted.
+func (w *window) Update() error {
+	if !w.finished {
+		select {
+		case w.err = <-w.result:
+			w.finished = true
+		default:
+		}
+	}
+	if w.finished && w.interrupt.Err() != nil {
+		return ebiten.Termination
+	}
+	return nil
+}
+
+// Draw renders the table, each philosopher in the colour of what they are doing with their number and meals eaten,
+// each fork beside whoever holds it, and a status line.
+func (w *window) Draw(screen *ebiten.Image) {
+	snap := w.watchdog.Snapshot()
+	n := len(snap.States)
+	vector.DrawFilledCircle(screen, windowSize/2, windowSize/2, tableSize, TableColor, true)
+
+	for f, holder := range snap.Holders {
+		a := seatAngle(forkPosition(f, holder, n), n)
+		x0, y0 := polar(forkInner, a)
+		x1, y1 := polar(forkOuter, a)
+		clr := FreeForkColor
+		if holder != 0 {
+			clr = HeldForkColor
+		}
+		vector.StrokeLine(screen, x0, y0, x1, y1, 3, clr, true)
+	}
+
+	radius := float32(min(30, math.Pi*seatRadius/float64(n)*0.8)) // Small enough for neighbours not to overlap.
+	for i, s := range snap.States {
+		x, y := polar(seatRadius, seatAngle(float64(i), n))
+		vector.DrawFilledCircle(screen, x, y, radius, stateColors[s], true)
+		if n <= 20 {
+			ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%d:%d", i+1, snap.Meals[i]), int(x)-12, int(y)-8)
+		}
+	}
+
+	ebitenutil.DebugPrintAt(screen, w.status(snap), 4, 4)
+}
+
+// status describes the dinner in a line, such as "hierarchy: 2 thinking, 1 hungry, 2 eating, 14 meals eaten".
+func (w *window) status(snap philosophers.Snapshot) string {
+	var counts [philosophers.Finished + 1]int
+	meals := 0
+	for i, s := range snap.States {
+		counts[s]++
+		meals += snap.Meals[i]
+	}
+	line := fmt.Sprintf("%s: %d thinking, %d hungry, %d eating, %d meals eaten", w.cfg.Strategy, counts[philosophers.Thinking], counts[philosophers.Hungry], counts[philosophers.Eating], meals)
+	switch {
+	case w.finished && w.err != nil:
+		line += "\nDeadlocked: see the report in the terminal"
+	case w.finished && w.ctx.Err() != nil:
+		line += "\nEveryone was asked to leave, and has"
+	case w.finished:
+		line += "\nAll philosophers have finished dining"
+	}
+	return line
+}
+
+// Layout returns the fixed window size.
+func (w *window) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return windowSize, windowSize
+}
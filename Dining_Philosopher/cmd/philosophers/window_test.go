@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"dining_philosopher/philosophers"
+)
+
+func TestForkPosition(t *testing.T) {
+	tests := []struct {
+		f, holder, n int
+		want         float64
+	}{
+		{0, 0, 5, -0.5}, // On the table, between philosophers 5 and 1.
+		{0, 1, 5, -0.2}, // Philosopher 1's left fork.
+		{0, 5, 5, -0.8}, // Philosopher 5's right fork.
+		{2, 3, 5, 1.8},
+		{2, 2, 5, 1.2},
+		{1, 1, 2, 0.2}, // With two philosophers, each fork is both's.
+		{1, 2, 2, 0.8},
+	}
+	for _, tt := range tests {
+		if got := forkPosition(tt.f, tt.holder, tt.n); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("forkPosition(%d, %d, %d) = %g, want %g", tt.f, tt.holder, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestPolar(t *testing.T) {
+	x, y := polar(100, seatAngle(0, 4)) // The top of the table.
+	if x != windowSize/2 || y != windowSize/2-100 {
+		t.Errorf("top seat at (%g, %g)", x, y)
+	}
+	x, y = polar(100, seatAngle(1, 4)) // A quarter turn clockwise.
+	if math.Abs(float64(x)-(windowSize/2+100)) > 1e-3 || math.Abs(float64(y)-windowSize/2) > 1e-3 {
+		t.Errorf("right seat at (%g, %g)", x, y)
+	}
+}
+
+func TestWindowStatus(t *testing.T) {
+	snap := philosophers.Snapshot{
+		States:  []philosophers.State{philosophers.Eating, philosophers.Hungry, philosophers.Thinking},
+		Meals:   []int{1, 0, 0},
+		Holders: []int{1, 1, 0},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	win := &window{cfg: &philosophers.Config{Strategy: "waiter"}, ctx: ctx}
+	if got, want := win.status(snap), "waiter: 1 thinking, 1 hungry, 1 eating, 1 meals eaten"; got != want {
+		t.Errorf("status = %q, want %q", got, want)
+	}
+	win.finished, win.err = true, errors.New("deadlock")
+	if got, want := win.status(snap), "waiter: 1 thinking, 1 hungry, 1 eating, 1 meals eaten\nDeadlocked: see the report in the terminal"; got != want {
+		t.Errorf("status = %q, want %q", got, want)
+	}
+	cancel()
+	win.err = nil
+	if got, want := win.status(snap), "waiter: 1 thinking, 1 hungry, 1 eating, 1 meals eaten\nEveryone was asked to leave, and has"; got != want {
+		t.Errorf("status = %q, want %q", got, want)
+	}
+}
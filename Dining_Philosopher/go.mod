@@ -1,3 +1,19 @@
 module dining_philosopher
 
 go 1.23.1
+
+require (
+	Barrier v0.0.0
+	github.com/hajimehoshi/ebiten/v2 v2.8.3
+	golang.org/x/sys v0.25.0
+)
+
+require (
+	github.com/ebitengine/gomobile v0.0.0-20240911145611-4856209ac325 // indirect
+	github.com/ebitengine/hideconsole v1.0.0 // indirect
+	github.com/ebitengine/purego v0.8.0 // indirect
+	github.com/jezek/xgb v1.1.1 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+)
+
+replace Barrier => ../Barrier
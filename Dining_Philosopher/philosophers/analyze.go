@@ -0,0 +1,201 @@
+//--------------------------------------------
+// Author: Ronan Green
+// Created on 14/10/2024
+// Modified by: Ronan Green
+// Description:
+// Replays a trace written by a watchdog and reports how long each
+// philosopher waited, how busy and contended each fork was, and who
+// waited on whom, as a table or a Graphviz contention graph.
+//--------------------------------------------
+
+package philosophers
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+	"time"
+)
+
+// Analysis is what a replayed trace shows about a dinner.
+type Analysis struct {
+	Philosophers int
+	Strategy     string
+	Duration     time.Duration            // From the start event to the last event.
+	Diners       []DinerAnalysis          // By philosopher Id-1.
+	Forks        []ForkAnalysis           // By fork number.
+	Blocked      map[[2]int]time.Duration // Time each philosopher waited on a fork held by another, by their Ids.
+}
+
+// DinerAnalysis is what one philosopher did.
+type DinerAnalysis struct {
+	Meals       int
+	Waited      time.Duration
+	LongestWait time.Duration
+}
+
+// ForkAnalysis is how one fork was used.
+type ForkAnalysis struct {
+	Pickups   int
+	Held      time.Duration // Time anyone held it.
+	Contended time.Duration // Time someone held it while the other philosopher sharing it waited for forks.
+}
+
+// forksOf returns the numbers of the left and right forks of philosopher id at a table of n.
+func forksOf(id, n int) [2]int {
+	return [2]int{id - 1, id % n}
+}
+
+// AnalyzeTrace replays the trace read from r.
+//
+// Input:
+//   - r (io.Reader): JSON lines as written by Watchdog.TraceTo, starting with a start event.
+//
+// Output:
+//   - *Analysis: The waits, meals, fork use and contention in the trace.
+//   - error: Returns an error naming the line of an event that cannot be read or does not fit the table.
+//
+// Functionality:
+//  1. Follows who holds each fork and who is waiting for forks, event by event.
+//  2. Between each event and the next, adds the time passed to each fork held, and to every waiting philosopher's
+//     count of time blocked on each neighbour holding one of their forks: the edges of the contention graph.
+//  3. A trace cut short, such as by a deadlock, is analysed up to its last event.
+func AnalyzeTrace(r io.Reader) (*Analysis, error) {
+	dec := json.NewDecoder(r)
+	var start Event
+	if err := dec.Decode(&start); err != nil || start.Kind != EventStart || start.Philosophers < 2 {
+		return nil, errors.New("line 1: the trace does not begin with a start event")
+	}
+	n := start.Philosophers
+	a := &Analysis{Philosophers: n, Strategy: start.Strategy, Diners: make([]DinerAnalysis, n), Forks: make([]ForkAnalysis, n), Blocked: map[[2]int]time.Duration{}}
+	holders := make([]int, n)
+	waitingSince := make([]time.Time, n) // Zero unless the philosopher is waiting.
+	last := start.Time
+
+	for line := 2; ; line++ {
+		var e Event
+		if err := dec.Decode(&e); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if e.Philosopher < 1 || e.Philosopher > n || e.Fork != nil && (*e.Fork < 0 || *e.Fork >= n) {
+			return nil, fmt.Errorf("line %d: philosopher %d or fork is not at a table of %d", line, e.Philosopher, n)
+		}
+		if e.Time.Before(last) {
+			return nil, fmt.Errorf("line %d: the event is earlier than the one before", line)
+		}
+
+		elapsed := e.Time.Sub(last)
+		for f, holder := range holders {
+			if holder != 0 {
+				a.Forks[f].Held += elapsed
+			}
+		}
+		for i, since := range waitingSince {
+			if since.IsZero() {
+				continue
+			}
+			for _, f := range forksOf(i+1, n) {
+				if holder := holders[f]; holder != 0 && holder != i+1 {
+					a.Blocked[[2]int{i + 1, holder}] += elapsed
+					a.Forks[f].Contended += elapsed
+				}
+			}
+		}
+		last = e.Time
+
+		d := &a.Diners[e.Philosopher-1]
+		switch e.Kind {
+		case EventPickup, EventPutdown:
+			if e.Fork == nil {
+				return nil, fmt.Errorf("line %d: %s event without a fork", line, e.Kind)
+			}
+			holders[*e.Fork] = 0
+			if e.Kind == EventPickup {
+				holders[*e.Fork] = e.Philosopher
+				a.Forks[*e.Fork].Pickups++
+			}
+		case EventWaitStart:
+			waitingSince[e.Philosopher-1] = e.Time
+		case EventWaitEnd:
+			if since := waitingSince[e.Philosopher-1]; !since.IsZero() {
+				d.Waited += e.Time.Sub(since)
+				d.LongestWait = max(d.LongestWait, e.Time.Sub(since))
+			}
+			waitingSince[e.Philosopher-1] = time.Time{}
+		case EventEatStart:
+			d.Meals++
+		case EventEatEnd, EventLeave:
+		default:
+			return nil, fmt.Errorf("line %d: unknown event %q", line, e.Kind)
+		}
+	}
+	a.Duration = last.Sub(start.Time)
+	return a, nil
+}
+
+// edges returns the pairs of philosophers in the contention graph, the first waiting on the second, most time first.
+func (a *Analysis) edges() [][2]int {
+	edges := make([][2]int, 0, len(a.Blocked))
+	for e := range a.Blocked {
+		edges = append(edges, e)
+	}
+	slices.SortFunc(edges, func(x, y [2]int) int {
+		if c := cmp.Compare(a.Blocked[y], a.Blocked[x]); c != 0 {
+			return c
+		}
+		return slices.Compare(x[:], y[:])
+	})
+	return edges
+}
+
+// WriteText writes the analysis as tables.
+func (a *Analysis) WriteText(out io.Writer) {
+	meals := 0
+	for _, d := range a.Diners {
+		meals += d.Meals
+	}
+	fmt.Fprintf(out, "%d philosophers using %s for %v, eating %d meals\n\n", a.Philosophers, a.Strategy, a.Duration.Round(time.Millisecond), meals)
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Philosopher\tMeals\tWaited\tLongest wait\t")
+	for i, d := range a.Diners {
+		fmt.Fprintf(tw, "%d\t%d\t%v\t%v\t\n", i+1, d.Meals, d.Waited.Round(time.Millisecond), d.LongestWait.Round(time.Millisecond))
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "Fork\tPickups\tHeld\tContended\t")
+	for f, fork := range a.Forks {
+		fmt.Fprintf(tw, "%d\t%d\t%v\t%v\t\n", f, fork.Pickups, fork.Held.Round(time.Millisecond), fork.Contended.Round(time.Millisecond))
+	}
+	tw.Flush()
+
+	fmt.Fprintln(out, "\nWho waited on whom:")
+	if len(a.Blocked) == 0 {
+		fmt.Fprintln(out, "  no one waited on anyone")
+	}
+	for _, e := range a.edges() {
+		fmt.Fprintf(out, "  %d waited %v on %d\n", e[0], a.Blocked[e].Round(time.Millisecond), e[1])
+	}
+}
+
+// WriteDot writes the contention graph in Graphviz's DOT language, an arrow from each philosopher to each neighbour
+// they waited on, labelled with the time and drawn thicker the longer it was. Render it with dot -Tpng.
+func (a *Analysis) WriteDot(out io.Writer) {
+	var longest time.Duration
+	for _, d := range a.Blocked {
+		longest = max(longest, d)
+	}
+	fmt.Fprintf(out, "digraph contention {\n\tlabel=%q;\n\tlayout=circo;\n", fmt.Sprintf("%s, %v", a.Strategy, a.Duration.Round(time.Millisecond)))
+	for i, d := range a.Diners {
+		fmt.Fprintf(out, "\t%d [label=\"%d\\n%d meals\"];\n", i+1, i+1, d.Meals)
+	}
+	for _, e := range a.edges() {
+		fmt.Fprintf(out, "\t%d -> %d [label=%q, penwidth=%.1f];\n", e[0], e[1], a.Blocked[e].Round(time.Millisecond).String(), 1+4*float64(a.Blocked[e])/float64(longest))
+	}
+	fmt.Fprintln(out, "}")
+}
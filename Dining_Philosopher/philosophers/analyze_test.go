@@ -0,0 +1,114 @@
+package philosophers
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// traceOf runs steps against a watchdog tracing to a buffer, advancing the clock 10ms before each, and returns the
+// trace.
+func traceOf(t *testing.T, n int, steps ...func(w *Watchdog)) string {
+	t.Helper()
+	w, clock, _ := newTestWatchdog(n)
+	var trace strings.Builder
+	w.TraceTo(&trace, &Config{Strategy: "hierarchy"})
+	for _, step := range steps {
+		clock.t = clock.t.Add(10 * time.Millisecond)
+		step(w)
+	}
+	if err := w.FlushTrace(); err != nil {
+		t.Fatal(err)
+	}
+	return trace.String()
+}
+
+func TestTrace(t *testing.T) {
+	trace := traceOf(t, 2,
+		func(w *Watchdog) { w.set(1, Hungry); w.held(0, 1); w.held(1, 1) },
+		func(w *Watchdog) { w.set(1, Eating) },
+		func(w *Watchdog) { w.set(1, Thinking); w.held(1, 2) }, // Handed over, as Chandy-Misra does.
+	)
+	want := []string{
+		`"event":"start","philosophers":2,"strategy":"hierarchy"}`,
+		`"event":"wait_start","philosopher":1}`,
+		`"event":"pickup","philosopher":1,"fork":0}`,
+		`"event":"pickup","philosopher":1,"fork":1}`,
+		`"event":"wait_end","philosopher":1}`,
+		`"event":"eat_start","philosopher":1}`,
+		`"event":"eat_end","philosopher":1}`,
+		`"event":"putdown","philosopher":1,"fork":1}`,
+		`"event":"pickup","philosopher":2,"fork":1}`,
+	}
+	lines := strings.Split(strings.TrimSuffix(trace, "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("trace has %d lines, want %d:\n%s", len(lines), len(want), trace)
+	}
+	for i := range want {
+		if !strings.HasSuffix(lines[i], want[i]) {
+			t.Errorf("line %d = %s, want it to end %s", i+1, lines[i], want[i])
+		}
+	}
+}
+
+func TestAnalyzeTrace(t *testing.T) {
+	// Philosopher 1 eats for 20ms while philosopher 2, hungry, waits on them, then philosopher 2 eats.
+	trace := traceOf(t, 2,
+		func(w *Watchdog) { w.set(1, Hungry); w.held(0, 1); w.held(1, 1); w.set(1, Eating) },
+		func(w *Watchdog) { w.set(2, Hungry) },
+		func(w *Watchdog) {},
+		func(w *Watchdog) {
+			w.set(1, Thinking)
+			w.held(1, 0)
+			w.held(0, 0)
+			w.held(1, 2)
+			w.held(0, 2)
+			w.set(2, Eating)
+		},
+		func(w *Watchdog) { w.set(2, Thinking); w.held(0, 0); w.held(1, 0) },
+	)
+	a, err := AnalyzeTrace(strings.NewReader(trace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Philosophers != 2 || a.Strategy != "hierarchy" || a.Duration != 50*time.Millisecond {
+		t.Errorf("analysis of %d philosophers using %s for %v, want 2 using hierarchy for 50ms", a.Philosophers, a.Strategy, a.Duration)
+	}
+	if want := (DinerAnalysis{Meals: 1, Waited: 20 * time.Millisecond, LongestWait: 20 * time.Millisecond}); a.Diners[1] != want {
+		t.Errorf("philosopher 2: %+v, want %+v", a.Diners[1], want)
+	}
+	if want := (ForkAnalysis{Pickups: 2, Held: 40 * time.Millisecond, Contended: 20 * time.Millisecond}); a.Forks[0] != want {
+		t.Errorf("fork 0: %+v, want %+v", a.Forks[0], want)
+	}
+	if got := a.Blocked[[2]int{2, 1}]; got != 40*time.Millisecond || len(a.Blocked) != 1 {
+		t.Errorf("Blocked = %v, want 2 waiting 40ms on 1, 20ms for each fork", a.Blocked)
+	}
+
+	var dot strings.Builder
+	a.WriteDot(&dot)
+	if !strings.Contains(dot.String(), `2 -> 1 [label="40ms", penwidth=5.0];`) {
+		t.Errorf("DOT graph has no edge from 2 to 1:\n%s", &dot)
+	}
+	var text strings.Builder
+	a.WriteText(&text)
+	if !strings.Contains(text.String(), "2 waited 40ms on 1") {
+		t.Errorf("text has no edge from 2 to 1:\n%s", &text)
+	}
+}
+
+func TestAnalyzeTraceErrors(t *testing.T) {
+	start := `{"time":"2024-10-14T12:00:00Z","event":"start","philosophers":2}` + "\n"
+	for _, trace := range []string{
+		"",
+		`{"time":"2024-10-14T12:00:00Z","event":"pickup","philosopher":1,"fork":0}`,
+		start + `{"time":"2024-10-14T12:00:01Z","event":"pickup","philosopher":3,"fork":0}`,
+		start + `{"time":"2024-10-14T12:00:01Z","event":"pickup","philosopher":1}`,
+		start + `{"time":"2024-10-14T12:00:01Z","event":"dance","philosopher":1}`,
+		start + `{"time":"2024-10-14T11:00:00Z","event":"eat_start","philosopher":1}`,
+		start + `not json`,
+	} {
+		if _, err := AnalyzeTrace(strings.NewReader(trace)); err == nil {
+			t.Errorf("analyzeTrace(%q) = nil error", trace)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package philosophers
+
+import (
+	"context"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// simClock is a simulated clock. Sleeping philosophers wait until the test advances it, so a dinner of many simulated
+// seconds runs in milliseconds.
+type simClock struct {
+	mu       sync.Mutex
+	now      time.Time
+	sleepers []*simSleeper
+}
+
+type simSleeper struct {
+	until time.Time
+	wake  chan struct{}
+}
+
+func newSimClock() *simClock {
+	return &simClock{now: time.Date(2024, 10, 14, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *simClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *simClock) Sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	c.mu.Lock()
+	s := &simSleeper{until: c.now.Add(d), wake: make(chan struct{})}
+	c.sleepers = append(c.sleepers, s)
+	c.mu.Unlock()
+	select {
+	case <-s.wake:
+		return true
+	case <-ctx.Done():
+		c.mu.Lock()
+		c.sleepers = slices.DeleteFunc(c.sleepers, func(other *simSleeper) bool { return other == s })
+		c.mu.Unlock()
+		return false
+	}
+}
+
+// asleep returns how many are sleeping.
+func (c *simClock) asleep() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.sleepers)
+}
+
+// advance moves the clock on to the earliest time anyone sleeping is due to wake, and wakes everyone due then.
+func (c *simClock) advance() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.sleepers) == 0 {
+		return
+	}
+	next := c.sleepers[0].until
+	for _, s := range c.sleepers {
+		if s.until.Before(next) {
+			next = s.until
+		}
+	}
+	c.now = next
+	c.sleepers = slices.DeleteFunc(c.sleepers, func(s *simSleeper) bool {
+		if s.until.After(next) {
+			return false
+		}
+		close(s.wake)
+		return true
+	})
+}
+
+// fixedRNG always chooses the same number, as far as n allows.
+type fixedRNG int64
+
+func (r fixedRNG) Int63n(n int64) int64 {
+	return min(int64(r), n-1)
+}
+
+func TestThinkAndEat(t *testing.T) {
+	clock := newSimClock()
+	p := &Philosopher{Id: 1, Config: &Config{MaxThink: 3 * time.Second, MaxEat: time.Second}, Clock: clock, Rand: fixedRNG(1500)}
+	done := make(chan bool)
+	go func() {
+		done <- p.think(context.Background())
+	}()
+	for clock.asleep() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.advance()
+	if !<-done || clock.Now().Sub(newSimClock().now) != 1500*time.Millisecond {
+		t.Errorf("thinking took %v, want 1.5s", clock.Now().Sub(newSimClock().now))
+	}
+
+	go func() {
+		p.eat(context.Background())
+		close(done)
+	}()
+	for clock.asleep() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.advance()
+	<-done
+	if got := clock.Now().Sub(newSimClock().now); got != 1500*time.Millisecond+999*time.Millisecond {
+		t.Errorf("after eating, %v had passed, want 2.499s: the meal should be cut to the longest, 999ms", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if p.think(ctx) {
+		t.Error("think() with a cancelled context = true, want false")
+	}
+}
+
+// TestStrategiesSimulated holds a dinner for each strategy in simulated time, with think and eat times of up to a
+// second chosen from a fixed seed, and checks that every philosopher eats every meal well within the time it would
+// take were the meals eaten one at a time.
+//
+// The clock is moved on whenever every philosopher still at the table is asleep or waiting for forks, once that has
+// held for a moment of real time so that anyone about to pick up free forks has done so.
+func TestStrategiesSimulated(t *testing.T) {
+	const n, meals = 5, 10
+	for _, name := range StrategyNames[1:] { // naive can deadlock.
+		clock := newSimClock()
+		start := clock.now
+		cfg := &Config{Philosophers: n, Meals: meals, MaxThink: time.Second, MaxEat: time.Second, Strategy: name, Seed: 42, Clock: clock, Out: io.Discard}
+		watchdog := NewWatchdog(n, 0, io.Discard)
+		watchdog.now = clock.Now
+		table, err := NewTable(cfg, watchdog)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result := make(chan error, 1)
+		go func() {
+			result <- table.Dine(context.Background())
+		}()
+		limit := start.Add(meals * n * cfg.MaxEat) // Meals one at a time, with thinking alongside, would take this long.
+		quiet := 0
+	run:
+		for {
+			select {
+			case err := <-result:
+				if err != nil {
+					t.Fatalf("%s: Dine() = %v", name, err)
+				}
+				break run
+			case <-time.After(100 * time.Microsecond):
+			}
+			if idle(clock, watchdog) {
+				quiet++
+			} else {
+				quiet = 0
+			}
+			if quiet < 3 {
+				continue
+			}
+			if clock.asleep() == 0 {
+				t.Fatalf("%s: every philosopher at the table is waiting for forks, after %v: deadlock", name, clock.Now().Sub(start))
+			}
+			clock.advance()
+			quiet = 0
+			if clock.Now().After(limit) {
+				t.Fatalf("%s: the dinner has taken more than %v", name, limit.Sub(start))
+			}
+		}
+
+		for _, s := range watchdog.Stats() {
+			if s.Meals != meals {
+				t.Errorf("%s: philosopher %d ate %d meals, want %d", name, s.Id, s.Meals, meals)
+			}
+		}
+		t.Logf("%s: %d meals each in %v of simulated time", name, meals, clock.Now().Sub(start))
+	}
+}
+
+// idle reports whether every philosopher still at the table is asleep or waiting for forks, so only the clock can
+// move the dinner on.
+func idle(clock *simClock, watchdog *Watchdog) bool {
+	waiting, atTable := 0, 0
+	for _, s := range watchdog.Snapshot().States {
+		switch s {
+		case Finished:
+			continue
+		case Hungry:
+			waiting++
+		}
+		atTable++
+	}
+	return atTable > 0 && clock.asleep()+waiting == atTable
+}
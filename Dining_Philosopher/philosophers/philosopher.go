@@ -0,0 +1,197 @@
+//--------------------------------------------
+// Author: Ronan Green
+// Created on 14/10/2024
+// Modified by: Ronan Green
+// Description:
+// A philosopher alternates between thinking and eating, and needs both of
+// the forks beside them to eat.
+//--------------------------------------------
+
+package philosophers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+)
+
+// Philosopher represents a philosopher with an ID and two forks (left and right).
+type Philosopher struct {
+	Id        int
+	Left      int // Number of the left fork, from 0.
+	Right     int // Number of the right fork, from 0.
+	LeftFork  Fork
+	RightFork Fork
+	Config    *Config   // The settings of the dinner they are at.
+	Strategy  Strategy  // How they pick up and put down their forks.
+	Watchdog  *Watchdog // Follows what they do, or nil.
+	Clock     Clock     // Times their thinking and eating.
+	Rand      RNG       // Chooses how long they think and eat.
+
+	state State // What they are doing, changed only by their own goroutine with setState.
+}
+
+// State is what a philosopher is doing. A philosopher thinks, becomes hungry and waits for forks, eats once they have
+// both, and goes back to thinking, until they leave the table.
+type State int
+
+const (
+	Thinking State = iota
+	Hungry         // Waiting for forks.
+	Eating
+	Finished // Left the table, having eaten every meal or been asked to leave.
+)
+
+var stateNames = [...]string{"thinking", "hungry", "eating", "finished"}
+
+func (s State) String() string {
+	return stateNames[s]
+}
+
+// transitions holds, for each state, the states a philosopher can go on to from it. A hungry philosopher asked to
+// leave goes back to thinking without eating.
+var transitions = [...][]State{
+	Thinking: {Hungry, Finished},
+	Hungry:   {Eating, Thinking, Finished},
+	Eating:   {Thinking, Finished},
+	Finished: {},
+}
+
+// setState moves the philosopher on to state s and tells the watchdog. It panics if s cannot follow what they are
+// doing now, which would be a bug in the dinner.
+func (p *Philosopher) setState(s State) {
+	if !slices.Contains(transitions[p.state], s) {
+		panic(fmt.Sprintf("philosopher %d cannot go from %s to %s", p.Id, p.state, s))
+	}
+	p.state = s
+	p.Watchdog.set(p.Id, s)
+}
+
+// Clock tells the time and waits, so tests can run a dinner in simulated time rather than wait for it.
+type Clock interface {
+	Now() time.Time
+	// Sleep waits for d, or until ctx is cancelled, and reports whether it waited the whole time.
+	Sleep(ctx context.Context, d time.Duration) bool
+}
+
+// RNG chooses random numbers from 0 up to, but not including, n. A *rand.Rand is one.
+type RNG interface {
+	Int63n(n int64) int64
+}
+
+// SystemClock is the real clock.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (SystemClock) Sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// dineAll has the philosopher dine until they have eaten Config.Meals meals, forever if it is 0, or until ctx is
+// cancelled. A philosopher asked to leave stops thinking or eating at once and puts down any forks they hold, but
+// one waiting for forks leaves only once they have them.
+func (p *Philosopher) dineAll(ctx context.Context) {
+	for meal := 0; (p.Config.Meals == 0 || meal < p.Config.Meals) && ctx.Err() == nil; meal++ {
+		p.dine(ctx) // Philosopher goes through the dine process
+	}
+	p.setState(Finished)
+	if ctx.Err() != nil {
+		fmt.Fprintf(p.out(), "Philosopher %d has left the table early\n", p.Id)
+		return
+	}
+	fmt.Fprintf(p.out(), "Philosopher %d has finished dining\n", p.Id)
+}
+
+// dine represents the philosopher's process of thinking, acquiring forks, eating, and releasing forks.
+func (p *Philosopher) dine(ctx context.Context) {
+	if !p.think(ctx) { // Philosopher thinks before attempting to eat
+		return
+	}
+
+	p.setState(Hungry)
+	p.Strategy.Acquire(p) // Pick up both forks as the strategy says
+	if ctx.Err() == nil {
+		p.setState(Eating)
+		p.eat(ctx) // Philosopher eats after acquiring both forks
+	}
+	p.Strategy.Release(p) // Put both forks down after eating
+	p.setState(Thinking)
+}
+
+// PickUp locks fork f, the philosopher's left or right fork, and tells the watchdog they hold it.
+func (p *Philosopher) PickUp(f int) {
+	p.fork(f).Lock()
+	p.Watchdog.held(f, p.Id)
+}
+
+// TryPickUp locks fork f, the philosopher's left or right fork, if it is on the table, telling the watchdog they hold
+// it, and reports whether they picked it up.
+func (p *Philosopher) TryPickUp(f int) bool {
+	if !p.fork(f).TryLock() {
+		return false
+	}
+	p.Watchdog.held(f, p.Id)
+	return true
+}
+
+// PutDown tells the watchdog the philosopher no longer holds fork f, then unlocks it.
+func (p *Philosopher) PutDown(f int) {
+	p.Watchdog.held(f, 0)
+	p.fork(f).Unlock()
+}
+
+// fork returns the mutex for fork f, the philosopher's left or right fork.
+func (p *Philosopher) fork(f int) Fork {
+	if f == p.Left {
+		return p.LeftFork
+	}
+	return p.RightFork
+}
+
+// think simulates the philosopher thinking for a random amount of time, and reports whether they finished before ctx
+// was cancelled.
+func (p *Philosopher) think(ctx context.Context) bool {
+	t := randomDuration(p.Rand, p.Config.MaxThink) // Random thinking time up to Config.MaxThink
+	fmt.Fprintf(p.out(), "Philosopher %d is thinking for %v\n", p.Id, t)
+	return p.Clock.Sleep(ctx, t) // Simulate thinking by sleeping
+}
+
+// eat simulates the philosopher eating for a random amount of time, or until ctx is cancelled.
+func (p *Philosopher) eat(ctx context.Context) {
+	t := randomDuration(p.Rand, p.Config.MaxEat) // Random eating time up to Config.MaxEat
+	fmt.Fprintf(p.out(), "Philosopher %d is eating for %v\n", p.Id, t)
+	p.Clock.Sleep(ctx, t) // Simulate eating by sleeping
+}
+
+// out returns where the philosopher says what they are doing: Config.Out, or stdout if it is nil.
+func (p *Philosopher) out() io.Writer {
+	if p.Config.Out == nil {
+		return os.Stdout
+	}
+	return p.Config.Out
+}
+
+// randomDuration returns a duration chosen by rng from 0 up to, but not including, max, to the millisecond if max is
+// at least one. It returns 0 if max is 0.
+func randomDuration(rng RNG, max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	if max >= time.Millisecond {
+		return time.Duration(rng.Int63n(int64(max/time.Millisecond))) * time.Millisecond
+	}
+	return time.Duration(rng.Int63n(int64(max)))
+}
@@ -0,0 +1,290 @@
+//--------------------------------------------
+// Author: Ronan Green
+// Created on 14/10/2024
+// Modified by: Ronan Green
+// Description:
+// The ways a philosopher can pick up their forks, chosen by name.
+// Every strategy but naive avoids deadlock.
+//--------------------------------------------
+
+package philosophers
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Strategy decides how philosophers pick up and put down their forks.
+type Strategy interface {
+	Acquire(p *Philosopher) // Blocks until p holds both of their forks.
+	Release(p *Philosopher) // Puts down both of p's forks after eating.
+}
+
+// StrategyNames lists the strategies NewStrategy accepts, in the order the help lists them.
+var StrategyNames = []string{"naive", "hierarchy", "asymmetric", "waiter", "chandy-misra", "trylock", "priority"}
+
+// NewStrategy returns the strategy with the given name for a table of n philosophers.
+func NewStrategy(name string, n int) (Strategy, error) {
+	switch name {
+	case "naive":
+		return naive{}, nil
+	case "hierarchy":
+		return hierarchy{}, nil
+	case "asymmetric":
+		return asymmetric{}, nil
+	case "waiter":
+		return newWaiter(n), nil
+	case "chandy-misra":
+		return newChandyMisra(n), nil
+	case "trylock":
+		return &tryLock{}, nil
+	case "priority":
+		return newPriority(n), nil
+	}
+	return nil, fmt.Errorf("unknown strategy %q (want one of %s)", name, strings.Join(StrategyNames, ", "))
+}
+
+// naive has every philosopher pick up their left fork and then their right. If every philosopher picks up their
+// left fork at once, each waits forever for the right one, held by their neighbour: the table deadlocks.
+type naive struct{}
+
+func (naive) Acquire(p *Philosopher) {
+	p.PickUp(p.Left)
+	p.PickUp(p.Right)
+}
+
+func (naive) Release(p *Philosopher) {
+	p.PutDown(p.Right)
+	p.PutDown(p.Left)
+}
+
+// hierarchy numbers the forks and has every philosopher pick up the lower-numbered of their two forks first.
+// The last philosopher, whose right fork is fork 0, therefore reaches right first, which breaks the cycle of
+// philosophers each holding one fork and waiting for the next.
+type hierarchy struct{}
+
+func (hierarchy) Acquire(p *Philosopher) {
+	first, second := p.Left, p.Right
+	if p.Right < p.Left {
+		first, second = second, first
+	}
+	p.PickUp(first)
+	p.PickUp(second)
+}
+
+func (hierarchy) Release(p *Philosopher) {
+	p.PutDown(p.Right)
+	p.PutDown(p.Left)
+}
+
+// asymmetric has odd-numbered philosophers pick up their left fork first and even-numbered ones their right. An even
+// philosopher and the odd one on their left, such as 2 and 3, then reach first for the fork between them, and
+// whichever loses holds nothing, so the cycle of philosophers each holding one fork cannot close.
+type asymmetric struct{}
+
+func (asymmetric) Acquire(p *Philosopher) {
+	first, second := p.Left, p.Right
+	if p.Id%2 == 0 {
+		first, second = second, first
+	}
+	p.PickUp(first)
+	p.PickUp(second)
+}
+
+func (asymmetric) Release(p *Philosopher) {
+	p.PutDown(p.Right)
+	p.PutDown(p.Left)
+}
+
+// waiter seats at most n-1 philosophers at the table at once, a semaphore acting as the arbitrator. With one seat
+// empty, at least one seated philosopher can always pick up both forks.
+type waiter struct {
+	seats chan struct{} // Holds a token for each philosopher seated.
+}
+
+func newWaiter(n int) *waiter {
+	return &waiter{seats: make(chan struct{}, n-1)}
+}
+
+func (w *waiter) Acquire(p *Philosopher) {
+	w.seats <- struct{}{} // Wait for the waiter to seat us.
+	p.PickUp(p.Left)
+	p.PickUp(p.Right)
+}
+
+func (w *waiter) Release(p *Philosopher) {
+	p.PutDown(p.Right)
+	p.PutDown(p.Left)
+	<-w.seats // Leave the table, freeing a seat.
+}
+
+// chandyMisra passes the forks between neighbours as tokens, following Chandy and Misra's solution for philosophers
+// who can only send each other messages. Each fork is held by one of its two philosophers and is clean or dirty:
+//   - At the start, each fork is dirty and held by the lower-numbered of its philosophers, so no cycle of
+//     philosophers each waiting on the next can form.
+//   - A hungry philosopher sends a request token for each fork they lack to its holder.
+//   - A holder given a request gives up the fork, cleaning it, if it is dirty and they are not eating; otherwise they
+//     keep the request until they have eaten.
+//   - Eating dirties both forks, and a philosopher who has eaten hands over every fork requested meanwhile.
+//
+// A philosopher only gives up a fork they have not eaten with yet if it is dirty, so a hungry philosopher who has
+// been sent clean forks keeps them, and no one starves. The messages are modelled as changes to the forks' state
+// under one lock, with a condition variable to wake philosophers waiting for forks to arrive. The watchdog is told of
+// a fork only once it first changes hands.
+type chandyMisra struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	forks  []cmFork // Fork i lies between philosopher i, to its right, and philosopher i-1, to its left.
+	eating []bool   // Whether each philosopher is eating.
+}
+
+// cmFork is the state of a fork in the Chandy-Misra solution.
+type cmFork struct {
+	holder    int  // Index of the philosopher holding the fork.
+	dirty     bool // Whether the holder has eaten with it since receiving it.
+	requested bool // Whether the other philosopher has sent a request token for it.
+}
+
+func newChandyMisra(n int) *chandyMisra {
+	c := &chandyMisra{forks: make([]cmFork, n), eating: make([]bool, n)}
+	c.cond = sync.NewCond(&c.mu)
+	for i := range c.forks {
+		c.forks[i] = cmFork{holder: min(i, (i+n-1)%n), dirty: true}
+	}
+	return c
+}
+
+// other returns the philosopher who shares fork f with philosopher me.
+func (c *chandyMisra) other(f, me int) int {
+	if me == f {
+		return (f + len(c.forks) - 1) % len(c.forks)
+	}
+	return f
+}
+
+func (c *chandyMisra) Acquire(p *Philosopher) {
+	me := p.Id - 1
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for {
+		for _, f := range []int{p.Left, p.Right} {
+			fork := &c.forks[f]
+			if fork.holder == me {
+				continue
+			}
+			fork.requested = true // Send the holder a request token.
+			if fork.dirty && !c.eating[fork.holder] {
+				// The holder answers by cleaning the fork and sending it over.
+				fork.holder, fork.dirty, fork.requested = me, false, false
+				p.Watchdog.held(f, p.Id)
+				c.cond.Broadcast() // Wake the holder, if hungry, to ask for it back.
+			}
+		}
+		if c.forks[p.Left].holder == me && c.forks[p.Right].holder == me {
+			break
+		}
+		c.cond.Wait()
+	}
+	c.eating[me] = true
+}
+
+func (c *chandyMisra) Release(p *Philosopher) {
+	me := p.Id - 1
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.eating[me] = false
+	for _, f := range []int{p.Left, p.Right} {
+		fork := &c.forks[f]
+		fork.dirty = true
+		if fork.requested {
+			fork.holder, fork.dirty, fork.requested = c.other(f, me), false, false
+			p.Watchdog.held(f, fork.holder+1)
+		}
+	}
+	c.cond.Broadcast()
+}
+
+// Backoff of the trylock strategy: how long a philosopher waits, at most, after first failing to pick up their second
+// fork, doubling with each failure up to maxBackoff.
+const (
+	minBackoff = 10 * time.Microsecond
+	maxBackoff = 10 * time.Millisecond
+)
+
+// tryLock has every philosopher pick up their left fork and then try their right, putting the left back down if the
+// right is taken and waiting before trying again. No one holds a fork while waiting for another, so the table cannot
+// deadlock, but philosophers who keep picking up and putting down forks in step can livelock, none ever eating. A
+// random wait, whose limit doubles with each failure, breaks the step.
+type tryLock struct {
+	putDowns atomic.Int64 // Times a philosopher put their left fork back down, a measure of wasted effort.
+}
+
+func (t *tryLock) Acquire(p *Philosopher) {
+	for backoff := minBackoff; ; backoff = min(2*backoff, maxBackoff) {
+		p.PickUp(p.Left)
+		if p.TryPickUp(p.Right) {
+			return
+		}
+		p.PutDown(p.Left)
+		t.putDowns.Add(1)
+		time.Sleep(time.Duration(rand.Int63n(int64(backoff))))
+	}
+}
+
+func (t *tryLock) Release(p *Philosopher) {
+	p.PutDown(p.Right)
+	p.PutDown(p.Left)
+}
+
+// priority seats philosophers with a monitor: a lock over the whole table and a condition variable to wait on. A
+// hungry philosopher takes a ticket and eats once neither neighbour is eating and neither has been waiting longer,
+// holding an earlier ticket. Someone who becomes hungry can never overtake a neighbour already waiting, so the
+// longest-waiting philosopher at the table is always next to eat once their neighbours finish: no one starves, and
+// no one deadlocks.
+type priority struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	eating  []bool   // Whether each philosopher is eating.
+	tickets []uint64 // Each hungry philosopher's ticket, or 0 if they are not hungry.
+	issued  uint64   // The last ticket handed out.
+}
+
+func newPriority(n int) *priority {
+	t := &priority{eating: make([]bool, n), tickets: make([]uint64, n)}
+	t.cond = sync.NewCond(&t.mu)
+	return t
+}
+
+// first reports whether philosopher i, who is hungry, may eat ahead of philosopher j: j is not eating, and is not
+// hungry or has a later ticket.
+func (t *priority) first(i, j int) bool {
+	return !t.eating[j] && (t.tickets[j] == 0 || t.tickets[i] < t.tickets[j])
+}
+
+func (t *priority) Acquire(p *Philosopher) {
+	i, n := p.Id-1, len(t.eating)
+	t.mu.Lock()
+	t.issued++
+	t.tickets[i] = t.issued
+	for !t.first(i, (i+n-1)%n) || !t.first(i, (i+1)%n) {
+		t.cond.Wait()
+	}
+	t.tickets[i], t.eating[i] = 0, true
+	t.mu.Unlock()
+	// Neither neighbour is eating, so both forks are free.
+	p.PickUp(p.Left)
+	p.PickUp(p.Right)
+}
+
+func (t *priority) Release(p *Philosopher) {
+	p.PutDown(p.Right)
+	p.PutDown(p.Left)
+	t.mu.Lock()
+	t.eating[p.Id-1] = false
+	t.mu.Unlock()
+	t.cond.Broadcast() // Wake the neighbours, and anyone else, to check whether they may eat now.
+}
@@ -0,0 +1,171 @@
+package philosophers
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// table seats n philosophers with forks as a Table does, picking up their forks with the named strategy.
+func table(t *testing.T, name string, n int) []*Philosopher {
+	t.Helper()
+	strategy, err := NewStrategy(name, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forks := make([]*sync.Mutex, n)
+	for i := range forks {
+		forks[i] = &sync.Mutex{}
+	}
+	philosophers := make([]*Philosopher, n)
+	for i := range philosophers {
+		philosophers[i] = &Philosopher{Id: i + 1, Left: i, Right: (i + 1) % n, LeftFork: forks[i], RightFork: forks[(i+1)%n], Strategy: strategy}
+	}
+	return philosophers
+}
+
+// TestStrategies has every philosopher eat many meals with no time to think, so they all compete for their forks,
+// and checks that the dinner finishes and that no two neighbours ever eat at once.
+func TestStrategies(t *testing.T) {
+	for _, name := range StrategyNames[1:] { // naive can deadlock.
+		for _, n := range []int{2, 3, 5, 16} {
+			t.Run(fmt.Sprintf("%s/%d", name, n), func(t *testing.T) {
+				philosophers := table(t, name, n)
+				eating := make([]atomic.Bool, n)
+				var wg sync.WaitGroup
+				wg.Add(n)
+				for i, p := range philosophers {
+					go func() {
+						defer wg.Done()
+						for range 200 {
+							p.Strategy.Acquire(p)
+							eating[i].Store(true)
+							if eating[(i+1)%n].Load() || eating[(i+n-1)%n].Load() {
+								t.Errorf("philosopher %d is eating beside a neighbour", p.Id)
+							}
+							eating[i].Store(false)
+							p.Strategy.Release(p)
+						}
+					}()
+				}
+				testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+			})
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{Philosophers: 5, Meals: 3, Strategy: "waiter"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate(%+v) = %v", valid, err)
+	}
+	for _, c := range []Config{
+		{Philosophers: 1, Strategy: "hierarchy"},
+		{Philosophers: 5, Meals: -1, Strategy: "hierarchy"},
+		{Philosophers: 5, MaxEat: -time.Second, Strategy: "hierarchy"},
+		{Philosophers: 5, Strategy: "left-handed"},
+	} {
+		if err := c.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want an error", c)
+		}
+	}
+}
+
+// BenchmarkStrategies measures how quickly each strategy feeds a table of 5 philosophers who never think and eat in
+// no time, so they always compete for forks. ns/op is the time per meal. For trylock, putdowns/op is how often a
+// philosopher put a fork back down per meal: effort wasted on the brink of livelock rather than blocked in deadlock.
+func BenchmarkStrategies(b *testing.B) {
+	const n = 5
+	for _, name := range StrategyNames[1:] { // naive can deadlock.
+		b.Run(name, func(b *testing.B) {
+			strategy, err := NewStrategy(name, n)
+			if err != nil {
+				b.Fatal(err)
+			}
+			forks := make([]*sync.Mutex, n)
+			for i := range forks {
+				forks[i] = &sync.Mutex{}
+			}
+			var meals atomic.Int64
+			var wg sync.WaitGroup
+			wg.Add(n)
+			b.ResetTimer()
+			for i := range n {
+				p := &Philosopher{Id: i + 1, Left: i, Right: (i + 1) % n, LeftFork: forks[i], RightFork: forks[(i+1)%n], Strategy: strategy}
+				go func() {
+					defer wg.Done()
+					for meals.Add(1) <= int64(b.N) {
+						p.Strategy.Acquire(p)
+						p.Strategy.Release(p)
+					}
+				}()
+			}
+			wg.Wait()
+			if t, ok := strategy.(*tryLock); ok {
+				b.ReportMetric(float64(t.putDowns.Load())/float64(b.N), "putdowns/op")
+			}
+		})
+	}
+}
+
+// TestPriorityOrder checks that with the priority strategy a philosopher who became hungry first eats first, even
+// when a later neighbour's forks free up at the same moment.
+func TestPriorityOrder(t *testing.T) {
+	philosophers := table(t, "priority", 3)
+	strategy := philosophers[0].Strategy.(*priority)
+	waitForTicket := func(i int) {
+		for {
+			strategy.mu.Lock()
+			ticket := strategy.tickets[i]
+			strategy.mu.Unlock()
+			if ticket != 0 {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	p1, p2, p3 := philosophers[0], philosophers[1], philosophers[2]
+	strategy.Acquire(p2) // 2 eats, so 1 and 3, who share a fork, both wait.
+	ate := make(chan int, 2)
+	go func() {
+		strategy.Acquire(p1)
+		ate <- 1
+	}()
+	waitForTicket(0)
+	go func() {
+		strategy.Acquire(p3)
+		ate <- 3
+	}()
+	waitForTicket(2)
+
+	strategy.Release(p2)
+	if first := <-ate; first != 1 {
+		t.Fatalf("philosopher %d ate first, want 1, who was hungry first", first)
+	}
+	select {
+	case <-ate:
+		t.Fatal("philosopher 3 ate beside philosopher 1")
+	case <-time.After(20 * time.Millisecond):
+	}
+	strategy.Release(p1)
+	<-ate
+	strategy.Release(p3)
+}
+
+func TestSetState(t *testing.T) {
+	p := &Philosopher{Id: 1}
+	for _, s := range []State{Hungry, Eating, Thinking, Hungry, Thinking, Finished} {
+		p.setState(s)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("setState(eating) after finished did not panic")
+		}
+	}()
+	p.setState(Eating)
+}
@@ -0,0 +1,199 @@
+//--------------------------------------------
+// Author: Ronan Green
+// Created on 14/10/2024
+// Modified by: Ronan Green
+// Description:
+// A table of philosophers with a fork between each pair, and the settings
+// of the dinner held at it.
+//--------------------------------------------
+
+// Package philosophers simulates the dining philosophers problem: philosophers seated round a table, with a fork
+// between each pair, who each need both forks beside them to eat. A Table holds a dinner, with the philosophers
+// picking up their forks by one of the named strategies or by any other Strategy, and a Watchdog reports a deadlock or
+// a starving philosopher.
+//
+// A variation of the problem can import the package rather than copy it:
+//
+//	cfg := &philosophers.Config{Philosophers: 5, Meals: 3, MaxThink: time.Second, MaxEat: time.Second, Strategy: "waiter"}
+//	table, err := philosophers.NewTable(cfg, nil)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	table.SetStrategy(myStrategy) // Optional.
+//	err = table.Dine(context.Background())
+package philosophers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"Barrier/barrier"
+)
+
+// Config holds the settings of a dinner.
+type Config struct {
+	Philosophers    int           // Number of philosophers, and so of forks, at the table.
+	Meals           int           // Meals each philosopher eats before leaving the table; 0 for no limit.
+	MaxThink        time.Duration // Longest a philosopher thinks before each meal.
+	MaxEat          time.Duration // Longest a meal lasts.
+	Strategy        string        // Name of the strategy the philosophers pick up their forks with.
+	Watchdog        time.Duration // How long without a meal the watchdog reports a deadlock after; 0 for no watchdog.
+	Duration        time.Duration // How long the dinner lasts before everyone is asked to leave; 0 for no limit.
+	Seed            int64         // Seeds each philosopher's think and eat times, so a run can be repeated; 0 for a new seed.
+	InstrumentForks bool          // Makes each fork a barrier.Mutex, timing how long it is held and waited for, for Table.ForkStats.
+	Clock           Clock         // Times the thinking and eating; the system clock if nil.
+	Out             io.Writer     // Where each philosopher says what they are doing; stdout if nil.
+}
+
+// Validate checks the settings describe a dinner that can be held.
+func (c *Config) Validate() error {
+	switch {
+	case c.Philosophers < 2:
+		return fmt.Errorf("there must be at least 2 philosophers, so each has two forks, not %d", c.Philosophers)
+	case c.Meals < 0:
+		return fmt.Errorf("the number of meals cannot be negative, not %d", c.Meals)
+	case c.MaxThink < 0 || c.MaxEat < 0 || c.Watchdog < 0 || c.Duration < 0:
+		return fmt.Errorf("the think, eat, watchdog and duration times cannot be negative")
+	}
+	_, err := NewStrategy(c.Strategy, c.Philosophers)
+	return err
+}
+
+// Fork is a fork on the table: a *sync.Mutex, or a *barrier.Mutex when Config.InstrumentForks is set.
+type Fork interface {
+	Lock()
+	TryLock() bool
+	Unlock()
+}
+
+// Table is a round table of philosophers, with a fork between each pair.
+type Table struct {
+	Config       *Config
+	Forks        []Fork         // Fork f is philosopher f+1's left fork and philosopher f's right.
+	Philosophers []*Philosopher // Philosopher Id-1 sits between forks Id-1 and Id, around the table.
+	Watchdog     *Watchdog      // Follows the dinner.
+}
+
+// NewTable seats cfg.Philosophers philosophers at a table, each picking up their forks with cfg.Strategy and
+// followed by watchdog. A nil watchdog is replaced by one that follows the dinner but never reports, so the table's
+// Watchdog can still give a Snapshot and Stats. It returns an error if cfg is not valid.
+func NewTable(cfg *Config, watchdog *Watchdog) (*Table, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	strategy, err := NewStrategy(cfg.Strategy, cfg.Philosophers)
+	if err != nil {
+		return nil, err
+	}
+	if watchdog == nil {
+		watchdog = NewWatchdog(cfg.Philosophers, 0, io.Discard)
+	}
+	clock, seed := cfg.Clock, cfg.Seed
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	// Create a fork (mutex) for each philosopher.
+	t := &Table{Config: cfg, Forks: make([]Fork, cfg.Philosophers), Philosophers: make([]*Philosopher, cfg.Philosophers), Watchdog: watchdog}
+	for i := range t.Forks {
+		if cfg.InstrumentForks {
+			t.Forks[i] = &barrier.Mutex{}
+		} else {
+			t.Forks[i] = &sync.Mutex{} // Initialize each fork as a mutex
+		}
+	}
+
+	// Assign forks to each philosopher.
+	for i := range t.Philosophers {
+		// Each philosopher gets a left fork and a right fork (next fork in the circle).
+		t.Philosophers[i] = &Philosopher{
+			Id:        i + 1, // Philosopher IDs are 1-based
+			Left:      i,
+			Right:     (i + 1) % cfg.Philosophers, // Right fork is the next one in the circle
+			LeftFork:  t.Forks[i],
+			RightFork: t.Forks[(i+1)%cfg.Philosophers],
+			Config:    cfg,
+			Strategy:  strategy,
+			Watchdog:  watchdog,
+			Clock:     clock,
+			Rand:      rand.New(rand.NewSource(seed + int64(i))), // Each their own, as a rand.Rand is not safe to share
+		}
+	}
+	return t, nil
+}
+
+// SetStrategy has every philosopher pick up their forks with s rather than cfg.Strategy, for trying a strategy of
+// one's own. Call it before Dine.
+func (t *Table) SetStrategy(s Strategy) {
+	for _, p := range t.Philosophers {
+		p.Strategy = s
+	}
+}
+
+// Dine holds the dinner and waits for every philosopher to finish dining. Cancelling ctx asks them all to leave, and
+// Dine returns nil once they have. It returns an error if the watchdog finds them deadlocked, leaving them at the
+// table.
+func (t *Table) Dine(ctx context.Context) error {
+	var wg sync.WaitGroup
+	wg.Add(len(t.Philosophers))
+
+	// Start a goroutine for each philosopher to dine concurrently.
+	for _, phil := range t.Philosophers {
+		go func(p *Philosopher) {
+			defer wg.Done() // Mark this goroutine as done when finished
+			p.dineAll(ctx)  // Philosopher dines until they have eaten every meal or are asked to leave
+		}(phil)
+	}
+
+	// Wait for all philosophers to finish dining, or the watchdog to find them deadlocked.
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return t.Watchdog.Watch(done)
+}
+
+// ForkStats returns how each fork has been used so far, by fork number, or nil unless Config.InstrumentForks is set.
+// Unlike AnalyzeTrace, which times the forks from what the philosophers report, it times the mutexes themselves, so it
+// also counts how often a philosopher found a fork taken. It can be called while the philosophers dine.
+func (t *Table) ForkStats() []barrier.MutexStats {
+	if !t.Config.InstrumentForks {
+		return nil
+	}
+	stats := make([]barrier.MutexStats, len(t.Forks))
+	for f, fork := range t.Forks {
+		stats[f] = fork.(*barrier.Mutex).Stats()
+	}
+	return stats
+}
+
+// WriteForkStats writes ForkStats as a table, with a row totalling the forks, or nothing unless Config.InstrumentForks
+// is set.
+func (t *Table) WriteForkStats(out io.Writer) {
+	stats := t.ForkStats()
+	if stats == nil {
+		return
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Fork\tPickups\tContended\tWaited\tLongest wait\tHeld\tLongest held\t")
+	row := func(name string, s barrier.MutexStats) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%v\t%v\t%v\t%v\t\n", name, s.Acquisitions, s.Contended, s.Wait.Round(time.Millisecond),
+			s.MaxWait.Round(time.Millisecond), s.Hold.Round(time.Millisecond), s.MaxHold.Round(time.Millisecond))
+	}
+	var total barrier.MutexStats
+	for f, s := range stats {
+		row(fmt.Sprint(f), s)
+		total = total.Add(s)
+	}
+	row("All", total)
+	tw.Flush()
+}
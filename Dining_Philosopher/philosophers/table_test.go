@@ -0,0 +1,132 @@
+package philosophers
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// TestDinnerStops checks that cancelling a dinner with no meal limit has every philosopher leave the table and put
+// down their forks, whatever the strategy.
+func TestDinnerStops(t *testing.T) {
+	for _, name := range StrategyNames[1:] { // naive can deadlock.
+		cfg := &Config{Philosophers: 5, MaxThink: time.Millisecond, MaxEat: time.Millisecond, Strategy: name, Out: io.Discard}
+		table, err := NewTable(cfg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		testutil.RequireCompletesWithin(t, 10*time.Second, func() {
+			if err := table.Dine(ctx); err != nil {
+				t.Errorf("%s: Dine() = %v", name, err)
+			}
+		})
+		cancel()
+
+		snap := table.Watchdog.Snapshot()
+		for i, s := range snap.States {
+			if s != Finished {
+				t.Errorf("%s: philosopher %d is %s, want finished", name, i+1, s)
+			}
+		}
+		if name == "chandy-misra" {
+			continue // Forks stay with whoever last ate with them.
+		}
+		for f, holder := range snap.Holders {
+			if holder != 0 {
+				t.Errorf("%s: fork %d is still held by philosopher %d", name, f, holder)
+			}
+		}
+	}
+}
+
+// countingStrategy picks up forks as hierarchy does, counting the meals it has been asked for.
+type countingStrategy struct {
+	hierarchy
+	acquired atomic.Int64
+}
+
+func (s *countingStrategy) Acquire(p *Philosopher) {
+	s.acquired.Add(1)
+	s.hierarchy.Acquire(p)
+}
+
+func TestSetStrategy(t *testing.T) {
+	cfg := &Config{Philosophers: 3, Meals: 2, Strategy: "naive", Out: io.Discard}
+	table, err := NewTable(cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &countingStrategy{}
+	table.SetStrategy(s)
+	if err := table.Dine(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.acquired.Load(); got != 6 {
+		t.Errorf("the strategy was asked for forks %d times, want 6", got)
+	}
+	for _, st := range table.Watchdog.Stats() {
+		if st.Meals != 2 {
+			t.Errorf("philosopher %d ate %d meals, want 2", st.Id, st.Meals)
+		}
+	}
+}
+
+func TestNewTable(t *testing.T) {
+	if _, err := NewTable(&Config{Philosophers: 1, Strategy: "hierarchy"}, nil); err == nil {
+		t.Error("NewTable seated a single philosopher")
+	}
+	table, err := NewTable(&Config{Philosophers: 4, Strategy: "hierarchy"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range table.Philosophers {
+		if p.LeftFork != table.Forks[p.Left] || p.RightFork != table.Forks[p.Right] || p.Right != (i+1)%4 {
+			t.Errorf("philosopher %d sits between forks %d and %d", p.Id, p.Left, p.Right)
+		}
+	}
+}
+
+// TestForkStats checks that instrumented forks count every pickup, and that plain forks give no statistics.
+func TestForkStats(t *testing.T) {
+	cfg := &Config{Philosophers: 5, Meals: 4, MaxThink: time.Millisecond, MaxEat: time.Millisecond, Strategy: "hierarchy", Out: io.Discard}
+	table, err := NewTable(cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.ForkStats() != nil {
+		t.Error("ForkStats() without InstrumentForks is not nil")
+	}
+
+	cfg.InstrumentForks = true
+	if table, err = NewTable(cfg, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Dine(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	stats := table.ForkStats()
+	if len(stats) != 5 {
+		t.Fatalf("ForkStats() has %d forks, want 5", len(stats))
+	}
+	for f, s := range stats {
+		// Each fork is picked up for every meal of the two philosophers beside it.
+		if s.Acquisitions != 8 {
+			t.Errorf("fork %d was picked up %d times, want 8", f, s.Acquisitions)
+		}
+		if s.Hold <= 0 {
+			t.Errorf("fork %d was never held", f)
+		}
+	}
+
+	var out strings.Builder
+	table.WriteForkStats(&out)
+	if lines := strings.Count(out.String(), "\n"); lines != 7 {
+		t.Errorf("WriteForkStats wrote %d lines, want a header, 5 forks and a total:\n%s", lines, out.String())
+	}
+}
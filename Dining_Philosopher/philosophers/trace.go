@@ -0,0 +1,76 @@
+//--------------------------------------------
+// Author: Ronan Green
+// Created on 14/10/2024
+// Modified by: Ronan Green
+// Description:
+// Writes every fork picked up or put down, and every wait and meal started
+// or ended, as a timestamped JSON line, for AnalyzeTrace to
+// replay.
+//--------------------------------------------
+
+package philosophers
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Kinds of event in a trace.
+const (
+	EventStart     = "start"      // The dinner started; the event gives the number of philosophers and the strategy.
+	EventPickup    = "pickup"     // A philosopher picked up a fork.
+	EventPutdown   = "putdown"    // A philosopher put down, or handed over, a fork.
+	EventWaitStart = "wait_start" // A philosopher became hungry and started waiting for forks.
+	EventWaitEnd   = "wait_end"   // A philosopher stopped waiting, usually because they have both forks.
+	EventEatStart  = "eat_start"  // A philosopher started eating.
+	EventEatEnd    = "eat_end"    // A philosopher finished eating.
+	EventLeave     = "leave"      // A philosopher left the table.
+)
+
+// Event is one line of a trace, such as
+// {"time":"2024-10-14T12:00:00.001Z","event":"pickup","philosopher":2,"fork":1}.
+type Event struct {
+	Time         time.Time `json:"time"`
+	Kind         string    `json:"event"`
+	Philosopher  int       `json:"philosopher,omitempty"`  // Id of the philosopher, from 1.
+	Fork         *int      `json:"fork,omitempty"`         // Number of the fork, from 0, for pickup and putdown.
+	Philosophers int       `json:"philosophers,omitempty"` // Number of philosophers, for start.
+	Strategy     string    `json:"strategy,omitempty"`     // Name of the strategy, for start.
+}
+
+// tracer writes events as JSON lines, keeping the first error.
+type tracer struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+	err error
+}
+
+func newTracer(out io.Writer) *tracer {
+	buf := bufio.NewWriter(out)
+	return &tracer{buf: buf, enc: json.NewEncoder(buf)}
+}
+
+// emit writes e, unless writing an earlier event failed.
+func (t *tracer) emit(e Event) {
+	if t.err == nil {
+		t.err = t.enc.Encode(e)
+	}
+}
+
+// flush writes any buffered events, returning the first error writing any.
+func (t *tracer) flush() error {
+	if t.err == nil {
+		t.err = t.buf.Flush()
+	}
+	return t.err
+}
+
+// stateEvents holds the events for starting, and for ending, each state of a philosopher, or "" for none.
+var stateEvents = [...]struct{ start, end string }{
+	Thinking: {},
+	Hungry:   {EventWaitStart, EventWaitEnd},
+	Eating:   {EventEatStart, EventEatEnd},
+	Finished: {EventLeave, ""},
+}
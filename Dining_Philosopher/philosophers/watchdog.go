@@ -0,0 +1,273 @@
+//--------------------------------------------
+// Author: Ronan Green
+// Created on 14/10/2024
+// Modified by: Ronan Green
+// Description:
+// A watchdog that follows what each philosopher is doing and which forks
+// they hold, and reports a deadlock or a starving philosopher rather than
+// letting the dinner hang silently.
+//--------------------------------------------
+
+package philosophers
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Watchdog follows the philosophers at a dinner. If no one eats for Timeout while every philosopher still at the table
+// is hungry, it reports the dinner as deadlocked, saying what each philosopher is doing and which forks they hold, and
+// if one philosopher waits that long for forks
+// while others eat it reports them as starving. A nil *Watchdog follows nothing, so philosophers can dine without one.
+type Watchdog struct {
+	Timeout time.Duration // How long without a meal is a deadlock, or without forks is starvation; 0 to never report.
+	Out     io.Writer     // Where reports are written.
+
+	mu       sync.Mutex
+	now      func() time.Time
+	lastMeal time.Time        // When someone last started eating, or the dinner started.
+	diners   []dinerRecord    // What each philosopher is doing, by Id-1.
+	holders  []int            // Id of the philosopher holding each fork, or 0 if it is on the table.
+	starving map[int]struct{} // Ids of philosophers reported as starving and not yet fed.
+	trace    *tracer          // Where events are written, or nil.
+}
+
+// dinerRecord is what the watchdog knows of one philosopher.
+type dinerRecord struct {
+	state       State
+	since       time.Time     // When they started doing it.
+	meals       int           // Meals eaten.
+	thought     time.Duration // Total time spent thinking.
+	waited      time.Duration // Total time spent hungry.
+	longestWait time.Duration // Longest time spent hungry before a meal.
+}
+
+// NewWatchdog returns a watchdog for a table of n philosophers, reporting to out.
+func NewWatchdog(n int, timeout time.Duration, out io.Writer) *Watchdog {
+	w := &Watchdog{Timeout: timeout, Out: out, now: time.Now, diners: make([]dinerRecord, n), holders: make([]int, n), starving: map[int]struct{}{}}
+	w.lastMeal = w.now()
+	for i := range w.diners {
+		w.diners[i].since = w.lastMeal
+	}
+	return w
+}
+
+// set records that philosopher id is now in state s.
+func (w *Watchdog) set(id int, s State) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.now()
+	d := &w.diners[id-1]
+	d.finish(now)
+	if w.trace != nil && d.state != s {
+		if kind := stateEvents[d.state].end; kind != "" {
+			w.trace.emit(Event{Time: now, Kind: kind, Philosopher: id})
+		}
+		if kind := stateEvents[s].start; kind != "" {
+			w.trace.emit(Event{Time: now, Kind: kind, Philosopher: id})
+		}
+	}
+	if s == Eating {
+		d.meals++
+		w.lastMeal = now
+		delete(w.starving, id)
+	}
+	d.state, d.since = s, now
+}
+
+// held records that philosopher id, or no one if id is 0, now holds fork f.
+func (w *Watchdog) held(f, id int) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if previous := w.holders[f]; w.trace != nil && previous != id {
+		now := w.now()
+		if previous != 0 {
+			w.trace.emit(Event{Time: now, Kind: EventPutdown, Philosopher: previous, Fork: &f})
+		}
+		if id != 0 {
+			w.trace.emit(Event{Time: now, Kind: EventPickup, Philosopher: id, Fork: &f})
+		}
+	}
+	w.holders[f] = id
+}
+
+// TraceTo has the watchdog write every event at the dinner described by cfg to out, as JSON lines, starting with a
+// start event. Call it before the dinner starts, and FlushTrace once it ends.
+func (w *Watchdog) TraceTo(out io.Writer, cfg *Config) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.trace = newTracer(out)
+	w.trace.emit(Event{Time: w.now(), Kind: EventStart, Philosophers: len(w.diners), Strategy: cfg.Strategy})
+}
+
+// FlushTrace writes any events still buffered, returning the first error writing the trace, if any.
+func (w *Watchdog) FlushTrace() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.trace == nil {
+		return nil
+	}
+	return w.trace.flush()
+}
+
+// Watch checks on the dinner until done is closed, returning an error once it has reported a deadlock. A starving
+// philosopher is reported once each time they starve, and the dinner goes on.
+func (w *Watchdog) Watch(done <-chan struct{}) error {
+	if w.Timeout <= 0 {
+		<-done
+		return nil
+	}
+	ticker := time.NewTicker(max(w.Timeout/10, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return nil
+		case <-ticker.C:
+			if err := w.check(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// check reports a deadlock, returning it as an error, or any newly starving philosophers: those who have waited
+// Timeout for forks while someone else has started eating.
+func (w *Watchdog) check() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.now()
+	if idle := now.Sub(w.lastMeal); idle >= w.Timeout && w.stuck() {
+		fmt.Fprintf(w.Out, "Watchdog: no philosopher has eaten for %v and all are waiting for forks, so the dinner is deadlocked\n", idle.Round(time.Millisecond))
+		w.writeTable(now)
+		fmt.Fprintf(w.Out, "Goroutines:\n%s\n", goroutines())
+		return fmt.Errorf("deadlock: no philosopher has eaten for %v", idle.Round(time.Millisecond))
+	}
+	for i, d := range w.diners {
+		if _, reported := w.starving[i+1]; d.state == Hungry && !reported && now.Sub(d.since) >= w.Timeout && w.lastMeal.After(d.since) {
+			w.starving[i+1] = struct{}{}
+			fmt.Fprintf(w.Out, "Watchdog: philosopher %d is starving, having waited %v for forks while others eat\n", i+1, now.Sub(d.since).Round(time.Millisecond))
+		}
+	}
+	return nil
+}
+
+// stuck reports whether every philosopher still at the table is hungry, so none can free a fork for another. The caller
+// holds w.mu.
+func (w *Watchdog) stuck() bool {
+	waiting := false
+	for _, d := range w.diners {
+		switch d.state {
+		case Thinking, Eating:
+			return false
+		case Hungry:
+			waiting = true
+		}
+	}
+	return waiting
+}
+
+// writeTable writes what each philosopher is doing and which forks they hold. The caller holds w.mu.
+func (w *Watchdog) writeTable(now time.Time) {
+	for i, d := range w.diners {
+		var forks []string
+		for f, holder := range w.holders {
+			if holder == i+1 {
+				forks = append(forks, fmt.Sprint(f))
+			}
+		}
+		held := "holding no forks"
+		if len(forks) > 0 {
+			held = "holding fork " + strings.Join(forks, " and ")
+		}
+		fmt.Fprintf(w.Out, "Philosopher %d: %s for %v, %s\n", i+1, d.state, now.Sub(d.since).Round(time.Millisecond), held)
+	}
+}
+
+// Snapshot is what is happening at the table at one moment, for drawing it.
+type Snapshot struct {
+	Time    time.Time   // When the snapshot was taken.
+	States  []State     // What each philosopher is doing, by Id-1.
+	Since   []time.Time // When each philosopher started doing it, by Id-1.
+	Meals   []int       // Meals each philosopher has eaten, by Id-1.
+	Holders []int       // Id of the philosopher holding each fork, or 0 if it is on the table.
+}
+
+// Snapshot returns what each philosopher is doing and who holds each fork.
+func (w *Watchdog) Snapshot() Snapshot {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n := len(w.diners)
+	snap := Snapshot{Time: w.now(), States: make([]State, n), Since: make([]time.Time, n), Meals: make([]int, n), Holders: slices.Clone(w.holders)}
+	for i, d := range w.diners {
+		snap.States[i], snap.Since[i], snap.Meals[i] = d.state, d.since, d.meals
+	}
+	return snap
+}
+
+// finish adds the time spent in the current state up to now to the totals.
+func (d *dinerRecord) finish(now time.Time) {
+	switch d.state {
+	case Thinking:
+		d.thought += now.Sub(d.since)
+	case Hungry:
+		wait := now.Sub(d.since)
+		d.waited += wait
+		d.longestWait = max(d.longestWait, wait)
+	}
+}
+
+// Stats is what one philosopher did at a dinner.
+type Stats struct {
+	Id          int
+	Meals       int           // Meals eaten.
+	Thought     time.Duration // Total time spent thinking.
+	Waited      time.Duration // Total time spent hungry, waiting for forks.
+	LongestWait time.Duration // Longest time spent hungry at once.
+}
+
+// Stats returns what each philosopher has done so far, counting the time spent in what they are doing now, so a
+// philosopher stuck in a deadlock has waited until the call.
+func (w *Watchdog) Stats() []Stats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.now()
+	stats := make([]Stats, len(w.diners))
+	for i, d := range w.diners {
+		d.finish(now) // d is a copy, so the totals kept are unchanged.
+		stats[i] = Stats{Id: i + 1, Meals: d.meals, Thought: d.thought, Waited: d.waited, LongestWait: d.longestWait}
+	}
+	return stats
+}
+
+// WriteSummary writes how many meals each philosopher ate and how long they waited for forks, to show whether any
+// starved.
+func (w *Watchdog) WriteSummary(out io.Writer) {
+	for _, s := range w.Stats() {
+		fmt.Fprintf(out, "Philosopher %d ate %d meals, waiting %v for forks in all and at most %v at once\n",
+			s.Id, s.Meals, s.Waited.Round(time.Millisecond), s.LongestWait.Round(time.Millisecond))
+	}
+}
+
+// goroutines returns the stacks of every goroutine, growing the buffer until they fit.
+func goroutines() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,152 @@
+package philosophers
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock the test moves by hand.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestWatchdog(n int) (*Watchdog, *fakeClock, *strings.Builder) {
+	var out strings.Builder
+	clock := &fakeClock{t: time.Date(2024, 10, 14, 12, 0, 0, 0, time.UTC)}
+	w := NewWatchdog(n, time.Second, &out)
+	w.now = clock.now
+	w.lastMeal = clock.t
+	for i := range w.diners {
+		w.diners[i].since = clock.t
+	}
+	return w, clock, &out
+}
+
+func TestWatchdogDeadlock(t *testing.T) {
+	w, clock, out := newTestWatchdog(2)
+	for id := 1; id <= 2; id++ {
+		w.set(id, Hungry)
+		w.held(id-1, id) // Each holds their left fork and waits for the right.
+	}
+	clock.t = clock.t.Add(500 * time.Millisecond)
+	if err := w.check(); err != nil {
+		t.Fatalf("check() after 500ms = %v, want nil", err)
+	}
+	clock.t = clock.t.Add(time.Second)
+	if err := w.check(); err == nil {
+		t.Fatal("check() after 1.5s = nil, want a deadlock")
+	}
+	for _, want := range []string{"deadlocked", "Philosopher 1: hungry for 1.5s, holding fork 0", "Philosopher 2: hungry for 1.5s, holding fork 1", "Goroutines:"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWatchdogNotDeadlockedWhileEating(t *testing.T) {
+	w, clock, _ := newTestWatchdog(2)
+	w.set(1, Hungry)
+	w.set(1, Eating)
+	w.set(2, Hungry)
+	clock.t = clock.t.Add(time.Minute) // A long meal is not a deadlock.
+	if err := w.check(); err != nil {
+		t.Errorf("check() = %v, want nil", err)
+	}
+}
+
+func TestWatchdogStarvation(t *testing.T) {
+	w, clock, out := newTestWatchdog(3)
+	w.set(1, Hungry)
+	for range 3 {
+		clock.t = clock.t.Add(400 * time.Millisecond)
+		w.set(2, Hungry)
+		w.set(2, Eating)
+		w.set(2, Thinking)
+		if err := w.check(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.check() // Reported only once.
+	if got := strings.Count(out.String(), "philosopher 1 is starving"); got != 1 {
+		t.Errorf("philosopher 1 reported starving %d times, want 1:\n%s", got, out)
+	}
+	if strings.Contains(out.String(), "philosopher 2") {
+		t.Errorf("philosopher 2 reported starving:\n%s", out)
+	}
+
+	clock.t = clock.t.Add(300 * time.Millisecond)
+	w.set(1, Eating)
+	var summary strings.Builder
+	w.WriteSummary(&summary)
+	if want := "Philosopher 1 ate 1 meals, waiting 1.5s for forks in all and at most 1.5s at once"; !strings.Contains(summary.String(), want) {
+		t.Errorf("summary does not contain %q:\n%s", want, &summary)
+	}
+}
+
+// TestWatchNaive checks the watchdog catches a real deadlock: every philosopher holding their left fork.
+func TestWatchNaive(t *testing.T) {
+	philosophers := table(t, "naive", 3)
+	var out strings.Builder
+	w := NewWatchdog(3, 50*time.Millisecond, &out)
+	for _, p := range philosophers {
+		p.Watchdog = w
+		w.set(p.Id, Hungry)
+		p.PickUp(p.Left)
+	}
+	for _, p := range philosophers {
+		go p.PickUp(p.Right) // Blocks forever, as the neighbour holds it.
+	}
+	if err := w.Watch(make(chan struct{})); err == nil {
+		t.Fatal("Watch() = nil, want a deadlock")
+	}
+	if !strings.Contains(out.String(), "Philosopher 3: hungry") || !strings.Contains(out.String(), "holding fork 2") {
+		t.Errorf("report does not say who holds what:\n%s", &out)
+	}
+}
+
+func TestStats(t *testing.T) {
+	w, clock, _ := newTestWatchdog(2)
+	clock.t = clock.t.Add(20 * time.Millisecond)
+	w.set(1, Hungry)
+	clock.t = clock.t.Add(5 * time.Millisecond)
+	w.set(1, Eating)
+	clock.t = clock.t.Add(10 * time.Millisecond)
+	w.set(1, Thinking)
+	w.set(2, Hungry)
+	clock.t = clock.t.Add(7 * time.Millisecond) // Philosopher 2 is still waiting.
+
+	want := []Stats{
+		{Id: 1, Meals: 1, Thought: 27 * time.Millisecond, Waited: 5 * time.Millisecond, LongestWait: 5 * time.Millisecond},
+		{Id: 2, Meals: 0, Thought: 35 * time.Millisecond, Waited: 7 * time.Millisecond, LongestWait: 7 * time.Millisecond},
+	}
+	got := w.Stats()
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Stats()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if again := w.Stats(); again[1] != want[1] {
+		t.Errorf("Stats() changed the totals: %+v, want %+v", again[1], want[1])
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	w, _, _ := newTestWatchdog(3)
+	w.set(1, Hungry)
+	w.set(1, Eating)
+	w.held(0, 1)
+	w.held(1, 1)
+	w.set(2, Hungry)
+	snap := w.Snapshot()
+	if want := []State{Eating, Hungry, Thinking}; !slices.Equal(snap.States, want) {
+		t.Errorf("States = %v, want %v", snap.States, want)
+	}
+	if want := []int{1, 0, 0}; !slices.Equal(snap.Meals, want) {
+		t.Errorf("Meals = %v, want %v", snap.Meals, want)
+	}
+	if want := []int{1, 1, 0}; !slices.Equal(snap.Holders, want) {
+		t.Errorf("Holders = %v, want %v", snap.Holders, want)
+	}
+}
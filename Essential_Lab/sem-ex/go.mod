@@ -2,4 +2,6 @@ module sem-ex
 
 go 1.23.1
 
-require golang.org/x/sync v0.8.0
+require Barrier v0.0.0
+
+replace Barrier => ../../Barrier
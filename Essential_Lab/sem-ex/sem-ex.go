@@ -6,7 +6,7 @@ import (
 	"log"
 	"runtime"
 
-	"golang.org/x/sync/semaphore"
+	"Barrier/barrier"
 )
 
 // Example_workerPool demonstrates how to use a semaphore to limit the number of
@@ -19,7 +19,7 @@ func main() {
 
 	var (
 		maxWorkers = runtime.GOMAXPROCS(0)
-		sem        = semaphore.NewWeighted(int64(maxWorkers))
+		sem        = barrier.NewSemaphore(maxWorkers)
 		out        = make([]int, 64)
 	)
 
@@ -42,7 +42,7 @@ func main() {
 	//
 	// If you are already waiting for the workers by some other means (such as an
 	// errgroup.Group), you can omit this final Acquire call.
-	if err := sem.Acquire(ctx, int64(maxWorkers)); err != nil {
+	if err := sem.Acquire(ctx, maxWorkers); err != nil {
 		log.Printf("Failed to acquire semaphore: %v", err)
 	}
 
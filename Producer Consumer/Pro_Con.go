@@ -3,43 +3,358 @@
 // Created on 14/10/2024
 // Modified by: Ronan Green
 // Description:
-// 
+// Producers send numbered items down a channel, which may be buffered,
+// to consumers that take them off it, each working at its own pace.
+// Ctrl+C stops the producers, and the consumers then finish the items
+// already sent, within the drain timeout if there is one.
+// The producers can be held to a rate, and held back while the consumers
+// work through a backlog, which is reported as it grows and shrinks.
+// The program ends as soon as every item has been consumed, and prints
+// how the work was shared out.
 // Issues:
-// 
-//
+// The first version slept for 10 seconds and hoped both had finished,
+// which cut the consumer off before its last item, as consuming ten items
+// at a second each takes at least 11 seconds once the first is produced.
 //--------------------------------------------
 
 package main
 
 import (
-    "fmt"
-    "time"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"Pro_Con/ratelimit"
 )
 
-// producer sends integers to the channel 'ch'.
-// It runs in a loop from 0 to 9, simulating a delay with time.Sleep.
-// After sending all values, it closes the channel.
-func producer(ch chan int) {
-    for i := 0; i < 10; i++ {
-        time.Sleep(time.Second) // Simulate some work with a 1-second delay
-        fmt.Println("Producer: sending", i) // Log the value being sent
-        ch <- i // Send the value to the channel
-    }
-    close(ch) // Close the channel to signal no more values will be sent
+// config holds the settings of a run.
+type config struct {
+	items        int           // Items sent in all, shared out between the producers.
+	producers    int           // Goroutines sending items.
+	consumers    int           // Goroutines taking items.
+	buffer       int           // Items the channel holds before the producers block; 0 for an unbuffered channel.
+	produce      time.Duration // How long producing each item takes.
+	consume      time.Duration // How long consuming each item takes.
+	rate         float64       // Items the producers may send a second between them; 0 for no limit.
+	burst        int           // Items the producers may send at once, above the rate, after a pause.
+	high         int           // Backlog at which the producers stop sending; 0 to never stop them.
+	low          int           // Backlog the consumers must bring it down to before the producers go on.
+	report       time.Duration // How often to report the backlog; 0 to not report it.
+	drainTimeout time.Duration // How long the consumers have to finish once the producers stop; 0 for as long as they need.
+	out          io.Writer     // Where the producers and consumers say what they are doing.
+}
+
+// result is what each producer and consumer did in a run.
+type result struct {
+	produced []int         // Number of items each producer sent, by producer.
+	consumed [][]int       // The items each consumer took, by consumer, in the order it took them.
+	dropped  []int         // The items left on the channel when the drain timed out.
+	elapsed  time.Duration // From starting the producers to the last item being consumed or dropped.
+	peak     int           // The deepest the backlog of items sent but not yet taken grew.
+	pauses   int           // Times the backlog reached the high watermark, stopping the producers.
+}
+
+// producer sends its share of the items to the channel 'ch': producer id, from 0, sends the integers id, id+producers,
+// id+2*producers and so on below cfg.items, so between them the producers send each item once.
+// It waits for the limiter, if there is one, before producing each item, simulates the work with a sleep, and
+// waits for the backlog to let it send it. It returns the number it sent, which is fewer than its share if ctx is
+// cancelled first: it then stops wherever it is waiting, and the item it was producing is never sent.
+func producer(ctx context.Context, id int, ch chan<- int, cfg config, limiter *ratelimit.TokenBucket, b *backlog) int {
+	sent := 0
+	for i := id; i < cfg.items; i += cfg.producers {
+		if limiter != nil && limiter.Wait(ctx) != nil {
+			break
+		}
+		if sleep(ctx, cfg.produce) != nil { // Simulate some work
+			break
+		}
+		if !b.add() { // Wait while the consumers work through a backlog
+			break
+		}
+		fmt.Fprintf(cfg.out, "Producer %d: sending %d\n", id, i) // Log the value being sent
+		select {
+		case ch <- i: // Send the value to the channel
+			sent++
+		case <-ctx.Done():
+			b.withdraw()
+			return sent
+		}
+	}
+	return sent
+}
+
+// sleep waits for d, or until ctx is cancelled, returning ctx.Err().
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // consumer receives integers from the read-only channel 'ch'.
-// It processes values received from the channel until it is closed.
-func consumer(ch <-chan int) {
-    for i := range ch { // Read values from the channel until it's closed
-        time.Sleep(time.Second) // Simulate some work with a 1-second delay
-        fmt.Println("Consumer: receiving", i) // Log the value being received
-    }
+// It processes values received from the channel until it is closed, and returns them in the order received.
+// Once abandon is closed it takes no more, but an item it has already taken is always finished.
+func consumer(id int, ch <-chan int, abandon <-chan struct{}, cfg config, b *backlog) []int {
+	var consumed []int
+	for {
+		select {
+		case <-abandon: // Checked first, as select picks at random when an item is waiting too.
+			return consumed
+		default:
+		}
+		select {
+		case i, ok := <-ch:
+			if !ok { // Closed and empty: every item has been consumed
+				return consumed
+			}
+			b.remove()
+			time.Sleep(cfg.consume)                                    // Simulate some work
+			fmt.Fprintf(cfg.out, "Consumer %d: receiving %d\n", id, i) // Log the value being received
+			consumed = append(consumed, i)
+		case <-abandon:
+			return consumed
+		}
+	}
+}
+
+// system is a run in progress: the producers, the consumers and the channel between them. Create one with start,
+// stop the producers early with Close, and wait for the consumers to finish with Drain.
+//
+// With several producers none of them can close the channel when it is done, as the others may still be sending, so
+// the channel is closed once a WaitGroup of the producers has finished, whether they sent their whole share or were
+// stopped by Close. The consumers finish once it is closed and they have taken every item left on it, unless Drain
+// runs out of time and abandons them.
+type system struct {
+	cfg           config
+	ch            chan int
+	backlog       *backlog
+	result        result
+	start         time.Time
+	cancel        context.CancelFunc // Stops the producers.
+	closeOnce     sync.Once
+	finished      chan struct{} // Closed once every producer has returned and the channel is closed.
+	abandon       chan struct{} // Closed when the drain times out, to stop the consumers taking items.
+	consumers     sync.WaitGroup
+	stopReporting func()
+}
+
+// start starts the producers and consumers of a run. The producers share one rate limiter, so -rate limits them all
+// together, and one backlog, which a goroutine reports every cfg.report until the run is drained.
+func start(cfg config) *system {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &system{
+		cfg:      cfg,
+		ch:       make(chan int, cfg.buffer), // Unbuffered when cfg.buffer is 0
+		backlog:  newBacklog(cfg.high, cfg.low),
+		result:   result{produced: make([]int, cfg.producers), consumed: make([][]int, cfg.consumers)},
+		start:    time.Now(),
+		cancel:   cancel,
+		finished: make(chan struct{}),
+		abandon:  make(chan struct{}),
+	}
+	var limiter *ratelimit.TokenBucket
+	if cfg.rate > 0 {
+		limiter = ratelimit.New(cfg.rate, max(cfg.burst, 1))
+	}
+	s.stopReporting = startReporting(s.backlog, cfg)
+
+	var producers sync.WaitGroup
+	producers.Add(cfg.producers)
+	for id := range cfg.producers {
+		go func() {
+			defer producers.Done()
+			s.result.produced[id] = producer(ctx, id, s.ch, cfg, limiter, s.backlog) // Each goroutine writes only its own entry
+		}()
+	}
+	go func() {
+		producers.Wait()
+		close(s.ch) // Close the channel to signal no more values will be sent
+		close(s.finished)
+	}()
+
+	s.consumers.Add(cfg.consumers)
+	for id := range cfg.consumers {
+		go func() {
+			defer s.consumers.Done()
+			s.result.consumed[id] = consumer(id, s.ch, s.abandon, cfg, s.backlog)
+		}()
+	}
+	return s
+}
+
+// Close stops the producers, wherever they are waiting, and returns once they all have and the channel is closed.
+// The items already sent stay on the channel for the consumers. Closing a system whose producers have sent all their
+// items, or closing it twice, does nothing.
+func (s *system) Close() {
+	s.closeOnce.Do(func() {
+		s.cancel()
+		s.backlog.close() // Release producers held back by the backlog.
+	})
+	<-s.finished
+}
+
+// Drain closes s, if it is not already, and waits for the consumers to finish every item left on the channel. With a
+// timeout above zero, it waits no longer than that: the consumers then finish the items they have in hand, but take
+// no more, and the items still on the channel are dropped. It returns what the producers and consumers did, including
+// the items dropped. Drain is called once, at the end of a run.
+func (s *system) Drain(timeout time.Duration) result {
+	s.Close()
+	drained := make(chan struct{})
+	go func() {
+		s.consumers.Wait()
+		close(drained)
+	}()
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-drained:
+		case <-timer.C:
+			close(s.abandon)
+			<-drained // Wait for the items in hand.
+		}
+	} else {
+		<-drained
+	}
+	for i := range s.ch { // The channel is closed, so this takes only the items left on it.
+		s.result.dropped = append(s.result.dropped, i)
+	}
+
+	s.result.elapsed = time.Since(s.start)
+	s.stopReporting()
+	s.result.peak, s.result.pauses = s.backlog.peak, s.backlog.pauses // Safe to read now every producer and consumer has finished.
+	return s.result
+}
+
+// run starts the producers and consumers and waits for the producers to send every item or for ctx to be cancelled,
+// as it is by Ctrl+C, whichever is first, then drains the run, giving the consumers up to cfg.drainTimeout to finish.
+// Without a timeout, run returns exactly when the last item has been consumed, however the times and buffer size
+// are set.
+func run(ctx context.Context, cfg config) result {
+	s := start(cfg)
+	select {
+	case <-s.finished:
+	case <-ctx.Done():
+		fmt.Fprintln(cfg.out, "Stopping the producers and draining the items sent")
+	}
+	return s.Drain(cfg.drainTimeout)
+}
+
+// startReporting reports the backlog to cfg.out every cfg.report, so its depth can be watched as production outpaces
+// consumption or falls behind it. It returns a function that stops the reports and waits for the last one to be
+// written, so none appears after the summary.
+func startReporting(b *backlog, cfg config) (stop func()) {
+	if cfg.report <= 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(cfg.report)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				b.report(cfg.out)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// throughput returns the items consumed per second.
+func (r result) throughput() float64 {
+	total := 0
+	for _, items := range r.consumed {
+		total += len(items)
+	}
+	if r.elapsed <= 0 {
+		return 0
+	}
+	return float64(total) / r.elapsed.Seconds()
+}
+
+// writeSummary writes a table of how many items each producer sent and each consumer took, and their share of the
+// total, so an uneven distribution of the work shows, followed by the total throughput.
+func (r result) writeSummary(out io.Writer) {
+	total := 0
+	for _, n := range r.produced {
+		total += n
+	}
+	share := func(n int) string {
+		if total == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f%%", 100*float64(n)/float64(total))
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Worker\tItems\tShare\t")
+	for id, n := range r.produced {
+		fmt.Fprintf(tw, "Producer %d\t%d\t%s\t\n", id, n, share(n))
+	}
+	for id, items := range r.consumed {
+		fmt.Fprintf(tw, "Consumer %d\t%d\t%s\t\n", id, len(items), share(len(items)))
+	}
+	tw.Flush()
+	consumed := total - len(r.dropped)
+	fmt.Fprintf(out, "\n%d items consumed in %v: %.1f items/s\n", consumed, r.elapsed.Round(time.Millisecond), r.throughput())
+	if len(r.dropped) > 0 {
+		fmt.Fprintf(out, "%d items dropped when the drain timed out: %v\n", len(r.dropped), r.dropped)
+	}
+	fmt.Fprintf(out, "Peak queue depth %d; producers paused %d times\n", r.peak, r.pauses)
 }
 
 func main() {
-    ch := make(chan int) // Create an unbuffered channel of type int
-    go producer(ch) // Start the producer goroutine
-    go consumer(ch) // Start the consumer goroutine
-    time.Sleep(time.Second * 10) // Allow some time for producer and consumer to complete their work
-}
\ No newline at end of file
+	cfg := config{out: os.Stdout}
+	flag.IntVar(&cfg.items, "items", 10, "number of items sent in all, shared out between the producers")
+	flag.IntVar(&cfg.producers, "producers", 1, "number of producer goroutines")
+	flag.IntVar(&cfg.consumers, "consumers", 1, "number of consumer goroutines")
+	flag.IntVar(&cfg.buffer, "buffer", 0, "number of items the channel holds before the producers wait; 0 for an unbuffered channel")
+	flag.DurationVar(&cfg.produce, "produce", time.Second, "how long producing each item takes")
+	flag.DurationVar(&cfg.consume, "consume", time.Second, "how long consuming each item takes")
+	flag.Float64Var(&cfg.rate, "rate", 0, "items the producers may send a second between them; 0 for no limit")
+	flag.IntVar(&cfg.burst, "burst", 1, "items the producers may send at once, above the rate, after a pause")
+	flag.IntVar(&cfg.high, "high", 0, "backlog of items sent but not yet taken at which the producers stop; 0 to never stop them")
+	flag.IntVar(&cfg.low, "low", 0, "backlog the consumers must bring it down to before the producers go on")
+	flag.DurationVar(&cfg.report, "report", 0, "how often to report the backlog, such as 500ms; 0 to not report it")
+	flag.DurationVar(&cfg.drainTimeout, "drain-timeout", 0, "how long the consumers have to finish the items sent once the producers stop; 0 for as long as they need")
+	flag.Parse()
+	if cfg.items < 0 || cfg.buffer < 0 || cfg.produce < 0 || cfg.consume < 0 || cfg.rate < 0 || cfg.report < 0 || cfg.drainTimeout < 0 {
+		fmt.Fprintln(os.Stderr, "Error: items, buffer, rate and times cannot be negative")
+		os.Exit(2)
+	}
+	if cfg.burst < 1 {
+		fmt.Fprintln(os.Stderr, "Error: the burst must be at least one item")
+		os.Exit(2)
+	}
+	if cfg.high < 0 || (cfg.high > 0 && (cfg.low < 0 || cfg.low >= cfg.high)) {
+		fmt.Fprintln(os.Stderr, "Error: the high watermark cannot be negative, and the low one must be below it")
+		os.Exit(2)
+	}
+	if cfg.producers < 1 || cfg.consumers < 1 {
+		fmt.Fprintln(os.Stderr, "Error: there must be at least one producer and one consumer")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt) // Ctrl+C stops the producers.
+	defer stop()
+	r := run(ctx, cfg)
+	fmt.Println()
+	r.writeSummary(os.Stdout)
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// TestRun checks that every item is consumed exactly once, and that run returns once they are, however many
+// producers and consumers there are, whichever of them is slower and however large the buffer.
+func TestRun(t *testing.T) {
+	for _, workers := range [][2]int{{1, 1}, {3, 1}, {1, 3}, {4, 4}} {
+		for _, buffer := range []int{0, 1, 5, 100} {
+			for _, times := range [][2]time.Duration{{0, 0}, {time.Millisecond, 0}, {0, time.Millisecond}} {
+				cfg := config{items: 20, producers: workers[0], consumers: workers[1], buffer: buffer, produce: times[0], consume: times[1], out: io.Discard}
+				var r result
+				testutil.RequireCompletesWithin(t, 10*time.Second, func() {
+					r = run(context.Background(), cfg)
+				})
+				var consumed []int
+				for _, items := range r.consumed {
+					consumed = append(consumed, items...)
+				}
+				slices.Sort(consumed)
+				want := make([]int, cfg.items)
+				for i := range want {
+					want[i] = i
+				}
+				if !slices.Equal(consumed, want) {
+					t.Errorf("%+v: consumed %v, want %v", cfg, consumed, want)
+				}
+				produced := 0
+				for _, n := range r.produced {
+					produced += n
+				}
+				if produced != cfg.items {
+					t.Errorf("%+v: produced %v, want %d in all", cfg, r.produced, cfg.items)
+				}
+			}
+		}
+	}
+}
+
+// TestRunOrder checks that a single consumer takes a single producer's items in the order they were sent.
+func TestRunOrder(t *testing.T) {
+	r := run(context.Background(), config{items: 20, producers: 1, consumers: 1, out: io.Discard})
+	if !slices.IsSorted(r.consumed[0]) {
+		t.Errorf("consumed %v, want them in order", r.consumed[0])
+	}
+}
+
+// TestRunNoItems checks that producers with nothing to send still let the consumers finish.
+func TestRunNoItems(t *testing.T) {
+	testutil.RequireCompletesWithin(t, 10*time.Second, func() {
+		r := run(context.Background(), config{producers: 2, consumers: 2, out: io.Discard})
+		for id, items := range r.consumed {
+			if len(items) != 0 {
+				t.Errorf("consumer %d consumed %v, want nothing", id, items)
+			}
+		}
+	})
+}
+
+// TestRunRateLimit checks that the producers between them send no faster than the rate once the burst is spent.
+func TestRunRateLimit(t *testing.T) {
+	cfg := config{items: 20, producers: 4, consumers: 4, buffer: 20, rate: 200, burst: 4, out: io.Discard}
+	r := run(context.Background(), cfg)
+	// The burst goes at once and the other 16 at 200 a second, so no sooner than 80ms.
+	if least := 80 * time.Millisecond; r.elapsed < least {
+		t.Errorf("20 items at 200 a second, in bursts of 4, took %v, want at least %v", r.elapsed, least)
+	}
+}
+
+// TestRunBackpressure checks that fast producers stop once the backlog reaches the high watermark, though the buffer
+// has room for more, and that every item still gets through.
+func TestRunBackpressure(t *testing.T) {
+	cfg := config{items: 40, producers: 4, consumers: 1, buffer: 40, consume: time.Millisecond, high: 5, low: 2, out: io.Discard}
+	var r result
+	testutil.RequireCompletesWithin(t, 10*time.Second, func() {
+		r = run(context.Background(), cfg)
+	})
+	if r.peak != cfg.high {
+		t.Errorf("peak backlog %d, want the high watermark, %d", r.peak, cfg.high)
+	}
+	if r.pauses == 0 {
+		t.Error("the producers never paused")
+	}
+	if len(r.consumed[0]) != cfg.items {
+		t.Errorf("consumed %d items, want %d", len(r.consumed[0]), cfg.items)
+	}
+
+	cfg.high = 0 // Without backpressure, the producers fill the buffer.
+	if r := run(context.Background(), cfg); r.peak <= 5 || r.pauses != 0 {
+		t.Errorf("without a high watermark: peak %d, %d pauses, want a peak above 5 and no pauses", r.peak, r.pauses)
+	}
+}
+
+// TestBacklog checks that the producers stay paused from the high watermark until the depth falls to the low one.
+func TestBacklog(t *testing.T) {
+	b := newBacklog(3, 1)
+	for range 3 {
+		b.add()
+	}
+	if !b.paused || b.pauses != 1 {
+		t.Fatalf("paused, pauses = %v, %d at the high watermark, want true, 1", b.paused, b.pauses)
+	}
+	added := make(chan struct{})
+	go func() {
+		b.add()
+		close(added)
+	}()
+	b.remove() // Depth 2: still above low.
+	select {
+	case <-added:
+		t.Fatal("a producer went on before the depth fell to the low watermark")
+	case <-time.After(20 * time.Millisecond):
+	}
+	b.remove() // Depth 1: the producers go on.
+	testutil.RequireCompletesWithin(t, 10*time.Second, func() { <-added })
+	if b.depth != 2 || b.peak != 3 {
+		t.Errorf("depth, peak = %d, %d, want 2, 3", b.depth, b.peak)
+	}
+}
+
+// syncWriter is a strings.Builder that goroutines can write to at once.
+type syncWriter struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.Write(p)
+}
+
+func (w *syncWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.String()
+}
+
+// TestRunReport checks that the backlog is reported while the run goes on, and not after it ends.
+func TestRunReport(t *testing.T) {
+	var out syncWriter
+	run(context.Background(), config{items: 10, producers: 1, consumers: 1, consume: 5 * time.Millisecond, buffer: 10, report: 10 * time.Millisecond, out: &out})
+	ended := out.String()
+	if !strings.Contains(ended, "Queue depth") {
+		t.Errorf("no backlog reported:\n%s", ended)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if out.String() != ended {
+		t.Error("the backlog was reported after run returned")
+	}
+}
+
+// sentOnce checks that the items consumed and dropped together are exactly those the producers sent, once each.
+func sentOnce(t *testing.T, r result) {
+	t.Helper()
+	produced := 0
+	for _, n := range r.produced {
+		produced += n
+	}
+	taken := slices.Clone(r.dropped)
+	for _, items := range r.consumed {
+		taken = append(taken, items...)
+	}
+	slices.Sort(taken)
+	if len(slices.Compact(slices.Clone(taken))) != len(taken) || len(taken) != produced {
+		t.Errorf("consumed and dropped %v, want each of the %d items produced once", taken, produced)
+	}
+}
+
+// TestDrain checks that consumers finish every item sent after Close stops the producers part way.
+func TestDrain(t *testing.T) {
+	cfg := config{items: 1000, producers: 2, consumers: 2, buffer: 10, produce: time.Millisecond, consume: 2 * time.Millisecond, out: io.Discard}
+	s := start(cfg)
+	time.Sleep(20 * time.Millisecond)
+	var r result
+	testutil.RequireCompletesWithin(t, 10*time.Second, func() {
+		s.Close()
+		r = s.Drain(0)
+	})
+	sentOnce(t, r)
+	if len(r.dropped) != 0 {
+		t.Errorf("dropped %v without a drain timeout", r.dropped)
+	}
+	if r.produced[0]+r.produced[1] == cfg.items {
+		t.Error("the producers sent every item: Close did not stop them")
+	}
+}
+
+// TestDrainTimeout checks that when the consumers cannot finish in time, they finish the items in hand and the items
+// left on the channel are dropped.
+func TestDrainTimeout(t *testing.T) {
+	cfg := config{items: 20, producers: 1, consumers: 2, buffer: 20, consume: 20 * time.Millisecond, out: io.Discard}
+	s := start(cfg)
+	<-s.finished // Every item is sent, as the buffer holds them all.
+	var r result
+	testutil.RequireCompletesWithin(t, 10*time.Second, func() {
+		r = s.Drain(30 * time.Millisecond)
+	})
+	sentOnce(t, r)
+	if len(r.dropped) == 0 || len(r.dropped) == cfg.items {
+		t.Errorf("dropped %d of %d items, want some consumed in time and the rest dropped", len(r.dropped), cfg.items)
+	}
+	if r.produced[0] != cfg.items {
+		t.Errorf("produced %d items, want %d", r.produced[0], cfg.items)
+	}
+}
+
+// TestDrainBackpressure checks that Close releases producers held back by the backlog.
+func TestDrainBackpressure(t *testing.T) {
+	cfg := config{items: 100, producers: 4, consumers: 1, buffer: 10, consume: 50 * time.Millisecond, high: 4, low: 1, out: io.Discard}
+	s := start(cfg)
+	time.Sleep(10 * time.Millisecond) // Let the backlog reach the high watermark.
+	var r result
+	testutil.RequireCompletesWithin(t, 10*time.Second, func() {
+		r = s.Drain(time.Millisecond)
+	})
+	sentOnce(t, r)
+}
+
+// TestRunInterrupted checks that cancelling the context passed to run stops the producers and drains the run.
+func TestRunInterrupted(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	var r result
+	testutil.RequireCompletesWithin(t, 10*time.Second, func() {
+		r = run(ctx, config{items: 1000, producers: 2, consumers: 2, produce: time.Millisecond, out: io.Discard})
+	})
+	sentOnce(t, r)
+	if r.produced[0]+r.produced[1] == 1000 {
+		t.Error("the producers sent every item: cancelling did not stop them")
+	}
+}
+
+// TestWriteSummary checks that the summary lists every worker and the total.
+func TestWriteSummary(t *testing.T) {
+	r := result{produced: []int{3, 2}, consumed: [][]int{{0, 1}, {2}, {3}}, dropped: []int{4}, elapsed: 2 * time.Second, peak: 3, pauses: 1}
+	var b strings.Builder
+	r.writeSummary(&b)
+	summary := strings.Join(strings.Fields(b.String()), " ") // Ignore the alignment.
+	for _, want := range []string{"Producer 0 3 60.0%", "Producer 1 2 40.0%", "Consumer 0 2 40.0%", "Consumer 2 1 20.0%", "4 items consumed in 2s: 2.0 items/s", "Peak queue depth 3; producers paused 1 times", "1 items dropped when the drain timed out: [4]"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary does not contain %q:\n%s", want, b.String())
+		}
+	}
+}
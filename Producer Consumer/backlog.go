@@ -0,0 +1,109 @@
+//--------------------------------------------
+// Author: Ronan Green
+// Created on 16/10/2026
+// Modified by: Ronan Green
+// Description:
+// Counts the items the producers have sent that the consumers have not
+// yet taken, and holds the producers back while that backlog is too deep.
+//--------------------------------------------
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// backlog counts the items waiting for a consumer: those in the channel's buffer and those a producer is blocked
+// sending. A producer counts an item in before it sends it and a consumer counts it out once it has received it, so
+// the depth never goes below zero.
+//
+// With a high watermark set, it is also the producers' backpressure: once the depth reaches high, the producers stop
+// sending until the consumers have brought it down to low. The gap between the two stops the producers starting and
+// stopping with every item, as they would with a single threshold; with low one below high, it behaves as one.
+type backlog struct {
+	high, low int // Watermarks; high is 0 to never hold the producers back.
+
+	mu       sync.Mutex
+	resumed  *sync.Cond // Broadcast when the depth falls to low and the producers may go on.
+	depth    int        // Items counted in and not yet out.
+	peak     int        // The greatest depth.
+	paused   bool       // Whether the producers are waiting for the depth to fall to low.
+	pauses   int        // Times the depth reached high.
+	produced int        // Items counted in.
+	consumed int        // Items counted out.
+	closed   bool       // Whether the producers have been stopped.
+}
+
+// newBacklog returns an empty backlog with the watermarks high and low.
+func newBacklog(high, low int) *backlog {
+	b := &backlog{high: high, low: low}
+	b.resumed = sync.NewCond(&b.mu)
+	return b
+}
+
+// add counts in an item about to be sent, first waiting while the producers are held back. Checking and counting
+// under one lock means several producers cannot each see room for one more and push the depth past high. It reports
+// false, without counting the item, if the producers are stopped by close first.
+func (b *backlog) add() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.high > 0 && (b.paused || b.depth >= b.high) && !b.closed {
+		b.resumed.Wait()
+	}
+	if b.closed {
+		return false
+	}
+	b.depth++
+	b.produced++
+	b.peak = max(b.peak, b.depth)
+	if b.high > 0 && b.depth >= b.high {
+		b.paused = true
+		b.pauses++
+	}
+	return true
+}
+
+// withdraw uncounts an item that add counted in but that was never sent, as its producer was stopped.
+func (b *backlog) withdraw() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.depth--
+	b.produced--
+	if b.paused && b.depth <= b.low {
+		b.paused = false
+		b.resumed.Broadcast()
+	}
+}
+
+// close stops the producers: any waiting in add, and any that call it later, get false.
+func (b *backlog) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.resumed.Broadcast()
+}
+
+// remove counts out an item a consumer has received, letting the producers go on if the depth has fallen to low.
+func (b *backlog) remove() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.depth--
+	b.consumed++
+	if b.paused && b.depth <= b.low {
+		b.paused = false
+		b.resumed.Broadcast()
+	}
+}
+
+// report writes a line showing the depth now and at its greatest, and whether the producers are being held back.
+func (b *backlog) report(out io.Writer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state := ""
+	if b.paused {
+		state = ", producers paused"
+	}
+	fmt.Fprintf(out, "Queue depth %d (peak %d): %d produced, %d consumed%s\n", b.depth, b.peak, b.produced, b.consumed, state)
+}
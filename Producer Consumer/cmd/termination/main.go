@@ -0,0 +1,102 @@
+//--------------------------------------------
+// Author: Ronan Green
+// Created on 16/10/2026
+// Modified by: Ronan Green
+// Description:
+// Passes the same items from several producers to several consumers
+// with each termination protocol in the terminate package, a poison
+// pill for each consumer and closing the channel, and prints how many
+// items each consumer received and how long each protocol took.
+//--------------------------------------------
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"Pro_Con/terminate"
+)
+
+// pill is the poison pill: the items are numbered from 0, so none is -1.
+const pill = -1
+
+// config is the items, producers, consumers and buffer to compare the protocols with.
+type config struct {
+	items     int
+	producers int
+	consumers int
+	buffer    int
+}
+
+// outcome is what passing the items with one protocol came to.
+type outcome struct {
+	protocol string
+	received []int // Items received by each consumer.
+	elapsed  time.Duration
+}
+
+// compare passes the items with each protocol in turn. The items are shared out between the producers in turn, as
+// in the main program.
+func compare(cfg config) []outcome {
+	items := make([][]int, cfg.producers)
+	for i := range cfg.items {
+		items[i%cfg.producers] = append(items[i%cfg.producers], i)
+	}
+	protocols := []struct {
+		name string
+		p    terminate.Protocol[int]
+	}{
+		{"Poison pill", terminate.NewPoisonPill(cfg.producers, cfg.consumers, cfg.buffer, pill)},
+		{"Close channel", terminate.NewCloseChannel[int](cfg.producers, cfg.buffer)},
+	}
+	outcomes := make([]outcome, len(protocols))
+	for i, protocol := range protocols {
+		start := time.Now()
+		received := terminate.Pass(protocol.p, items, cfg.consumers)
+		outcomes[i] = outcome{protocol: protocol.name, received: make([]int, len(received)), elapsed: time.Since(start)}
+		for consumer, items := range received {
+			outcomes[i].received[consumer] = len(items)
+		}
+	}
+	return outcomes
+}
+
+// writeOutcomes writes a table of the items each consumer received with each protocol, their total and the time
+// taken.
+func writeOutcomes(out io.Writer, outcomes []outcome) {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(tw, "Protocol\t")
+	for consumer := range outcomes[0].received {
+		fmt.Fprintf(tw, "Consumer %d\t", consumer)
+	}
+	fmt.Fprintln(tw, "Total\tTime\t")
+	for _, o := range outcomes {
+		fmt.Fprintf(tw, "%s\t", o.protocol)
+		total := 0
+		for _, n := range o.received {
+			fmt.Fprintf(tw, "%d\t", n)
+			total += n
+		}
+		fmt.Fprintf(tw, "%d\t%v\t\n", total, o.elapsed.Round(time.Microsecond))
+	}
+	tw.Flush()
+}
+
+func main() {
+	var cfg config
+	flag.IntVar(&cfg.items, "items", 100000, "number of items to pass")
+	flag.IntVar(&cfg.producers, "producers", 4, "number of goroutines sending the items")
+	flag.IntVar(&cfg.consumers, "consumers", 4, "number of goroutines receiving the items")
+	flag.IntVar(&cfg.buffer, "buffer", 0, "size of the channel's buffer")
+	flag.Parse()
+	if cfg.items < 0 || cfg.producers < 1 || cfg.consumers < 1 || cfg.buffer < 0 {
+		fmt.Fprintln(os.Stderr, "Error: there must be at least one producer and consumer, and the items and buffer cannot be negative")
+		os.Exit(2)
+	}
+	writeOutcomes(os.Stdout, compare(cfg))
+}
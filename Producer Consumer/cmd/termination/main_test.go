@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// TestCompare checks that both protocols pass every item, however many producers and consumers there are.
+func TestCompare(t *testing.T) {
+	for _, workers := range [][2]int{{1, 1}, {3, 1}, {1, 3}, {4, 4}} {
+		cfg := config{items: 50, producers: workers[0], consumers: workers[1], buffer: 2}
+		var outcomes []outcome
+		testutil.RequireCompletesWithin(t, 10*time.Second, func() {
+			outcomes = compare(cfg)
+		})
+		for _, o := range outcomes {
+			total := 0
+			for _, n := range o.received {
+				total += n
+			}
+			if total != cfg.items || len(o.received) != cfg.consumers {
+				t.Errorf("%+v: %s: %d consumers received %d items, want %d and %d", cfg, o.protocol, len(o.received), total, cfg.consumers, cfg.items)
+			}
+		}
+	}
+}
+
+// TestWriteOutcomes checks the table has a column for each consumer and a row for each protocol.
+func TestWriteOutcomes(t *testing.T) {
+	var out strings.Builder
+	writeOutcomes(&out, []outcome{
+		{"Poison pill", []int{3, 2}, 1500 * time.Microsecond},
+		{"Close channel", []int{1, 4}, 2 * time.Millisecond},
+	})
+	want := "Protocol Consumer 0 Consumer 1 Total Time Poison pill 3 2 5 1.5ms Close channel 1 4 5 2ms"
+	if got := strings.Join(strings.Fields(out.String()), " "); got != want {
+		t.Errorf("writeOutcomes wrote %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,229 @@
+//--------------------------------------------
+// Author: Ronan Green
+// Created on 16/10/2026
+// Modified by: Ronan Green
+// Description:
+// Reads the results files written by the Wa-Tor benchmarks and prints
+// the frame rate of each grid size and thread count, parsing the rows
+// concurrently in a pipeline: one goroutine reads the lines of every
+// file, several parse them, and one adds them up.
+//--------------------------------------------
+
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"Pro_Con/pipeline"
+)
+
+// line is a row of a results file, before it is parsed.
+type line struct {
+	file    string
+	number  int            // Line number in the file, from 1.
+	columns map[string]int // The file's header: the index of each column by name.
+	text    string
+}
+
+// row is the part of a parsed row that is summarised.
+type row struct {
+	gridSize  int
+	threads   int
+	frameRate float64
+}
+
+// group is the grid size and thread count rows are summarised by.
+type group struct {
+	gridSize int
+	threads  int
+}
+
+// summary is the frame rates of the runs of one grid size and thread count.
+type summary struct {
+	group
+	runs     int
+	total    float64
+	min, max float64
+}
+
+// mean returns the mean frame rate.
+func (s summary) mean() float64 {
+	return s.total / float64(s.runs)
+}
+
+// readLines sends every row of each file down the pipeline, skipping the "#" metadata comment the Wa-Tor program
+// writes above the header and any blank lines. Rows are read in order; a row that spans lines, within quotes, is not
+// expected, as the Wa-Tor files hold only numbers.
+func readLines(files []string) func(ctx context.Context, emit func(line) error) error {
+	return func(ctx context.Context, emit func(line) error) error {
+		for _, name := range files {
+			if err := readFile(name, emit); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// readFile sends every row of the file name down the pipeline.
+func readFile(name string, emit func(line) error) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	var columns map[string]int
+	for number := 1; scanner.Scan(); number++ {
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if columns == nil { // The header.
+			header, err := csv.NewReader(strings.NewReader(text)).Read()
+			if err != nil {
+				return fmt.Errorf("%s:%d: %w", name, number, err)
+			}
+			columns = make(map[string]int, len(header))
+			for i, column := range header {
+				columns[column] = i
+			}
+			continue
+		}
+		if err := emit(line{file: name, number: number, columns: columns, text: text}); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return nil
+}
+
+// parse parses the grid size, thread count and frame rate of a row. The columns are found by name, so files written
+// before later columns were added parse the same.
+func parse(ctx context.Context, l line) (row, error) {
+	fields, err := csv.NewReader(strings.NewReader(l.text)).Read()
+	if err != nil {
+		return row{}, fmt.Errorf("%s:%d: %w", l.file, l.number, err)
+	}
+	field := func(column string) (string, error) {
+		i, ok := l.columns[column]
+		if !ok {
+			return "", fmt.Errorf("%s: no %q column", l.file, column)
+		}
+		if i >= len(fields) {
+			return "", fmt.Errorf("%s:%d: no %q value", l.file, l.number, column)
+		}
+		return fields[i], nil
+	}
+	var r row
+	var text string
+	if text, err = field("Grid Size"); err == nil {
+		r.gridSize, err = strconv.Atoi(text)
+	}
+	if err == nil {
+		if text, err = field("Thread Count"); err == nil {
+			r.threads, err = strconv.Atoi(text)
+		}
+	}
+	if err == nil {
+		if text, err = field("Frame Rate"); err == nil {
+			r.frameRate, err = strconv.ParseFloat(text, 64)
+		}
+	}
+	if err != nil {
+		return row{}, fmt.Errorf("%s:%d: %w", l.file, l.number, err)
+	}
+	return r, nil
+}
+
+// summarise parses the rows of files on workers goroutines and returns the frame rates of each grid size and thread
+// count, in order of grid size then thread count. It stops at the first file that cannot be read or row that cannot be
+// parsed, returning the error.
+func summarise(ctx context.Context, files []string, workers int) ([]summary, error) {
+	p := pipeline.New(ctx)
+	lines := pipeline.Source(p, readLines(files))
+	rows := pipeline.Stage(p, lines, workers, parse)
+	groups := make(map[group]*summary)
+	pipeline.Sink(p, rows, func(ctx context.Context, r row) error {
+		g := group{r.gridSize, r.threads}
+		s, ok := groups[g]
+		if !ok {
+			s = &summary{group: g, min: r.frameRate, max: r.frameRate}
+			groups[g] = s
+		}
+		s.runs++
+		s.total += r.frameRate
+		s.min = min(s.min, r.frameRate)
+		s.max = max(s.max, r.frameRate)
+		return nil
+	})
+	if err := p.Wait(); err != nil {
+		return nil, err
+	}
+
+	summaries := make([]summary, 0, len(groups))
+	for _, s := range groups {
+		summaries = append(summaries, *s)
+	}
+	slices.SortFunc(summaries, func(a, b summary) int {
+		return cmp.Or(cmp.Compare(a.gridSize, b.gridSize), cmp.Compare(a.threads, b.threads))
+	})
+	return summaries, nil
+}
+
+// writeSummaries writes a table of the frame rates of each grid size and thread count.
+func writeSummaries(out io.Writer, summaries []summary) {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Grid Size\tThreads\tRuns\tMean FPS\tMin FPS\tMax FPS\t")
+	for _, s := range summaries {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%.2f\t%.2f\t%.2f\t\n", s.gridSize, s.threads, s.runs, s.mean(), s.min, s.max)
+	}
+	tw.Flush()
+}
+
+func main() {
+	workers := flag.Int("workers", runtime.NumCPU(), "number of goroutines parsing rows")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: watorstats [-workers n] [results.csv ...]")
+		fmt.Fprintln(flag.CommandLine.Output(), "With no files, reads the results files in ../Wa-tor.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *workers < 1 {
+		fmt.Fprintln(os.Stderr, "Error: there must be at least one worker")
+		os.Exit(2)
+	}
+	files := flag.Args()
+	if len(files) == 0 {
+		files, _ = filepath.Glob(filepath.Join("..", "Wa-tor", "simulation_results*.csv")) // The pattern is valid.
+		if len(files) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: no results files given or found in ../Wa-tor")
+			os.Exit(2)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt) // Ctrl+C stops the pipeline.
+	defer stop()
+	summaries, err := summarise(ctx, files, *workers)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	writeSummaries(os.Stdout, summaries)
+}
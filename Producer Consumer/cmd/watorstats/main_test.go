@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeFile writes a results file to a temporary directory and returns its name.
+func writeFile(t *testing.T, name, text string) string {
+	t.Helper()
+	name = filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(name, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+// TestSummarise checks that rows from old and new files, with and without the metadata comment and later columns,
+// are grouped by grid size and thread count.
+func TestSummarise(t *testing.T) {
+	old := writeFile(t, "old.csv", "Grid Size,Thread Count,Frame Rate\n2500,1,50\n2500,1,60\n\n2500,2,58\n")
+	fresh := writeFile(t, "new.csv", "# run, 50x50, seed 1\nGrid Size,Thread Count,Frame Rate,Total Alloc (MB)\n2500,1,40,1.5\n10000,4,30.5,2\n")
+	for _, workers := range []int{1, 4} {
+		got, err := summarise(context.Background(), []string{old, fresh}, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []summary{
+			{group: group{2500, 1}, runs: 3, total: 150, min: 40, max: 60},
+			{group: group{2500, 2}, runs: 1, total: 58, min: 58, max: 58},
+			{group: group{10000, 4}, runs: 1, total: 30.5, min: 30.5, max: 30.5},
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%d workers: summarise() = %+v, want %+v", workers, got, want)
+		}
+	}
+}
+
+// TestSummariseErrors checks that a bad row or file stops the pipeline with an error naming where it is.
+func TestSummariseErrors(t *testing.T) {
+	for _, test := range []struct {
+		name, text, want string
+	}{
+		{"bad.csv", "Grid Size,Thread Count,Frame Rate\n2500,1,50\n2500,one,60\n", "bad.csv:3"},
+		{"short.csv", "Grid Size,Thread Count,Frame Rate\n2500,1\n", `short.csv:2: no "Frame Rate" value`},
+		{"columns.csv", "Grid Size,Threads,Frame Rate\n2500,1,50\n", `no "Thread Count" column`},
+	} {
+		_, err := summarise(context.Background(), []string{writeFile(t, test.name, test.text)}, 4)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: summarise() = %v, want an error containing %q", test.name, err, test.want)
+		}
+	}
+	if _, err := summarise(context.Background(), []string{filepath.Join(t.TempDir(), "missing.csv")}, 4); !os.IsNotExist(err) {
+		t.Errorf("summarise() of a missing file = %v, want a not-exist error", err)
+	}
+}
@@ -1,3 +1,7 @@
 module Pro_Con
 
 go 1.23.1
+
+require Barrier v0.0.0
+
+replace Barrier => ../Barrier
@@ -0,0 +1,41 @@
+package pipeline_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"Pro_Con/pipeline"
+)
+
+// Summing numbers parsed from text on four goroutines, stopping at the first that cannot be parsed.
+func Example() {
+	for _, text := range []string{"1 2 3 4 5", "1 2 three 4 5"} {
+		p := pipeline.New(context.Background())
+		words := pipeline.Source(p, func(ctx context.Context, emit func(string) error) error {
+			for _, word := range strings.Fields(text) {
+				if err := emit(word); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		numbers := pipeline.Stage(p, words, 4, func(ctx context.Context, word string) (int, error) {
+			return strconv.Atoi(word)
+		})
+		sum := 0
+		pipeline.Sink(p, numbers, func(ctx context.Context, n int) error {
+			sum += n
+			return nil
+		})
+		if err := p.Wait(); err != nil {
+			fmt.Println("error:", err)
+			continue
+		}
+		fmt.Println("sum:", sum)
+	}
+	// Output:
+	// sum: 15
+	// error: strconv.Atoi: parsing "three": invalid syntax
+}
@@ -0,0 +1,156 @@
+// Package pipeline connects producers and consumers into a pipeline: a Source sends items down a channel, each Stage
+// passes them through a function run by several goroutines at once, fanning the items out between them and their
+// results back in to one channel, and a Sink takes the results at the end. For example, to square numbers on four
+// goroutines:
+//
+//	p := pipeline.New(ctx)
+//	numbers := pipeline.Source(p, func(ctx context.Context, emit func(int) error) error {
+//		for i := range 100 {
+//			if err := emit(i); err != nil {
+//				return err // The pipeline has stopped.
+//			}
+//		}
+//		return nil
+//	})
+//	squares := pipeline.Stage(p, numbers, 4, func(ctx context.Context, n int) (int, error) {
+//		return n * n, nil
+//	})
+//	sum := 0
+//	pipeline.Sink(p, squares, func(ctx context.Context, n int) error {
+//		sum += n // A sink runs on one goroutine.
+//		return nil
+//	})
+//	if err := p.Wait(); err != nil {
+//		...
+//	}
+//
+// The first function to return an error stops the whole pipeline: it cancels the context every function is given,
+// every goroutine sending or receiving gives up, and Wait returns that error once they all have, so none is left
+// blocked on a channel no one will read. Cancelling the context given to New stops it in the same way.
+//
+// A stage with more than one goroutine does not keep the items in order.
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Pipeline runs the goroutines of a Source, its Stages and a Sink, and collects the first error among them. Create
+// one with New, add the parts in order, each taking the channel of the one before, and call Wait.
+type Pipeline struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error // The first error, set once.
+}
+
+// New returns an empty pipeline that stops when ctx is done.
+func New(ctx context.Context) *Pipeline {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Pipeline{ctx: ctx, cancel: cancel}
+}
+
+// Wait waits for every part of p to finish, and returns the first error any returned, or the error of the context
+// given to New if that was cancelled first, or nil if every item reached the sink.
+func (p *Pipeline) Wait() error {
+	p.wg.Wait()
+	p.cancel() // Release the context's resources.
+	return p.err
+}
+
+// Source starts a goroutine running gen, which sends items down the channel returned by calling emit with each.
+// emit waits for the next stage to take the item; once the pipeline has stopped it returns the context's error instead,
+// which gen should return. The channel is closed when gen returns. An error from gen stops the pipeline.
+func Source[T any](p *Pipeline, gen func(ctx context.Context, emit func(T) error) error) <-chan T {
+	out := make(chan T)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(out)
+		p.check(gen(p.ctx, func(item T) error { return send(p.ctx, out, item) }))
+	}()
+	return out
+}
+
+// Stage starts workers goroutines each taking items from in, calling fn with them and sending the results down the
+// channel returned, which is closed once in is closed and every worker has finished. The results come out in the
+// order the workers finish them. An error from fn stops the pipeline. Stage panics if workers is less than 1.
+func Stage[In, Out any](p *Pipeline, in <-chan In, workers int, fn func(ctx context.Context, item In) (Out, error)) <-chan Out {
+	if workers < 1 {
+		panic(fmt.Sprintf("pipeline: %d workers: a stage needs at least one", workers))
+	}
+	out := make(chan Out)
+	var stage sync.WaitGroup
+	stage.Add(workers)
+	p.wg.Add(workers)
+	for range workers {
+		go func() {
+			defer p.wg.Done()
+			defer stage.Done()
+			p.check(drain(p.ctx, in, func(item In) error {
+				result, err := fn(p.ctx, item)
+				if err != nil {
+					return err
+				}
+				return send(p.ctx, out, result)
+			}))
+		}()
+	}
+	go func() {
+		stage.Wait() // Fan in: the channel is closed once no worker can send on it.
+		close(out)
+	}()
+	return out
+}
+
+// Sink starts a goroutine calling fn with each item from in, until it is closed. fn is only ever called from that
+// goroutine, so it can gather the results without a lock; read them after Wait returns. An error from fn stops the
+// pipeline.
+func Sink[T any](p *Pipeline, in <-chan T, fn func(ctx context.Context, item T) error) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.check(drain(p.ctx, in, func(item T) error { return fn(p.ctx, item) }))
+	}()
+}
+
+// check stops p with err, if it is the first error.
+func (p *Pipeline) check(err error) {
+	if err == nil {
+		return
+	}
+	p.once.Do(func() {
+		p.err = err
+		p.cancel()
+	})
+}
+
+// send sends item down out, giving up with ctx's error if it is done first.
+func send[T any](ctx context.Context, out chan<- T, item T) error {
+	select {
+	case out <- item:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// drain calls fn with each item from in until it is closed, fn returns an error or ctx is done.
+func drain[T any](ctx context.Context, in <-chan T, fn func(T) error) error {
+	for {
+		select {
+		case item, ok := <-in:
+			if !ok {
+				return nil
+			}
+			if err := fn(item); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
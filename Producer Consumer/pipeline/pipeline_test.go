@@ -0,0 +1,140 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// count is a source of the integers from 0 to n-1.
+func count(n int) func(ctx context.Context, emit func(int) error) error {
+	return func(ctx context.Context, emit func(int) error) error {
+		for i := range n {
+			if err := emit(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// TestPipeline checks that every item passes through every stage exactly once, with the stages' workers running at
+// once, under the race detector.
+func TestPipeline(t *testing.T) {
+	for _, workers := range []int{1, 2, 8} {
+		p := New(context.Background())
+		numbers := Source(p, count(1000))
+		doubled := Stage(p, numbers, workers, func(ctx context.Context, n int) (int, error) { return 2 * n, nil })
+		plusOne := Stage(p, doubled, workers, func(ctx context.Context, n int) (int, error) { return n + 1, nil })
+		var got []int
+		Sink(p, plusOne, func(ctx context.Context, n int) error {
+			got = append(got, n)
+			return nil
+		})
+		var err error
+		testutil.RequireCompletesWithin(t, 10*time.Second, func() { err = p.Wait() })
+		if err != nil {
+			t.Fatalf("%d workers: Wait() = %v", workers, err)
+		}
+		slices.Sort(got)
+		for i, n := range got {
+			if n != 2*i+1 {
+				t.Fatalf("%d workers: got %v, want 1, 3, 5 and so on to 1999", workers, got)
+			}
+		}
+		if len(got) != 1000 {
+			t.Errorf("%d workers: %d items reached the sink, want 1000", workers, len(got))
+		}
+	}
+}
+
+// TestOrder checks that a stage of one worker keeps the items in order.
+func TestOrder(t *testing.T) {
+	p := New(context.Background())
+	same := Stage(p, Source(p, count(100)), 1, func(ctx context.Context, n int) (int, error) { return n, nil })
+	var got []int
+	Sink(p, same, func(ctx context.Context, n int) error {
+		got = append(got, n)
+		return nil
+	})
+	if err := p.Wait(); err != nil || !slices.IsSorted(got) || len(got) != 100 {
+		t.Errorf("Wait() = %v with %v, want nil with 0 to 99 in order", err, got)
+	}
+}
+
+// TestErrors checks that an error from the source, a stage or the sink stops every part of the pipeline, from an
+// endless source, and that Wait returns it.
+func TestErrors(t *testing.T) {
+	errBoom := errors.New("boom")
+	for _, fails := range []string{"source", "stage", "sink"} {
+		p := New(context.Background())
+		var sent atomic.Int64
+		numbers := Source(p, func(ctx context.Context, emit func(int) error) error {
+			for i := 0; ; i++ { // Endless, so only the error can end it.
+				if fails == "source" && i == 50 {
+					return errBoom
+				}
+				if err := emit(i); err != nil {
+					return err
+				}
+				sent.Add(1)
+			}
+		})
+		passed := Stage(p, numbers, 4, func(ctx context.Context, n int) (int, error) {
+			if fails == "stage" && n == 50 {
+				return 0, errBoom
+			}
+			return n, nil
+		})
+		Sink(p, passed, func(ctx context.Context, n int) error {
+			if fails == "sink" && n >= 50 {
+				return errBoom
+			}
+			return nil
+		})
+		var err error
+		testutil.RequireCompletesWithin(t, 10*time.Second, func() { err = p.Wait() })
+		if !errors.Is(err, errBoom) {
+			t.Errorf("%s fails: Wait() = %v, want %v", fails, err, errBoom)
+		}
+	}
+}
+
+// TestCancel checks that cancelling the context given to New stops the pipeline, and that Wait returns its error.
+func TestCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := New(ctx)
+	numbers := Source(p, func(ctx context.Context, emit func(int) error) error {
+		for i := 0; ; i++ {
+			if err := emit(i); err != nil {
+				return err
+			}
+		}
+	})
+	Sink(p, Stage(p, numbers, 4, func(ctx context.Context, n int) (int, error) { return n, nil }), func(ctx context.Context, n int) error {
+		if n == 100 {
+			cancel()
+		}
+		return nil
+	})
+	var err error
+	testutil.RequireCompletesWithin(t, 10*time.Second, func() { err = p.Wait() })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() = %v, want context.Canceled", err)
+	}
+}
+
+func TestStagePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("a stage of 0 workers did not panic")
+		}
+	}()
+	p := New(context.Background())
+	Stage(p, Source(p, count(1)), 0, func(ctx context.Context, n int) (int, error) { return n, nil })
+}
@@ -0,0 +1,108 @@
+package queue
+
+import (
+	"sync"
+	"time"
+)
+
+// Chan is a bounded blocking queue held in a buffered channel, which already blocks a send while full and a receive
+// while empty. Create one with NewChan.
+//
+// The channel itself is never closed, as a producer sending on a closed channel would panic. Close closes a second
+// channel, done, instead, and every Put and Take selects on it too, so a waiting goroutine is woken by either. When
+// both are ready, select picks one at random, so a Put racing a Close can still add its item, and a Take after Close
+// checks the buffer again before returning ErrClosed, so no added item is lost.
+type Chan[T any] struct {
+	items chan T
+	done  chan struct{} // Closed by Close.
+	once  sync.Once
+}
+
+var _ Blocking[int] = (*Chan[int])(nil)
+
+// NewChan returns an empty queue holding up to capacity items. It panics if capacity is less than 1, as an unbuffered
+// channel would hold none.
+func NewChan[T any](capacity int) *Chan[T] {
+	checkCapacity(capacity)
+	return &Chan[T]{items: make(chan T, capacity), done: make(chan struct{})}
+}
+
+// Put adds item to the back of q, waiting while it is full. It returns ErrClosed if q is closed first.
+func (q *Chan[T]) Put(item T) error {
+	if q.closed() {
+		return ErrClosed
+	}
+	select {
+	case q.items <- item:
+		return nil
+	case <-q.done:
+		return ErrClosed
+	}
+}
+
+// TryPut adds item to the back of q, waiting up to timeout while it is full. It returns ErrTimeout if q is still full
+// then, or ErrClosed if q is closed first.
+func (q *Chan[T]) TryPut(item T, timeout time.Duration) error {
+	if q.closed() {
+		return ErrClosed
+	}
+	select {
+	case q.items <- item:
+		return nil
+	default:
+	}
+	if timeout <= 0 {
+		return ErrTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case q.items <- item:
+		return nil
+	case <-q.done:
+		return ErrClosed
+	case <-timer.C:
+		return ErrTimeout
+	}
+}
+
+// Take removes the item at the front of q, waiting while it is empty. Once q is closed and empty it returns ErrClosed.
+func (q *Chan[T]) Take() (T, error) {
+	select {
+	case item := <-q.items:
+		return item, nil
+	case <-q.done:
+		select { // Closed, but items may be left.
+		case item := <-q.items:
+			return item, nil
+		default:
+			var zero T
+			return zero, ErrClosed
+		}
+	}
+}
+
+// Close stops q accepting items and wakes every waiting producer and consumer. The items in q can still be taken.
+func (q *Chan[T]) Close() {
+	q.once.Do(func() { close(q.done) })
+}
+
+// Len returns the number of items in q.
+func (q *Chan[T]) Len() int {
+	return len(q.items)
+}
+
+// Cap returns the number of items q holds when full.
+func (q *Chan[T]) Cap() int {
+	return cap(q.items)
+}
+
+// closed reports whether Close has been called.
+func (q *Chan[T]) closed() bool {
+	select {
+	case <-q.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,168 @@
+package queue
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// Priority is a bounded blocking queue whose consumers always take the highest-priority item it holds, so urgent work
+// overtakes work that is already waiting. Items of equal priority are taken in the order they were put. It waits and
+// closes as Queue does, with a mutex and two condition variables around a binary heap rather than a ring buffer, so
+// Put and Take cost O(log n) in the number of items held. Create one with NewPriority.
+//
+// Only what is in the queue is ordered: an item taken may be overtaken moments later by a higher one put after it,
+// and with several consumers the highest item goes to whichever Take gets the mutex first.
+type Priority[T any] struct {
+	mu       sync.Mutex
+	notFull  *sync.Cond // Signalled when an item is taken, for a waiting producer.
+	notEmpty *sync.Cond // Signalled when an item is put, for a waiting consumer.
+	items    entries[T]
+	capacity int
+	seq      uint64 // Numbers the items put, to keep those of equal priority in order.
+	closed   bool
+}
+
+var _ Blocking[int] = (*Priority[int])(nil)
+
+// entry is an item in a Priority queue and when it was put.
+type entry[T any] struct {
+	item T
+	seq  uint64
+}
+
+// entries is a heap of entries with the highest-priority item at the root, for container/heap.
+type entries[T any] struct {
+	list   []entry[T]
+	higher func(a, b T) bool
+}
+
+func (e *entries[T]) Len() int { return len(e.list) }
+
+func (e *entries[T]) Less(i, j int) bool {
+	a, b := e.list[i], e.list[j]
+	if e.higher(a.item, b.item) {
+		return true
+	}
+	if e.higher(b.item, a.item) {
+		return false
+	}
+	return a.seq < b.seq // Equal priority: first put, first taken.
+}
+
+func (e *entries[T]) Swap(i, j int) { e.list[i], e.list[j] = e.list[j], e.list[i] }
+
+func (e *entries[T]) Push(x any) { e.list = append(e.list, x.(entry[T])) }
+
+func (e *entries[T]) Pop() any {
+	last := len(e.list) - 1
+	x := e.list[last]
+	e.list[last] = entry[T]{} // Do not keep the item alive once taken.
+	e.list = e.list[:last]
+	return x
+}
+
+// NewPriority returns an empty priority queue holding up to capacity items, where higher(a, b) reports whether a is
+// to be taken before b. It panics if capacity is less than 1.
+func NewPriority[T any](capacity int, higher func(a, b T) bool) *Priority[T] {
+	checkCapacity(capacity)
+	q := &Priority[T]{items: entries[T]{list: make([]entry[T], 0, capacity), higher: higher}, capacity: capacity}
+	q.notFull = sync.NewCond(&q.mu)
+	q.notEmpty = sync.NewCond(&q.mu)
+	return q
+}
+
+// Put adds item to q, waiting while it is full. It returns ErrClosed if q is closed first.
+func (q *Priority[T]) Put(item T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.full() && !q.closed {
+		q.notFull.Wait()
+	}
+	if q.closed {
+		return ErrClosed
+	}
+	q.push(item)
+	return nil
+}
+
+// TryPut adds item to q, waiting up to timeout while it is full. It returns ErrTimeout if q is still full then, or
+// ErrClosed if q is closed first. It times the wait as Queue.TryPut does.
+func (q *Priority[T]) TryPut(item T, timeout time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.full() && !q.closed && timeout > 0 {
+		expired := false
+		timer := time.AfterFunc(timeout, func() {
+			q.mu.Lock()
+			expired = true
+			q.mu.Unlock()
+			q.notFull.Broadcast()
+		})
+		defer timer.Stop()
+		for q.full() && !q.closed && !expired {
+			q.notFull.Wait()
+		}
+	}
+	if q.closed {
+		return ErrClosed
+	}
+	if q.full() {
+		return ErrTimeout
+	}
+	q.push(item)
+	return nil
+}
+
+// Take removes the highest-priority item in q, waiting while it is empty. Once q is closed and empty it returns
+// ErrClosed.
+func (q *Priority[T]) Take() (T, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.items.Len() == 0 && !q.closed {
+		q.notEmpty.Wait()
+	}
+	if q.items.Len() == 0 {
+		var zero T
+		return zero, ErrClosed
+	}
+	e := heap.Pop(&q.items).(entry[T])
+	q.notFull.Signal()
+	return e.item, nil
+}
+
+// Close stops q accepting items and wakes every waiting producer and consumer. The items in q can still be taken.
+func (q *Priority[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.notFull.Broadcast()
+	q.notEmpty.Broadcast()
+}
+
+// Len returns the number of items in q.
+func (q *Priority[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.Len()
+}
+
+// Cap returns the number of items q holds when full.
+func (q *Priority[T]) Cap() int {
+	return q.capacity
+}
+
+// full reports whether there is no room in q. The caller holds q.mu.
+func (q *Priority[T]) full() bool {
+	return q.items.Len() == q.capacity
+}
+
+// push adds item to q and wakes a consumer. The caller holds q.mu and has checked there is room.
+func (q *Priority[T]) push(item T) {
+	heap.Push(&q.items, entry[T]{item: item, seq: q.seq})
+	q.seq++
+	q.notEmpty.Signal()
+}
@@ -0,0 +1,182 @@
+package queue
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// task is an item with a priority, numbered so the tests can tell tasks of equal priority apart.
+type task struct {
+	priority int
+	id       int
+}
+
+// higher puts tasks of higher priority first.
+func higher(a, b task) bool {
+	return a.priority > b.priority
+}
+
+// equal treats every item as the same priority, so a Priority queue behaves as a first-in, first-out one.
+func equal(a, b int) bool {
+	return false
+}
+
+// TestPriorityOrder checks that tasks come out highest priority first and, among equals, in the order they were put.
+func TestPriorityOrder(t *testing.T) {
+	q := NewPriority(10, higher)
+	for i, p := range []int{2, 5, 1, 5, 3, 2, 5} {
+		q.Put(task{p, i})
+	}
+	var got []task
+	for q.Len() > 0 {
+		tk, _ := q.Take()
+		got = append(got, tk)
+	}
+	want := []task{{5, 1}, {5, 3}, {5, 6}, {3, 4}, {2, 0}, {2, 5}, {1, 2}}
+	if !slices.Equal(got, want) {
+		t.Errorf("took %v, want %v", got, want)
+	}
+}
+
+// TestPriorityOvertakes checks that an urgent task put while others wait is taken before them.
+func TestPriorityOvertakes(t *testing.T) {
+	q := NewPriority(3, higher)
+	q.Put(task{1, 0})
+	q.Put(task{1, 1})
+	q.Put(task{9, 2})
+	if tk, _ := q.Take(); tk.id != 2 {
+		t.Errorf("Take() = %v, want the urgent task 2", tk)
+	}
+}
+
+// TestPriorityProducers checks that the order holds however the puts of several producers interleave: once they
+// have all finished, the tasks come out sorted.
+func TestPriorityProducers(t *testing.T) {
+	const producers, tasks = 8, 200
+	q := NewPriority(producers*tasks, higher)
+	var wg sync.WaitGroup
+	wg.Add(producers)
+	for p := range producers {
+		go func() {
+			defer wg.Done()
+			for i := range tasks {
+				q.Put(task{(i * 7919) % 101, p*tasks + i}) // Priorities scattered over 0 to 100.
+			}
+		}()
+	}
+	testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+	q.Close()
+	var got []int
+	for {
+		tk, err := q.Take()
+		if err != nil {
+			break
+		}
+		got = append(got, tk.priority)
+	}
+	if len(got) != producers*tasks {
+		t.Fatalf("took %d tasks, want %d", len(got), producers*tasks)
+	}
+	if !slices.IsSortedFunc(got, func(a, b int) int { return b - a }) {
+		t.Errorf("took priorities %v, want them highest first", got)
+	}
+}
+
+// TestPriorityConsumers checks that consumers contending for a full queue each take tasks highest priority first, as
+// every Take removes the highest left, so the tasks each consumer gets are a falling subsequence of the whole order.
+func TestPriorityConsumers(t *testing.T) {
+	const consumers, tasks = 8, 2000
+	q := NewPriority(tasks, higher)
+	for i := range tasks {
+		q.Put(task{(i * 7919) % 1009, i})
+	}
+	q.Close()
+	taken := make([][]int, consumers)
+	var wg sync.WaitGroup
+	wg.Add(consumers)
+	for c := range consumers {
+		go func() {
+			defer wg.Done()
+			for {
+				tk, err := q.Take()
+				if err != nil {
+					return
+				}
+				taken[c] = append(taken[c], tk.priority)
+			}
+		}()
+	}
+	testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+	total := 0
+	for c, priorities := range taken {
+		total += len(priorities)
+		if !slices.IsSortedFunc(priorities, func(a, b int) int { return b - a }) {
+			t.Errorf("consumer %d took priorities %v, want them highest first", c, priorities)
+		}
+	}
+	if total != tasks {
+		t.Errorf("took %d tasks, want %d", total, tasks)
+	}
+}
+
+// TestPriorityContention checks that, with producers and consumers running at once, every task is taken exactly once
+// and no consumer ever takes a task while a higher one is held back: the highest priority put is always taken first,
+// as it is put before any consumer starts and can never be overtaken.
+func TestPriorityContention(t *testing.T) {
+	const producers, consumers, tasks = 4, 4, 500
+	q := NewPriority(16, higher)
+	q.Put(task{1000, -1}) // Higher than any the producers put.
+	start := make(chan struct{})
+	var producing, consuming sync.WaitGroup
+	producing.Add(producers)
+	for p := range producers {
+		go func() {
+			defer producing.Done()
+			<-start
+			for i := range tasks {
+				q.Put(task{(i * 7919) % 101, p*tasks + i})
+			}
+		}()
+	}
+	var mu sync.Mutex
+	var first []int // The first task each consumer took.
+	seen := make(map[int]bool)
+	consuming.Add(consumers)
+	for range consumers {
+		go func() {
+			defer consuming.Done()
+			<-start
+			for n := 0; ; n++ {
+				tk, err := q.Take()
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				if seen[tk.id] {
+					t.Errorf("task %d taken twice", tk.id)
+				}
+				seen[tk.id] = true
+				if n == 0 {
+					first = append(first, tk.id)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	close(start)
+	testutil.RequireCompletesWithin(t, 10*time.Second, func() {
+		producing.Wait()
+		q.Close()
+		consuming.Wait()
+	})
+	if len(seen) != producers*tasks+1 {
+		t.Errorf("took %d tasks, want %d", len(seen), producers*tasks+1)
+	}
+	if !slices.Contains(first, -1) {
+		t.Errorf("no consumer took the highest task first; they started with %v", first)
+	}
+}
@@ -0,0 +1,189 @@
+// Package queue provides bounded blocking queues for producers and consumers: Put waits while the queue is full, Take
+// waits while it is empty, and Close tells the consumers no more items are coming. For example:
+//
+//	q := queue.New[int](10)
+//	go func() {
+//		defer q.Close() // Let the consumer finish once every item is taken.
+//		for i := range 100 {
+//			q.Put(i)
+//		}
+//	}()
+//	for {
+//		item, err := q.Take()
+//		if err != nil {
+//			break // Closed and empty.
+//		}
+//		use(item)
+//	}
+//
+// There are two implementations of the same behaviour, so they can be compared: Queue, a ring buffer guarded by a
+// mutex and two condition variables, as a queue is written in C++ or Java, and Chan, a wrapper around a buffered
+// channel. Priority waits and closes in the same way but hands out the highest-priority item first. All three satisfy
+// Blocking.
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned by Put and TryPut on a closed queue, and by Take once a closed queue is empty.
+var ErrClosed = errors.New("queue: closed")
+
+// ErrTimeout is returned by TryPut when the queue stayed full for the whole timeout.
+var ErrTimeout = errors.New("queue: timed out")
+
+// Blocking is a bounded queue safe for any number of producers and consumers. Queue and Chan hand out items first in,
+// first out; Priority, highest priority first.
+type Blocking[T any] interface {
+	// Put adds item to the queue, waiting while it is full. It returns ErrClosed, without adding the
+	// item, if the queue is closed before there is room.
+	Put(item T) error
+	// TryPut is Put, waiting no longer than timeout for room; it returns ErrTimeout if there was none. A timeout of
+	// zero or less only adds the item if there is room now.
+	TryPut(item T, timeout time.Duration) error
+	// Take removes the next item from the queue, waiting while it is empty. Once the queue is closed, Take
+	// still returns the items left in it, then ErrClosed.
+	Take() (T, error)
+	// Close stops the queue accepting items and wakes every goroutine waiting in Put or Take. Closing a closed
+	// queue does nothing.
+	Close()
+	// Len returns the number of items in the queue.
+	Len() int
+	// Cap returns the number of items the queue holds when full.
+	Cap() int
+}
+
+// Queue is a bounded blocking queue held in a ring buffer. A single mutex guards it, and producers and consumers wait
+// on separate condition variables, notFull and notEmpty, so a Put wakes only a consumer and a Take only a producer.
+// Create one with New.
+type Queue[T any] struct {
+	mu       sync.Mutex
+	notFull  *sync.Cond // Signalled when an item is taken, for a waiting producer.
+	notEmpty *sync.Cond // Signalled when an item is put, for a waiting consumer.
+	items    []T        // The ring buffer, of length Cap.
+	head     int        // Index of the front item.
+	count    int        // Items in the buffer.
+	closed   bool
+}
+
+var _ Blocking[int] = (*Queue[int])(nil)
+
+// New returns an empty queue holding up to capacity items. It panics if capacity is less than 1.
+func New[T any](capacity int) *Queue[T] {
+	checkCapacity(capacity)
+	q := &Queue[T]{items: make([]T, capacity)}
+	q.notFull = sync.NewCond(&q.mu)
+	q.notEmpty = sync.NewCond(&q.mu)
+	return q
+}
+
+// Put adds item to the back of q, waiting while it is full. It returns ErrClosed if q is closed first.
+func (q *Queue[T]) Put(item T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.full() && !q.closed {
+		q.notFull.Wait()
+	}
+	if q.closed {
+		return ErrClosed
+	}
+	q.push(item)
+	return nil
+}
+
+// TryPut adds item to the back of q, waiting up to timeout while it is full. It returns ErrTimeout if q is still full
+// then, or ErrClosed if q is closed first.
+//
+// A sync.Cond cannot wait with a timeout, so a timer marks the wait expired and wakes every producer: the others see
+// there is still no room and wait again.
+func (q *Queue[T]) TryPut(item T, timeout time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.full() && !q.closed && timeout > 0 {
+		expired := false
+		timer := time.AfterFunc(timeout, func() {
+			q.mu.Lock()
+			expired = true
+			q.mu.Unlock()
+			q.notFull.Broadcast()
+		})
+		defer timer.Stop()
+		for q.full() && !q.closed && !expired {
+			q.notFull.Wait()
+		}
+	}
+	if q.closed {
+		return ErrClosed
+	}
+	if q.full() {
+		return ErrTimeout
+	}
+	q.push(item)
+	return nil
+}
+
+// Take removes the item at the front of q, waiting while it is empty. Once q is closed and empty it returns ErrClosed.
+func (q *Queue[T]) Take() (T, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.count == 0 && !q.closed {
+		q.notEmpty.Wait()
+	}
+	if q.count == 0 {
+		var zero T
+		return zero, ErrClosed
+	}
+	item := q.items[q.head]
+	var zero T
+	q.items[q.head] = zero // Do not keep the item alive once taken.
+	q.head = (q.head + 1) % len(q.items)
+	q.count--
+	q.notFull.Signal()
+	return item, nil
+}
+
+// Close stops q accepting items and wakes every waiting producer and consumer. The items in q can still be taken.
+func (q *Queue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.notFull.Broadcast()
+	q.notEmpty.Broadcast()
+}
+
+// Len returns the number of items in q.
+func (q *Queue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.count
+}
+
+// Cap returns the number of items q holds when full.
+func (q *Queue[T]) Cap() int {
+	return len(q.items)
+}
+
+// full reports whether there is no room in q. The caller holds q.mu.
+func (q *Queue[T]) full() bool {
+	return q.count == len(q.items)
+}
+
+// push adds item to the back of q and wakes a consumer. The caller holds q.mu and has checked there is room.
+func (q *Queue[T]) push(item T) {
+	q.items[(q.head+q.count)%len(q.items)] = item
+	q.count++
+	q.notEmpty.Signal()
+}
+
+// checkCapacity panics unless a queue of capacity items can hold any.
+func checkCapacity(capacity int) {
+	if capacity < 1 {
+		panic(fmt.Sprintf("queue: capacity %d: a queue must hold at least one item", capacity))
+	}
+}
@@ -0,0 +1,283 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// queues are the implementations, which every test runs against.
+var queues = []struct {
+	name string
+	new  func(capacity int) Blocking[int]
+}{
+	{"Cond", func(capacity int) Blocking[int] { return New[int](capacity) }},
+	{"Chan", func(capacity int) Blocking[int] { return NewChan[int](capacity) }},
+	{"Priority", func(capacity int) Blocking[int] { return NewPriority(capacity, equal) }}, // First in, first out when all are equal.
+}
+
+// TestFIFO checks that items come out in the order they went in, across the end of the ring buffer.
+func TestFIFO(t *testing.T) {
+	for _, impl := range queues {
+		q := impl.new(3)
+		var got []int
+		for i := range 10 {
+			if err := q.Put(i); err != nil {
+				t.Fatalf("%s: Put(%d) = %v", impl.name, i, err)
+			}
+			if i%2 == 1 { // Take two for every two put, leaving one behind, so the buffer wraps.
+				for range 2 {
+					item, err := q.Take()
+					if err != nil {
+						t.Fatalf("%s: Take() = %v", impl.name, err)
+					}
+					got = append(got, item)
+				}
+			}
+		}
+		if want := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}; !slices.Equal(got, want) {
+			t.Errorf("%s: took %v, want %v", impl.name, got, want)
+		}
+		if q.Len() != 0 || q.Cap() != 3 {
+			t.Errorf("%s: Len(), Cap() = %d, %d, want 0, 3", impl.name, q.Len(), q.Cap())
+		}
+	}
+}
+
+// TestPutBlocks checks that Put waits while the queue is full, until an item is taken.
+func TestPutBlocks(t *testing.T) {
+	for _, impl := range queues {
+		q := impl.new(1)
+		q.Put(1)
+		put := make(chan error)
+		go func() { put <- q.Put(2) }()
+		select {
+		case err := <-put:
+			t.Fatalf("%s: Put to a full queue returned %v without waiting", impl.name, err)
+		case <-time.After(20 * time.Millisecond):
+		}
+		if item, _ := q.Take(); item != 1 {
+			t.Errorf("%s: Take() = %d, want 1", impl.name, item)
+		}
+		testutil.RequireCompletesWithin(t, 10*time.Second, func() {
+			if err := <-put; err != nil {
+				t.Errorf("%s: Put() = %v once there was room", impl.name, err)
+			}
+		})
+		if item, _ := q.Take(); item != 2 {
+			t.Errorf("%s: Take() = %d, want 2", impl.name, item)
+		}
+	}
+}
+
+// TestTryPut checks that TryPut times out on a full queue, adds at once to one with room, and adds an item once room
+// is made within the timeout.
+func TestTryPut(t *testing.T) {
+	const timeout = 20 * time.Millisecond
+	for _, impl := range queues {
+		q := impl.new(1)
+		if err := q.TryPut(1, 0); err != nil {
+			t.Errorf("%s: TryPut to an empty queue = %v", impl.name, err)
+		}
+		if err := q.TryPut(2, 0); !errors.Is(err, ErrTimeout) {
+			t.Errorf("%s: TryPut(2, 0) to a full queue = %v, want ErrTimeout", impl.name, err)
+		}
+		start := time.Now()
+		if err := q.TryPut(2, timeout); !errors.Is(err, ErrTimeout) {
+			t.Errorf("%s: TryPut(2, %v) to a full queue = %v, want ErrTimeout", impl.name, timeout, err)
+		}
+		if waited := time.Since(start); waited < timeout {
+			t.Errorf("%s: TryPut gave up after %v, want at least %v", impl.name, waited, timeout)
+		}
+
+		go func() {
+			time.Sleep(timeout)
+			q.Take()
+		}()
+		if err := q.TryPut(3, 10*time.Second); err != nil {
+			t.Errorf("%s: TryPut once room was made = %v", impl.name, err)
+		}
+		if item, _ := q.Take(); item != 3 {
+			t.Errorf("%s: Take() = %d, want 3", impl.name, item)
+		}
+	}
+}
+
+// TestClose checks that a closed queue refuses items but gives up those it holds, then returns ErrClosed, and that
+// closing it twice is harmless.
+func TestClose(t *testing.T) {
+	for _, impl := range queues {
+		q := impl.new(3)
+		q.Put(1)
+		q.Put(2)
+		q.Close()
+		q.Close()
+		if err := q.Put(3); !errors.Is(err, ErrClosed) {
+			t.Errorf("%s: Put after Close = %v, want ErrClosed", impl.name, err)
+		}
+		if err := q.TryPut(3, time.Second); !errors.Is(err, ErrClosed) {
+			t.Errorf("%s: TryPut after Close = %v, want ErrClosed", impl.name, err)
+		}
+		for _, want := range []int{1, 2} {
+			if item, err := q.Take(); item != want || err != nil {
+				t.Errorf("%s: Take() after Close = %d, %v, want %d, nil", impl.name, item, err, want)
+			}
+		}
+		if _, err := q.Take(); !errors.Is(err, ErrClosed) {
+			t.Errorf("%s: Take() from a closed, empty queue = %v, want ErrClosed", impl.name, err)
+		}
+	}
+}
+
+// TestCloseWakes checks that Close releases goroutines waiting to put to a full queue or take from an empty one.
+func TestCloseWakes(t *testing.T) {
+	for _, impl := range queues {
+		full, empty := impl.new(1), impl.new(1)
+		full.Put(0)
+		var wg sync.WaitGroup
+		wg.Add(6)
+		for range 2 {
+			go func() {
+				defer wg.Done()
+				if err := full.Put(1); !errors.Is(err, ErrClosed) {
+					t.Errorf("%s: Put() woken by Close = %v, want ErrClosed", impl.name, err)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				if err := full.TryPut(1, time.Minute); !errors.Is(err, ErrClosed) {
+					t.Errorf("%s: TryPut() woken by Close = %v, want ErrClosed", impl.name, err)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				if _, err := empty.Take(); !errors.Is(err, ErrClosed) {
+					t.Errorf("%s: Take() woken by Close = %v, want ErrClosed", impl.name, err)
+				}
+			}()
+		}
+		time.Sleep(20 * time.Millisecond) // Let them start waiting.
+		full.Close()
+		empty.Close()
+		testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+	}
+}
+
+// TestProducersConsumers checks that every item put by several producers is taken exactly once by several consumers,
+// under the race detector, with each producer's items taken in the order it put them.
+func TestProducersConsumers(t *testing.T) {
+	const producers, consumers, items = 4, 4, 1000
+	for _, impl := range queues {
+		for _, capacity := range []int{1, 16} {
+			q := impl.new(capacity)
+			var wg sync.WaitGroup
+			wg.Add(producers)
+			for p := range producers {
+				go func() {
+					defer wg.Done()
+					for i := range items {
+						if err := q.Put(p*items + i); err != nil {
+							t.Error(err)
+						}
+					}
+				}()
+			}
+			taken := make([][]int, consumers)
+			var done sync.WaitGroup
+			done.Add(consumers)
+			for c := range consumers {
+				go func() {
+					defer done.Done()
+					for {
+						item, err := q.Take()
+						if err != nil {
+							return
+						}
+						taken[c] = append(taken[c], item)
+					}
+				}()
+			}
+			testutil.RequireCompletesWithin(t, 10*time.Second, func() {
+				wg.Wait()
+				q.Close()
+				done.Wait()
+			})
+
+			var all []int
+			for c, took := range taken {
+				for p := range producers { // Each consumer sees each producer's items in order.
+					mine := slices.DeleteFunc(slices.Clone(took), func(item int) bool { return item/items != p })
+					if !slices.IsSorted(mine) {
+						t.Errorf("%s, capacity %d: consumer %d took producer %d's items out of order", impl.name, capacity, c, p)
+					}
+				}
+				all = append(all, took...)
+			}
+			slices.Sort(all)
+			want := make([]int, producers*items)
+			for i := range want {
+				want[i] = i
+			}
+			if !slices.Equal(all, want) {
+				t.Errorf("%s, capacity %d: took %d items, want each of the %d once", impl.name, capacity, len(all), len(want))
+			}
+		}
+	}
+}
+
+func TestNewPanics(t *testing.T) {
+	for _, impl := range queues {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: a queue of capacity 0 did not panic", impl.name)
+				}
+			}()
+			impl.new(0)
+		}()
+	}
+}
+
+// BenchmarkQueues times passing an item from producers to consumers through each queue, with one producer and one
+// consumer and with four of each, through a queue of 1 and of 64.
+func BenchmarkQueues(b *testing.B) {
+	for _, workers := range []int{1, 4} {
+		for _, capacity := range []int{1, 64} {
+			for _, impl := range queues {
+				b.Run(fmt.Sprintf("%s/workers=%d/cap=%d", impl.name, workers, capacity), func(b *testing.B) {
+					b.ReportAllocs()
+					q := impl.new(capacity)
+					var producing, consuming sync.WaitGroup
+					producing.Add(workers)
+					consuming.Add(workers)
+					for p := range workers {
+						go func() {
+							defer producing.Done()
+							for i := p; i < b.N; i += workers {
+								q.Put(i)
+							}
+						}()
+					}
+					for range workers {
+						go func() {
+							defer consuming.Done()
+							for {
+								if _, err := q.Take(); err != nil {
+									return
+								}
+							}
+						}()
+					}
+					producing.Wait()
+					q.Close() // The consumers stop once they have emptied it.
+					consuming.Wait()
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Package ratelimit provides a token bucket, to hold producers to a steady rate while letting them go faster for
+// short bursts. For example, to send no more than 5 items a second, or 10 at once after a pause:
+//
+//	limiter := ratelimit.New(5, 10)
+//	for _, item := range items {
+//		if err := limiter.Wait(ctx); err != nil {
+//			return err
+//		}
+//		send(item)
+//	}
+//
+// It does the job of golang.org/x/time/rate.Limiter in a form small enough to read.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a bucket that fills with tokens at a fixed rate, up to a size, its burst. Taking an item costs a
+// token, so over any period the items taken are no more than the burst plus the rate times the period. It is safe for
+// any number of goroutines, which share the rate between them. Create one with New.
+//
+// Wait reserves its token at once, letting the bucket go into debt, and then sleeps until the token would have been
+// there, so goroutines are served in the order they called Wait and none is starved by others arriving as tokens
+// appear.
+type TokenBucket struct {
+	rate  float64 // Tokens added a second.
+	burst float64 // Tokens the bucket holds when full.
+
+	mu     sync.Mutex
+	tokens float64   // Tokens in the bucket at last; negative when goroutines are waiting for tokens to come.
+	last   time.Time // When tokens was last brought up to date.
+}
+
+// New returns a full bucket that fills at rate tokens a second up to burst tokens. It panics if rate is not positive
+// or burst is less than 1, as no token could ever be taken.
+func New(rate float64, burst int) *TokenBucket {
+	if rate <= 0 {
+		panic(fmt.Sprintf("ratelimit: rate %v: tokens must be added at a positive rate", rate))
+	}
+	if burst < 1 {
+		panic(fmt.Sprintf("ratelimit: burst %d: the bucket must hold at least one token", burst))
+	}
+	return &TokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Allow takes a token if there is one and reports whether it did, without waiting.
+func (b *TokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fill(time.Now())
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Wait takes a token, waiting until there is one or ctx is done. It returns nil once it has the token, or ctx.Err()
+// without it, in which case the token it reserved is given back for the next goroutine.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.fill(now)
+	b.tokens--
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second)) // How long until the debt is paid off.
+	b.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.fill(time.Now())
+		b.tokens = min(b.tokens+1, b.burst)
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// fill adds the tokens that have come since the bucket was last brought up to date, up to its burst. The caller holds
+// b.mu.
+func (b *TokenBucket) fill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.tokens+elapsed.Seconds()*b.rate, b.burst)
+		b.last = now
+	}
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// TestAllow checks that a full bucket allows a burst at once, then refuses until a token has come.
+func TestAllow(t *testing.T) {
+	b := New(50, 3) // A token every 20ms.
+	for i := range 3 {
+		if !b.Allow() {
+			t.Fatalf("Allow() %d of a burst of 3 = false", i+1)
+		}
+	}
+	if b.Allow() {
+		t.Error("Allow() of an empty bucket = true")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if !b.Allow() {
+		t.Error("Allow() = false once a token should have come")
+	}
+}
+
+// TestWaitRate checks that goroutines sharing a bucket are held to its rate once the burst is spent.
+func TestWaitRate(t *testing.T) {
+	const rate, burst, waits = 200, 5, 25
+	b := New(rate, burst)
+	start := time.Now()
+	var wg sync.WaitGroup
+	wg.Add(waits)
+	for range waits {
+		go func() {
+			defer wg.Done()
+			if err := b.Wait(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	testutil.RequireCompletesWithin(t, 10*time.Second, wg.Wait)
+	// The burst goes at once and the other 20 at 200 a second, so no sooner than 100ms.
+	if elapsed, least := time.Since(start), time.Duration(waits-burst)*time.Second/rate; elapsed < least {
+		t.Errorf("%d waits took %v, want at least %v", waits, elapsed, least)
+	}
+}
+
+// TestWaitCancel checks that a Wait ended by its context returns the error and gives its token back.
+func TestWaitCancel(t *testing.T) {
+	b := New(10, 1) // A token every 100ms.
+	b.Allow()
+	b.Wait(context.Background()) // Empty the bucket, and the next token with it.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() with an expired context = %v, want context.DeadlineExceeded", err)
+	}
+	time.Sleep(110 * time.Millisecond)
+	if !b.Allow() {
+		t.Error("Allow() = false: the cancelled Wait kept its token")
+	}
+}
+
+func TestNewPanics(t *testing.T) {
+	for _, args := range []struct {
+		rate  float64
+		burst int
+	}{{0, 1}, {-1, 1}, {1, 0}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("New(%v, %d) did not panic", args.rate, args.burst)
+				}
+			}()
+			New(args.rate, args.burst)
+		}()
+	}
+}
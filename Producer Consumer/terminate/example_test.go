@@ -0,0 +1,24 @@
+package terminate_test
+
+import (
+	"fmt"
+	"slices"
+
+	"Pro_Con/terminate"
+)
+
+// Passing the same items from two producers to three consumers with each protocol.
+func Example() {
+	producers := [][]int{{1, 3, 5}, {2, 4, 6}}
+	for _, p := range []terminate.Protocol[int]{
+		terminate.NewPoisonPill(len(producers), 3, 0, 0), // No producer sends 0.
+		terminate.NewCloseChannel[int](len(producers), 0),
+	} {
+		received := slices.Concat(terminate.Pass(p, producers, 3)...)
+		slices.Sort(received)
+		fmt.Printf("%T: %v\n", p, received)
+	}
+	// Output:
+	// *terminate.PoisonPill[int]: [1 2 3 4 5 6]
+	// *terminate.CloseChannel[int]: [1 2 3 4 5 6]
+}
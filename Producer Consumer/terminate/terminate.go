@@ -0,0 +1,194 @@
+// Package terminate compares two ways for producers to tell consumers that no more items are coming, behind the one
+// Protocol interface:
+//
+//   - PoisonPill sends a sentinel value, the pill, that no producer sends as an item. A consumer that receives it
+//     stops.
+//   - CloseChannel closes the channel, which every consumer, however many, sees once it has received the items
+//     already sent.
+//
+// Both are simple with one producer, which sends its pills or closes the channel after its last item. Both break
+// when every producer of several does the same, as neither producer knows whether the others have finished:
+//
+//   - Each producer sending pills as it finishes stops the consumers while the other producers are still sending.
+//     Their items are never received, and with no consumer left a producer blocks forever on a full channel.
+//   - Each producer closing the channel as it finishes panics: the second producer to close it panics with "close of
+//     closed channel", and any still sending with "send on closed channel".
+//
+// So each producer calls Finish instead, and only the last of them to do so sends the pills or closes the channel,
+// which means the number of producers has to be known from the start. The pill has further costs: one value of the
+// item type is given up for it, the number of consumers has to be known too, so there is a pill for each, and a
+// consumer that goes on receiving after its pill waits forever. Closing the channel has none of these, which is why
+// the program in this module closes its channel once a sync.WaitGroup of its producers is done.
+package terminate
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Protocol is a channel of items from a fixed number of producers to consumers, with a way for the producers to say
+// they have finished. Each producer calls Send for every item and then Finish once; each consumer calls Receive
+// until it reports false.
+type Protocol[T any] interface {
+	// Send sends item to a consumer, waiting while the channel is full. It panics if every producer has finished.
+	Send(item T)
+	// Finish records that a producer has sent its last item. Once every producer has finished, the consumers see the
+	// end of the items once they have received those already sent. Finish panics if called more times than there
+	// are producers.
+	Finish()
+	// Receive returns the next item, waiting until one is sent, or reports false once every producer has finished and
+	// every item has been received.
+	Receive() (T, bool)
+}
+
+// finisher counts the producers that have finished, to run the end of a protocol once the last has.
+type finisher struct {
+	mu        sync.Mutex
+	producers int // Producers yet to finish.
+}
+
+// finish records that a producer has finished and calls last if it was the last to. Counting and calling under one
+// lock means no Send can see the producers still running after last has begun.
+func (f *finisher) finish(last func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.producers == 0 {
+		panic("terminate: Finish called more times than there are producers")
+	}
+	if f.producers--; f.producers == 0 {
+		last()
+	}
+}
+
+// finished reports whether every producer has finished.
+func (f *finisher) finished() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.producers == 0
+}
+
+// checkCounts panics if a protocol is created for fewer than one producer or consumer.
+func checkCounts(producers, consumers, buffer int) {
+	if producers < 1 || consumers < 1 {
+		panic(fmt.Sprintf("terminate: %d producers and %d consumers: there must be at least one of each", producers, consumers))
+	}
+	if buffer < 0 {
+		panic(fmt.Sprintf("terminate: buffer of %d: the buffer cannot be negative", buffer))
+	}
+}
+
+// PoisonPill is a Protocol that ends with a pill for each consumer. Create one with NewPoisonPill.
+type PoisonPill[T comparable] struct {
+	items     chan T
+	pill      T
+	consumers int
+	finisher
+}
+
+// NewPoisonPill returns a protocol for producers and consumers, with a buffer of buffer items, whose last producer to
+// finish sends pill once for each consumer. It panics if there is not at least one producer and one consumer.
+//
+// Every consumer must call Receive until it reports false, as a consumer that stops early leaves its pill for another,
+// which then stops with items still to come, and one that calls it again afterwards waits forever, as there is no
+// pill left for it.
+func NewPoisonPill[T comparable](producers, consumers, buffer int, pill T) *PoisonPill[T] {
+	checkCounts(producers, consumers, buffer)
+	return &PoisonPill[T]{items: make(chan T, buffer), pill: pill, consumers: consumers, finisher: finisher{producers: producers}}
+}
+
+// Send sends item to a consumer. It panics if item is the pill, which would stop the consumer that received it
+// while the others carried on, or if every producer has finished, as the item would be behind the pills and never
+// received.
+func (p *PoisonPill[T]) Send(item T) {
+	if item == p.pill {
+		panic(fmt.Sprintf("terminate: %v is the poison pill and cannot be sent as an item", item))
+	}
+	if p.finished() {
+		panic("terminate: Send after every producer has finished")
+	}
+	p.items <- item
+}
+
+// Finish records that a producer has finished. The last to finish sends the pills, one at a time as the consumers
+// make room for them, so it waits until the channel has room for the last.
+func (p *PoisonPill[T]) Finish() {
+	p.finish(func() {
+		for range p.consumers {
+			p.items <- p.pill
+		}
+	})
+}
+
+// Receive returns the next item, or reports false if it is a pill.
+func (p *PoisonPill[T]) Receive() (T, bool) {
+	item := <-p.items
+	if item == p.pill {
+		var zero T
+		return zero, false
+	}
+	return item, true
+}
+
+// CloseChannel is a Protocol that ends by closing its channel. Create one with NewCloseChannel.
+type CloseChannel[T any] struct {
+	items chan T
+	finisher
+}
+
+// NewCloseChannel returns a protocol for producers, with a buffer of buffer items, whose last producer to finish
+// closes the channel. Any number of consumers may receive from it, and go on calling Receive once it has reported
+// false. It panics if there is not at least one producer.
+func NewCloseChannel[T any](producers, buffer int) *CloseChannel[T] {
+	checkCounts(producers, 1, buffer)
+	return &CloseChannel[T]{items: make(chan T, buffer), finisher: finisher{producers: producers}}
+}
+
+// Send sends item to a consumer. It panics, as a send on a closed channel does, if every producer has finished.
+//
+// A producer that has not finished cannot be sending when the channel is closed, as the last producer closes it
+// after its own last send.
+func (c *CloseChannel[T]) Send(item T) {
+	if c.finished() {
+		panic("terminate: Send after every producer has finished")
+	}
+	c.items <- item
+}
+
+// Finish records that a producer has finished. The last to finish closes the channel.
+func (c *CloseChannel[T]) Finish() {
+	c.finish(func() { close(c.items) })
+}
+
+// Receive returns the next item, or reports false once the channel is closed and empty.
+func (c *CloseChannel[T]) Receive() (T, bool) {
+	item, ok := <-c.items
+	return item, ok
+}
+
+// Pass runs a goroutine for each slice of producers, sending its items over p in order and then finishing, and
+// consumers goroutines receiving until p reports the end, and returns the items each consumer received, in the order
+// it received them. With a PoisonPill, consumers must be the number it was created for.
+func Pass[T any](p Protocol[T], producers [][]T, consumers int) [][]T {
+	received := make([][]T, consumers)
+	var wg sync.WaitGroup
+	wg.Add(len(producers) + consumers)
+	for _, items := range producers {
+		go func() {
+			defer wg.Done()
+			for _, item := range items {
+				p.Send(item)
+			}
+			p.Finish()
+		}()
+	}
+	for i := range consumers {
+		go func() {
+			defer wg.Done()
+			for item, ok := p.Receive(); ok; item, ok = p.Receive() {
+				received[i] = append(received[i], item)
+			}
+		}()
+	}
+	wg.Wait()
+	return received
+}
@@ -0,0 +1,180 @@
+package terminate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"Barrier/testutil"
+)
+
+// pill is the poison pill of the PoisonPill protocols tested, a value no producer sends.
+const pill = -1
+
+// protocols makes each Protocol for the tests to run.
+var protocols = []struct {
+	name string
+	new  func(producers, consumers, buffer int) Protocol[int]
+}{
+	{"pill", func(producers, consumers, buffer int) Protocol[int] {
+		return NewPoisonPill(producers, consumers, buffer, pill)
+	}},
+	{"close", func(producers, consumers, buffer int) Protocol[int] {
+		return NewCloseChannel[int](producers, buffer)
+	}},
+}
+
+// share deals items 0 to n-1 out between producers in turn, as the program in this module does.
+func share(producers, n int) [][]int {
+	items := make([][]int, producers)
+	for i := range n {
+		items[i%producers] = append(items[i%producers], i)
+	}
+	return items
+}
+
+// catch returns the value fn panics with, or nil if it returns.
+func catch(fn func()) (r any) {
+	defer func() { r = recover() }()
+	fn()
+	return nil
+}
+
+// TestPass checks that with either protocol every item is received exactly once and every consumer stops, however
+// many producers and consumers there are and however large the buffer, and that a single consumer receives each
+// producer's items in the order they were sent.
+func TestPass(t *testing.T) {
+	for _, impl := range protocols {
+		for _, workers := range [][2]int{{1, 1}, {4, 1}, {1, 4}, {4, 4}} {
+			for _, buffer := range []int{0, 1, 10} {
+				for _, n := range []int{0, 1, 100} {
+					name := fmt.Sprintf("%s: %d producers, %d consumers, buffer %d, %d items", impl.name, workers[0], workers[1], buffer, n)
+					var received [][]int
+					testutil.RequireCompletesWithin(t, 10*time.Second, func() {
+						received = Pass(impl.new(workers[0], workers[1], buffer), share(workers[0], n), workers[1])
+					})
+					all := slices.Concat(received...)
+					slices.Sort(all)
+					if want := slices.Concat(share(1, n)...); !slices.Equal(all, want) {
+						t.Errorf("%s: received %v, want %v", name, all, want)
+					}
+					if workers[1] != 1 {
+						continue
+					}
+					next := make([]int, workers[0]) // The next item expected from each producer.
+					for i := range next {
+						next[i] = i
+					}
+					for _, item := range received[0] {
+						if producer := item % workers[0]; item != next[producer] {
+							t.Errorf("%s: received %d from producer %d, want %d first", name, item, producer, next[producer])
+						} else {
+							next[producer] += workers[0]
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestReceiveAfterEnd checks that a consumer of a closed channel can go on receiving, while one that has taken its
+// pill waits, as no pill is left for it.
+func TestReceiveAfterEnd(t *testing.T) {
+	c := NewCloseChannel[int](1, 0)
+	c.Finish()
+	for range 3 {
+		if item, ok := c.Receive(); ok {
+			t.Fatalf("a closed channel received %d", item)
+		}
+	}
+
+	p := NewPoisonPill(1, 1, 1, pill)
+	p.Finish()
+	if item, ok := p.Receive(); ok {
+		t.Fatalf("received %d rather than the pill", item)
+	}
+	received := make(chan bool)
+	go func() {
+		_, ok := p.Receive()
+		received <- ok
+	}()
+	select {
+	case <-received:
+		t.Fatal("a second Receive after the pill returned")
+	case <-time.After(50 * time.Millisecond):
+	}
+	p.items <- 1 // Release the consumer.
+	<-received
+}
+
+// TestMisuse checks that sending after every producer has finished, finishing too often, sending the pill and
+// creating a protocol without producers or consumers all panic.
+func TestMisuse(t *testing.T) {
+	for _, impl := range protocols {
+		p := impl.new(2, 1, 10)
+		p.Send(1)
+		p.Finish()
+		if r := catch(func() { p.Send(2) }); r != nil {
+			t.Errorf("%s: a Send with a producer still running panicked: %v", impl.name, r)
+		}
+		p.Finish()
+		if r := catch(func() { p.Send(3) }); r == nil {
+			t.Errorf("%s: a Send after every producer finished did not panic", impl.name)
+		}
+		if r := catch(p.Finish); r == nil {
+			t.Errorf("%s: a third Finish with two producers did not panic", impl.name)
+		}
+		for _, counts := range [][3]int{{0, 1, 0}, {1, 1, -1}} {
+			if r := catch(func() { impl.new(counts[0], counts[1], counts[2]) }); r == nil {
+				t.Errorf("%s: %d producers, %d consumers and a buffer of %d did not panic", impl.name, counts[0], counts[1], counts[2])
+			}
+		}
+	}
+
+	if r := catch(func() { NewPoisonPill(1, 1, 1, pill).Send(pill) }); r == nil || !strings.Contains(fmt.Sprint(r), "poison pill") {
+		t.Errorf("sending the pill as an item panicked with %v, want a panic naming the poison pill", r)
+	}
+	if r := catch(func() { NewPoisonPill(1, 0, 1, pill) }); r == nil {
+		t.Error("a poison pill for no consumers did not panic")
+	}
+}
+
+// TestEachProducerEnds shows why the last producer to finish ends the items, rather than each producer ending them
+// as it finishes, as a single producer would: the first producer's pill stops the consumer before the second
+// producer's items reach it, and the second producer to close the channel panics.
+func TestEachProducerEnds(t *testing.T) {
+	items := make(chan int, 10)
+	items <- pill // The first producer finishes at once.
+	for i := range 3 {
+		items <- i // The second is still sending.
+	}
+	items <- pill
+	var received []int
+	for item := <-items; item != pill; item = <-items {
+		received = append(received, item)
+	}
+	if len(received) != 0 || len(items) != 4 {
+		t.Errorf("the consumer received %v and left %d items, want none received and every item left", received, len(items))
+	}
+
+	closing := make(chan int)
+	close(closing) // The first producer finishes.
+	if r := catch(func() { closing <- 1 }); r == nil || !strings.Contains(fmt.Sprint(r), "send on closed channel") {
+		t.Errorf("the second producer sending panicked with %v, want send on closed channel", r)
+	}
+	if r := catch(func() { close(closing) }); r == nil || !strings.Contains(fmt.Sprint(r), "close of closed channel") {
+		t.Errorf("the second producer closing panicked with %v, want close of closed channel", r)
+	}
+}
+
+// BenchmarkProtocols times passing items from four producers to four consumers with each protocol.
+func BenchmarkProtocols(b *testing.B) {
+	for _, impl := range protocols {
+		b.Run(impl.name, func(b *testing.B) {
+			Pass(impl.new(4, 4, 16), share(4, b.N), 4)
+		})
+	}
+}
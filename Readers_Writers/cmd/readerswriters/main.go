@@ -0,0 +1,69 @@
+//--------------------------------------------
+// Author: Ronan Green
+// Created on 16/10/2024
+// Modified by: Ronan Green
+// Description:
+// Runs the same readers and writers against each readers-writers lock and
+// prints how often each side got in, how long they waited and how many
+// starved.
+//--------------------------------------------
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"readers_writers/readerswriters"
+)
+
+// locks lists the locks the demo can run, in the order it runs them.
+var locks = []struct {
+	name string
+	new  func() readerswriters.RWLock
+}{
+	{"reader", func() readerswriters.RWLock { return &readerswriters.ReaderPreference{} }},
+	{"writer", func() readerswriters.RWLock { return &readerswriters.WriterPreference{} }},
+	{"fair", func() readerswriters.RWLock { return &readerswriters.Fair{} }},
+	{"sync", func() readerswriters.RWLock { return &sync.RWMutex{} }},
+}
+
+func main() {
+	var w readerswriters.Workload
+	flag.IntVar(&w.Readers, "readers", 10, "number of reader goroutines")
+	flag.IntVar(&w.Writers, "writers", 2, "number of writer goroutines")
+	flag.DurationVar(&w.Duration, "duration", 2*time.Second, "how long each lock is run for")
+	flag.DurationVar(&w.ReadTime, "read", 5*time.Millisecond, "how long each read holds the lock")
+	flag.DurationVar(&w.WriteTime, "write", 5*time.Millisecond, "how long each write holds the lock")
+	flag.DurationVar(&w.Pause, "pause", time.Millisecond, "how long each goroutine waits between turns")
+	only := flag.String("lock", "", "run only this lock: reader, writer, fair or sync; empty for all")
+	flag.Parse()
+	if w.Readers < 0 || w.Writers < 0 || w.Readers+w.Writers == 0 || w.Duration <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: there must be some readers or writers, none negative, and a positive duration")
+		os.Exit(2)
+	}
+
+	fmt.Printf("%d readers and %d writers for %v each, reading for %v and writing for %v\n\n", w.Readers, w.Writers, w.Duration, w.ReadTime, w.WriteTime)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Lock\tReads\tMean wait\tMax wait\tStarved\tWrites\tMean wait\tMax wait\tStarved\t")
+	ran := false
+	for _, lk := range locks {
+		if *only != "" && *only != lk.name {
+			continue
+		}
+		ran = true
+		r := readerswriters.Measure(lk.new(), w)
+		fmt.Fprintf(tw, "%s\t%d\t%v\t%v\t%d\t%d\t%v\t%v\t%d\t\n", lk.name,
+			r.Readers.Turns, r.Readers.MeanWait.Round(time.Microsecond), r.Readers.MaxWait.Round(time.Microsecond), r.Readers.Starved,
+			r.Writers.Turns, r.Writers.MeanWait.Round(time.Microsecond), r.Writers.MaxWait.Round(time.Microsecond), r.Writers.Starved)
+	}
+	tw.Flush()
+	if !ran {
+		fmt.Fprintf(os.Stderr, "Error: unknown lock %q (want reader, writer, fair or sync)\n", *only)
+		os.Exit(2)
+	}
+}
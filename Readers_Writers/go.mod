@@ -0,0 +1,3 @@
+module readers_writers
+
+go 1.23.1
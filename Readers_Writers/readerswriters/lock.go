@@ -0,0 +1,150 @@
+// Package readerswriters implements the classic readers-writers problem: many goroutines may read shared data at
+// once, but a writer needs it to itself. Each lock here solves it with a different preference, and Measure runs a
+// workload against one to show who waits, and who starves.
+//
+// The reader-preference and fair locks follow The Little Book of Semaphores, with sync.Mutex as the semaphores; the
+// writer-preference lock is a monitor, one mutex with condition variables.
+package readerswriters
+
+import "sync"
+
+// RWLock is a readers-writers lock: any number of readers may hold it at once between RLock and RUnlock, or one
+// writer alone between Lock and Unlock. *sync.RWMutex is one.
+type RWLock interface {
+	RLock()
+	RUnlock()
+	Lock()
+	Unlock()
+}
+
+// ReaderPreference lets a reader in whenever other readers are reading, even while a writer waits, so a steady stream
+// of readers can starve the writers. The first reader in locks writers out of the room and the last out lets them in:
+// the "lightswitch" pattern. The zero value is an unlocked lock.
+type ReaderPreference struct {
+	mu        sync.Mutex // Guards readers.
+	readers   int        // Readers in the room.
+	roomEmpty sync.Mutex // Held by a writer, or by the readers while any are in the room.
+}
+
+// RLock waits until no writer is in the room, then enters as a reader.
+func (l *ReaderPreference) RLock() {
+	l.mu.Lock()
+	l.readers++
+	if l.readers == 1 {
+		l.roomEmpty.Lock() // First in locks the writers out.
+	}
+	l.mu.Unlock()
+}
+
+// RUnlock leaves the room as a reader.
+func (l *ReaderPreference) RUnlock() {
+	l.mu.Lock()
+	l.readers--
+	if l.readers == 0 {
+		l.roomEmpty.Unlock() // Last out lets the writers in.
+	}
+	l.mu.Unlock()
+}
+
+// Lock waits until the room is empty, then enters as the writer.
+func (l *ReaderPreference) Lock() {
+	l.roomEmpty.Lock()
+}
+
+// Unlock leaves the room as the writer.
+func (l *ReaderPreference) Unlock() {
+	l.roomEmpty.Unlock()
+}
+
+// WriterPreference keeps new readers out while any writer is waiting, so a steady stream of writers can starve the
+// readers. The zero value is an unlocked lock.
+type WriterPreference struct {
+	mu      sync.Mutex
+	cond    sync.Cond // Signalled whenever the room empties or a writer leaves; L is mu.
+	readers int       // Readers in the room.
+	writing bool      // Whether a writer is in the room.
+	waiting int       // Writers waiting to enter.
+}
+
+func (l *WriterPreference) init() {
+	if l.cond.L == nil {
+		l.cond.L = &l.mu
+	}
+}
+
+// RLock waits until no writer is in the room or waiting to enter, then enters as a reader.
+func (l *WriterPreference) RLock() {
+	l.mu.Lock()
+	l.init()
+	for l.writing || l.waiting > 0 {
+		l.cond.Wait()
+	}
+	l.readers++
+	l.mu.Unlock()
+}
+
+// RUnlock leaves the room as a reader.
+func (l *WriterPreference) RUnlock() {
+	l.mu.Lock()
+	l.init()
+	l.readers--
+	if l.readers == 0 {
+		l.cond.Broadcast()
+	}
+	l.mu.Unlock()
+}
+
+// Lock waits until the room is empty, keeping new readers out meanwhile, then enters as the writer.
+func (l *WriterPreference) Lock() {
+	l.mu.Lock()
+	l.init()
+	l.waiting++
+	for l.writing || l.readers > 0 {
+		l.cond.Wait()
+	}
+	l.waiting--
+	l.writing = true
+	l.mu.Unlock()
+}
+
+// Unlock leaves the room as the writer.
+func (l *WriterPreference) Unlock() {
+	l.mu.Lock()
+	l.init()
+	l.writing = false
+	l.cond.Broadcast()
+	l.mu.Unlock()
+}
+
+// Fair starves neither readers nor writers. Everyone passes through a turnstile on the way in, and a waiting writer
+// holds it, so readers who arrive after a writer wait behind it rather than keep the room full. How fairly goroutines
+// queueing at the turnstile are served is up to sync.Mutex, which hands itself over in the order goroutines wait once
+// any has waited over a millisecond. The zero value is an unlocked lock.
+type Fair struct {
+	turnstile sync.Mutex
+	room      ReaderPreference // The lightswitch, which the turnstile keeps from preferring readers.
+}
+
+// RLock passes the turnstile, then enters as a reader.
+func (l *Fair) RLock() {
+	l.turnstile.Lock()
+	l.turnstile.Unlock()
+	l.room.RLock()
+}
+
+// RUnlock leaves the room as a reader.
+func (l *Fair) RUnlock() {
+	l.room.RUnlock()
+}
+
+// Lock holds the turnstile, keeping later arrivals out, until the room is empty, then enters as the writer.
+func (l *Fair) Lock() {
+	l.turnstile.Lock()
+	l.room.Lock()
+	l.turnstile.Unlock()
+}
+
+// Unlock leaves the room as the writer.
+func (l *Fair) Unlock() {
+	l.room.Unlock()
+}
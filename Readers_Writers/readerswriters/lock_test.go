@@ -0,0 +1,98 @@
+package readerswriters
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// locks lists each lock by name, with sync.RWMutex to compare against.
+var locks = []struct {
+	name string
+	new  func() RWLock
+}{
+	{"reader", func() RWLock { return &ReaderPreference{} }},
+	{"writer", func() RWLock { return &WriterPreference{} }},
+	{"fair", func() RWLock { return &Fair{} }},
+	{"sync", func() RWLock { return &sync.RWMutex{} }},
+}
+
+// TestExclusion checks that a writer never holds a lock alongside anyone else, while readers share it.
+func TestExclusion(t *testing.T) {
+	for _, lk := range locks {
+		t.Run(lk.name, func(t *testing.T) {
+			l := lk.new()
+			var readers, writers, most atomic.Int32
+			var wg sync.WaitGroup
+			for i := range 40 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range 200 {
+						if i%4 == 0 {
+							l.Lock()
+							if w := writers.Add(1); w != 1 || readers.Load() != 0 {
+								t.Errorf("a writer is in with %d writers and %d readers", w, readers.Load())
+							}
+							writers.Add(-1)
+							l.Unlock()
+							continue
+						}
+						l.RLock()
+						r := readers.Add(1)
+						for m := most.Load(); r > m && !most.CompareAndSwap(m, r); m = most.Load() {
+						}
+						if writers.Load() != 0 {
+							t.Error("a reader is in with a writer")
+						}
+						readers.Add(-1)
+						l.RUnlock()
+					}
+				}()
+			}
+			wg.Wait()
+			t.Logf("at most %d readers at once", most.Load())
+		})
+	}
+}
+
+// readerWaitingForWriter has a reader hold l while a writer waits for it, then reports whether another reader could
+// get in before the writer.
+func readerWaitingForWriter(l RWLock) bool {
+	l.RLock()
+	writerIn := make(chan struct{})
+	go func() {
+		l.Lock()
+		close(writerIn)
+		l.Unlock()
+	}()
+	time.Sleep(20 * time.Millisecond) // Long enough for the writer to be waiting.
+
+	readerIn := make(chan struct{})
+	go func() {
+		l.RLock()
+		close(readerIn)
+		l.RUnlock()
+	}()
+	overtook := false
+	select {
+	case <-readerIn:
+		overtook = true
+	case <-time.After(20 * time.Millisecond):
+	}
+	l.RUnlock()
+	<-writerIn
+	<-readerIn
+	return overtook
+}
+
+// TestPreference checks that only the reader-preference lock lets a reader in while a writer waits.
+func TestPreference(t *testing.T) {
+	for _, lk := range locks[:3] {
+		want := lk.name == "reader"
+		if got := readerWaitingForWriter(lk.new()); got != want {
+			t.Errorf("%s: a new reader got in while a writer waited: %v, want %v", lk.name, got, want)
+		}
+	}
+}
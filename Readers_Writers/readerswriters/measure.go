@@ -0,0 +1,97 @@
+package readerswriters
+
+import (
+	"sync"
+	"time"
+)
+
+// Workload describes the readers and writers Measure sets against a lock.
+type Workload struct {
+	Readers   int           // Goroutines that read.
+	Writers   int           // Goroutines that write.
+	Duration  time.Duration // How long they keep taking turns.
+	ReadTime  time.Duration // How long each read holds the lock.
+	WriteTime time.Duration // How long each write holds the lock.
+	Pause     time.Duration // How long each goroutine waits after a turn before the next.
+}
+
+// RoleStats is how the readers, or the writers, fared in a run.
+type RoleStats struct {
+	Turns    int           // Times they held the lock within the run.
+	MeanWait time.Duration // Mean time waiting for the lock, over every turn.
+	MaxWait  time.Duration // Longest time any waited for the lock, counting a wait still going at the end of the run.
+	Starved  int           // Goroutines that never held the lock within the run.
+}
+
+// Result is how the readers and writers fared in a run.
+type Result struct {
+	Readers RoleStats
+	Writers RoleStats
+}
+
+// Measure runs w against l and reports how long the readers and writers waited for it, and how many starved.
+//
+// Input:
+//   - l (RWLock): The lock, unlocked.
+//   - w (Workload): The goroutines to run and how long they hold the lock.
+//
+// Output:
+//   - Result: Turns, waits and starved goroutines, for the readers and for the writers.
+//
+// Functionality:
+//  1. Starts every reader and writer at once, each taking the lock, holding it for the read or write time, letting
+//     it go and pausing, over and over, until w.Duration has passed.
+//  2. A turn counts if the lock was taken before the end of the run. A goroutine still waiting at the end waits on
+//     until it has the lock, and the whole of that wait counts towards the longest, as it shows how long a goroutine
+//     can starve for.
+func Measure(l RWLock, w Workload) Result {
+	var (
+		mu      sync.Mutex
+		result  Result
+		waited  [2]time.Duration // Total wait of the readers and of the writers, over the turns counted.
+		wg      sync.WaitGroup
+		started = time.Now()
+		end     = started.Add(w.Duration)
+	)
+	run := func(role *RoleStats, total *time.Duration, lock, unlock func(), hold time.Duration) {
+		defer wg.Done()
+		turns := 0
+		for time.Now().Before(end) {
+			asked := time.Now()
+			lock()
+			got := time.Now()
+			time.Sleep(hold)
+			unlock()
+
+			mu.Lock()
+			role.MaxWait = max(role.MaxWait, got.Sub(asked))
+			if got.Before(end) {
+				turns++
+				role.Turns++
+				*total += got.Sub(asked)
+			}
+			mu.Unlock()
+			time.Sleep(w.Pause)
+		}
+		if turns == 0 {
+			mu.Lock()
+			role.Starved++
+			mu.Unlock()
+		}
+	}
+	wg.Add(w.Readers + w.Writers)
+	for range w.Readers {
+		go run(&result.Readers, &waited[0], l.RLock, l.RUnlock, w.ReadTime)
+	}
+	for range w.Writers {
+		go run(&result.Writers, &waited[1], l.Lock, l.Unlock, w.WriteTime)
+	}
+	wg.Wait()
+
+	for i, role := range []*RoleStats{&result.Readers, &result.Writers} {
+		if role.Turns > 0 {
+			role.MeanWait = waited[i] / time.Duration(role.Turns)
+		}
+	}
+	return result
+}
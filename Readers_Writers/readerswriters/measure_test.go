@@ -0,0 +1,22 @@
+package readerswriters
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMeasure(t *testing.T) {
+	w := Workload{Readers: 4, Writers: 2, Duration: 50 * time.Millisecond, ReadTime: time.Millisecond, WriteTime: time.Millisecond, Pause: time.Millisecond}
+	for _, lk := range locks {
+		r := Measure(lk.new(), w)
+		if r.Readers.Turns == 0 || r.Writers.Turns == 0 {
+			t.Errorf("%s: %d reads and %d writes, want some of each", lk.name, r.Readers.Turns, r.Writers.Turns)
+		}
+		if r.Readers.MeanWait > r.Readers.MaxWait || r.Writers.MeanWait > r.Writers.MaxWait {
+			t.Errorf("%s: a mean wait is longer than the longest: %+v", lk.name, r)
+		}
+		if lk.name == "fair" && (r.Readers.Starved > 0 || r.Writers.Starved > 0) {
+			t.Errorf("fair: %d readers and %d writers starved", r.Readers.Starved, r.Writers.Starved)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"log"     // Reports chronons whose births and deaths do not add up.
+	"strconv" // Converts the counts to strings for the CSV file.
+
+	"Wator/wator" // Provides the populations and flows being balanced.
+)
+
+// balanceHeader lists the columns of the mass balance CSV file.
+var balanceHeader = []string{"Chronon", "Fish", "Sharks", "Fish Born", "Fish Eaten", "Disease Deaths", "Sharks Born", "Sharks Starved", "Fish Error", "Shark Error"}
+
+// balanceLog keeps the births and deaths of every chronon until the run finishes and they are written to the mass
+// balance file. Each chronon the change in each population should equal its births less its deaths; the difference
+// is recorded as the error, which is always zero unless a creature was lost or duplicated.
+type balanceLog struct {
+	last       wator.Stats // Totals at the end of the previous chronon.
+	rows       [][]string  // The rows recorded so far, one per chronon.
+	unbalanced int         // Chronons with a non-zero error.
+	first      int         // The first chronon with a non-zero error.
+}
+
+// newBalanceLog returns a log starting from the simulation's current populations.
+func newBalanceLog(sim *wator.Simulation) *balanceLog {
+	return &balanceLog{last: sim.Stats()}
+}
+
+// record adds the births and deaths of the chronon that has just run, logging the first chronon that does not balance.
+func (l *balanceLog) record(sim *wator.Simulation) {
+	stats := sim.Stats()
+	flows, last := stats.Flows, l.last.Flows
+	fishBorn, fishEaten := flows.FishBorn-last.FishBorn, flows.FishEaten-last.FishEaten
+	diseased := stats.DiseaseDeaths - l.last.DiseaseDeaths
+	sharksBorn, starved := flows.SharksBorn-last.SharksBorn, flows.SharksStarved-last.SharksStarved
+	fishError := int64(stats.Fish-l.last.Fish) - (fishBorn - fishEaten - diseased)
+	sharkError := int64(stats.Sharks-l.last.Sharks) - (sharksBorn - starved)
+
+	if fishError != 0 || sharkError != 0 {
+		if l.unbalanced == 0 {
+			l.first = stats.Chronon
+			log.Printf("chronon %d does not balance: %+d fish and %+d sharks not accounted for by births and deaths", stats.Chronon, fishError, sharkError)
+		}
+		l.unbalanced++
+	}
+	l.rows = append(l.rows, []string{
+		strconv.Itoa(stats.Chronon),
+		strconv.Itoa(stats.Fish),
+		strconv.Itoa(stats.Sharks),
+		strconv.FormatInt(fishBorn, 10),
+		strconv.FormatInt(fishEaten, 10),
+		strconv.FormatInt(diseased, 10),
+		strconv.FormatInt(sharksBorn, 10),
+		strconv.FormatInt(starved, 10),
+		strconv.FormatInt(fishError, 10),
+		strconv.FormatInt(sharkError, 10),
+	})
+	l.last = stats
+}
+
+// write appends the recorded rows to the mass balance file and logs whether every chronon balanced.
+func (l *balanceLog) write(files runFiles) error {
+	if l.unbalanced == 0 {
+		log.Printf("births and deaths balanced in all %d chronons", len(l.rows))
+	} else {
+		log.Printf("births and deaths did not balance in %d of %d chronons, first at chronon %d", l.unbalanced, len(l.rows), l.first)
+	}
+	return appendCSV(files.balance, files.metadata, balanceHeader, l.rows)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"Wator/wator"
+)
+
+func TestBalanceLog(t *testing.T) {
+	cfg := wator.DefaultConfig()
+	cfg.Seed = 1
+	cfg.Threads = 4
+	sim, err := wator.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := newBalanceLog(sim)
+	for i := 0; i < 100; i++ {
+		sim.Step()
+		l.record(sim)
+	}
+	if len(l.rows) != 100 || l.unbalanced != 0 {
+		t.Fatalf("%d rows with %d unbalanced chronons, want 100 rows that all balance", len(l.rows), l.unbalanced)
+	}
+
+	// Pretend three fish appeared from nowhere during the next chronon.
+	l.last.Fish -= 3
+	sim.Step()
+	l.record(sim)
+	if row := l.rows[len(l.rows)-1]; l.unbalanced != 1 || l.first != 101 || row[8] != "3" || row[9] != "0" {
+		t.Errorf("unbalanced chronon recorded as %v, with %d unbalanced chronons starting at %d", row, l.unbalanced, l.first)
+	}
+}
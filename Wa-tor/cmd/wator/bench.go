@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag" // Parses the bench command's flags.
+	"fmt"  // Prints the summary of the run.
+	"os"   // Writes the summary to stdout.
+	"time" // Reports how long the run took and seeds repeated runs.
+
+	"Wator/wator" // Provides the statistics of each run.
+)
+
+// benchRun is the outcome of one bench run, kept so repeated runs can be aggregated.
+type benchRun struct {
+	rate        float64          // Chronons per second.
+	stats       wator.Stats      // Statistics at the end of the run.
+	stability   stabilityTracker // How much the populations varied over the run.
+	files       runFiles         // The files the run wrote to.
+	interrupted bool             // Whether the run was stopped with Ctrl+C.
+}
+
+// benchCommand implements "wator bench": it runs the simulation for a fixed number of chronons without opening a
+// window and appends the chronon rate to the same results file the run command writes its frame rate to.
+// Without the cost of drawing, the rate measures the simulation alone, so thread counts can be compared fairly.
+// Progress is printed to stderr every few seconds unless -quiet is set, and a summary to stdout at the end.
+// With -stop-at-equilibrium the run ends early once the populations settle, and the rate covers the chronons run.
+// With -repeat the run is repeated with consecutive seeds and the spread of the results summarised.
+func benchCommand(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	cf := addConfigFlags(fs)
+	rf := addRecordFlags(fs)
+	ef := addEquilibriumFlags(fs)
+	sf := addSeasonFlags(fs)
+	chronons := fs.Int("chronons", 1000, "number of chronons to simulate")
+	quiet := fs.Bool("quiet", false, "do not print progress lines to stderr")
+	repeat := fs.Int("repeat", 1, "number of runs with consecutive seeds starting at -seed, summarised with means and 95% confidence intervals")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := cf.applyPreset(fs); err != nil {
+		return err
+	}
+	if *chronons < 1 {
+		return fmt.Errorf("chronons must be at least 1, got %d", *chronons)
+	}
+	if *repeat < 1 {
+		return fmt.Errorf("repeat must be at least 1, got %d", *repeat)
+	}
+	if *repeat > 1 {
+		if rf.snapshot != "" || rf.record != "" || rf.heatmap != "" {
+			return fmt.Errorf("-snapshot, -record and -heatmap would be overwritten by each run, so they cannot be used with -repeat")
+		}
+		return benchRepeated(cf, rf, ef, sf, *chronons, *repeat, *quiet)
+	}
+	_, err := runBench(cf, rf, ef, sf, *chronons, *quiet)
+	return err
+}
+
+// runBench runs one benchmark of the given number of chronons, prints its summary and appends it to the results file.
+func runBench(cf *configFlags, rf *recordFlags, ef *equilibriumFlags, sf *seasonFlags, chronons int, quiet bool) (benchRun, error) {
+	s, err := newSession("bench", cf, rf, ef, sf)
+	if err != nil {
+		return benchRun{}, err
+	}
+	progress := newProgressReporter(os.Stderr, chronons, quiet)
+	for i := 0; i < chronons && !s.stopped(); i++ {
+		if err := s.step(); err != nil {
+			s.finish(s.chrononRate())
+			return benchRun{}, err
+		}
+		progress.report(s.sim)
+	}
+
+	rate := s.chrononRate()
+	stats := s.sim.Stats()
+	fmt.Fprintf(os.Stdout, "%d chronons in %v (%.2f chronons/s): %d fish, %d sharks\n",
+		stats.Chronon, time.Since(s.start).Round(time.Millisecond), rate, stats.Fish, stats.Sharks)
+	run := benchRun{rate: rate, stats: stats, stability: s.stability, files: s.files, interrupted: s.interrupted.Load()}
+	return run, s.finish(rate)
+}
+
+// benchRepeated runs the benchmark repeat times with the seeds -seed, -seed+1 and so on, appending every run to one
+// results file, then prints and saves the mean, standard deviation and 95% confidence interval of each metric.
+// Ctrl+C stops after the current run, which is left out of the summary since it did not run to the end.
+func benchRepeated(cf *configFlags, rf *recordFlags, ef *equilibriumFlags, sf *seasonFlags, chronons, repeat int, quiet bool) error {
+	if cf.seed == 0 {
+		cf.seed = time.Now().UnixNano() // Chosen once, so the runs' seeds are consecutive and each can be rerun.
+	}
+	first := cf.seed
+	var runs []benchRun
+	for i := 0; i < repeat; i++ {
+		cf.seed = first + int64(i)
+		run, err := runBench(cf, rf, ef, sf, chronons, quiet)
+		if err != nil {
+			return err
+		}
+		if rf.results == "" {
+			rf.results = run.files.results // Later runs append to the file named after the first.
+		}
+		if run.interrupted {
+			fmt.Fprintf(os.Stdout, "interrupted during run %d of %d\n", i+1, repeat)
+			break
+		}
+		runs = append(runs, run)
+	}
+	if len(runs) == 0 {
+		return nil
+	}
+
+	summaries := summarizeRuns(runs)
+	fmt.Fprintf(os.Stdout, "\n%d runs with seeds %d to %d:\n", len(runs), first, first+int64(len(runs)-1))
+	printSummaries(os.Stdout, summaries)
+	return writeSummaries(runs[0].files, summaries)
+}
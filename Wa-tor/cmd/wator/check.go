@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"  // Extracts the cell of a broken invariant.
+	"fmt"     // Formats the diagnostic dump.
+	"strings" // Builds the diagnostic dump.
+
+	"Wator/wator" // Provides the invariant check.
+)
+
+// dumpRadius is how many cells either side of a broken invariant the diagnostic dump shows.
+const dumpRadius = 5
+
+// checkInvariants panics with a diagnostic dump if sim has broken one of its invariants.
+// It is called after every chronon with -check, so corruption is caught on the chronon it happens rather than
+// showing up much later as a population that drifts or a creature that vanishes.
+func checkInvariants(sim *wator.Simulation) {
+	if err := sim.Check(); err != nil {
+		panic(invariantDump(sim, err))
+	}
+}
+
+// invariantDump describes a broken invariant for the -check panic.
+//
+// Input:
+//   - sim (*wator.Simulation): The simulation that failed the check.
+//   - err (error): The error returned by Check.
+//
+// Output:
+//   - string: The error, the configuration and populations, the cells around the problem with the offending cell
+//     in brackets, and where the whole grid was saved.
+//
+// Functionality:
+// The grid is saved as a text scenario named after the chronon, so the layout can be inspected in full or loaded
+// with -scenario. The breed and starve counters are not part of a scenario, so a rerun from it is not identical.
+func invariantDump(sim *wator.Simulation, err error) string {
+	cfg, stats := sim.Config(), sim.Stats()
+	snap := sim.Snapshot()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "wator: invariant violated: %v\n", err)
+	fmt.Fprintf(&b, "config: threads=%d width=%d height=%d seed=%d fish-breed=%d shark-breed=%d shark-starve=%d sight=%d deterministic=%t\n",
+		cfg.Threads, cfg.Width, cfg.Height, cfg.Seed, cfg.FishBreed, cfg.SharkBreed, cfg.SharkStarve, cfg.SightRadius, cfg.Deterministic)
+	fmt.Fprintf(&b, "listed: %d fish, %d sharks; on the grid: %d fish, %d sharks\n",
+		stats.Fish, stats.Sharks, snap.Count(wator.Fish), snap.Count(wator.Shark))
+
+	var invariant *wator.InvariantError
+	if errors.As(err, &invariant) {
+		fmt.Fprintf(&b, "cells around (%d, %d), wrapping around the edges:\n", invariant.X, invariant.Y)
+		for dy := -dumpRadius; dy <= dumpRadius; dy++ {
+			for dx := -dumpRadius; dx <= dumpRadius; dx++ {
+				x := ((invariant.X+dx)%cfg.Width + cfg.Width) % cfg.Width
+				y := ((invariant.Y+dy)%cfg.Height + cfg.Height) % cfg.Height
+				if dx == 0 && dy == 0 {
+					fmt.Fprintf(&b, "[%c]", cellChar(snap.At(x, y)))
+				} else {
+					fmt.Fprintf(&b, " %c ", cellChar(snap.At(x, y)))
+				}
+			}
+			b.WriteByte('\n')
+		}
+	}
+
+	filename := fmt.Sprintf("wator_check_chronon%d.txt", stats.Chronon)
+	if err := saveScenario(filename, snap); err != nil {
+		fmt.Fprintf(&b, "the grid could not be saved: %v\n", err)
+	} else {
+		fmt.Fprintf(&b, "grid saved to %s\n", filename)
+	}
+	return b.String()
+}
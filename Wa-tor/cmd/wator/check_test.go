@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"Wator/wator"
+)
+
+func TestInvariantDump(t *testing.T) {
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil { // The dump saves the grid to the working directory.
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	layout := wator.NewSnapshot(20, 20)
+	layout.Set(0, 0, wator.Shark)
+	cfg := wator.DefaultConfig()
+	cfg.Width, cfg.Height = 20, 20
+	cfg.Layout = layout
+	sim, err := wator.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dump := invariantDump(sim, &wator.InvariantError{X: 0, Y: 0, Problem: "test"})
+	for _, want := range []string{"invariant violated: chronon 0: cell (0, 0): test", "[S]", "grid saved to wator_check_chronon0.txt"} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("dump does not contain %q:\n%s", want, dump)
+		}
+	}
+	if _, err := os.Stat("wator_check_chronon0.txt"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"flag"  // Parses the compare command's flags.
+	"fmt"   // Formats the panel text and summary.
+	"image" // Provides the panel rectangles.
+	"time"  // Reports the time per chronon.
+
+	"Wator/wator"        // Runs the two simulations.
+	"Wator/wator/render" // Draws the grids.
+
+	"github.com/hajimehoshi/ebiten/v2"            // Runs the window and its game loop.
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil" // Draws the panel text.
+)
+
+// comparison shows two simulations started from the same seed side by side, advancing both by one chronon per frame.
+type comparison struct {
+	sims      [2]*wator.Simulation // The left (A) and right (B) simulations.
+	renderers [2]render.Renderer   // Draw each grid into its half of the window.
+	diverged  int                  // First chronon at which the grids differed, or -1 while they agree.
+	last      float64              // Fraction of cells that differed after the latest chronon.
+}
+
+// compareCommand implements "wator compare": it runs two engine configurations from the same seed and layout, for
+// example 1 thread against 8, and shows them side by side with the fraction of cells on which they disagree.
+// Identical configurations stay identical only when stepping is reproducible (one thread, or -deterministic), so the
+// view demonstrates both what concurrency costs in reproducibility and what it gains in speed.
+func compareCommand(args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	cf := addConfigFlags(fs)
+	threads := [2]*int{
+		fs.Int("a-threads", 1, "thread count of the left simulation"),
+		fs.Int("b-threads", 8, "thread count of the right simulation"),
+	}
+	deterministic := [2]*bool{
+		fs.Bool("a-deterministic", false, "step the left simulation's partitions one at a time"),
+		fs.Bool("b-deterministic", false, "step the right simulation's partitions one at a time"),
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "threads" || f.Name == "deterministic" {
+			err = fmt.Errorf("-%s differs between the two simulations; use -a-%s and -b-%s", f.Name, f.Name, f.Name)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if err := cf.applyPreset(fs); err != nil { // After the check above, since the preset sets -threads.
+		return err
+	}
+
+	c := &comparison{diverged: -1}
+	for i := range c.sims {
+		cf.threads, cf.deterministic = *threads[i], *deterministic[i]
+		sim, err := cf.newSimulation()
+		if err != nil {
+			return fmt.Errorf("simulation %c: %w", 'A'+i, err)
+		}
+		c.sims[i] = sim
+		defer sim.Close()
+		cf.seed = sim.Config().Seed // Give B the seed A picked when -seed was 0.
+	}
+
+	ebiten.SetWindowSize(2*windowSize, windowSize)
+	ebiten.SetWindowTitle("Wa-Tor World: A | B")
+	if err := ebiten.RunGame(c); err != nil {
+		return err
+	}
+	c.printSummary()
+	return nil
+}
+
+// Update advances both simulations by one chronon and measures how far apart they are.
+func (c *comparison) Update() error {
+	c.sims[0].Step()
+	c.sims[1].Step()
+	c.last = divergence(c.sims[0].Snapshot(), c.sims[1].Snapshot())
+	if c.last > 0 && c.diverged < 0 {
+		c.diverged = c.sims[0].Chronon()
+	}
+	return nil
+}
+
+// Draw renders each grid in its half of the window, with its configuration and speed above it and the divergence
+// between the two below the left grid.
+func (c *comparison) Draw(screen *ebiten.Image) {
+	for i, sim := range c.sims {
+		panel := image.Rect(i*windowSize, 0, (i+1)*windowSize, windowSize)
+		c.renderers[i].Draw(screen.SubImage(panel).(*ebiten.Image), sim.Snapshot())
+		ebitenutil.DebugPrintAt(screen, panelText('A'+rune(i), sim), panel.Min.X+4, 4)
+	}
+
+	status := fmt.Sprintf("%.1f%% of cells differ", 100*c.last)
+	if c.diverged >= 0 {
+		status += fmt.Sprintf(" (first diverged at chronon %d)", c.diverged)
+	}
+	ebitenutil.DebugPrintAt(screen, status, 4, windowSize-20)
+}
+
+// Layout returns the size of the two panels side by side.
+func (c *comparison) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return 2 * windowSize, windowSize
+}
+
+// panelText describes a simulation's configuration, populations and average time per chronon.
+func panelText(name rune, sim *wator.Simulation) string {
+	cfg, stats := sim.Config(), sim.Stats()
+	mode := ""
+	if cfg.Deterministic {
+		mode = ", deterministic"
+	}
+	return fmt.Sprintf("%c: %s%s\nChronon %d: %d fish, %d sharks\n%v per chronon",
+		name, threadCount(cfg.Threads), mode, stats.Chronon, stats.Fish, stats.Sharks, timePerChronon(stats))
+}
+
+// threadCount describes a thread count, such as "1 thread" or "8 threads".
+func threadCount(threads int) string {
+	if threads == 1 {
+		return "1 thread"
+	}
+	return fmt.Sprintf("%d threads", threads)
+}
+
+// timePerChronon returns the average time Step has taken.
+func timePerChronon(stats wator.Stats) time.Duration {
+	if stats.Chronon == 0 {
+		return 0
+	}
+	return (stats.Elapsed / time.Duration(stats.Chronon)).Round(time.Microsecond)
+}
+
+// printSummary prints how far the two simulations got, how fast each was and when they diverged.
+func (c *comparison) printSummary() {
+	for i, sim := range c.sims {
+		stats := sim.Stats()
+		fmt.Printf("%c: %s, %d chronons, %v per chronon, %d fish, %d sharks\n",
+			'A'+i, threadCount(sim.Config().Threads), stats.Chronon, timePerChronon(stats), stats.Fish, stats.Sharks)
+	}
+	if c.diverged < 0 {
+		fmt.Println("the grids never diverged")
+	} else {
+		fmt.Printf("the grids first diverged at chronon %d; %.1f%% of cells differed at the end\n", c.diverged, 100*c.last)
+	}
+}
+
+// divergence returns the fraction of cells whose contents differ between two grids of the same size.
+func divergence(a, b wator.Snapshot) float64 {
+	if len(a.Cells) == 0 {
+		return 0
+	}
+	differ := 0
+	for i := range a.Cells {
+		if a.Cells[i] != b.Cells[i] {
+			differ++
+		}
+	}
+	return float64(differ) / float64(len(a.Cells))
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+
+	"Wator/wator"
+)
+
+func TestDivergence(t *testing.T) {
+	a, b := wator.NewSnapshot(4, 4), wator.NewSnapshot(4, 4)
+	if got := divergence(*a, *b); got != 0 {
+		t.Errorf("identical grids differ by %v", got)
+	}
+	a.Set(0, 0, wator.Fish)
+	b.Set(1, 0, wator.Fish)
+	if got := divergence(*a, *b); got != 2.0/16 {
+		t.Errorf("divergence = %v, want %v", got, 2.0/16)
+	}
+}
+
+func TestCompareRejectsSharedThreads(t *testing.T) {
+	if err := compareCommand([]string{"-threads", "4"}); err == nil {
+		t.Error("-threads was accepted")
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"Wator/wator"
+)
+
+func TestScenarioRoundTrip(t *testing.T) {
+	layout, err := parseTextScenario(strings.NewReader("F.S\n.#\n\n"), 4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []wator.Cell{
+		wator.Fish, wator.Empty, wator.Shark, wator.Empty,
+		wator.Empty, wator.Land, wator.Empty, wator.Empty,
+		wator.Empty, wator.Empty, wator.Empty, wator.Empty,
+	}
+	if !slices.Equal(layout.Cells, want) {
+		t.Fatalf("parsed %v, want %v", layout.Cells, want)
+	}
+
+	filename := filepath.Join(t.TempDir(), "saved.txt")
+	if err := saveScenario(filename, *layout); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := loadScenario(filename, 4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(reloaded.Cells, want) {
+		t.Fatalf("reloaded %v, want %v", reloaded.Cells, want)
+	}
+
+	if _, err := parseTextScenario(strings.NewReader("FFFFF\n"), 4, 3); err == nil {
+		t.Error("a row wider than the grid was accepted")
+	}
+}
+
+func TestReplayRoundTrip(t *testing.T) {
+	cfg := wator.DefaultConfig()
+	cfg.Width, cfg.Height, cfg.Threads, cfg.Seed = 20, 10, 2, 3
+	sim, err := wator.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filename := filepath.Join(t.TempDir(), "run.replay")
+	recorder, err := newReplayRecorder(filename, sim.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	grids := []wator.Snapshot{sim.Snapshot()}
+	for i := 0; i < 30; i++ {
+		sim.Step()
+		grids = append(grids, sim.Snapshot())
+		if err := recorder.record(grids[len(grids)-1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := loadReplay(filename, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.chronons) != 30 {
+		t.Fatalf("replay has %d chronons, want 30", len(g.chronons))
+	}
+	for i := 1; i < len(grids); i++ {
+		g.stepForward()
+		if !slices.Equal(g.grid.Cells, grids[i].Cells) {
+			t.Fatalf("chronon %d played forwards differs from the recording", i)
+		}
+	}
+	for i := len(grids) - 2; i >= 0; i-- {
+		g.stepBackward()
+		if !slices.Equal(g.grid.Cells, grids[i].Cells) {
+			t.Fatalf("chronon %d played backwards differs from the recording", i)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"flag"    // Registers the flags shared by the subcommands.
+	"fmt"     // Formats errors for invalid flag combinations.
+	"log"     // Describes the preset being used.
+	"strconv" // Converts a preset's values to flag values.
+	"strings" // Lists the preset names in the flag's help.
+
+	"Wator/wator" // Provides the simulation configuration.
+)
+
+// configFlags holds the flags that describe a simulation, shared by the run, bench and render commands.
+type configFlags struct {
+	width, height int           // Grid size in cells.
+	threads       int           // Number of partitions stepped concurrently.
+	seed          int64         // Random seed; 0 picks one from the clock.
+	scenario      string        // Text or PNG file giving the starting layout.
+	distribution  string        // Name of the random starting distribution, used without a scenario.
+	order         string        // Name of the order in which fish and sharks move each chronon.
+	engine        string        // Name of the engine that runs the partitions concurrently.
+	fishDensity   float64       // Average fraction of cells that start with a fish.
+	sharkDensity  float64       // Average fraction of cells that start with a shark.
+	sight         int           // Shark sight radius.
+	disease       wator.Disease // Infection among the fish; off unless the lifetime is set.
+	crowding      int           // Number of neighbouring sharks that makes a cell crowded for a territorial shark; 0 disables territoriality.
+	mutation      int           // Largest change to a newborn's inherited breed threshold; 0 disables evolution.
+	params        wator.Params  // Breeding and starvation thresholds.
+	deterministic bool          // Step partitions one at a time so multi-threaded runs are reproducible.
+	check         bool          // Verify the simulation's invariants after every chronon.
+	lockStats     bool          // Time every boundary mutex and write their statistics to the boundaries file.
+	preset        string        // Named preset supplying the defaults of the other flags; empty for none.
+}
+
+// addConfigFlags registers the simulation flags on fs, with defaults taken from wator.DefaultConfig.
+func addConfigFlags(fs *flag.FlagSet) *configFlags {
+	def := wator.DefaultConfig()
+	f := &configFlags{}
+	fs.IntVar(&f.width, "width", def.Width, "number of cells in the x direction")
+	fs.IntVar(&f.height, "height", def.Height, "number of cells in the y direction")
+	fs.IntVar(&f.threads, "threads", def.Threads, "number of partitions stepped concurrently (2 gives 2x1 partitions, 4 gives 2x2, 8 gives 4x2)")
+	fs.Int64Var(&f.seed, "seed", 0, "random seed for the run; 0 picks one from the clock")
+	fs.StringVar(&f.scenario, "scenario", "", "text or PNG file describing the initial grid layout")
+	fs.StringVar(&f.distribution, "distribution", def.Distribution.String(), "where the random starting population is placed: uniform, clusters, stripes, gradient or colony")
+	fs.StringVar(&f.order, "order", def.Order.String(), "which creatures move first each chronon: fish-first, sharks-first or interleaved (both in a random order)")
+	fs.StringVar(&f.engine, "engine", def.Engine.String(), "how the partitions run with more than one thread: workers (a goroutine each, meeting at barriers) or pool (a work-stealing pool)")
+	fs.Float64Var(&f.fishDensity, "fish-density", def.FishDensity, "average fraction of cells that start with a fish")
+	fs.Float64Var(&f.sharkDensity, "shark-density", def.SharkDensity, "average fraction of cells that start with a shark")
+	fs.IntVar(&f.sight, "sight", 0, "shark sight radius in cells; sharks hunt the nearest visible fish when it is 2 or more (0 disables hunting)")
+	fs.IntVar(&f.crowding, "crowding", 0, "make sharks territorial: a moving shark tries cells next to this many other sharks last (1 to 3; 0 disables it)")
+	fs.Float64Var(&f.disease.Fraction, "infected", 0.05, "with -infection-lifetime, the fraction of the starting fish that are infected")
+	fs.Float64Var(&f.disease.Spread, "infection-spread", 0.25, "with -infection-lifetime, the chance each chronon that an infected fish infects each healthy fish next to it")
+	fs.IntVar(&f.disease.Lifetime, "infection-lifetime", 0, "chronons an infected fish lives after catching the disease (0 disables the disease)")
+	fs.IntVar(&f.mutation, "mutation", 0, "make breed times heritable, each newborn's changing from its parent's by up to this many chronons either way (0 disables evolution)")
+	fs.IntVar(&f.params.FishBreed, "fish-breed", def.FishBreed, "chronons a fish must survive before breeding")
+	fs.IntVar(&f.params.SharkBreed, "shark-breed", def.SharkBreed, "chronons a shark must survive before breeding")
+	fs.IntVar(&f.params.SharkStarve, "shark-starve", def.SharkStarve, "chronons a shark can go without eating before it starves")
+	fs.BoolVar(&f.deterministic, "deterministic", false, "step the partitions one at a time so runs with several threads are reproducible")
+	fs.StringVar(&f.preset, "preset", "", "start from a named configuration, with any other flags given overriding it: "+strings.Join(wator.PresetNames(), ", "))
+	fs.BoolVar(&f.lockStats, "lock-stats", false, "time how long each boundary mutex is held and waited for, writing them to a _boundaries.csv file (slows the run)")
+	fs.BoolVar(&f.check, "check", false, "verify the simulation's invariants after every chronon and panic with a diagnostic dump if one is broken")
+	return f
+}
+
+// applyPreset gives the simulation flags that were not set on the command line the values of the -preset
+// configuration, if one was named. Commands call it straight after parsing their flags, so anything later derived
+// from the flags, such as the seeds of repeated runs, starts from the preset.
+func (f *configFlags) applyPreset(fs *flag.FlagSet) error {
+	if f.preset == "" {
+		return nil
+	}
+	cfg, err := wator.Preset(f.preset)
+	if err != nil {
+		return err
+	}
+	values := map[string]string{
+		"width":              strconv.Itoa(cfg.Width),
+		"height":             strconv.Itoa(cfg.Height),
+		"threads":            strconv.Itoa(cfg.Threads),
+		"seed":               strconv.FormatInt(cfg.Seed, 10),
+		"distribution":       cfg.Distribution.String(),
+		"order":              cfg.Order.String(),
+		"engine":             cfg.Engine.String(),
+		"fish-density":       strconv.FormatFloat(cfg.FishDensity, 'g', -1, 64),
+		"shark-density":      strconv.FormatFloat(cfg.SharkDensity, 'g', -1, 64),
+		"sight":              strconv.Itoa(cfg.SightRadius),
+		"crowding":           strconv.Itoa(cfg.Crowding),
+		"infected":           strconv.FormatFloat(cfg.Disease.Fraction, 'g', -1, 64),
+		"infection-spread":   strconv.FormatFloat(cfg.Disease.Spread, 'g', -1, 64),
+		"infection-lifetime": strconv.Itoa(cfg.Disease.Lifetime),
+		"mutation":           strconv.Itoa(cfg.Mutation),
+		"fish-breed":         strconv.Itoa(cfg.FishBreed),
+		"shark-breed":        strconv.Itoa(cfg.SharkBreed),
+		"shark-starve":       strconv.Itoa(cfg.SharkStarve),
+		"deterministic":      strconv.FormatBool(cfg.Deterministic),
+	}
+	fs.Visit(func(set *flag.Flag) {
+		delete(values, set.Name) // Flags given on the command line override the preset.
+	})
+	for name, value := range values {
+		if !cfg.Disease.Enabled() && (name == "infected" || name == "infection-spread") {
+			continue // Keep the flags' defaults, so -infection-lifetime alone turns the disease on as usual.
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("preset %s: %w", f.preset, err)
+		}
+	}
+	log.Printf("preset %s: %s", f.preset, wator.PresetDescription(f.preset))
+	return nil
+}
+
+// newSimulation creates a simulation from the parsed flags, loading the scenario file if one was given.
+func (f *configFlags) newSimulation() (*wator.Simulation, error) {
+	cfg := wator.DefaultConfig()
+	cfg.Width, cfg.Height = f.width, f.height
+	cfg.Threads = f.threads
+	cfg.Seed = f.seed
+	cfg.SightRadius = f.sight
+	cfg.Crowding = f.crowding
+	cfg.Disease = f.disease
+	cfg.Mutation = f.mutation
+	cfg.Params = f.params
+	cfg.Deterministic = f.deterministic
+	cfg.InstrumentLocks = f.lockStats
+	cfg.FishDensity, cfg.SharkDensity = f.fishDensity, f.sharkDensity
+	distribution, err := wator.ParseDistribution(f.distribution)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Distribution = distribution
+	if cfg.Order, err = wator.ParseOrder(f.order); err != nil {
+		return nil, err
+	}
+	if cfg.Engine, err = wator.ParseEngine(f.engine); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err // Check the size before a scenario layout is allocated for it.
+	}
+	if f.scenario != "" {
+		layout, err := loadScenario(f.scenario, cfg.Width, cfg.Height)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Layout = layout
+	}
+	return wator.New(cfg)
+}
+
+// recordFlags holds the flags that record a run to files, shared by the run and bench commands.
+type recordFlags struct {
+	snapshot string // File to record the grid to after every chronon, for snapdiff.
+	record   string // Replay log to record every cell change to.
+	state    string // File to save the grid to if the run is interrupted.
+	results  string // Results CSV file to append to; empty to name one after the run.
+	heatmap  string // PNG file to save the fish and shark occupancy heatmaps to at the end of the run.
+	balance  bool   // Write the births and deaths of every chronon to the mass balance file.
+}
+
+// addRecordFlags registers the recording flags on fs.
+func addRecordFlags(fs *flag.FlagSet) *recordFlags {
+	f := &recordFlags{}
+	fs.StringVar(&f.snapshot, "snapshot", "", "file to record the grid to after every chronon, for use with snapdiff")
+	fs.StringVar(&f.record, "record", "", "file to record every cell change to, for playback with wator replay")
+	fs.StringVar(&f.results, "results", "", "results CSV file to append to (default: a new file named after the command, thread count, grid size, seed and start time)")
+	fs.StringVar(&f.heatmap, "heatmap", "", "PNG file to save heatmaps of how often each cell held a fish and a shark to when the run ends, with _fish and _sharks added before the extension")
+	fs.BoolVar(&f.balance, "balance", false, "write the births and deaths of every chronon to a _balance.csv file and check that they account for the change in the populations")
+	fs.StringVar(&f.state, "state", "", "file to save the grid to as a text scenario if the run is interrupted with Ctrl+C")
+	return f
+}
+
+// equilibriumFlags holds the flags that detect when the populations have settled, shared by the run and bench commands.
+type equilibriumFlags struct {
+	window    int     // Chronons examined by the detector; 0 turns detection off.
+	threshold float64 // Largest variation allowed, relative to the mean population.
+	stop      bool    // End the run once equilibrium is reached.
+}
+
+// addEquilibriumFlags registers the equilibrium detection flags on fs.
+func addEquilibriumFlags(fs *flag.FlagSet) *equilibriumFlags {
+	f := &equilibriumFlags{}
+	fs.IntVar(&f.window, "equilibrium-window", 0, "detect equilibrium over this many chronons and record when it is reached in the results file (0 disables detection)")
+	fs.Float64Var(&f.threshold, "equilibrium-threshold", 0.05, "largest variation in either population over the window, relative to its mean, that counts as settled")
+	fs.BoolVar(&f.stop, "stop-at-equilibrium", false, "end the run as soon as equilibrium is reached; requires -equilibrium-window")
+	return f
+}
+
+// newDetector returns the detector described by the flags, or nil when detection is off.
+func (f *equilibriumFlags) newDetector() (*wator.EquilibriumDetector, error) {
+	if f.window == 0 {
+		if f.stop {
+			return nil, fmt.Errorf("-stop-at-equilibrium requires -equilibrium-window")
+		}
+		return nil, nil
+	}
+	return wator.NewEquilibriumDetector(f.window, f.threshold)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestApplyPreset(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cf := addConfigFlags(fs)
+	if err := fs.Parse([]string{"-preset", "fish-heavy", "-fish-breed", "4", "-threads", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cf.applyPreset(fs); err != nil {
+		t.Fatal(err)
+	}
+	// The preset fills in what was not given, and the flags given override it.
+	if cf.width != 100 || cf.seed != 1 || cf.sharkDensity != 0.005 || cf.params.SharkBreed != 12 {
+		t.Errorf("preset not applied: width %d, seed %d, shark density %g, shark breed %d", cf.width, cf.seed, cf.sharkDensity, cf.params.SharkBreed)
+	}
+	if cf.params.FishBreed != 4 || cf.threads != 2 {
+		t.Errorf("flags did not override the preset: fish breed %d, threads %d", cf.params.FishBreed, cf.threads)
+	}
+	if cf.disease.Spread != 0.25 {
+		t.Errorf("infection spread %g, want the flag's default", cf.disease.Spread)
+	}
+
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	cf = addConfigFlags(fs)
+	if err := fs.Parse([]string{"-preset", "nonexistent"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cf.applyPreset(fs); err == nil {
+		t.Error("an unknown preset was accepted")
+	}
+}
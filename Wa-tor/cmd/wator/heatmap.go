@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"           // Wraps errors with the heatmap they concern.
+	"log"           // Reports where the heatmaps were written.
+	"path/filepath" // Splits the heatmap name around its extension.
+
+	"Wator/wator"        // Provides the occupancy counts.
+	"Wator/wator/render" // Converts the counts to images.
+)
+
+// heatmapSize is the approximate width or height in pixels of the larger side of a saved heatmap.
+// Small grids are scaled up so their cells can be seen; grids this size or larger are saved at one pixel per cell.
+const heatmapSize = 512
+
+// heatmapFiles returns the names of the fish and shark heatmaps for the -heatmap file name.
+func heatmapFiles(filename string) (fish, sharks string) {
+	ext := filepath.Ext(filename)
+	stem := filename[:len(filename)-len(ext)]
+	return stem + "_fish" + ext, stem + "_sharks" + ext
+}
+
+// saveHeatmaps writes the fish and shark counts of o as two PNG heatmaps named after filename.
+// Each is shaded relative to its own busiest cell, so the spatial structure of the scarcer species still shows.
+func saveHeatmaps(filename string, o *wator.Occupancy) error {
+	scale := max(1, heatmapSize/max(o.Width, o.Height))
+	fishFile, sharkFile := heatmapFiles(filename)
+	if err := savePNG(fishFile, render.Heatmap(o.Fish, o.Width, o.Height, render.FishColor), scale); err != nil {
+		return fmt.Errorf("failed to save fish heatmap: %w", err)
+	}
+	if err := savePNG(sharkFile, render.Heatmap(o.Sharks, o.Width, o.Height, render.SharkColor), scale); err != nil {
+		return fmt.Errorf("failed to save shark heatmap: %w", err)
+	}
+	log.Printf("heatmaps of %d chronons saved to %s and %s", o.Chronons, fishFile, sharkFile)
+	return nil
+}
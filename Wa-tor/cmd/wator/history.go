@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"  // Formats the history status line.
+	"time" // Records when the run was paused.
+
+	"Wator/wator" // Provides the grids kept in the history.
+
+	"github.com/hajimehoshi/ebiten/v2"            // Provides the screen image and key codes.
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil" // Draws the history status line.
+	"github.com/hajimehoshi/ebiten/v2/inpututil"  // Detects single key presses.
+)
+
+// history is a ring buffer of the most recent grids shown in the run window, so a paused run can be stepped
+// backwards to see how a particular extinction or collapse came about.
+type history struct {
+	snaps []wator.Snapshot // The kept grids; snaps[next-1] is the newest.
+	next  int              // The slot the next grid is written to.
+	count int              // Number of slots filled, at most len(snaps).
+}
+
+// newHistory returns a history keeping the last size grids, or nil when size is 0.
+// A nil *history keeps nothing, which is how -history 0 turns it off.
+func newHistory(size int) *history {
+	if size == 0 {
+		return nil
+	}
+	return &history{snaps: make([]wator.Snapshot, size)}
+}
+
+// push adds snap as the newest grid, replacing the oldest once the history is full.
+func (h *history) push(snap wator.Snapshot) {
+	if h == nil {
+		return
+	}
+	h.snaps[h.next] = snap
+	h.next = (h.next + 1) % len(h.snaps)
+	h.count = min(h.count+1, len(h.snaps))
+}
+
+// len returns the number of grids kept.
+func (h *history) len() int {
+	if h == nil {
+		return 0
+	}
+	return h.count
+}
+
+// at returns the grid back chronons before the newest, where 0 is the newest and len()-1 the oldest kept.
+func (h *history) at(back int) wator.Snapshot {
+	return h.snaps[(h.next-1-back+2*len(h.snaps))%len(h.snaps)]
+}
+
+// handleHistoryKeys pauses and resumes the run and steps through the history while paused.
+//
+// Functionality:
+//  1. Space pauses the run, keeping the newest grid on screen, or resumes it from the newest chronon.
+//     The simulation cannot be rewound, so resuming always continues from where it was paused.
+//  2. While paused, Left steps one chronon further back, up to the oldest grid kept, and Right one chronon forward,
+//     up to the newest.
+//
+// Time spent paused is recorded so it does not count towards -duration or the frame rate.
+func (w *window) handleHistoryKeys() {
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		if w.paused {
+			w.pausedFor += time.Since(w.pausedAt)
+			w.back = 0
+		} else {
+			w.pausedAt = time.Now()
+		}
+		w.paused = !w.paused
+	}
+	if !w.paused {
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) && w.back < w.history.len()-1 {
+		w.back++
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) && w.back > 0 {
+		w.back--
+	}
+}
+
+// drawHistoryStatus shows, below the parameter panel, the chronon on screen while paused and how far back it is.
+func (w *window) drawHistoryStatus(screen *ebiten.Image) {
+	if !w.paused {
+		return
+	}
+	line := "Paused (Space resumes)"
+	if w.history.len() > 0 {
+		snap := w.history.at(w.back)
+		line += fmt.Sprintf("\nChronon %d, %d back of %d kept (Left/Right): %d fish, %d sharks",
+			snap.Chronon, w.back, w.history.len()-1, snap.Count(wator.Fish), snap.Count(wator.Shark))
+	}
+	ebitenutil.DebugPrintAt(screen, line, 4, 4+16*len(parameterKeys))
+}
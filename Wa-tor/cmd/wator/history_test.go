@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"Wator/wator"
+)
+
+func TestHistory(t *testing.T) {
+	h := newHistory(3)
+	for chronon := 0; chronon < 5; chronon++ {
+		h.push(wator.Snapshot{Chronon: chronon})
+	}
+	if h.len() != 3 {
+		t.Fatalf("len = %d, want 3", h.len())
+	}
+	for back, want := range []int{4, 3, 2} {
+		if got := h.at(back).Chronon; got != want {
+			t.Errorf("at(%d) is chronon %d, want %d", back, got, want)
+		}
+	}
+
+	off := newHistory(0)
+	off.push(wator.Snapshot{})
+	if off.len() != 0 {
+		t.Error("-history 0 kept a grid")
+	}
+}
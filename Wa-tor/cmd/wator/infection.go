@@ -0,0 +1,32 @@
+package main
+
+import (
+	"strconv" // Converts the counts to strings for the CSV file.
+
+	"Wator/wator" // Provides the populations being logged.
+)
+
+// infectionHeader lists the columns of the infection curve CSV file.
+var infectionHeader = []string{"Chronon", "Fish", "Infected", "Disease Deaths"}
+
+// infectionLog keeps the course of an epidemic, one row per chronon, until the run finishes and it is written to the
+// infection curve file. Each row is a few integers, so even a long run keeps little in memory.
+type infectionLog struct {
+	rows [][]string // The rows recorded so far, starting at chronon 0.
+}
+
+// record adds the populations at the end of the current chronon.
+func (l *infectionLog) record(sim *wator.Simulation) {
+	stats := sim.Stats()
+	l.rows = append(l.rows, []string{
+		strconv.Itoa(stats.Chronon),
+		strconv.Itoa(stats.Fish),
+		strconv.Itoa(stats.Infected),
+		strconv.FormatInt(stats.DiseaseDeaths, 10),
+	})
+}
+
+// write appends the recorded rows to the infection curve file.
+func (l *infectionLog) write(files runFiles) error {
+	return appendCSV(files.infection, files.metadata, infectionHeader, l.rows)
+}
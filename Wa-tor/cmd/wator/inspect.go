@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"         // Formats the tooltip text.
+	"image/color" // Provides the tooltip background colour.
+	"strings"     // Measures the tooltip text.
+
+	"Wator/wator" // Provides the creature details.
+
+	"github.com/hajimehoshi/ebiten/v2"            // Provides the cursor position and screen image.
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil" // Draws the tooltip text.
+	"github.com/hajimehoshi/ebiten/v2/vector"     // Draws the tooltip background.
+)
+
+// Tooltip layout, in pixels. The debug font draws characters 6 pixels wide on 16 pixel lines.
+const (
+	tooltipOffset    = 16 // Distance from the cursor to the tooltip's corner.
+	tooltipCharWidth = 6
+	tooltipLine      = 16
+	tooltipPadding   = 4
+)
+
+// tooltipBackground keeps the tooltip readable over fish and sharks.
+var tooltipBackground = color.RGBA{0, 0, 0, 200}
+
+// drawTooltip shows the details of the creature under the mouse cursor: its kind, breed timer, starve counter and
+// age. Nothing is shown over open water, or while a paused window shows a grid from the history, since the details
+// are only known for the newest chronon.
+func (w *window) drawTooltip(screen *ebiten.Image) {
+	if w.back > 0 {
+		return
+	}
+	cfg := w.session.sim.Config()
+	mx, my := ebiten.CursorPosition()
+	if mx < 0 || mx >= windowSize || my < 0 || my >= windowSize {
+		return
+	}
+	info, ok := w.session.sim.Inspect(mx*cfg.Width/windowSize, my*cfg.Height/windowSize)
+	if !ok {
+		return
+	}
+
+	text := tooltipText(info)
+	lines := strings.Split(text, "\n")
+	width := 0
+	for _, line := range lines {
+		width = max(width, len(line))
+	}
+	boxWidth := width*tooltipCharWidth + 2*tooltipPadding
+	boxHeight := len(lines)*tooltipLine + 2*tooltipPadding
+
+	// Keep the tooltip inside the window by flipping it to the other side of the cursor near the edges.
+	x, y := mx+tooltipOffset, my+tooltipOffset
+	if x+boxWidth > windowSize {
+		x = mx - tooltipOffset - boxWidth
+	}
+	if y+boxHeight > windowSize {
+		y = my - tooltipOffset - boxHeight
+	}
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(boxWidth), float32(boxHeight), tooltipBackground, false)
+	ebitenutil.DebugPrintAt(screen, text, x+tooltipPadding, y+tooltipPadding)
+}
+
+// tooltipText describes a creature for the tooltip.
+func tooltipText(info wator.CreatureInfo) string {
+	switch info.Kind {
+	case wator.Land:
+		return fmt.Sprintf("Land at (%d, %d)", info.X, info.Y)
+	case wator.Shark:
+		return fmt.Sprintf("Shark at (%d, %d)\nAge: %d chronons\nBreed timer: %d of %d\nStarve counter: %d",
+			info.X, info.Y, info.Age, info.BreedTimer, info.BreedThreshold, info.Starve)
+	}
+	text := fmt.Sprintf("Fish at (%d, %d)\nAge: %d chronons\nBreed timer: %d of %d", info.X, info.Y, info.Age, info.BreedTimer, info.BreedThreshold)
+	if info.Infected {
+		text += "\nInfected"
+	}
+	return text
+}
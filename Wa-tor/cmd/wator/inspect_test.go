@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"Wator/wator"
+)
+
+func TestTooltipText(t *testing.T) {
+	for _, tc := range []struct {
+		info wator.CreatureInfo
+		want string
+	}{
+		{wator.CreatureInfo{Kind: wator.Fish, X: 1, Y: 2, BreedTimer: 3, BreedThreshold: 5, Age: 7}, "Fish at (1, 2)\nAge: 7 chronons\nBreed timer: 3 of 5"},
+		{wator.CreatureInfo{Kind: wator.Shark, X: 4, Y: 5, BreedTimer: 1, BreedThreshold: 4, Starve: 2, Age: 9}, "Shark at (4, 5)\nAge: 9 chronons\nBreed timer: 1 of 4\nStarve counter: 2"},
+		{wator.CreatureInfo{Kind: wator.Fish, X: 1, Y: 2, BreedTimer: 3, BreedThreshold: 5, Age: 7, Infected: true}, "Fish at (1, 2)\nAge: 7 chronons\nBreed timer: 3 of 5\nInfected"},
+		{wator.CreatureInfo{Kind: wator.Land, X: 0, Y: 0}, "Land at (0, 0)"},
+	} {
+		if got := tooltipText(tc.info); got != tc.want {
+			t.Errorf("tooltipText(%+v) = %q, want %q", tc.info, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Command wator runs the Wa-Tor predator-prey simulation.
+//
+// It replaces the separate single, two, four and eight thread programs: the thread count is now a flag, and every
+// feature those programs had is available from one binary through these subcommands:
+//
+//	wator run    [flags]            watch a simulation in a window and append its frame rate to the results file
+//	wator bench  [flags]            run a simulation without a window and append its chronon rate to the results file
+//	wator replay [-speed x] file    play back a log recorded with -record
+//	wator render [flags] -out file  simulate without a window and save the grid as PNG images
+//	wator compare [flags]           run two configurations from the same seed side by side
+//	wator serve  [flags]            run a simulation controlled by an HTTP API
+//
+// run, bench, render, compare and serve share the simulation flags (-preset, -width, -height, -threads, -seed,
+// -scenario, -sight, -fish-breed, -shark-breed, -shark-starve and -deterministic).
+// Run "wator <command> -h" to list a command's flags.
+package main
+
+import (
+	"errors" // Recognises flag.ErrHelp.
+	"flag"   // Reports -h as flag.ErrHelp.
+	"fmt"    // Prints the usage message.
+	"log"    // Reports errors from the subcommands.
+	"os"     // Reads the command line and sets the exit status.
+)
+
+// command is one of the wator subcommands.
+type command struct {
+	name    string                    // Name typed after "wator".
+	summary string                    // One-line description shown in the usage message.
+	run     func(args []string) error // Parses the remaining arguments and runs the command.
+}
+
+// commands lists the subcommands in the order they are shown in the usage message.
+var commands = []command{
+	{"run", "watch a simulation in a window", runCommand},
+	{"bench", "run a simulation without a window and record its speed", benchCommand},
+	{"replay", "play back a log recorded with -record", replayCommand},
+	{"render", "simulate without a window and save the grid as PNG images", renderCommand},
+	{"compare", "run two configurations from the same seed side by side", compareCommand},
+	{"serve", "run a simulation controlled by an HTTP API", serveCommand},
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("wator: ")
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		usage()
+		return
+	}
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		err := c.run(os.Args[2:])
+		if errors.Is(err, flag.ErrHelp) {
+			return // The flag set has already printed the command's flags.
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "wator: unknown command %q\n", name)
+	usage()
+	os.Exit(2)
+}
+
+// usage prints the list of subcommands to stderr.
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: wator <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, `Run "wator <command> -h" for the flags of a command.`)
+}
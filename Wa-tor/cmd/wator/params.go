@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"     // Formats the on-screen parameter panel.
+	"strconv" // Converts parameter values to strings for the CSV file.
+
+	"Wator/wator" // Provides the parameters being adjusted.
+
+	"github.com/hajimehoshi/ebiten/v2"            // Provides the screen image and key codes.
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil" // Draws the parameter panel text.
+	"github.com/hajimehoshi/ebiten/v2/inpututil"  // Detects single key presses for parameter adjustment.
+)
+
+// parameterKeys binds a pair of keys to each adjustable parameter.
+// The first key raises the value by one and the second lowers it by one.
+var parameterKeys = []struct {
+	name     string                     // Name shown on the panel and written to the parameter log.
+	up, down ebiten.Key                 // Keys that raise and lower the value.
+	keys     string                     // Key labels shown on the panel.
+	value    func(p *wator.Params) *int // Returns the field holding the value.
+}{
+	{"Fish Breed", ebiten.KeyQ, ebiten.KeyA, "Q/A", func(p *wator.Params) *int { return &p.FishBreed }},
+	{"Shark Breed", ebiten.KeyW, ebiten.KeyS, "W/S", func(p *wator.Params) *int { return &p.SharkBreed }},
+	{"Shark Starve", ebiten.KeyE, ebiten.KeyD, "E/D", func(p *wator.Params) *int { return &p.SharkStarve }},
+}
+
+// handleParameterKeys adjusts the breed and starve parameters in response to key presses.
+//
+// Output:
+//   - error: Returns an error if a change cannot be written to the parameter log.
+//
+// Functionality:
+// 1. Checks each parameter's up and down keys for a new press.
+// 2. Changes the parameter by one, never allowing it to drop below one chronon.
+// 3. Appends every change to the parameter log so tuning sessions can be reviewed afterwards.
+//
+// It is called at the start of Update so a change applies to the whole of the next chronon.
+func (w *window) handleParameterKeys() error {
+	sim := w.session.sim
+	cfg := sim.Config()
+	params := cfg.Params
+	for _, binding := range parameterKeys {
+		delta := 0
+		if inpututil.IsKeyJustPressed(binding.up) {
+			delta++
+		}
+		if inpututil.IsKeyJustPressed(binding.down) {
+			delta--
+		}
+
+		value := binding.value(&params)
+		if delta == 0 || *value+delta < 1 {
+			continue // No change requested, or the change would make the parameter meaningless.
+		}
+
+		old := params
+		*value += delta
+		if err := sim.SetParams(params); err != nil {
+			return err
+		}
+		if err := logParameterChanges(w.session.files, cfg.Threads, sim.Chronon(), old, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logParameterChanges appends a row to the parameter log for every parameter that differs between from and to.
+func logParameterChanges(files runFiles, threads, chronon int, from, to wator.Params) error {
+	var rows [][]string
+	for _, binding := range parameterKeys {
+		if before, after := *binding.value(&from), *binding.value(&to); before != after {
+			rows = append(rows, []string{
+				strconv.Itoa(chronon),
+				strconv.Itoa(threads),
+				binding.name,
+				strconv.Itoa(before),
+				strconv.Itoa(after),
+			})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return appendCSV(files.parameters, files.metadata, parameterHeader, rows)
+}
+
+// drawParameterPanel renders the current parameter values and their key bindings in the top-left corner.
+func (w *window) drawParameterPanel(screen *ebiten.Image) {
+	params := w.session.sim.Config().Params
+	panel := ""
+	for _, binding := range parameterKeys {
+		panel += fmt.Sprintf("%s: %d (%s)\n", binding.name, *binding.value(&params), binding.keys)
+	}
+	ebitenutil.DebugPrintAt(screen, panel, 4, 4)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"  // Formats the progress lines.
+	"io"   // Provides the writer progress is reported to.
+	"time" // Measures the chronon rate and estimates the time remaining.
+
+	"Wator/wator" // Provides the statistics being reported.
+)
+
+// progressInterval is how often a headless run reports its progress.
+const progressInterval = 2 * time.Second
+
+// progressReporter prints a line describing a headless run every progressInterval, so long batch runs show that they
+// are still making progress and roughly when they will finish. A nil *progressReporter reports nothing, which is
+// how -quiet turns it off.
+type progressReporter struct {
+	out         io.Writer        // Where progress lines are written, normally stderr.
+	total       int              // Number of chronons the run will simulate.
+	now         func() time.Time // Returns the current time; replaced in tests.
+	last        time.Time        // When the previous line was printed, or the run started.
+	lastChronon int              // The chronon reached when the previous line was printed.
+}
+
+// newProgressReporter returns a reporter for a run of total chronons starting now, or nil when quiet is set.
+func newProgressReporter(out io.Writer, total int, quiet bool) *progressReporter {
+	if quiet {
+		return nil
+	}
+	return &progressReporter{out: out, total: total, now: time.Now, last: time.Now()}
+}
+
+// report prints a progress line for sim if progressInterval has passed since the previous one.
+// It is called after every chronon, so the statistics are only gathered when a line is due.
+//
+// Functionality:
+// The rate is measured over the interval since the previous line rather than the whole run, so the estimate of the
+// time remaining follows the populations as they grow and shrink and the chronons get cheaper or dearer.
+func (p *progressReporter) report(sim *wator.Simulation) {
+	if p == nil {
+		return
+	}
+	now := p.now()
+	elapsed := now.Sub(p.last)
+	if elapsed < progressInterval {
+		return
+	}
+	stats := sim.Stats()
+
+	rate := float64(stats.Chronon-p.lastChronon) / elapsed.Seconds()
+	line := fmt.Sprintf("chronon %d/%d (%.0f%%)  fish %d  sharks %d  %.1f chronons/s",
+		stats.Chronon, p.total, 100*float64(stats.Chronon)/float64(p.total), stats.Fish, stats.Sharks, rate)
+	if rate > 0 {
+		eta := time.Duration(float64(p.total-stats.Chronon) / rate * float64(time.Second))
+		line += fmt.Sprintf("  ETA %v", eta.Round(time.Second))
+	}
+	fmt.Fprintln(p.out, line)
+
+	p.last = now
+	p.lastChronon = stats.Chronon
+}
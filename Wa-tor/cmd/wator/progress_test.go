@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"Wator/wator"
+)
+
+func TestProgressReporter(t *testing.T) {
+	cfg := wator.DefaultConfig()
+	cfg.Seed = 1
+	sim, err := wator.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	clock := time.Unix(0, 0)
+	p := newProgressReporter(&out, 100, false)
+	p.now = func() time.Time { return clock }
+	p.last = clock
+
+	for i := 0; i < 10; i++ {
+		sim.Step()
+		clock = clock.Add(100 * time.Millisecond)
+		p.report(sim)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("reported before %v had passed: %q", progressInterval, out.String())
+	}
+
+	for i := 0; i < 10; i++ {
+		sim.Step()
+		clock = clock.Add(100 * time.Millisecond)
+		p.report(sim)
+	}
+	// 20 chronons in 2 seconds leaves 80 chronons at 10 chronons/s.
+	if line := out.String(); !strings.HasPrefix(line, "chronon 20/100 (20%)") || !strings.HasSuffix(line, "10.0 chronons/s  ETA 8s\n") {
+		t.Fatalf("unexpected progress line %q", line)
+	}
+
+	newProgressReporter(&out, 100, true).report(sim) // A quiet reporter is nil and must not panic.
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"     // Formats the summary table.
+	"io"      // Provides the writer the summary table is printed to.
+	"math"    // Provides the square root for standard deviations.
+	"strconv" // Converts the summaries to strings for the CSV file.
+)
+
+// tCritical95 holds the two-sided 95% critical values of Student's t distribution for 1 to 30 degrees of freedom.
+// With few runs the sample standard deviation is itself uncertain, so the interval is wider than the normal 1.96.
+var tCritical95 = []float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+// summaryHeader lists the columns of the summary CSV file written by repeated runs.
+var summaryHeader = []string{"Metric", "Runs", "Mean", "Std Dev", "CI 95% Low", "CI 95% High"}
+
+// summary describes how a metric varied over repeated runs.
+type summary struct {
+	metric    string  // Name of the metric.
+	runs      int     // Number of runs measured.
+	mean      float64 // Mean over the runs.
+	stdDev    float64 // Sample standard deviation; 0 for a single run.
+	low, high float64 // 95% confidence interval of the mean; the mean itself for a single run.
+}
+
+// summarize returns the mean, sample standard deviation and 95% confidence interval of the mean of values.
+func summarize(metric string, values []float64) summary {
+	n := len(values)
+	s := summary{metric: metric, runs: n}
+	for _, v := range values {
+		s.mean += v / float64(n)
+	}
+	s.low, s.high = s.mean, s.mean
+	if n < 2 {
+		return s
+	}
+	for _, v := range values {
+		s.stdDev += (v - s.mean) * (v - s.mean)
+	}
+	s.stdDev = math.Sqrt(s.stdDev / float64(n-1))
+	t := 1.96
+	if n-1 <= len(tCritical95) {
+		t = tCritical95[n-2]
+	}
+	margin := t * s.stdDev / math.Sqrt(float64(n))
+	s.low, s.high = s.mean-margin, s.mean+margin
+	return s
+}
+
+// summarizeRuns summarises the chronon rate and the population metrics of repeated runs.
+func summarizeRuns(runs []benchRun) []summary {
+	metrics := []struct {
+		name  string
+		value func(r benchRun) float64
+	}{
+		{"Chronons/s", func(r benchRun) float64 { return r.rate }},
+		{"Final Fish", func(r benchRun) float64 { return float64(r.stats.Fish) }},
+		{"Final Sharks", func(r benchRun) float64 { return float64(r.stats.Sharks) }},
+		{"Mean Fish", func(r benchRun) float64 { return r.stability.fishMean }},
+		{"Mean Sharks", func(r benchRun) float64 { return r.stability.sharkMean }},
+		{"Fish CV", func(r benchRun) float64 { fish, _, _ := r.stability.variation(); return fish }},
+		{"Shark CV", func(r benchRun) float64 { _, sharks, _ := r.stability.variation(); return sharks }},
+	}
+	summaries := make([]summary, len(metrics))
+	for i, m := range metrics {
+		values := make([]float64, len(runs))
+		for j, r := range runs {
+			values[j] = m.value(r)
+		}
+		summaries[i] = summarize(m.name, values)
+	}
+	return summaries
+}
+
+// printSummaries writes the summaries to out as an aligned table.
+func printSummaries(out io.Writer, summaries []summary) {
+	fmt.Fprintf(out, "%-14s %12s %12s %25s\n", "metric", "mean", "std dev", "95% confidence interval")
+	for _, s := range summaries {
+		fmt.Fprintf(out, "%-14s %12.4g %12.4g %12.4g - %-12.4g\n", s.metric, s.mean, s.stdDev, s.low, s.high)
+	}
+}
+
+// writeSummaries appends the summaries to the summary file named after the results file.
+func writeSummaries(files runFiles, summaries []summary) error {
+	rows := make([][]string, len(summaries))
+	for i, s := range summaries {
+		rows[i] = []string{
+			s.metric,
+			strconv.Itoa(s.runs),
+			strconv.FormatFloat(s.mean, 'f', 4, 64),
+			strconv.FormatFloat(s.stdDev, 'f', 4, 64),
+			strconv.FormatFloat(s.low, 'f', 4, 64),
+			strconv.FormatFloat(s.high, 'f', 4, 64),
+		}
+	}
+	return appendCSV(files.summary, files.metadata, summaryHeader, rows)
+}
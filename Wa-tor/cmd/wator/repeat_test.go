@@ -0,0 +1,27 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	s := summarize("rate", []float64{10, 12, 14})
+	// Sample standard deviation 2, and the interval is t(2 df) * 2 / sqrt(3) either side of the mean.
+	margin := 4.303 * 2 / math.Sqrt(3)
+	if s.runs != 3 || s.mean != 12 || s.stdDev != 2 || math.Abs(s.low-(12-margin)) > 1e-9 || math.Abs(s.high-(12+margin)) > 1e-9 {
+		t.Errorf("summarize = %+v", s)
+	}
+
+	if s := summarize("rate", []float64{7}); s.stdDev != 0 || s.low != 7 || s.high != 7 {
+		t.Errorf("single run summarised as %+v", s)
+	}
+
+	many := make([]float64, 100)
+	for i := range many {
+		many[i] = float64(i % 2)
+	}
+	if s := summarize("rate", many); math.Abs(s.high-s.mean-1.96*s.stdDev/10) > 1e-9 {
+		t.Errorf("100 runs did not use the normal critical value: %+v", s)
+	}
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bufio"           // Buffers the replay log while recording and reading.
+	"bytes"           // Compares the log's magic header.
+	"encoding/binary" // Encodes counts and cell offsets as varints to keep the log compact.
+	"flag"            // Parses the replay command's flags.
+	"fmt"             // Formats errors and the replay status line.
+	"image/color"     // Clears the screen between replay frames.
+	"io"              // Reads the fixed-size parts of the log.
+	"os"              // Creates and opens replay logs.
+
+	"Wator/wator"        // Provides the grid being replayed.
+	"Wator/wator/render" // Draws the replayed grid.
+
+	"github.com/hajimehoshi/ebiten/v2"            // Runs the replay viewer.
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil" // Draws the replay status line.
+	"github.com/hajimehoshi/ebiten/v2/inpututil"  // Detects the replay control keys.
+)
+
+// replayMagic starts every replay log and identifies its format version.
+var replayMagic = []byte("WATORRP1")
+
+// maxReplayDim bounds the grid size read from a replay header, so a corrupt log cannot request a huge allocation.
+const maxReplayDim = 1 << 14
+
+// replayRecorder writes every cell that changes in each chronon to a compact binary log.
+//
+// Layout of the log:
+//   - The magic bytes "WATORRP1", then the grid width and height as uvarints.
+//   - The starting grid: one byte per cell, holding its wator.Cell value, row by row.
+//   - One block per chronon: the number of changed cells as a uvarint, then for each change the gap since the previous
+//     changed cell (as a uvarint) and a byte holding the old kind in the upper bits and the new kind in the lower two.
+//
+// Keeping the old kind lets the viewer step backwards as well as forwards.
+type replayRecorder struct {
+	file   *os.File                    // The log being written.
+	writer *bufio.Writer               // Buffered writer wrapping file.
+	prev   []wator.Cell                // Cells at the end of the previous chronon, indexed y*width + x.
+	buf    [binary.MaxVarintLen64]byte // Scratch space for encoding varints.
+}
+
+// newReplayRecorder creates a replay log and writes the starting grid.
+//
+// Input:
+//   - filename (string): The file to create; an existing file is truncated.
+//   - snap (wator.Snapshot): The grid at chronon 0; its size is written to the header.
+//
+// Output:
+//   - *replayRecorder: A recorder ready to receive chronons.
+//   - error: Returns an error if the file cannot be created or written.
+func newReplayRecorder(filename string, snap wator.Snapshot) (*replayRecorder, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replay: %w", err)
+	}
+
+	r := &replayRecorder{file: file, writer: bufio.NewWriter(file), prev: append([]wator.Cell(nil), snap.Cells...)}
+	r.writer.Write(replayMagic)
+	r.writeUvarint(snap.Width)
+	r.writeUvarint(snap.Height)
+	for _, c := range r.prev {
+		r.writer.WriteByte(byte(c))
+	}
+	if err := r.writer.Flush(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write replay header: %w", err)
+	}
+	return r, nil
+}
+
+// record appends the cells that changed since the previous chronon.
+func (r *replayRecorder) record(snap wator.Snapshot) error {
+	changes := 0
+	for i, c := range snap.Cells {
+		if c != r.prev[i] {
+			changes++
+		}
+	}
+
+	r.writeUvarint(changes)
+	last := -1
+	for i, c := range snap.Cells {
+		if c == r.prev[i] {
+			continue
+		}
+		r.writeUvarint(i - last - 1)
+		r.writer.WriteByte(byte(r.prev[i])<<2 | byte(c))
+		r.prev[i] = c
+		last = i
+	}
+	// bufio.Writer remembers the first write error, and an empty write reports it.
+	if _, err := r.writer.Write(nil); err != nil {
+		return fmt.Errorf("failed to write replay: %w", err)
+	}
+	return nil
+}
+
+// writeUvarint writes n to the log as a uvarint.
+func (r *replayRecorder) writeUvarint(n int) {
+	size := binary.PutUvarint(r.buf[:], uint64(n))
+	r.writer.Write(r.buf[:size])
+}
+
+// Close flushes any buffered chronons and closes the replay log.
+func (r *replayRecorder) Close() error {
+	if err := r.writer.Flush(); err != nil {
+		r.file.Close()
+		return fmt.Errorf("failed to flush replay: %w", err)
+	}
+	return r.file.Close()
+}
+
+// replayChange is a single cell change read back from a replay log.
+type replayChange struct {
+	index    int        // Cell index, y*width + x.
+	old, new wator.Cell // Cell contents before and after the chronon.
+}
+
+// replayGame plays a replay log back in the viewer.
+//
+// Controls:
+//   - Space pauses and resumes.
+//   - Right and Left step one chronon forwards or backwards while paused.
+//   - Up and Down double or halve the playback speed.
+type replayGame struct {
+	chronons [][]replayChange // The changes made in each chronon.
+	grid     wator.Snapshot   // The grid at the current chronon, sized to match the log.
+	speed    float64          // Chronons played per frame; below one plays in slow motion.
+	progress float64          // Fraction of a chronon accumulated towards the next step.
+	paused   bool             // Whether playback is paused.
+	renderer render.Renderer  // Draws the grid.
+}
+
+// replayCommand implements "wator replay": it plays back a log recorded with -record.
+func replayCommand(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	speed := fs.Float64("speed", 1, "chronons played per frame")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: wator replay [-speed x] file")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	viewer, err := loadReplay(fs.Arg(0), *speed)
+	if err != nil {
+		return err
+	}
+	ebiten.SetWindowSize(windowSize, windowSize)
+	ebiten.SetWindowTitle("Ebiten Wa-Tor World (replay)")
+	return ebiten.RunGame(viewer)
+}
+
+// loadReplay reads a replay log recorded with the -record flag.
+// The grid size is taken from the log, so a replay can be watched whatever -width and -height it was recorded with.
+func loadReplay(filename string, speed float64) (*replayGame, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay: %w", err)
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+
+	magic := make([]byte, len(replayMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil || !bytes.Equal(magic, replayMagic) {
+		return nil, fmt.Errorf("%s is not a Wa-Tor replay", filename)
+	}
+	width, err1 := binary.ReadUvarint(reader)
+	height, err2 := binary.ReadUvarint(reader)
+	if err1 != nil || err2 != nil {
+		return nil, fmt.Errorf("%s: truncated header", filename)
+	}
+	if width == 0 || height == 0 || width > maxReplayDim || height > maxReplayDim {
+		return nil, fmt.Errorf("%s: invalid grid size %dx%d", filename, width, height)
+	}
+
+	g := &replayGame{speed: speed, grid: *wator.NewSnapshot(int(width), int(height))}
+	cells := make([]byte, len(g.grid.Cells))
+	if _, err := io.ReadFull(reader, cells); err != nil {
+		return nil, fmt.Errorf("%s: truncated starting grid", filename)
+	}
+	for i, kind := range cells {
+		if wator.Cell(kind) > wator.Land {
+			return nil, fmt.Errorf("%s: invalid cell kind %d", filename, kind)
+		}
+		g.grid.Cells[i] = wator.Cell(kind)
+	}
+
+	for {
+		count, err := binary.ReadUvarint(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: chronon %d: %w", filename, len(g.chronons)+1, err)
+		}
+		changes := make([]replayChange, 0, min(count, uint64(len(cells))))
+		index := -1
+		for i := uint64(0); i < count; i++ {
+			gap, err := binary.ReadUvarint(reader)
+			if err != nil {
+				return g, nil // A run interrupted mid-write leaves a partial chronon; play everything before it.
+			}
+			kinds, err := reader.ReadByte()
+			if err != nil {
+				return g, nil
+			}
+			index += int(gap) + 1
+			if index < 0 || index >= len(cells) {
+				return nil, fmt.Errorf("%s: chronon %d: cell %d is off the grid", filename, len(g.chronons)+1, index)
+			}
+			changes = append(changes, replayChange{index: index, old: wator.Cell(kinds >> 2 & 3), new: wator.Cell(kinds & 3)})
+		}
+		g.chronons = append(g.chronons, changes)
+	}
+	return g, nil
+}
+
+// stepForward applies the next chronon's changes.
+func (g *replayGame) stepForward() {
+	if g.grid.Chronon == len(g.chronons) {
+		return
+	}
+	for _, c := range g.chronons[g.grid.Chronon] {
+		g.grid.Cells[c.index] = c.new
+	}
+	g.grid.Chronon++
+}
+
+// stepBackward undoes the most recent chronon's changes.
+func (g *replayGame) stepBackward() {
+	if g.grid.Chronon == 0 {
+		return
+	}
+	g.grid.Chronon--
+	for _, c := range g.chronons[g.grid.Chronon] {
+		g.grid.Cells[c.index] = c.old
+	}
+}
+
+// Update handles the playback controls and advances the replay.
+func (g *replayGame) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		g.paused = !g.paused
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+		g.speed *= 2
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+		g.speed /= 2
+	}
+
+	if g.paused {
+		if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) {
+			g.stepForward()
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) {
+			g.stepBackward()
+		}
+		return nil
+	}
+
+	g.progress += g.speed
+	for g.progress >= 1 && g.grid.Chronon < len(g.chronons) {
+		g.stepForward()
+		g.progress--
+	}
+	if g.grid.Chronon == len(g.chronons) {
+		g.paused = true // Stop at the end so the last frame can be inspected.
+		g.progress = 0
+	}
+	return nil
+}
+
+// Draw renders the grid at the current chronon and the playback status.
+func (g *replayGame) Draw(screen *ebiten.Image) {
+	screen.Fill(color.Black)
+	g.renderer.Draw(screen, g.grid)
+
+	status := fmt.Sprintf("Chronon %d/%d  Speed %gx", g.grid.Chronon, len(g.chronons), g.speed)
+	if g.paused {
+		status += "  Paused (Left/Right to step)"
+	}
+	ebitenutil.DebugPrintAt(screen, status, 4, 4)
+}
+
+// Layout returns the window size, matching the live simulation.
+func (g *replayGame) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return windowSize, windowSize
+}
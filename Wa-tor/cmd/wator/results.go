@@ -0,0 +1,368 @@
+package main
+
+import (
+	"bytes"         // Parses the results file from memory.
+	"encoding/csv"  // Reads and writes the results files.
+	"fmt"           // Formats results file names, metadata and errors.
+	"math"          // Provides the square root for the population standard deviations.
+	"os"            // Reads, appends to and replaces results files.
+	"path/filepath" // Finds the extension of the results file.
+	"runtime"       // Provides MemStats for measuring allocations and heap size, and the Go version for the metadata.
+	"slices"        // Compares header rows.
+	"strconv"       // Converts the measurements to strings for the CSV files.
+	"strings"       // Strips the extension of the results file.
+	"time"          // Formats the start time and converts lock wait times to milliseconds.
+
+	"Wator/wator" // Provides the statistics being written.
+)
+
+// memorySampleInterval is the number of chronons between heap samples.
+// runtime.ReadMemStats briefly stops the world, so sampling every chronon would distort the rate being measured.
+const memorySampleInterval = 30
+
+// resultsHeader lists the columns of the results CSV file.
+// Files written before a column was added are padded by upgradeCSV.
+var resultsHeader = []string{"Grid Size", "Thread Count", "Frame Rate", "Total Alloc (MB)", "Allocations", "Peak Heap (MB)", "Lock Acquisitions", "Contended Locks", "Lock Wait (ms)", "Equilibrium Chronon", "Crowding", "Crowded Cells", "Fish CV", "Shark CV"}
+
+// contentionHeader lists the columns of the per-partition lock contention CSV file.
+// Files written before the partition population columns were added are padded by upgradeCSV.
+var contentionHeader = []string{"Grid Size", "Thread Count", "Partition", "Lock Acquisitions", "Contended Locks", "Lock Wait (ms)", "Fish", "Sharks", "Handoffs"}
+
+// boundaryHeader lists the columns of the boundary mutex CSV file.
+var boundaryHeader = []string{"Grid Size", "Thread Count", "Boundary", "Lock Acquisitions", "Contended Locks", "Lock Wait (ms)", "Max Wait (ms)", "Lock Hold (ms)", "Max Hold (ms)"}
+
+// parameterHeader lists the columns of the live parameter change CSV file.
+var parameterHeader = []string{"Frame", "Thread Count", "Parameter", "Old Value", "New Value"}
+
+// runFiles names the CSV files written by a run and describes the run in a metadata row at the top of each.
+type runFiles struct {
+	results    string // One row per run.
+	contention string // One row per partition of a multi-threaded run.
+	boundaries string // One row per boundary mutex of a multi-threaded run, with -lock-stats.
+	parameters string // One row per live parameter change.
+	infection  string // One row per chronon of a run with the disease enabled.
+	population string // One row per chronon of a run following a schedule of seasons.
+	summary    string // One row per metric summarising repeated bench runs.
+	traits     string // Breed threshold distributions every few chronons of a run with evolving traits.
+	balance    string // One row per chronon of births, deaths and population changes, with -balance.
+	metadata   string // The configuration and start time of the run, written as a "#" comment before the header row.
+}
+
+// newRunFiles names the files for a run of the given command.
+//
+// Input:
+//   - command (string): The subcommand, "run" or "bench".
+//   - results (string): The results file given with -results; empty to name it automatically.
+//   - cfg (wator.Config): The configuration of the run, including the seed chosen when -seed was 0.
+//   - scenario (string): The scenario file the run started from, if any.
+//   - schedule (string): The schedule of seasons given with -seasons, if any.
+//   - started (time.Time): When the run started.
+//
+// Output:
+//   - runFiles: The file names and metadata row.
+//
+// Functionality:
+// By default every run gets its own results file, named after the command, thread count, grid size, seed and start
+// time (for example wator_bench_4t_200x200_seed42_20241201-153000.csv), so results are never mixed up between
+// configurations. The contention, boundary, parameter, infection, population, summary and trait files are named after
+// the results file with "_contention", "_boundaries", "_parameters", "_infection", "_population", "_summary" and
+// "_traits" added before the extension.
+func newRunFiles(command, results string, cfg wator.Config, scenario, schedule string, started time.Time) runFiles {
+	if results == "" {
+		results = fmt.Sprintf("wator_%s_%dt_%dx%d_seed%d_%s.csv",
+			command, cfg.Threads, cfg.Width, cfg.Height, cfg.Seed, started.Format("20060102-150405"))
+	}
+	stem := strings.TrimSuffix(results, filepath.Ext(results))
+
+	metadata := fmt.Sprintf("wator %s threads=%d width=%d height=%d seed=%d fish-breed=%d shark-breed=%d shark-starve=%d sight=%d crowding=%d deterministic=%t",
+		command, cfg.Threads, cfg.Width, cfg.Height, cfg.Seed, cfg.FishBreed, cfg.SharkBreed, cfg.SharkStarve, cfg.SightRadius, cfg.Crowding, cfg.Deterministic)
+	if cfg.Disease.Enabled() {
+		metadata += fmt.Sprintf(" infected=%g infection-spread=%g infection-lifetime=%d", cfg.Disease.Fraction, cfg.Disease.Spread, cfg.Disease.Lifetime)
+	}
+	if cfg.Order != wator.FishFirst {
+		metadata += fmt.Sprintf(" order=%s", cfg.Order)
+	}
+	if cfg.Engine != wator.PersistentWorkers {
+		metadata += fmt.Sprintf(" engine=%s", cfg.Engine)
+	}
+	if cfg.Mutation > 0 {
+		metadata += fmt.Sprintf(" mutation=%d", cfg.Mutation)
+	}
+	if schedule != "" {
+		metadata += fmt.Sprintf(" seasons=%q", schedule)
+	}
+	if scenario != "" {
+		metadata += fmt.Sprintf(" scenario=%q", scenario)
+	} else {
+		metadata += fmt.Sprintf(" distribution=%s fish-density=%g shark-density=%g", cfg.Distribution, cfg.FishDensity, cfg.SharkDensity)
+	}
+	metadata += fmt.Sprintf(" started=%s go=%s", started.Format(time.RFC3339), runtime.Version())
+
+	return runFiles{
+		results:    results,
+		contention: stem + "_contention.csv",
+		boundaries: stem + "_boundaries.csv",
+		parameters: stem + "_parameters.csv",
+		infection:  stem + "_infection.csv",
+		population: stem + "_population.csv",
+		summary:    stem + "_summary.csv",
+		traits:     stem + "_traits.csv",
+		balance:    stem + "_balance.csv",
+		metadata:   metadata,
+	}
+}
+
+// memoryTracker measures how much memory a run allocates and how large the heap grows.
+type memoryTracker struct {
+	started         bool   // Whether the baseline has been taken.
+	startTotalAlloc uint64 // Cumulative bytes allocated by the process when the run started.
+	startMallocs    uint64 // Cumulative heap objects allocated by the process when the run started.
+	totalAlloc      uint64 // Bytes allocated since the run started.
+	mallocs         uint64 // Heap objects allocated since the run started.
+	peakHeap        uint64 // Largest live heap size seen in any sample.
+}
+
+// sample reads the runtime memory statistics and updates the totals.
+// The first call records the baseline, so allocations made while setting up the simulation are not counted.
+func (m *memoryTracker) sample() {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	if !m.started {
+		m.started = true
+		m.startTotalAlloc = stats.TotalAlloc
+		m.startMallocs = stats.Mallocs
+	}
+	m.totalAlloc = stats.TotalAlloc - m.startTotalAlloc
+	m.mallocs = stats.Mallocs - m.startMallocs
+	if stats.HeapAlloc > m.peakHeap {
+		m.peakHeap = stats.HeapAlloc
+	}
+}
+
+// stabilityTracker measures how much the fish and shark populations vary over a run, so the effect of options such as
+// territorial sharks on the stability of the ecosystem can be compared between runs. Welford's method keeps the
+// running means and variances without storing every chronon.
+type stabilityTracker struct {
+	count               int     // Chronons observed.
+	fishMean, sharkMean float64 // Mean populations so far.
+	fishM2, sharkM2     float64 // Sums of squared differences from the means.
+}
+
+// observe records the populations at the end of a chronon.
+func (t *stabilityTracker) observe(fish, sharks int) {
+	t.count++
+	update := func(mean, m2 *float64, x float64) {
+		delta := x - *mean
+		*mean += delta / float64(t.count)
+		*m2 += delta * (x - *mean)
+	}
+	update(&t.fishMean, &t.fishM2, float64(fish))
+	update(&t.sharkMean, &t.sharkM2, float64(sharks))
+}
+
+// variation returns the coefficients of variation (standard deviation over mean) of the fish and shark populations,
+// the lower the steadier. ok is false until a chronon has been observed; a population that was always extinct has 0.
+func (t *stabilityTracker) variation() (fish, sharks float64, ok bool) {
+	if t.count == 0 {
+		return 0, 0, false
+	}
+	cv := func(mean, m2 float64) float64 {
+		if mean == 0 {
+			return 0
+		}
+		return math.Sqrt(m2/float64(t.count)) / mean
+	}
+	return cv(t.fishMean, t.fishM2), cv(t.sharkMean, t.sharkM2), true
+}
+
+// bytesToMB converts a byte count to mebibytes for the results file.
+func bytesToMB(b uint64) float64 {
+	return float64(b) / (1024 * 1024)
+}
+
+// durationToMS converts a duration to milliseconds for the CSV files.
+func durationToMS(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// writeResults appends one row describing a finished run to the results file.
+//
+// Input:
+//   - files (runFiles): The files of the run; the results file is created, with the metadata and header rows, if it does not exist.
+//   - cfg (wator.Config): The configuration of the run.
+//   - stats (wator.Stats): The statistics at the end of the run, including the boundary lock totals.
+//   - rate (float64): Frames per second in the window, or chronons per second without one.
+//   - memory (memoryTracker): The allocation and peak heap measurements of the run.
+//   - equilibrium (*wator.EquilibriumDetector): The run's equilibrium detector; nil when detection is off.
+//   - stability (stabilityTracker): The variation of the populations over the run.
+//
+// Output:
+//   - error: Returns an error if the file cannot be upgraded, opened or written.
+//
+// The Equilibrium Chronon column is left empty unless the populations settled during the run, and the Fish CV and
+// Shark CV columns unless at least one chronon ran.
+func writeResults(files runFiles, cfg wator.Config, stats wator.Stats, rate float64, memory memoryTracker, equilibrium *wator.EquilibriumDetector, stability stabilityTracker) error {
+	settled := ""
+	if equilibrium != nil {
+		if chronon, ok := equilibrium.Reached(); ok {
+			settled = strconv.Itoa(chronon)
+		}
+	}
+	fishCV, sharkCV := "", ""
+	if fish, sharks, ok := stability.variation(); ok {
+		fishCV, sharkCV = strconv.FormatFloat(fish, 'f', 4, 64), strconv.FormatFloat(sharks, 'f', 4, 64)
+	}
+
+	// Bring files written before the memory columns existed up to date so every row has the same columns.
+	if err := upgradeCSV(files.results, resultsHeader); err != nil {
+		return fmt.Errorf("failed to upgrade results file: %w", err)
+	}
+	return appendCSV(files.results, files.metadata, resultsHeader, [][]string{{
+		strconv.Itoa(cfg.Width * cfg.Height),
+		strconv.Itoa(cfg.Threads),
+		strconv.FormatFloat(rate, 'f', 2, 64),
+		strconv.FormatFloat(bytesToMB(memory.totalAlloc), 'f', 2, 64),
+		strconv.FormatUint(memory.mallocs, 10),
+		strconv.FormatFloat(bytesToMB(memory.peakHeap), 'f', 2, 64),
+		strconv.FormatInt(stats.Locks.Acquisitions, 10),
+		strconv.FormatInt(stats.Locks.Contended, 10),
+		strconv.FormatFloat(durationToMS(stats.Locks.Wait), 'f', 3, 64),
+		settled,
+		strconv.Itoa(cfg.Crowding),
+		strconv.FormatInt(stats.CrowdedCells, 10),
+		fishCV,
+		sharkCV,
+	}})
+}
+
+// writeContention appends one row per partition describing how much it blocked on its neighbours' boundary locks,
+// how many fish and sharks it ends the run with and how many creatures it handed to its neighbours.
+// Comparing the rows for different thread counts shows which partitioning spends the least time waiting at its boundaries.
+func writeContention(files runFiles, cfg wator.Config, stats wator.Stats) error {
+	rows := make([][]string, len(stats.PartitionLocks))
+	for i, locks := range stats.PartitionLocks {
+		rows[i] = []string{
+			strconv.Itoa(cfg.Width * cfg.Height),
+			strconv.Itoa(cfg.Threads),
+			strconv.Itoa(i),
+			strconv.FormatInt(locks.Acquisitions, 10),
+			strconv.FormatInt(locks.Contended, 10),
+			strconv.FormatFloat(durationToMS(locks.Wait), 'f', 3, 64),
+			strconv.Itoa(stats.Buckets[i].Fish),
+			strconv.Itoa(stats.Buckets[i].Sharks),
+			strconv.FormatInt(stats.Buckets[i].HandedOff, 10),
+		}
+	}
+	if err := upgradeCSV(files.contention, contentionHeader); err != nil {
+		return fmt.Errorf("failed to upgrade contention file: %w", err)
+	}
+	return appendCSV(files.contention, files.metadata, contentionHeader, rows)
+}
+
+// writeBoundaries appends one row per boundary mutex, in lock order, describing how often it was taken and how long
+// partitions waited for it and held it. Unlike the contention file, which sums each partition's waits over all its
+// boundaries, it shows which boundaries are fought over.
+func writeBoundaries(files runFiles, cfg wator.Config, stats wator.Stats) error {
+	rows := make([][]string, len(stats.Boundaries))
+	for i, b := range stats.Boundaries {
+		rows[i] = []string{
+			strconv.Itoa(cfg.Width * cfg.Height),
+			strconv.Itoa(cfg.Threads),
+			strconv.Itoa(i),
+			strconv.FormatInt(b.Acquisitions, 10),
+			strconv.FormatInt(b.Contended, 10),
+			strconv.FormatFloat(durationToMS(b.Wait), 'f', 3, 64),
+			strconv.FormatFloat(durationToMS(b.MaxWait), 'f', 3, 64),
+			strconv.FormatFloat(durationToMS(b.Hold), 'f', 3, 64),
+			strconv.FormatFloat(durationToMS(b.MaxHold), 'f', 3, 64),
+		}
+	}
+	return appendCSV(files.boundaries, files.metadata, boundaryHeader, rows)
+}
+
+// appendCSV appends rows to a CSV file. When the file is new or empty, the metadata row is written first as a
+// "#" comment (read the file with pandas.read_csv(filename, comment="#")), followed by the header row.
+func appendCSV(filename, metadata string, header []string, rows [][]string) error {
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to get file stats: %w", err)
+	}
+	writer := csv.NewWriter(file)
+	if stat.Size() == 0 {
+		if metadata != "" {
+			fmt.Fprintf(file, "# %s\n", metadata)
+		}
+		writer.Write(header)
+	}
+	writer.WriteAll(rows) // WriteAll flushes the header too.
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write to %s: %w", filename, err)
+	}
+	return nil
+}
+
+// upgradeCSV rewrites a results or contention file created before some of its columns existed.
+//
+// Input:
+//   - filename (string): The CSV file; a missing or empty file is left alone.
+//   - header ([]string): The file's current columns, which only ever gain columns at the end.
+//
+// Output:
+//   - error: Returns an error if the file cannot be read or rewritten.
+//
+// Functionality:
+// Older files are missing some of the columns, such as the memory and lock columns of the results file. Appending
+// longer rows to them would leave a file that pandas and other CSV readers reject, so the header is replaced with
+// header and existing rows are padded with empty values for the new columns. A metadata comment at the top of the
+// file is kept.
+func upgradeCSV(filename string, header []string) error {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	body := data // The CSV records after any metadata comments.
+	for bytes.HasPrefix(body, []byte("#")) {
+		_, body, _ = bytes.Cut(body, []byte("\n"))
+	}
+	comments := data[:len(data)-len(body)]
+
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1 // Rows may be shorter than the new header.
+	records, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+	if len(records) == 0 || slices.Equal(records[0], header) {
+		return nil
+	}
+
+	records[0] = header
+	for i := 1; i < len(records); i++ {
+		for len(records[i]) < len(header) {
+			records[i] = append(records[i], "")
+		}
+	}
+
+	out, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to rewrite %s: %w", filename, err)
+	}
+	defer out.Close()
+	out.Write(comments)
+	writer := csv.NewWriter(out)
+	writer.WriteAll(records)
+	return writer.Error()
+}
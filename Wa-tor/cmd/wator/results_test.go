@@ -0,0 +1,116 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"Wator/wator"
+)
+
+func TestNewRunFiles(t *testing.T) {
+	cfg := wator.DefaultConfig()
+	cfg.Threads, cfg.Width, cfg.Height, cfg.Seed = 4, 200, 100, 42
+	started := time.Date(2024, 12, 1, 15, 30, 0, 0, time.UTC)
+
+	files := newRunFiles("bench", "", cfg, "", "", started)
+	if want := "wator_bench_4t_200x100_seed42_20241201-153000.csv"; files.results != want {
+		t.Errorf("results file %q, want %q", files.results, want)
+	}
+	if want := "wator_bench_4t_200x100_seed42_20241201-153000_contention.csv"; files.contention != want {
+		t.Errorf("contention file %q, want %q", files.contention, want)
+	}
+	if !strings.Contains(files.metadata, "threads=4 width=200 height=100 seed=42") {
+		t.Errorf("metadata %q does not describe the configuration", files.metadata)
+	}
+
+	if files := newRunFiles("run", "out/mine.csv", cfg, "", "", started); files.results != "out/mine.csv" || files.parameters != "out/mine_parameters.csv" {
+		t.Errorf("-results gave files %q and %q", files.results, files.parameters)
+	}
+}
+
+func TestWriteResults(t *testing.T) {
+	cfg := wator.DefaultConfig()
+	files := newRunFiles("bench", filepath.Join(t.TempDir(), "results.csv"), cfg, "", "", time.Now())
+
+	// A file from before the memory and lock columns were added, which must be upgraded without losing its rows.
+	if err := os.WriteFile(files.results, []byte("Grid Size,Thread Count,Frame Rate\n2500,1,60.00\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := writeResults(files, cfg, wator.Stats{}, 100, memoryTracker{}, nil, stabilityTracker{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(files.results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 || lines[0] != strings.Join(resultsHeader, ",") || lines[1] != "2500,1,60.00,,,,,,,,,,," {
+		t.Fatalf("upgraded file is\n%s", data)
+	}
+
+	fresh := newRunFiles("bench", filepath.Join(t.TempDir(), "fresh.csv"), cfg, "", "", time.Now())
+	if err := writeResults(fresh, cfg, wator.Stats{}, 100, memoryTracker{}, nil, stabilityTracker{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := upgradeCSV(fresh.results, resultsHeader); err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(fresh.results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines = strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 || lines[0] != "# "+fresh.metadata || lines[1] != strings.Join(resultsHeader, ",") {
+		t.Fatalf("new file is\n%s", data)
+	}
+}
+
+func TestWriteContention(t *testing.T) {
+	cfg := wator.DefaultConfig()
+	cfg.Threads = 2
+	files := newRunFiles("bench", filepath.Join(t.TempDir(), "results.csv"), cfg, "", "", time.Now())
+
+	// A file from before the partition population columns were added.
+	old := "Grid Size,Thread Count,Partition,Lock Acquisitions,Contended Locks,Lock Wait (ms)\n2500,2,0,10,1,0.500\n"
+	if err := os.WriteFile(files.contention, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stats := wator.Stats{
+		PartitionLocks: make([]wator.LockStats, 2),
+		Buckets:        []wator.BucketStats{{Fish: 30, Sharks: 4, HandedOff: 7}, {Fish: 25, Sharks: 6, HandedOff: 9}},
+	}
+	if err := writeContention(files, cfg, stats); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(files.contention)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 || lines[0] != strings.Join(contentionHeader, ",") || lines[1] != "2500,2,0,10,1,0.500,,," ||
+		lines[3] != "2500,2,1,0,0,0.000,25,6,9" {
+		t.Fatalf("contention file is\n%s", data)
+	}
+}
+
+func TestStabilityTracker(t *testing.T) {
+	var st stabilityTracker
+	if _, _, ok := st.variation(); ok {
+		t.Error("variation reported before any chronon")
+	}
+	for _, fish := range []int{90, 110, 90, 110} {
+		st.observe(fish, 0)
+	}
+	fish, sharks, ok := st.variation()
+	if !ok || math.Abs(fish-0.1) > 1e-9 || sharks != 0 {
+		t.Errorf("variation = %g, %g, %v, want 0.1, 0, true", fish, sharks, ok)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors" // Joins the game loop and results errors.
+	"flag"   // Parses the run command's flags.
+	"fmt"    // Formats flag errors.
+	"time"   // Limits how long the run lasts.
+
+	"Wator/wator/render" // Draws the grid.
+
+	"github.com/hajimehoshi/ebiten/v2" // Runs the window and its game loop.
+)
+
+// windowSize is the width and height of the window in pixels; the grid is scaled to fill it.
+const windowSize = 800
+
+// window shows a session in an Ebiten window, advancing it by one chronon per frame.
+type window struct {
+	session  *session        // The simulation and the files it records to.
+	duration time.Duration   // How long to run before writing the results; 0 runs until the window is closed.
+	frames   int             // Frames that advanced the simulation.
+	done     bool            // Set once the duration has passed or, with -stop-at-equilibrium, the populations have settled; the final grid stays on screen.
+	renderer render.Renderer // Draws the grid in a single batched draw call.
+
+	history   *history      // The most recent grids, for stepping backwards while paused; nil with -history 0.
+	paused    bool          // Stops the simulation so the history can be browsed.
+	back      int           // How many chronons before the newest the grid on screen is, while paused.
+	pausedAt  time.Time     // When the current pause started.
+	pausedFor time.Duration // Total time spent in earlier pauses, which does not count towards -duration or the frame rate.
+}
+
+// runCommand implements "wator run": it shows the simulation in a window, or in the terminal with -render=tui,
+// appends the average frame rate to the results file after -duration, and stops early, still writing the results,
+// when Ctrl+C is pressed.
+func runCommand(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	cf := addConfigFlags(fs)
+	rf := addRecordFlags(fs)
+	ef := addEquilibriumFlags(fs)
+	sf := addSeasonFlags(fs)
+	duration := fs.Duration("duration", 10*time.Second, "how long to run before appending the frame rate to the results file; 0 runs until the window is closed")
+	historySize := fs.Int("history", 300, "number of recent chronons kept for stepping backwards while paused (0 keeps none)")
+	view := fs.String("render", "ebiten", "how to show the simulation: ebiten opens a window, tui draws it in the terminal")
+	fps := fs.Int("fps", 15, "with -render=tui, the largest number of frames drawn per second")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := cf.applyPreset(fs); err != nil {
+		return err
+	}
+	if *historySize < 0 {
+		return fmt.Errorf("history must not be negative, got %d", *historySize)
+	}
+	if *view != "ebiten" && *view != "tui" {
+		return fmt.Errorf("unknown renderer %q (want ebiten or tui)", *view)
+	}
+	if *fps < 1 {
+		return fmt.Errorf("fps must be at least 1, got %d", *fps)
+	}
+
+	s, err := newSession("run", cf, rf, ef, sf)
+	if err != nil {
+		return err
+	}
+	if *view == "tui" {
+		return runInTerminal(s, *duration, *fps)
+	}
+	w := &window{session: s, duration: *duration, history: newHistory(*historySize)}
+	w.history.push(s.sim.Snapshot())
+
+	ebiten.SetWindowSize(windowSize, windowSize)
+	ebiten.SetWindowTitle("Ebiten Wa-Tor World")
+	err = ebiten.RunGame(w)
+	return errors.Join(err, s.finish(w.frameRate())) // Closing the window before -duration still records the run.
+}
+
+// frameRate returns the average number of frames per second the simulation was running for.
+func (w *window) frameRate() float64 {
+	elapsed := w.runningTime().Seconds()
+	if elapsed > 0 {
+		return float64(w.frames) / elapsed
+	}
+	return 0
+}
+
+// runningTime returns how long the simulation has been running, leaving out the time spent paused.
+func (w *window) runningTime() time.Duration {
+	elapsed := time.Since(w.session.start) - w.pausedFor
+	if w.paused {
+		elapsed -= time.Since(w.pausedAt)
+	}
+	return elapsed
+}
+
+// Update progresses the simulation by one chronon.
+//
+// Output:
+//   - error: ebiten.Termination once an interrupted run has saved its results, or an error writing a file.
+//
+// Functionality:
+//  1. Applies any live breed/starve changes before this chronon runs.
+//  2. On Ctrl+C, writes the results (and the grid, with -state) and stops the game loop.
+//  3. Handles pausing and stepping through the history, and goes no further while paused or once the run is done.
+//  4. Once -duration has passed, or with -stop-at-equilibrium once the populations have settled, writes the results
+//     and leaves the final grid on screen.
+//  5. Otherwise steps the simulation, records the chronon to the snapshot and replay files and adds it to the history.
+func (w *window) Update() error {
+	if err := w.handleParameterKeys(); err != nil {
+		return err
+	}
+	if w.session.interrupted.Load() {
+		if err := w.session.finish(w.frameRate()); err != nil {
+			return err
+		}
+		return ebiten.Termination
+	}
+	if w.handleHistoryKeys(); w.paused || w.done {
+		return nil
+	}
+	if (w.duration > 0 && w.runningTime() > w.duration) || w.session.stopped() {
+		w.done = true
+		return w.session.finish(w.frameRate())
+	}
+
+	w.frames++
+	if err := w.session.step(); err != nil {
+		return err
+	}
+	w.history.push(w.session.sim.Snapshot())
+	return nil
+}
+
+// Draw renders the grid, the parameter panel, while paused the position in the history, and a tooltip describing
+// the creature under the mouse.
+func (w *window) Draw(screen *ebiten.Image) {
+	if w.history.len() > 0 {
+		w.renderer.Draw(screen, w.history.at(w.back)) // The newest entry is the current grid, so it is not copied twice.
+	} else {
+		w.renderer.Draw(screen, w.session.sim.Snapshot())
+	}
+	w.drawParameterPanel(screen)
+	w.drawHistoryStatus(screen)
+	w.drawTooltip(screen)
+}
+
+// Layout returns the fixed window size; the grid is scaled to fill it whatever its dimensions.
+func (w *window) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return windowSize, windowSize
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"         // Reads text scenario files line by line.
+	"fmt"           // Formats descriptive errors for malformed scenario files.
+	"image"         // Provides the Image interface used to read scenario pixels.
+	"image/color"   // Converts scenario pixels to RGBA for classification.
+	"image/png"     // Registers and decodes the PNG image format.
+	"io"            // Provides the Reader interface shared by both scenario formats.
+	"os"            // Opens scenario files from disk.
+	"path/filepath" // Extracts the file extension to select the scenario format.
+	"strings"       // Handles case-insensitive extension matching.
+
+	"Wator/wator"        // Provides the layout that scenarios are decoded into.
+	"Wator/wator/render" // Provides the colours PNG scenario pixels are matched against.
+)
+
+// scenarioCells lists the cell kinds a PNG scenario pixel can be classified as.
+// Each pixel is mapped to the kind whose render colour it is closest to.
+var scenarioCells = []wator.Cell{wator.Empty, wator.Fish, wator.Shark, wator.Land}
+
+// loadScenario opens a scenario file and decodes it into a starting layout for a width by height grid.
+// Files ending in ".png" are decoded as images; anything else is treated as text.
+func loadScenario(filename string, width, height int) (*wator.Snapshot, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open scenario: %w", err)
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(filename), ".png") {
+		return parsePNGScenario(file, width, height)
+	}
+	return parseTextScenario(file, width, height)
+}
+
+// parseTextScenario reads a text scenario where each line is a row of the grid (y) and each character a column (x).
+//
+// Characters:
+//   - 'F' places a fish.
+//   - 'S' places a shark.
+//   - '#' places land.
+//   - '.' or ' ' leaves the cell empty.
+//
+// Rows or columns missing from the file are left empty; a layout larger than the grid is an error.
+func parseTextScenario(r io.Reader, width, height int) (*wator.Snapshot, error) {
+	layout := wator.NewSnapshot(width, height)
+	scanner := bufio.NewScanner(r)
+
+	for y := 0; scanner.Scan(); y++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if y >= height {
+			if strings.TrimSpace(line) == "" {
+				continue // Allow trailing blank lines.
+			}
+			return nil, fmt.Errorf("scenario has more than %d rows", height)
+		}
+		if len(line) > width {
+			return nil, fmt.Errorf("scenario row %d has more than %d columns", y+1, width)
+		}
+		for x, ch := range []byte(line) {
+			switch ch {
+			case 'F', 'f':
+				layout.Set(x, y, wator.Fish)
+			case 'S', 's':
+				layout.Set(x, y, wator.Shark)
+			case '#':
+				layout.Set(x, y, wator.Land)
+			case '.', ' ':
+			default:
+				return nil, fmt.Errorf("scenario row %d column %d: unknown cell %q", y+1, x+1, ch)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+	return layout, nil
+}
+
+// parsePNGScenario reads a PNG scenario where each pixel is one grid cell.
+// Pixels are classified by the nearest render colour, so images exported from
+// ordinary paint programs (with slight colour variations) still load correctly.
+func parsePNGScenario(r io.Reader, width, height int) (*wator.Snapshot, error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode scenario image: %w", err)
+	}
+	bounds := img.Bounds()
+	if bounds.Dx() > width || bounds.Dy() > height {
+		return nil, fmt.Errorf("scenario image is %dx%d, larger than the %dx%d grid", bounds.Dx(), bounds.Dy(), width, height)
+	}
+
+	layout := wator.NewSnapshot(width, height)
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			layout.Set(x, y, classifyPixel(img, bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return layout, nil
+}
+
+// classifyPixel returns the cell kind whose render colour is closest to the pixel at (x, y).
+func classifyPixel(img image.Image, x, y int) wator.Cell {
+	c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+
+	best, bestDist := wator.Empty, -1
+	for _, kind := range scenarioCells {
+		rgb := render.Color(kind)
+		dr := int(c.R) - int(rgb.R)
+		dg := int(c.G) - int(rgb.G)
+		db := int(c.B) - int(rgb.B)
+		dist := dr*dr + dg*dg + db*db
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = kind, dist
+		}
+	}
+	return best
+}
+
+// saveScenario writes the grid to a text scenario file that -scenario can load again.
+// Only cell contents are saved; breed and starve timers start from zero when the file is reloaded.
+func saveScenario(filename string, snap wator.Snapshot) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create scenario: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	writeRows(writer, snap)
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write scenario: %w", err)
+	}
+	return nil
+}
+
+// writeRows writes one line per grid row using the scenario characters, as in scenario and snapshot files.
+func writeRows(w *bufio.Writer, snap wator.Snapshot) {
+	row := make([]byte, snap.Width+1)
+	row[snap.Width] = '\n'
+	for y := 0; y < snap.Height; y++ {
+		for x := 0; x < snap.Width; x++ {
+			row[x] = cellChar(snap.At(x, y))
+		}
+		w.Write(row)
+	}
+}
+
+// cellChar returns the scenario character describing the contents of a grid cell.
+func cellChar(c wator.Cell) byte {
+	switch c {
+	case wator.Fish:
+		return 'F'
+	case wator.Shark:
+		return 'S'
+	case wator.Land:
+		return '#'
+	}
+	return '.'
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"    // Registers the season flags.
+	"strconv" // Converts the populations and parameters to strings for the CSV file.
+
+	"Wator/wator" // Provides the schedule and the simulation it drives.
+)
+
+// populationHeader lists the columns of the population CSV file written by runs that follow a schedule of seasons.
+var populationHeader = []string{"Chronon", "Fish", "Sharks", "Season", "Season Start", "Fish Breed", "Shark Breed", "Shark Starve"}
+
+// seasonFlags holds the flags that vary the parameters with the seasons, shared by the run and bench commands.
+type seasonFlags struct {
+	schedule string // The schedule of seasons; empty keeps the parameters fixed.
+}
+
+// addSeasonFlags registers the season flags on fs.
+func addSeasonFlags(fs *flag.FlagSet) *seasonFlags {
+	f := &seasonFlags{}
+	fs.StringVar(&f.schedule, "seasons", "", `cycle of seasons that change the parameters, one per line or separated by ";", each a name, a length in chronons and the parameters it sets, e.g. "summer 200 fish-breed=3; winter 200 shark-starve=3"`)
+	return f
+}
+
+// newSchedule parses the schedule, starting each season from the parameters set on the command line, or returns nil
+// when no schedule was given.
+func (f *seasonFlags) newSchedule(base wator.Params) (wator.Schedule, error) {
+	if f.schedule == "" {
+		return nil, nil
+	}
+	return wator.ParseSchedule(f.schedule, base)
+}
+
+// seasons applies a schedule of seasons to a session's simulation and keeps the populations under each season, until
+// the run finishes and they are written to the population file.
+type seasons struct {
+	schedule wator.Schedule // The cycle of seasons.
+	current  int            // Index of the season in effect.
+	started  bool           // Whether the current season began with the chronon being run.
+	rows     [][]string     // One row per chronon recorded so far.
+}
+
+// begin switches to the next season before a chronon runs, if one begins with it.
+func (s *seasons) begin(sim *wator.Simulation) error {
+	s.current, s.started = s.schedule.Begins(sim.Chronon())
+	if !s.started {
+		return nil
+	}
+	return sim.SetParams(s.schedule[s.current].Params)
+}
+
+// record adds the populations at the end of a chronon, marking the chronons a season began with.
+func (s *seasons) record(sim *wator.Simulation) {
+	fish, sharks := sim.Population()
+	params := sim.Config().Params
+	start := ""
+	if s.started {
+		start = "yes"
+	}
+	s.rows = append(s.rows, []string{
+		strconv.Itoa(sim.Chronon()),
+		strconv.Itoa(fish),
+		strconv.Itoa(sharks),
+		s.schedule[s.current].Name,
+		start,
+		strconv.Itoa(params.FishBreed),
+		strconv.Itoa(params.SharkBreed),
+		strconv.Itoa(params.SharkStarve),
+	})
+}
+
+// write appends the recorded rows to the population file.
+func (s *seasons) write(files runFiles) error {
+	return appendCSV(files.population, files.metadata, populationHeader, s.rows)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"Wator/wator"
+)
+
+func TestSeasons(t *testing.T) {
+	cfg := wator.DefaultConfig()
+	cfg.Seed = 1
+	sim, err := wator.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sf := &seasonFlags{schedule: "summer 2 fish-breed=3; winter 1 shark-starve=2"}
+	schedule, err := sf.newSchedule(cfg.Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &seasons{schedule: schedule}
+	for i := 0; i < 4; i++ {
+		if err := s.begin(sim); err != nil {
+			t.Fatal(err)
+		}
+		sim.Step()
+		s.record(sim)
+	}
+	// Chronon, season, season start, fish breed and shark starve for each row.
+	want := [][5]string{{"1", "summer", "yes", "3", "5"}, {"2", "summer", "", "3", "5"}, {"3", "winter", "yes", "5", "2"}, {"4", "summer", "yes", "3", "5"}}
+	for i, w := range want {
+		row := s.rows[i]
+		if got := [5]string{row[0], row[3], row[4], row[5], row[7]}; got != w {
+			t.Errorf("row %d = %v, want %v", i, got, w)
+		}
+	}
+
+	if schedule, err := (&seasonFlags{}).newSchedule(cfg.Params); schedule != nil || err != nil {
+		t.Errorf("no -seasons gave %v, %v", schedule, err)
+	}
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"       // Bounds how long the HTTP server waits for open requests when shutting down.
+	"encoding/json" // Encodes the responses and decodes parameter changes.
+	"errors"        // Joins the simulation and results errors and recognises a closed server.
+	"flag"          // Parses the serve command's flags.
+	"fmt"           // Formats error messages for the clients.
+	"log"           // Reports the address the API is served on.
+	"net"           // Listens before the simulation starts, so a busy address is reported straight away.
+	"net/http"      // Serves the API.
+	"strconv"       // Parses the number of chronons to step.
+	"sync"          // Serialises access to the simulation between the handlers and the stepping goroutine.
+	"time"          // Paces the stepping goroutine while the simulation is stopped.
+
+	"Wator/wator" // Provides the parameters and statistics returned as JSON.
+)
+
+// idlePoll is how often a stopped simulation checks for Ctrl+C when no /start request arrives.
+const idlePoll = 100 * time.Millisecond
+
+// apiParams is the JSON form of wator.Params. In a PUT /params request, fields left out keep their current value.
+type apiParams struct {
+	FishBreed   *int `json:"fish_breed,omitempty"`
+	SharkBreed  *int `json:"shark_breed,omitempty"`
+	SharkStarve *int `json:"shark_starve,omitempty"`
+}
+
+// apiStats is the JSON body returned by GET /stats and by the control endpoints.
+type apiStats struct {
+	Chronon          int       `json:"chronon"`
+	Fish             int       `json:"fish"`
+	Sharks           int       `json:"sharks"`
+	Infected         int       `json:"infected"`
+	Running          bool      `json:"running"`
+	ChrononRate      float64   `json:"chronon_rate"` // Chronons per second of time spent stepping, so pauses do not lower it.
+	Params           apiParams `json:"params"`
+	LockAcquisitions int64     `json:"lock_acquisitions"`
+	ContendedLocks   int64     `json:"contended_locks"`
+	LockWaitMS       float64   `json:"lock_wait_ms"`
+}
+
+// apiServer controls a session over HTTP for the serve command.
+// The simulation is only touched with mu held, by the handlers and by the goroutine stepping it while it runs,
+// since a Simulation is not safe for concurrent use.
+type apiServer struct {
+	mu      sync.Mutex
+	session *session      // The simulation and the files it records to.
+	running bool          // Whether the simulation is stepping continuously.
+	stops   int           // How many times /stop has been requested, so a /step in progress can tell it was stopped.
+	wake    chan struct{} // Wakes the stepping goroutine when the simulation is started.
+	err     error         // The error that stopped the simulation; every later control request reports it.
+}
+
+// serveCommand implements "wator serve": it runs a simulation without a window, controlled by an HTTP API so
+// external scripts or a web page can start, stop and step it, change its parameters and read its statistics as JSON.
+// The simulation waits for POST /start unless -start is set. Ctrl+C (or, with -stop-at-equilibrium, the populations
+// settling) shuts the server down and appends the chronon rate to the results file as bench does.
+func serveCommand(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	cf := addConfigFlags(fs)
+	rf := addRecordFlags(fs)
+	ef := addEquilibriumFlags(fs)
+	sf := addSeasonFlags(fs)
+	addr := fs.String("addr", "localhost:8080", "address the HTTP API listens on")
+	start := fs.Bool("start", false, "start stepping straight away instead of waiting for POST /start")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := cf.applyPreset(fs); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	s, err := newSession("serve", cf, rf, ef, sf)
+	if err != nil {
+		listener.Close()
+		return err
+	}
+	api := newAPIServer(s)
+	api.running = *start
+
+	server := &http.Server{Handler: api.handler()}
+	go func() {
+		if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			log.Print(err)
+			s.interrupted.Store(true) // Without the API nothing can control the simulation, so end the run.
+		}
+	}()
+	log.Printf("serving the API on http://%s", listener.Addr())
+
+	runErr := api.run()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server.Shutdown(ctx)
+
+	api.mu.Lock() // A request still being answered must not read the simulation while the results are written.
+	defer api.mu.Unlock()
+	return errors.Join(runErr, s.finish(steppingRate(s.sim.Stats())))
+}
+
+// newAPIServer returns a server controlling s, with the simulation stopped.
+func newAPIServer(s *session) *apiServer {
+	return &apiServer{session: s, wake: make(chan struct{}, 1)}
+}
+
+// steppingRate returns the chronons simulated per second of time spent in Step.
+func steppingRate(stats wator.Stats) float64 {
+	if stats.Elapsed > 0 {
+		return float64(stats.Chronon) / stats.Elapsed.Seconds()
+	}
+	return 0
+}
+
+// run steps the simulation while it is running until the session is stopped, and returns the error from the
+// chronon that failed, if one did. While the simulation is stopped it waits for /start or for idlePoll to pass.
+func (a *apiServer) run() error {
+	for {
+		a.mu.Lock()
+		if a.session.stopped() {
+			a.mu.Unlock()
+			return nil
+		}
+		running := a.running
+		if running {
+			if err := a.session.step(); err != nil {
+				a.running, a.err = false, err
+				a.mu.Unlock()
+				return err
+			}
+		}
+		a.mu.Unlock()
+
+		if !running {
+			select {
+			case <-a.wake:
+			case <-time.After(idlePoll):
+			}
+		}
+	}
+}
+
+// handler returns the API's routes:
+//
+//	GET  /stats                 the populations, parameters, chronon rate and lock statistics
+//	POST /start                 step the simulation continuously
+//	POST /stop                  stop stepping
+//	POST /step?chronons=n       advance a stopped simulation by n chronons (default 1)
+//	GET  /params                the breeding and starvation thresholds
+//	PUT  /params                change some or all of the thresholds, such as {"fish_breed": 5}
+//
+// Every response is JSON. Errors are returned as {"error": "..."} with a 4xx or 5xx status.
+func (a *apiServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /stats", a.handleStats)
+	mux.HandleFunc("POST /start", a.handleStart)
+	mux.HandleFunc("POST /stop", a.handleStop)
+	mux.HandleFunc("POST /step", a.handleStep)
+	mux.HandleFunc("GET /params", a.handleGetParams)
+	mux.HandleFunc("PUT /params", a.handlePutParams)
+	return mux
+}
+
+// handleStats returns the current statistics.
+func (a *apiServer) handleStats(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	writeJSON(w, http.StatusOK, a.stats())
+}
+
+// handleStart starts stepping the simulation continuously.
+func (a *apiServer) handleStart(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		writeError(w, http.StatusInternalServerError, a.err)
+		return
+	}
+	a.running = true
+	select {
+	case a.wake <- struct{}{}:
+	default: // Already woken.
+	}
+	writeJSON(w, http.StatusOK, a.stats())
+}
+
+// handleStop stops stepping the simulation after the current chronon.
+func (a *apiServer) handleStop(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.running = false
+	a.stops++
+	writeJSON(w, http.StatusOK, a.stats())
+}
+
+// handleStep advances a stopped simulation by the number of chronons in the query, one by default.
+//
+// It holds a.mu for one chronon at a time rather than for the whole request, so a long step does not hold up the other
+// requests or shutting down. It ends early, answering with the chronon reached, if the simulation is started or
+// stopped meanwhile, the run ends or the client goes away.
+func (a *apiServer) handleStep(w http.ResponseWriter, r *http.Request) {
+	chronons := 1
+	if text := r.URL.Query().Get("chronons"); text != "" {
+		n, err := strconv.Atoi(text)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("chronons must be a whole number above 0, got %q", text))
+			return
+		}
+		chronons = n
+	}
+
+	a.mu.Lock()
+	switch {
+	case a.err != nil:
+		a.mu.Unlock()
+		writeError(w, http.StatusInternalServerError, a.err)
+		return
+	case a.running:
+		a.mu.Unlock()
+		writeError(w, http.StatusConflict, fmt.Errorf("the simulation is running; POST /stop before stepping it"))
+		return
+	}
+	stops := a.stops
+	a.mu.Unlock()
+
+	for i := 0; i < chronons && r.Context().Err() == nil; i++ {
+		a.mu.Lock()
+		if a.running || a.stops != stops || a.err != nil || a.session.stopped() {
+			a.mu.Unlock()
+			break
+		}
+		if err := a.session.step(); err != nil {
+			a.err = err
+			a.mu.Unlock()
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		a.mu.Unlock()
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		writeError(w, http.StatusInternalServerError, a.err)
+		return
+	}
+	writeJSON(w, http.StatusOK, a.stats())
+}
+
+// handleGetParams returns the current breeding and starvation thresholds.
+func (a *apiServer) handleGetParams(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	writeJSON(w, http.StatusOK, toAPIParams(a.session.sim.Config().Params))
+}
+
+// handlePutParams changes the thresholds named in the request from the next chronon on and logs each change to the
+// parameter log, as the parameter keys of the run command do.
+func (a *apiServer) handlePutParams(w http.ResponseWriter, r *http.Request) {
+	var change apiParams
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&change); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid parameters: %w", err))
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	sim := a.session.sim
+	cfg := sim.Config()
+	params := cfg.Params
+	if change.FishBreed != nil {
+		params.FishBreed = *change.FishBreed
+	}
+	if change.SharkBreed != nil {
+		params.SharkBreed = *change.SharkBreed
+	}
+	if change.SharkStarve != nil {
+		params.SharkStarve = *change.SharkStarve
+	}
+	if err := sim.SetParams(params); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := logParameterChanges(a.session.files, cfg.Threads, sim.Chronon(), cfg.Params, params); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIParams(params))
+}
+
+// stats returns the current statistics. The caller must hold a.mu.
+func (a *apiServer) stats() apiStats {
+	sim := a.session.sim
+	stats := sim.Stats()
+	return apiStats{
+		Chronon:          stats.Chronon,
+		Fish:             stats.Fish,
+		Sharks:           stats.Sharks,
+		Infected:         stats.Infected,
+		Running:          a.running,
+		ChrononRate:      steppingRate(stats),
+		Params:           toAPIParams(sim.Config().Params),
+		LockAcquisitions: stats.Locks.Acquisitions,
+		ContendedLocks:   stats.Locks.Contended,
+		LockWaitMS:       durationToMS(stats.Locks.Wait),
+	}
+}
+
+// toAPIParams returns the JSON form of p with every field set.
+func toAPIParams(p wator.Params) apiParams {
+	return apiParams{FishBreed: &p.FishBreed, SharkBreed: &p.SharkBreed, SharkStarve: &p.SharkStarve}
+}
+
+// writeJSON writes v as the JSON body of a response with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err as {"error": "..."} with the given status.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"Wator/wator"
+)
+
+// request sends a request to the API and decodes its JSON response into out, returning the status code.
+func request(t *testing.T, server *httptest.Server, method, path, body string, out any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestAPIServer(t *testing.T) {
+	cfg := wator.DefaultConfig()
+	cfg.Seed = 1
+	sim, err := wator.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &session{sim: sim, files: newRunFiles("serve", filepath.Join(t.TempDir(), "results.csv"), sim.Config(), "", "", time.Now())}
+	api := newAPIServer(s)
+	server := httptest.NewServer(api.handler())
+	defer server.Close()
+
+	var stats apiStats
+	if code := request(t, server, "POST", "/step?chronons=5", "", &stats); code != http.StatusOK || stats.Chronon != 5 || stats.Running {
+		t.Fatalf("POST /step returned %d and %+v", code, stats)
+	}
+	if code := request(t, server, "POST", "/step?chronons=0", "", nil); code != http.StatusBadRequest {
+		t.Errorf("stepping 0 chronons returned %d", code)
+	}
+
+	var params apiParams
+	if code := request(t, server, "PUT", "/params", `{"fish_breed": 7}`, &params); code != http.StatusOK ||
+		*params.FishBreed != 7 || *params.SharkBreed != cfg.SharkBreed {
+		t.Fatalf("PUT /params returned %d and %+v", code, params)
+	}
+	if got := sim.Config().FishBreed; got != 7 {
+		t.Errorf("fish breed is %d after PUT /params, want 7", got)
+	}
+	if data, err := os.ReadFile(s.files.parameters); err != nil || !strings.Contains(string(data), "5,1,Fish Breed,5,7") {
+		t.Errorf("parameter log is %q (%v)", data, err)
+	}
+	for _, body := range []string{`{"shark_starve": 0}`, `{"fish_bread": 2}`, `not json`} {
+		if code := request(t, server, "PUT", "/params", body, nil); code != http.StatusBadRequest {
+			t.Errorf("PUT /params with %s returned %d", body, code)
+		}
+	}
+
+	// A long step takes the lock a chronon at a time, so other requests are answered and /stop ends it.
+	stepped := make(chan apiStats)
+	go func() {
+		var stats apiStats
+		if resp, err := server.Client().Post(server.URL+"/step?chronons=1000000000", "", nil); err == nil {
+			json.NewDecoder(resp.Body).Decode(&stats)
+			resp.Body.Close()
+		}
+		stepped <- stats
+	}()
+	for stats.Chronon < 10 {
+		request(t, server, "GET", "/stats", "", &stats)
+	}
+	if code := request(t, server, "POST", "/stop", "", &stats); code != http.StatusOK {
+		t.Fatalf("POST /stop during a step returned %d", code)
+	}
+	select {
+	case stats = <-stepped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("POST /stop did not end a step of 1000000000 chronons")
+	}
+	if stats.Chronon < 10 || stats.Running {
+		t.Fatalf("the stopped step returned %+v", stats)
+	}
+
+	// Started, the stepping goroutine advances the simulation until Ctrl+C.
+	done := make(chan error)
+	go func() { done <- api.run() }()
+	if code := request(t, server, "POST", "/start", "", &stats); code != http.StatusOK || !stats.Running {
+		t.Fatalf("POST /start returned %d and %+v", code, stats)
+	}
+	if code := request(t, server, "POST", "/step", "", nil); code != http.StatusConflict {
+		t.Errorf("stepping a running simulation returned %d", code)
+	}
+	for stats.Chronon < 20 {
+		request(t, server, "GET", "/stats", "", &stats)
+	}
+	if code := request(t, server, "POST", "/stop", "", &stats); code != http.StatusOK || stats.Running {
+		t.Fatalf("POST /stop returned %d and %+v", code, stats)
+	}
+	s.interrupted.Store(true)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if code := request(t, server, "GET", "/stop", "", nil); code != http.StatusMethodNotAllowed {
+		t.Errorf("GET /stop returned %d", code)
+	}
+}
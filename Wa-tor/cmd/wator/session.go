@@ -0,0 +1,284 @@
+package main
+
+import (
+	"errors"      // Joins the errors from the files written when a run finishes.
+	"fmt"         // Wraps errors with the file they concern.
+	"log"         // Reports where partial results and the saved state were written.
+	"sync/atomic" // Provides the flag set when the run is interrupted.
+	"time"        // Measures the chronon rate.
+
+	"Wator/wator" // Runs the simulation.
+)
+
+// session is a simulation together with the files it records to and the measurements written when it finishes.
+// The run and bench commands both step their simulation through a session, so they record and report identically.
+type session struct {
+	sim               *wator.Simulation          // The simulation being run.
+	snapshots         *snapshotRecorder          // Records the grid after every chronon when -snapshot is set; nil otherwise.
+	replay            *replayRecorder            // Records every cell change when -record is set; nil otherwise.
+	stateFile         string                     // Where to save the grid if the run is interrupted; empty to skip.
+	check             bool                       // Whether to verify the invariants after every chronon.
+	heatmap           *wator.Occupancy           // Counts how often each cell is occupied when -heatmap is set; nil otherwise.
+	heatmapFile       string                     // Where to save the heatmaps when the run finishes.
+	files             runFiles                   // The CSV files the run writes to.
+	equilibrium       *wator.EquilibriumDetector // Watches the populations when -equilibrium-window is set; nil otherwise.
+	stopAtEquilibrium bool                       // Whether the run ends once equilibrium is reached.
+	memory            memoryTracker              // Allocation and peak heap measurements for the results file.
+	stability         stabilityTracker           // How much the populations vary, for the results file.
+	infection         *infectionLog              // The infection curve when the disease is enabled; nil otherwise.
+	seasons           *seasons                   // Varies the parameters with the seasons when -seasons is set; nil otherwise.
+	traits            *traitLog                  // Samples the breed thresholds when -mutation is set; nil otherwise.
+	balance           *balanceLog                // Accounts for every birth and death when -balance is set; nil otherwise.
+	start             time.Time                  // When the first chronon started.
+	interrupted       atomic.Bool                // Set by the Ctrl+C handler and checked between chronons.
+	finished          bool                       // Whether finish has already written the results.
+}
+
+// newSession creates the simulation described by the flags and opens the files it records to.
+//
+// Input:
+//   - command (string): The subcommand running the session, used to name its files.
+//   - cf (*configFlags): The simulation flags.
+//   - rf (*recordFlags): The recording flags.
+//   - ef (*equilibriumFlags): The equilibrium detection flags.
+//   - sf (*seasonFlags): The schedule of seasons.
+//
+// Output:
+//   - *session: A session at chronon 0 with Ctrl+C trapped, so an interrupted run still writes its results.
+//   - error: Returns an error if the configuration or schedule is invalid or a recording file cannot be created.
+func newSession(command string, cf *configFlags, rf *recordFlags, ef *equilibriumFlags, sf *seasonFlags) (*session, error) {
+	equilibrium, err := ef.newDetector()
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := sf.newSchedule(cf.params)
+	if err != nil {
+		return nil, err
+	}
+	sim, err := cf.newSimulation()
+	if err != nil {
+		return nil, err
+	}
+	s := &session{sim: sim, stateFile: rf.state, check: cf.check, heatmapFile: rf.heatmap, equilibrium: equilibrium, stopAtEquilibrium: ef.stop}
+	if rf.heatmap != "" {
+		cfg := sim.Config()
+		s.heatmap = wator.NewOccupancy(cfg.Width, cfg.Height)
+	}
+	if schedule != nil {
+		s.seasons = &seasons{schedule: schedule}
+	}
+	if sim.Config().Mutation > 0 {
+		s.traits = &traitLog{}
+		s.traits.record(sim) // Chronon 0 shows the starting thresholds.
+	}
+	if rf.balance {
+		s.balance = newBalanceLog(sim)
+	}
+	if sim.Config().Disease.Enabled() {
+		s.infection = &infectionLog{}
+		s.infection.record(sim) // Chronon 0 shows the fish infected at the start.
+	}
+
+	if rf.snapshot != "" {
+		if s.snapshots, err = newSnapshotRecorder(rf.snapshot, sim.Config()); err != nil {
+			return nil, err
+		}
+		if err := s.snapshots.record(sim.Snapshot()); err != nil { // Chronon 0 is the starting layout.
+			s.snapshots.Close()
+			return nil, err
+		}
+	}
+	if rf.record != "" {
+		if s.replay, err = newReplayRecorder(rf.record, sim.Snapshot()); err != nil {
+			if s.snapshots != nil {
+				s.snapshots.Close()
+			}
+			return nil, err
+		}
+	}
+
+	s.watchForInterrupt()
+	s.memory.sample() // The baseline, so the setup above is not counted.
+	s.start = time.Now()
+	s.files = newRunFiles(command, rf.results, sim.Config(), cf.scenario, sf.schedule, s.start)
+	return s, nil
+}
+
+// step advances the simulation by one chronon and records it.
+// The grid is only copied when a snapshot or replay file is being written, so unrecorded runs are not slowed down.
+// With -seasons, a season beginning with this chronon sets its parameters first, replacing any changed with the keys.
+func (s *session) step() error {
+	if s.seasons != nil {
+		if err := s.seasons.begin(s.sim); err != nil {
+			return err
+		}
+	}
+	s.sim.Step()
+	if s.check {
+		checkInvariants(s.sim)
+	}
+	chronon := s.sim.Chronon()
+	if chronon%memorySampleInterval == 0 {
+		s.memory.sample() // Periodically track heap growth for the results file.
+	}
+	fish, sharks := s.sim.Population()
+	s.stability.observe(fish, sharks)
+	if s.infection != nil {
+		s.infection.record(s.sim)
+	}
+	if s.seasons != nil {
+		s.seasons.record(s.sim)
+	}
+	if s.traits != nil {
+		s.traits.record(s.sim)
+	}
+	if s.balance != nil {
+		s.balance.record(s.sim)
+	}
+	if s.heatmap != nil {
+		if err := s.sim.AddOccupancy(s.heatmap); err != nil {
+			return err
+		}
+	}
+	if s.equilibrium != nil && !s.atEquilibrium() {
+		if s.equilibrium.Observe(chronon, fish, sharks) {
+			settled, _ := s.equilibrium.Reached()
+			log.Printf("equilibrium reached at chronon %d; the populations have settled since chronon %d", chronon, settled)
+		}
+	}
+	if s.snapshots == nil && s.replay == nil {
+		return nil
+	}
+
+	snap := s.sim.Snapshot()
+	if s.snapshots != nil {
+		if err := s.snapshots.record(snap); err != nil {
+			return err
+		}
+	}
+	if s.replay != nil {
+		if err := s.replay.record(snap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// atEquilibrium reports whether the populations have settled.
+func (s *session) atEquilibrium() bool {
+	if s.equilibrium == nil {
+		return false
+	}
+	_, ok := s.equilibrium.Reached()
+	return ok
+}
+
+// stopped reports whether the run should end before its next chronon, because it was interrupted or,
+// with -stop-at-equilibrium, because the populations have settled.
+func (s *session) stopped() bool {
+	return s.interrupted.Load() || (s.stopAtEquilibrium && s.atEquilibrium())
+}
+
+// chrononRate returns the number of chronons simulated per second since the session started.
+func (s *session) chrononRate() float64 {
+	elapsed := time.Since(s.start).Seconds()
+	if elapsed > 0 {
+		return float64(s.sim.Chronon()) / elapsed
+	}
+	return 0
+}
+
+// finish ends the run.
+//
+// Input:
+//   - rate (float64): The frame or chronon rate written to the results file.
+//
+// Output:
+//   - error: The errors from any file that could not be written, joined together.
+//
+// Functionality:
+//  1. Appends the run, including how much the populations varied and the chronon they settled by when equilibrium was
+//     detected, to the results file and, with more than one thread, every partition to the lock contention file and,
+//     with -lock-stats, every boundary mutex to the boundaries file, logging the name of the results file since it is
+//     chosen automatically unless -results is set.
+//  2. Writes the infection curve when the disease is enabled and the populations of each season with -seasons and the
+//     breed threshold distributions with -mutation and the births and deaths of every chronon with -balance, saves the
+//     occupancy heatmaps with -heatmap, and flushes and closes the snapshot file and replay log, if they are being
+//     recorded.
+//  3. When the run was interrupted, saves the grid as a text scenario if -state is set, so it can be resumed with -scenario.
+//  4. Closes the simulation, stopping the goroutines that step its partitions; it can still be drawn and read.
+//
+// Calling finish again does nothing, so an interrupt that arrives after a run has finished does not write it twice.
+func (s *session) finish(rate float64) error {
+	if s.finished {
+		return nil
+	}
+	s.finished = true
+
+	s.memory.sample() // A final sample so the totals cover the whole run.
+	cfg, stats := s.sim.Config(), s.sim.Stats()
+	var errs []error
+	if err := writeResults(s.files, cfg, stats, rate, s.memory, s.equilibrium, s.stability); err != nil {
+		errs = append(errs, err)
+	}
+	if cfg.Threads > 1 {
+		if err := writeContention(s.files, cfg, stats); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(stats.Boundaries) > 0 {
+		if err := writeBoundaries(s.files, cfg, stats); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if s.infection != nil {
+		if err := s.infection.write(s.files); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if s.seasons != nil {
+		if err := s.seasons.write(s.files); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if s.traits != nil {
+		if err := s.traits.write(s.files); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if s.balance != nil {
+		if err := s.balance.write(s.files); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if s.heatmap != nil {
+		if err := saveHeatmaps(s.heatmapFile, s.heatmap); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if s.snapshots != nil {
+		if err := s.snapshots.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if s.replay != nil {
+		if err := s.replay.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if !s.interrupted.Load() {
+		log.Printf("results appended to %s", s.files.results)
+	} else {
+		log.Printf("interrupted after %d chronons; partial results appended to %s", stats.Chronon, s.files.results)
+		if s.stateFile != "" {
+			if err := saveScenario(s.stateFile, s.sim.Snapshot()); err != nil {
+				errs = append(errs, fmt.Errorf("failed to save state: %w", err))
+			} else {
+				log.Printf("grid state saved to %s", s.stateFile)
+			}
+		}
+	}
+	s.sim.Close()
+	return errors.Join(errs...)
+}
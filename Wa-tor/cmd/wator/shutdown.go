@@ -0,0 +1,20 @@
+package main
+
+import (
+	"os"        // Provides the os.Interrupt signal value.
+	"os/signal" // Delivers Ctrl+C to the session instead of killing the process.
+)
+
+// watchForInterrupt traps Ctrl+C (SIGINT) so an interrupted run still saves its results.
+//
+// The signal handler only sets a flag; the shutdown work happens between chronons on the goroutine running the
+// simulation, so it never races with a chronon that is being processed. A second Ctrl+C kills the process as usual.
+func (s *session) watchForInterrupt() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go func() {
+		<-signals
+		signal.Stop(signals) // Restore the default behaviour for any further Ctrl+C.
+		s.interrupted.Store(true)
+	}()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio" // Buffers snapshot output so recording every chronon stays cheap.
+	"fmt"   // Formats the snapshot header and chronon markers.
+	"os"    // Creates the snapshot file.
+
+	"Wator/wator" // Provides the grids being recorded.
+)
+
+// snapshotVersion identifies the snapshot file layout read by the snapdiff tool.
+const snapshotVersion = 1
+
+// snapshotRecorder appends a copy of the grid to a snapshot file after every chronon.
+//
+// The file starts with a small header (format version, grid size, seed and thread count) followed by one block
+// per chronon: a "chronon N" line and then one line per grid row using the same characters as text scenario files.
+// Two recordings made with the same seed can be compared with the snapdiff tool to find where they diverge.
+type snapshotRecorder struct {
+	file   *os.File      // The snapshot file being written.
+	writer *bufio.Writer // Buffered writer wrapping file.
+}
+
+// newSnapshotRecorder creates the snapshot file and writes its header.
+//
+// Input:
+//   - filename (string): The file to create; an existing file is truncated.
+//   - cfg (wator.Config): The configuration of the run; its size, seed and thread count are written to the header.
+//
+// Output:
+//   - *snapshotRecorder: A recorder ready to receive chronons.
+//   - error: Returns an error if the file cannot be created or written.
+func newSnapshotRecorder(filename string, cfg wator.Config) (*snapshotRecorder, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	r := &snapshotRecorder{file: file, writer: bufio.NewWriter(file)}
+	fmt.Fprintf(r.writer, "wator-snapshot %d\n", snapshotVersion)
+	fmt.Fprintf(r.writer, "grid %d %d\n", cfg.Width, cfg.Height)
+	fmt.Fprintf(r.writer, "seed %d\n", cfg.Seed)
+	fmt.Fprintf(r.writer, "threads %d\n", cfg.Threads)
+	if err := r.writer.Flush(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write snapshot header: %w", err)
+	}
+	return r, nil
+}
+
+// record appends the grid in snap under its chronon number.
+func (r *snapshotRecorder) record(snap wator.Snapshot) error {
+	fmt.Fprintf(r.writer, "chronon %d\n", snap.Chronon)
+	writeRows(r.writer, snap)
+	// bufio.Writer remembers the first write error, and an empty write reports it.
+	if _, err := r.writer.Write(nil); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Close flushes any buffered chronons and closes the snapshot file.
+func (r *snapshotRecorder) Close() error {
+	if err := r.writer.Flush(); err != nil {
+		r.file.Close()
+		return fmt.Errorf("failed to flush snapshot: %w", err)
+	}
+	return r.file.Close()
+}
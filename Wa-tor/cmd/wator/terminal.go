@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"  // Joins the drawing and results errors.
+	"fmt"     // Formats the status line.
+	"os"      // Provides the terminal and its size variables.
+	"strconv" // Parses the terminal size variables.
+	"time"    // Paces the frames and limits how long the run lasts.
+
+	"Wator/wator/render/tui" // Draws the grid in the terminal.
+)
+
+// Terminal size used when it cannot be read from the terminal or the COLUMNS and LINES variables.
+const (
+	defaultColumns = 80
+	defaultLines   = 24
+)
+
+// runInTerminal shows a session in the terminal for "wator run -render=tui", advancing it by one chronon per frame.
+//
+// Input:
+//   - s (*session): The session to run.
+//   - duration (time.Duration): How long to run before writing the results; 0 runs until Ctrl+C.
+//   - fps (int): The largest number of frames drawn per second, which keeps the output within what an SSH
+//     connection can carry.
+//
+// Output:
+//   - error: The errors from drawing and from writing the results, joined together.
+//
+// Functionality:
+// The terminal is not switched to raw mode, so the window's keys for parameters and history are not available;
+// Ctrl+C ends the run and still writes its results, as it does in the window.
+func runInTerminal(s *session, duration time.Duration, fps int) error {
+	cols, rows := terminalSize()
+	r := tui.NewRenderer(os.Stdout, cols, rows)
+	if err := r.Start(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(time.Second / time.Duration(fps))
+	defer ticker.Stop()
+	frames := 0
+	var err error
+	for !s.stopped() && (duration == 0 || time.Since(s.start) < duration) {
+		if err = s.step(); err != nil {
+			break
+		}
+		frames++
+		snap := s.sim.Snapshot()
+		fish, sharks := s.sim.Population()
+		status := fmt.Sprintf("chronon %d  fish %d  sharks %d  %d threads  1 char = %d cells across  Ctrl+C stops",
+			snap.Chronon, fish, sharks, s.sim.Config().Threads, r.Scale(snap.Width, snap.Height))
+		if err = r.Draw(snap, status); err != nil {
+			break
+		}
+		<-ticker.C
+	}
+	stopErr := r.Stop() // Restore the terminal before finish logs where the results went.
+
+	rate := 0.0
+	if elapsed := time.Since(s.start).Seconds(); elapsed > 0 {
+		rate = float64(frames) / elapsed
+	}
+	return errors.Join(err, stopErr, s.finish(rate))
+}
+
+// terminalSize returns the size of the terminal on stdout in characters, falling back to the COLUMNS and LINES
+// environment variables and then to 80x24.
+func terminalSize() (cols, rows int) {
+	if cols, rows, err := stdoutSize(); err == nil && cols > 0 && rows > 0 {
+		return cols, rows
+	}
+	cols, rows = defaultColumns, defaultLines
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		cols = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+		rows = n
+	}
+	return cols, rows
+}
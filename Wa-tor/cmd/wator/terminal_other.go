@@ -0,0 +1,13 @@
+//go:build !unix
+
+package main
+
+import (
+	"errors" // Reports that the size cannot be read.
+)
+
+// stdoutSize reports that the terminal size cannot be read on this platform, so the COLUMNS and LINES variables
+// or the default size are used instead.
+func stdoutSize() (cols, rows int, err error) {
+	return 0, 0, errors.New("terminal size is not available on this platform")
+}
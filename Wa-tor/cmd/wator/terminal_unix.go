@@ -0,0 +1,18 @@
+//go:build unix
+
+package main
+
+import (
+	"os" // Provides stdout.
+
+	"golang.org/x/sys/unix" // Reads the terminal size.
+)
+
+// stdoutSize returns the size in characters of the terminal stdout is connected to.
+func stdoutSize() (cols, rows int, err error) {
+	ws, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"slices"  // Sorts the thresholds so each chronon's rows are in order.
+	"strconv" // Converts the counts to strings for the CSV file.
+
+	"Wator/wator" // Provides the breed thresholds being logged.
+)
+
+// traitInterval is the number of chronons between samples of the breed threshold distributions.
+// Thresholds change over generations rather than chronons, so sampling every chronon would only repeat rows.
+const traitInterval = 10
+
+// traitHeader lists the columns of the trait distribution CSV file. Each sample has one row per kind and threshold,
+// the long format pandas and plotting libraries expect.
+var traitHeader = []string{"Chronon", "Kind", "Breed Threshold", "Count"}
+
+// traitLog keeps samples of how many fish and sharks have each breed threshold, until the run finishes and they are
+// written to the trait file.
+type traitLog struct {
+	rows [][]string // The rows recorded so far.
+}
+
+// record adds the current distribution of breed thresholds every traitInterval chronons, and at chronon 0.
+func (l *traitLog) record(sim *wator.Simulation) {
+	chronon := sim.Chronon()
+	if chronon%traitInterval != 0 {
+		return
+	}
+	for _, kind := range []wator.Cell{wator.Fish, wator.Shark} {
+		counts := sim.BreedThresholds(kind)
+		thresholds := make([]int, 0, len(counts))
+		for threshold := range counts {
+			thresholds = append(thresholds, threshold)
+		}
+		slices.Sort(thresholds)
+		for _, threshold := range thresholds {
+			l.rows = append(l.rows, []string{strconv.Itoa(chronon), kind.String(), strconv.Itoa(threshold), strconv.Itoa(counts[threshold])})
+		}
+	}
+}
+
+// write appends the recorded rows to the trait file.
+func (l *traitLog) write(files runFiles) error {
+	return appendCSV(files.traits, files.metadata, traitHeader, l.rows)
+}
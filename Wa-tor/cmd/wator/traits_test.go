@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+
+	"Wator/wator"
+)
+
+func TestTraitLog(t *testing.T) {
+	cfg := wator.DefaultConfig()
+	cfg.Seed = 1
+	cfg.Mutation = 1
+	sim, err := wator.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var l traitLog
+	l.record(sim)
+	fish, sharks := sim.Population()
+	// At the start every fish has the fish breed time and every shark the shark breed time.
+	want := [][]string{{"0", "fish", "5", strconv.Itoa(fish)}, {"0", "shark", "5", strconv.Itoa(sharks)}}
+	if len(l.rows) != len(want) {
+		t.Fatalf("rows %v, want %v", l.rows, want)
+	}
+	for i := range want {
+		for j := range want[i] {
+			if l.rows[i][j] != want[i][j] {
+				t.Errorf("row %d is %v, want %v", i, l.rows[i], want[i])
+				break
+			}
+		}
+	}
+
+	sim.Step()
+	l.record(sim)
+	if len(l.rows) != len(want) {
+		t.Errorf("chronon 1 was sampled, although samples are %d chronons apart", traitInterval)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/csv" // Writes the merged statistics.
+	"fmt"          // Formats errors and the summary.
+	"io"           // Accepts any destination for the summary.
+	"net"          // Accepts worker connections.
+	"os"           // Creates the results file.
+	"strconv"      // Converts statistics to strings for the CSV file.
+	"time"         // Measures the whole run.
+
+	"Wator/wator" // Provides the breed and starve thresholds and checks them.
+)
+
+// coordinatorConfig describes the simulation the coordinator runs.
+type coordinatorConfig struct {
+	Listen   string // Address workers connect to.
+	Workers  int    // Number of workers to wait for.
+	Width    int    // Width of the grid.
+	Height   int    // Height of the grid.
+	Chronons int    // Number of chronons to simulate.
+	Seed     int64  // Base random seed; each worker adds its rank.
+	Results  string // CSV file the merged statistics are appended to.
+
+	Params       wator.Params // Breed and starve thresholds.
+	FishDensity  float64      // Fraction of cells that start with a fish.
+	SharkDensity float64      // Fraction of cells that start with a shark.
+}
+
+// totals is the merged view of every worker's statistics for one chronon.
+type totals struct {
+	fish, sharks, migrants, conflicts, returned int
+	slowest                                     time.Duration // Longest step of any worker; the ring moves at this pace.
+}
+
+// runCoordinator waits for the workers, hands out the rows and merges the statistics they report.
+//
+// Input:
+//   - cfg (coordinatorConfig): The grid size, number of workers and run length.
+//   - listener (net.Listener): An open listener for worker connections.
+//   - out (io.Writer): Destination for the progress summary.
+//
+// Output:
+//   - error: Returns an error if a worker disconnects or the results cannot be written.
+//
+// Functionality:
+//  1. Accepts cfg.Workers connections and reads each worker's listening address.
+//  2. Splits the grid into horizontal strips, one per worker, and sends each worker its strip and the address of the
+//     worker below it so the workers can connect into a ring.
+//  3. Reads one report from every worker for the starting population and then for each chronon, sums the
+//     populations and writes a row to the results file. A migrant is never lost, so the populations only change with
+//     the births and deaths of the wator rules.
+func runCoordinator(cfg coordinatorConfig, listener net.Listener, out io.Writer) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	workers := make([]*link, cfg.Workers)
+	addrs := make([]string, cfg.Workers)
+	for i := range workers {
+		conn, err := listener.Accept()
+		if err != nil {
+			return fmt.Errorf("accept worker: %w", err)
+		}
+		workers[i] = newLink(conn)
+		defer workers[i].Close()
+
+		var h hello
+		if err := workers[i].receive(&h); err != nil {
+			return err
+		}
+		addrs[i] = h.ListenAddr
+	}
+
+	row := 0
+	for i, w := range workers {
+		rows := cfg.Height / cfg.Workers
+		if i < cfg.Height%cfg.Workers {
+			rows++ // Spread the remainder over the first workers.
+		}
+		err := w.send(assignment{
+			Rank:     i,
+			Workers:  cfg.Workers,
+			Width:    cfg.Width,
+			StartRow: row,
+			Rows:     rows,
+			Seed:     cfg.Seed,
+			Chronons: cfg.Chronons,
+			Down:     addrs[(i+1)%cfg.Workers],
+
+			Params:       cfg.Params,
+			FishDensity:  cfg.FishDensity,
+			SharkDensity: cfg.SharkDensity,
+		})
+		if err != nil {
+			return err
+		}
+		row += rows
+	}
+	fmt.Fprintf(out, "coordinator: %d workers, %dx%d grid, %d chronons\n", cfg.Workers, cfg.Width, cfg.Height, cfg.Chronons)
+
+	file, err := os.OpenFile(cfg.Results, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open results: %w", err)
+	}
+	defer file.Close()
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+	if stat, err := file.Stat(); err == nil && stat.Size() == 0 {
+		writer.Write([]string{"Grid Size", "Workers", "Chronon", "Fish", "Sharks", "Migrants", "Conflicts", "Returned", "Slowest Step (ms)"})
+	}
+
+	start := time.Now()
+	var last totals
+	for chronon := 0; chronon <= cfg.Chronons; chronon++ { // Chronon 0 is the starting population.
+		var t totals
+		for _, w := range workers {
+			var s stats
+			if err := w.receive(&s); err != nil {
+				return err
+			}
+			if s.Chronon != chronon {
+				return fmt.Errorf("worker %d reported chronon %d, expected %d", s.Rank, s.Chronon, chronon)
+			}
+			t.fish += s.Fish
+			t.sharks += s.Sharks
+			t.migrants += s.Migrants
+			t.conflicts += s.Conflicts
+			t.returned += s.Returned
+			t.slowest = max(t.slowest, s.StepTime)
+		}
+		writer.Write([]string{
+			strconv.Itoa(cfg.Width * cfg.Height),
+			strconv.Itoa(cfg.Workers),
+			strconv.Itoa(chronon),
+			strconv.Itoa(t.fish),
+			strconv.Itoa(t.sharks),
+			strconv.Itoa(t.migrants),
+			strconv.Itoa(t.conflicts),
+			strconv.Itoa(t.returned),
+			strconv.FormatFloat(float64(t.slowest)/float64(time.Millisecond), 'f', 3, 64),
+		})
+		last = t
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+
+	elapsed := time.Since(start)
+	fmt.Fprintf(out, "coordinator: finished in %v (%.1f chronons/s); %d fish, %d sharks\n",
+		elapsed.Round(time.Millisecond), float64(cfg.Chronons)/elapsed.Seconds(), last.fish, last.sharks)
+	return nil
+}
+
+// validate reports an error if the workers could not simulate cfg.
+func (cfg coordinatorConfig) validate() error {
+	if cfg.Workers < 2 {
+		return fmt.Errorf("need at least 2 workers, got %d", cfg.Workers)
+	}
+	if cfg.Height < cfg.Workers {
+		return fmt.Errorf("grid height %d is smaller than the number of workers %d", cfg.Height, cfg.Workers)
+	}
+	if cfg.Width < 1 {
+		return fmt.Errorf("grid width must be at least 1, got %d", cfg.Width)
+	}
+	if cfg.FishDensity < 0 || cfg.SharkDensity < 0 || cfg.FishDensity+cfg.SharkDensity > 1 {
+		return fmt.Errorf("densities must not be negative or add up to more than 1, got fish %g and sharks %g", cfg.FishDensity, cfg.SharkDensity)
+	}
+	return cfg.Params.Validate()
+}
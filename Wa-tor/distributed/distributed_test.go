@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"Wator/wator"
+)
+
+// TestLocalKeepsEveryCreature runs a coordinator and workers over the loopback interface on a crowded grid, with
+// breeding and starving switched off, so every creature that crosses between strips must arrive somewhere: the fish
+// may only be eaten and the sharks must all survive.
+func TestLocalKeepsEveryCreature(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		fish, sharks float64
+	}{{"fish only", 0.9, 0}, {"fish and sharks", 0.5, 0.2}} {
+		cfg := coordinatorConfig{
+			Workers:  3,
+			Width:    12,
+			Height:   9,
+			Chronons: 20,
+			Seed:     1,
+			Results:  filepath.Join(t.TempDir(), "results.csv"),
+
+			Params:       wator.Params{FishBreed: 1000, SharkBreed: 1000, SharkStarve: 1000},
+			FishDensity:  tc.fish,
+			SharkDensity: tc.sharks,
+		}
+		if err := runLocal(cfg, io.Discard); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		rows := readResults(t, cfg.Results)
+		if len(rows) != cfg.Chronons+1 {
+			t.Fatalf("%s: %d rows of results, want one for the start and one per chronon", tc.name, len(rows))
+		}
+		start, migrants, conflicts := rows[0], 0, 0
+		for i, row := range rows {
+			switch {
+			case tc.sharks == 0 && row["Fish"] != start["Fish"]:
+				t.Errorf("%s, chronon %d: %d fish, want the %d at the start", tc.name, i, row["Fish"], start["Fish"])
+			case row["Fish"] > start["Fish"]:
+				t.Errorf("%s, chronon %d: %d fish without breeding, up from %d", tc.name, i, row["Fish"], start["Fish"])
+			case row["Sharks"] != start["Sharks"]:
+				t.Errorf("%s, chronon %d: %d sharks, want the %d at the start", tc.name, i, row["Sharks"], start["Sharks"])
+			}
+			migrants += row["Migrants"]
+			conflicts += row["Conflicts"]
+		}
+		if migrants == 0 || conflicts == 0 {
+			t.Errorf("%s: %d migrants and %d conflicts, want some of each", tc.name, migrants, conflicts)
+		}
+	}
+}
+
+// readResults reads the coordinator's results file, returning each row's numeric columns by name.
+func readResults(t *testing.T, name string) []map[string]int {
+	t.Helper()
+	file, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil || len(records) == 0 {
+		t.Fatalf("reading the results: %v", err)
+	}
+	var rows []map[string]int
+	for _, record := range records[1:] {
+		row := map[string]int{}
+		for i, column := range records[0] {
+			row[column], _ = strconv.Atoi(record[i]) // The step times are not whole numbers and are not needed.
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// TestAcceptReturnsWhenFull checks that a migrant whose target cell is taken goes to the nearest open water, and that
+// one arriving at a full strip is handed back rather than lost.
+func TestAcceptReturnsWhenFull(t *testing.T) {
+	s, err := newStrip(assignment{Width: 5, Rows: 3, Seed: 1, Params: wator.DefaultConfig().Params, FishDensity: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.sim.Remove(4, 3) // The only open water, in the far corner from the target.
+	migrants := []migrant{{Kind: wator.Shark, X: 1, Starve: 2}, {Kind: wator.Shark, X: 1}}
+	s.backUp = s.accept(1, migrants)
+	if info, ok := s.sim.Inspect(4, 3); !ok || info.Kind != wator.Shark || info.Starve != 2 {
+		t.Errorf("the open water holds %+v, %v, want the first shark", info, ok)
+	}
+	if len(s.backUp) != 1 || s.conflicts != 2 || s.returned != 1 {
+		t.Errorf("%d migrants handed back with %d conflicts and %d returned, want 1, 2 and 1", len(s.backUp), s.conflicts, s.returned)
+	}
+	if fish, sharks := s.count(); fish != 14 || sharks != 2 {
+		t.Errorf("counted %d fish and %d sharks, want 14 and 2, including the one handed back", fish, sharks)
+	}
+}
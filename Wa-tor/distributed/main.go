@@ -0,0 +1,131 @@
+// Command distributed runs Wa-Tor across several processes, so grids too large for one machine's cores can be simulated.
+//
+// The grid is split into horizontal strips, one per worker, each stepped with the rules of the wator package. Before
+// every chronon each worker swaps its first and last rows with the workers above and below it over TCP; after the
+// chronon it sends them the fish and sharks that moved into their rows. A coordinator hands out the strips and merges
+// the statistics every worker reports into a CSV file.
+//
+// Start a coordinator, then as many workers as it expects (on any machines that can reach it):
+//
+//	distributed -role coordinator -listen :9000 -workers 4 -width 400 -height 400 -chronons 1000
+//	distributed -role worker -coordinator host:9000
+//
+// Or run the coordinator and workers in one process, connected over the loopback interface:
+//
+//	distributed -role local -workers 4
+//
+// The run is headless; use the single-process versions to watch a simulation.
+package main
+
+import (
+	"flag" // Parses the command-line options.
+	"fmt"  // Prints errors.
+	"io"   // Accepts any destination for the coordinator's summary.
+	"net"  // Opens the coordinator's listener.
+	"os"   // Sets the exit status.
+	"time" // Provides the default seed.
+
+	"Wator/wator" // Provides the default thresholds and densities.
+)
+
+func main() {
+	role := flag.String("role", "local", "coordinator, worker, or local (coordinator and workers in one process)")
+	listen := flag.String("listen", ":9000", "address the coordinator listens on (worker: address for the neighbour connection, default any free port)")
+	coordAddr := flag.String("coordinator", "localhost:9000", "address of the coordinator (worker only)")
+	workers := flag.Int("workers", 4, "number of workers")
+	width := flag.Int("width", 400, "grid width in cells")
+	height := flag.Int("height", 400, "grid height in cells")
+	chronons := flag.Int("chronons", 1000, "number of chronons to simulate")
+	seed := flag.Int64("seed", 0, "random seed (0 uses the current time)")
+	defaults := wator.DefaultConfig()
+	fishBreed := flag.Int("fish-breed", defaults.FishBreed, "chronons before a fish breeds")
+	sharkBreed := flag.Int("shark-breed", defaults.SharkBreed, "chronons before a shark breeds")
+	sharkStarve := flag.Int("shark-starve", defaults.SharkStarve, "chronons a shark survives without eating")
+	fishDensity := flag.Float64("fish-density", defaults.FishDensity, "fraction of cells that start with a fish")
+	sharkDensity := flag.Float64("shark-density", defaults.SharkDensity, "fraction of cells that start with a shark")
+	results := flag.String("results", "distributed_results.csv", "CSV file the merged statistics are appended to")
+	flag.Parse()
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	cfg := coordinatorConfig{
+		Listen:   *listen,
+		Workers:  *workers,
+		Width:    *width,
+		Height:   *height,
+		Chronons: *chronons,
+		Seed:     *seed,
+		Results:  *results,
+
+		Params:       wator.Params{FishBreed: *fishBreed, SharkBreed: *sharkBreed, SharkStarve: *sharkStarve},
+		FishDensity:  *fishDensity,
+		SharkDensity: *sharkDensity,
+	}
+
+	var err error
+	switch *role {
+	case "coordinator":
+		err = coordinate(cfg)
+	case "worker":
+		workerListen := ":0"
+		if isFlagSet("listen") {
+			workerListen = *listen
+		}
+		err = runWorker(*coordAddr, workerListen)
+	case "local":
+		err = runLocal(cfg, os.Stdout)
+	default:
+		err = fmt.Errorf("unknown role %q", *role)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "distributed:", err)
+		os.Exit(1)
+	}
+}
+
+// coordinate opens the coordinator's listener and runs the coordinator.
+func coordinate(cfg coordinatorConfig) error {
+	listener, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		return fmt.Errorf("coordinator listen: %w", err)
+	}
+	defer listener.Close()
+	return runCoordinator(cfg, listener, os.Stdout)
+}
+
+// runLocal runs a coordinator and cfg.Workers workers in this process, connected over the loopback interface, writing
+// the coordinator's summary to out. It exercises exactly the same protocol as separate processes, which makes it handy
+// for trying the distributed mode out and for testing it.
+func runLocal(cfg coordinatorConfig, out io.Writer) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("coordinator listen: %w", err)
+	}
+	defer listener.Close()
+
+	errs := make(chan error, cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		go func() { errs <- runWorker(listener.Addr().String(), "127.0.0.1:0") }()
+	}
+	if err := runCoordinator(cfg, listener, out); err != nil {
+		return err
+	}
+	for i := 0; i < cfg.Workers; i++ {
+		if err := <-errs; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isFlagSet reports whether the named flag was given on the command line.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/gob" // Encodes the messages sent over each connection.
+	"fmt"          // Formats connection errors.
+	"net"          // Provides the TCP connections between processes.
+	"time"         // Records how long each step takes.
+
+	"Wator/wator" // Provides the thresholds, cell kinds and creatures sent between processes.
+)
+
+// hello is the first message a worker sends to the coordinator.
+type hello struct {
+	ListenAddr string // Address the worker accepts its upper neighbour's connection on.
+}
+
+// assignment tells a worker which rows it owns and how to reach its neighbour.
+// Workers form a ring: each one dials the worker owning the rows below it and accepts a connection from the one above.
+type assignment struct {
+	Rank     int    // Position of the worker in the ring, from 0.
+	Workers  int    // Number of workers in the ring.
+	Width    int    // Width of the whole grid.
+	StartRow int    // First grid row owned by the worker.
+	Rows     int    // Number of rows owned by the worker.
+	Seed     int64  // Seed for the worker's random source; each worker adds its rank.
+	Chronons int    // Number of chronons to simulate.
+	Down     string // Address of the worker owning the rows below.
+
+	Params       wator.Params // Breed and starve thresholds.
+	FishDensity  float64      // Fraction of cells that start with a fish.
+	SharkDensity float64      // Fraction of cells that start with a shark.
+}
+
+// halo carries a boundary row to a neighbour before each chronon, with the migrants the neighbour sent during the last
+// chronon that found no room.
+type halo struct {
+	Chronon  int          // Chronon the row belongs to, used to detect workers falling out of step.
+	Row      []wator.Cell // Cell kinds of the row.
+	Returned []migrant    // Migrants handed back to the neighbour that sent them.
+}
+
+// migrant is a fish or shark moving from one worker's rows into a neighbour's, with its breed timer, starve counter and
+// age. X is the column of the cell it moved into, in the neighbour's first or last row; Y is not used.
+type migrant = wator.CreatureInfo
+
+// migration carries the migrants for a neighbour after each chronon.
+type migration struct {
+	Chronon  int       // Chronon the moves were made in.
+	Migrants []migrant // Entities moving into the neighbour's rows.
+}
+
+// stats is the report each worker sends to the coordinator at the start and after every chronon.
+type stats struct {
+	Rank      int           // Worker the report came from.
+	Chronon   int           // Chronon the report describes.
+	Fish      int           // Fish in the worker's rows after the chronon, or being handed back to a neighbour.
+	Sharks    int           // Sharks in the worker's rows after the chronon, or being handed back to a neighbour.
+	Migrants  int           // Entities the worker sent to its neighbours.
+	Conflicts int           // Incoming migrants whose target cell had been taken, placed in the nearest free one.
+	Returned  int           // Incoming migrants that found no room in the worker's rows and are handed back.
+	StepTime  time.Duration // Time spent simulating the worker's rows, excluding communication.
+}
+
+// link is a gob-encoded TCP connection.
+type link struct {
+	conn net.Conn
+	enc  *gob.Encoder
+	dec  *gob.Decoder
+}
+
+// newLink wraps a connection with a gob encoder and decoder.
+func newLink(conn net.Conn) *link {
+	return &link{conn: conn, enc: gob.NewEncoder(conn), dec: gob.NewDecoder(conn)}
+}
+
+// send encodes a message onto the connection.
+func (l *link) send(v any) error {
+	if err := l.enc.Encode(v); err != nil {
+		return fmt.Errorf("send to %s: %w", l.conn.RemoteAddr(), err)
+	}
+	return nil
+}
+
+// receive decodes the next message from the connection into v.
+func (l *link) receive(v any) error {
+	if err := l.dec.Decode(v); err != nil {
+		return fmt.Errorf("receive from %s: %w", l.conn.RemoteAddr(), err)
+	}
+	return nil
+}
+
+// Close closes the underlying connection.
+func (l *link) Close() error {
+	return l.conn.Close()
+}
+
+// exchange sends out to a neighbour while receiving the neighbour's message into in.
+// Sending and receiving at the same time means two neighbours exchanging large rows can never both block on a full buffer.
+func (l *link) exchange(out, in any) error {
+	sent := make(chan error, 1)
+	go func() { sent <- l.send(out) }()
+	if err := l.receive(in); err != nil {
+		return err
+	}
+	return <-sent
+}
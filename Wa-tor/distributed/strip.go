@@ -0,0 +1,156 @@
+package main
+
+import (
+	"Wator/wator" // Steps each worker's rows with the same rules as the single-process simulation.
+)
+
+// strip holds the rows of the grid owned by one worker in a wator.Simulation, with a halo row above and below them.
+//
+// The simulation is rows+2 cells tall: row 0 stands for the last row of the worker above, rows 1 to rows are the
+// worker's own and row rows+1 stands for the first row of the worker below. Before every chronon the halo rows are
+// filled with land wherever the neighbour's row holds a creature, so a fish or shark can move into a cell the neighbour
+// leaves open but never takes or eats one of its creatures. A creature that ends the chronon in a halo row has moved
+// into the neighbour's rows, and is taken off the grid to be sent to the neighbour as a migrant.
+// The grid wraps around horizontally within the strip and vertically across the ring of workers.
+type strip struct {
+	sim      *wator.Simulation // The worker's rows and their halos.
+	width    int               // Width of the grid.
+	rows     int               // Number of rows owned by the worker.
+	outUp    []migrant         // Creatures that moved into the row above this chronon.
+	outDown  []migrant         // Creatures that moved into the row below this chronon.
+	backUp   []migrant         // Migrants from the worker above that found no room, to be returned to it.
+	backDown []migrant         // Migrants from the worker below that found no room, to be returned to it.
+
+	conflicts int // Migrants whose target cell had been taken, since setHalos started the chronon.
+	returned  int // Migrants that found no room at all and are returned, since setHalos started the chronon.
+}
+
+// newStrip creates the strip a worker is assigned, filled at random with the assignment's densities.
+func newStrip(a assignment) (*strip, error) {
+	cfg := wator.DefaultConfig()
+	cfg.Width, cfg.Height = a.Width, a.Rows+2
+	cfg.Params = a.Params
+	cfg.FishDensity, cfg.SharkDensity = a.FishDensity, a.SharkDensity
+	cfg.Seed = a.Seed + int64(a.Rank)
+	sim, err := wator.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := &strip{sim: sim, width: a.Width, rows: a.Rows}
+	for x := 0; x < s.width; x++ {
+		sim.Remove(x, 0) // The halo rows belong to the neighbours, so anything placed in them is discarded.
+		sim.Remove(x, s.rows+1)
+	}
+	return s, nil
+}
+
+// topRow and bottomRow return the cell kinds of the first and last rows owned, to be sent to the neighbours.
+func (s *strip) topRow() []wator.Cell    { return s.rowKinds(1) }
+func (s *strip) bottomRow() []wator.Cell { return s.rowKinds(s.rows) }
+
+// rowKinds returns the cell kinds of row y.
+func (s *strip) rowKinds(y int) []wator.Cell {
+	row := make([]wator.Cell, s.width)
+	for x := range row {
+		if info, ok := s.sim.Inspect(x, y); ok {
+			row[x] = info.Kind
+		}
+	}
+	return row
+}
+
+// setHalos starts a chronon, filling the halo rows with land wherever the neighbours' rows above and below hold
+// something, and with open water elsewhere.
+func (s *strip) setHalos(above, below []wator.Cell) error {
+	s.conflicts, s.returned = 0, 0
+	for x := 0; x < s.width; x++ {
+		for _, halo := range [2]struct {
+			y   int
+			row []wator.Cell
+		}{{0, above}, {s.rows + 1, below}} {
+			s.sim.Remove(x, halo.y) // The land of the last chronon.
+			if halo.row[x] == wator.Empty {
+				continue
+			}
+			if err := s.sim.Place(wator.CreatureInfo{Kind: wator.Land, X: x, Y: halo.y}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// step advances the strip by one chronon with the wator rules and collects the creatures that moved into the halo
+// rows in outUp and outDown.
+func (s *strip) step() {
+	s.sim.Step()
+	s.outUp = s.collect(0)
+	s.outDown = s.collect(s.rows + 1)
+}
+
+// collect takes the fish and sharks in halo row y off the grid and returns them as migrants.
+func (s *strip) collect(y int) []migrant {
+	var out []migrant
+	for x := 0; x < s.width; x++ {
+		if info, ok := s.sim.Inspect(x, y); ok && info.Kind != wator.Land {
+			s.sim.Remove(x, y)
+			out = append(out, info)
+		}
+	}
+	return out
+}
+
+// accept places migrants arriving from a neighbour, or returned by it, in the row next to that neighbour: edge is 1
+// for the worker above and rows for the worker below. It returns the migrants there was no room for.
+//
+// The neighbour only sends a creature into a cell that held nothing at the start of the chronon, but this worker's own
+// creatures may have moved into it since. Such a migrant is counted as a conflict and placed in the nearest open
+// water, searching outwards along the row and then row by row away from the edge. Only if the whole strip is full is it
+// returned, to be placed back in the neighbour's rows in the same way at the start of the next chronon, so no creature
+// is ever lost.
+func (s *strip) accept(edge int, migrants []migrant) []migrant {
+	inward := 1
+	if edge != 1 {
+		inward = -1
+	}
+	var rejected []migrant
+	for _, m := range migrants {
+		placed := s.place(m, m.X, edge)
+		if !placed {
+			s.conflicts++
+		}
+		for dy := 0; dy < s.rows && !placed; dy++ {
+			for dx := 0; dx <= s.width/2 && !placed; dx++ {
+				y := edge + inward*dy
+				placed = s.place(m, (m.X+dx)%s.width, y) || s.place(m, (m.X-dx+s.width)%s.width, y)
+			}
+		}
+		if !placed {
+			s.returned++
+			rejected = append(rejected, m)
+		}
+	}
+	return rejected
+}
+
+// place puts m into the cell (x, y) if it is open water, and reports whether it did.
+func (s *strip) place(m migrant, x, y int) bool {
+	m.X, m.Y = x, y
+	return s.sim.Place(m) == nil
+}
+
+// count returns the number of fish and sharks in the strip, including those it is returning to its neighbours, so
+// the counts of every worker add up to the whole population.
+func (s *strip) count() (fish, sharks int) {
+	fish, sharks = s.sim.Population()
+	for _, back := range [2][]migrant{s.backUp, s.backDown} {
+		for _, m := range back {
+			if m.Kind == wator.Fish {
+				fish++
+			} else {
+				sharks++
+			}
+		}
+	}
+	return fish, sharks
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"  // Formats errors.
+	"net"  // Connects to the coordinator and the neighbouring workers.
+	"time" // Times each step and retries dialling.
+)
+
+// worker simulates one strip of the grid and trades boundary rows and migrants with its neighbours.
+type worker struct {
+	assign assignment // Rows and neighbour given by the coordinator.
+	strip  *strip     // The rows owned by this worker.
+	coord  *link      // Connection to the coordinator.
+	up     *link      // Connection to the worker owning the rows above.
+	down   *link      // Connection to the worker owning the rows below.
+}
+
+// runWorker joins the simulation run by the coordinator at coordAddr and simulates the rows it is assigned.
+//
+// Input:
+//   - coordAddr (string): Address of the coordinator.
+//   - listenAddr (string): Address to accept the upper neighbour's connection on; ":0" picks a free port.
+//
+// Output:
+//   - error: Returns an error if any connection fails or a neighbour falls out of step.
+//
+// Functionality:
+//  1. Registers with the coordinator and waits for an assignment.
+//  2. Dials the worker below and accepts the connection from the worker above, forming a ring.
+//  3. Reports the starting population, then for each chronon: exchanges boundary rows, steps the strip, exchanges
+//     migrants and reports statistics.
+func runWorker(coordAddr, listenAddr string) error {
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return fmt.Errorf("worker listen: %w", err)
+	}
+	defer listener.Close()
+
+	conn, err := net.Dial("tcp", coordAddr)
+	if err != nil {
+		return fmt.Errorf("worker dial coordinator: %w", err)
+	}
+	w := &worker{coord: newLink(conn)}
+	defer w.coord.Close()
+
+	if err := w.coord.send(hello{ListenAddr: advertisedAddr(listener.Addr(), conn.LocalAddr())}); err != nil {
+		return err
+	}
+	if err := w.coord.receive(&w.assign); err != nil {
+		return err
+	}
+
+	if err := w.connectNeighbours(listener); err != nil {
+		return err
+	}
+	defer w.up.Close()
+	defer w.down.Close()
+
+	if w.strip, err = newStrip(w.assign); err != nil {
+		return fmt.Errorf("worker %d: %w", w.assign.Rank, err)
+	}
+	if err := w.report(0, 0); err != nil {
+		return err
+	}
+	for chronon := 1; chronon <= w.assign.Chronons; chronon++ {
+		if err := w.runChronon(chronon); err != nil {
+			return fmt.Errorf("worker %d chronon %d: %w", w.assign.Rank, chronon, err)
+		}
+	}
+	return nil
+}
+
+// connectNeighbours dials the worker below while accepting the connection from the worker above.
+// Every worker does both at once, so the ring forms regardless of the order in which workers start.
+func (w *worker) connectNeighbours(listener net.Listener) error {
+	accepted := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			accepted <- fmt.Errorf("accept upper neighbour: %w", err)
+			return
+		}
+		w.up = newLink(conn)
+		accepted <- nil
+	}()
+
+	var conn net.Conn
+	var err error
+	for attempt := 0; attempt < 50; attempt++ {
+		if conn, err = net.Dial("tcp", w.assign.Down); err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond) // The neighbour may still be starting up.
+	}
+	if err != nil {
+		return fmt.Errorf("dial lower neighbour: %w", err)
+	}
+	w.down = newLink(conn)
+	return <-accepted
+}
+
+// runChronon performs one chronon: boundary exchange, local step, migrant exchange and statistics report.
+// Migrants a neighbour had no room for come back with its boundary row and are placed before the strip is stepped.
+func (w *worker) runChronon(chronon int) error {
+	var fromUp, fromDown halo
+	err := exchangeBoth(
+		w.up, halo{Chronon: chronon, Row: w.strip.topRow(), Returned: w.strip.backUp}, &fromUp,
+		w.down, halo{Chronon: chronon, Row: w.strip.bottomRow(), Returned: w.strip.backDown}, &fromDown,
+	)
+	if err != nil {
+		return err
+	}
+	if fromUp.Chronon != chronon || fromDown.Chronon != chronon {
+		return fmt.Errorf("neighbours out of step: got rows for chronons %d and %d", fromUp.Chronon, fromDown.Chronon)
+	}
+	if err := w.strip.setHalos(fromUp.Row, fromDown.Row); err != nil {
+		return err
+	}
+	w.strip.backUp = w.strip.accept(1, fromUp.Returned)
+	w.strip.backDown = w.strip.accept(w.strip.rows, fromDown.Returned)
+
+	start := time.Now()
+	w.strip.step()
+	stepTime := time.Since(start)
+
+	var inUp, inDown migration
+	err = exchangeBoth(
+		w.up, migration{Chronon: chronon, Migrants: w.strip.outUp}, &inUp,
+		w.down, migration{Chronon: chronon, Migrants: w.strip.outDown}, &inDown,
+	)
+	if err != nil {
+		return err
+	}
+	w.strip.backUp = append(w.strip.backUp, w.strip.accept(1, inUp.Migrants)...)
+	w.strip.backDown = append(w.strip.backDown, w.strip.accept(w.strip.rows, inDown.Migrants)...)
+	return w.report(chronon, stepTime)
+}
+
+// report sends the coordinator the strip's population and the migrations of the chronon.
+func (w *worker) report(chronon int, stepTime time.Duration) error {
+	fish, sharks := w.strip.count()
+	return w.coord.send(stats{
+		Rank:      w.assign.Rank,
+		Chronon:   chronon,
+		Fish:      fish,
+		Sharks:    sharks,
+		Migrants:  len(w.strip.outUp) + len(w.strip.outDown),
+		Conflicts: w.strip.conflicts,
+		Returned:  w.strip.returned,
+		StepTime:  stepTime,
+	})
+}
+
+// exchangeBoth trades messages with both neighbours at the same time.
+// Exchanging with one neighbour and then the other would deadlock, because every worker in the ring would wait on the
+// same side first.
+func exchangeBoth(up *link, upOut, upIn any, down *link, downOut, downIn any) error {
+	done := make(chan error, 1)
+	go func() { done <- up.exchange(upOut, upIn) }()
+	errDown := down.exchange(downOut, downIn)
+	if errUp := <-done; errUp != nil {
+		return errUp
+	}
+	return errDown
+}
+
+// advertisedAddr returns the address neighbours should dial to reach listenAddr.
+// A listener bound to all interfaces reports an unspecified host, so the host the coordinator sees is used instead.
+func advertisedAddr(listenAddr, localAddr net.Addr) string {
+	listen := listenAddr.(*net.TCPAddr)
+	if !listen.IP.IsUnspecified() {
+		return listen.String()
+	}
+	local := localAddr.(*net.TCPAddr)
+	return (&net.TCPAddr{IP: local.IP, Port: listen.Port}).String()
+}
@@ -2,7 +2,11 @@ module Wator
 
 go 1.23.1
 
-require github.com/hajimehoshi/ebiten/v2 v2.8.3
+require (
+	Barrier v0.0.0
+	github.com/hajimehoshi/ebiten/v2 v2.8.3
+	golang.org/x/sys v0.25.0
+)
 
 require (
 	github.com/ebitengine/gomobile v0.0.0-20240911145611-4856209ac325 // indirect
@@ -10,5 +14,6 @@ require (
 	github.com/ebitengine/purego v0.8.0 // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
 	golang.org/x/sync v0.8.0 // indirect
-	golang.org/x/sys v0.25.0 // indirect
 )
+
+replace Barrier => ../Barrier
@@ -0,0 +1,209 @@
+// Command snapdiff compares two Wa-Tor snapshot files and reports the first chronon at which they diverge.
+//
+// Snapshots are recorded by running a simulation with the -snapshot flag. Recording the same seed with
+// different thread counts and comparing the results pins down where concurrency changes the outcome:
+//
+//	snapdiff [-max n] one_thread.snap eight_threads.snap
+//
+// The exit status is 0 when the recordings are identical, 1 when they diverge and 2 on error.
+package main
+
+import (
+	"bufio"   // Reads snapshot files line by line.
+	"flag"    // Parses the command-line options.
+	"fmt"     // Prints the divergence report.
+	"io"      // Signals the end of a snapshot with io.EOF.
+	"os"      // Opens snapshot files and sets the exit status.
+	"strconv" // Parses chronon numbers.
+	"strings" // Splits header and chronon lines.
+)
+
+// snapshot reads the header and chronon blocks of a snapshot file.
+type snapshot struct {
+	name    string            // File name, used in error messages.
+	header  map[string]string // Header fields such as "grid", "seed" and "threads".
+	width   int               // Number of cells in the x direction.
+	height  int               // Number of cells in the y direction.
+	scanner *bufio.Scanner    // Scanner positioned at the next chronon line.
+	pending string            // A "chronon" line read ahead while parsing the header.
+}
+
+// cellDiff describes a single cell that differs between the two snapshots.
+type cellDiff struct {
+	x, y int  // Position of the cell on the grid.
+	a, b byte // Cell contents in the first and second snapshot.
+}
+
+func main() {
+	maxCells := flag.Int("max", 20, "maximum number of differing cells to list")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: snapdiff [-max n] a.snap b.snap")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	a, err := openSnapshot(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "snapdiff:", err)
+		os.Exit(2)
+	}
+	b, err := openSnapshot(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "snapdiff:", err)
+		os.Exit(2)
+	}
+
+	diverged, err := compare(os.Stdout, a, b, *maxCells)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "snapdiff:", err)
+		os.Exit(2)
+	}
+	if diverged {
+		os.Exit(1)
+	}
+}
+
+// compare walks both snapshots chronon by chronon and writes a report of the first divergence to w.
+//
+// Input:
+//   - w (io.Writer): Destination for the report.
+//   - a, b (*snapshot): The two recordings to compare.
+//   - maxCells (int): The maximum number of differing cells to list.
+//
+// Output:
+//   - bool: True if the recordings diverge.
+//   - error: Returns an error if the recordings cannot be compared (e.g., different grid sizes or malformed files).
+func compare(w io.Writer, a, b *snapshot, maxCells int) (bool, error) {
+	if a.width != b.width || a.height != b.height {
+		return false, fmt.Errorf("grid sizes differ: %dx%d vs %dx%d", a.width, a.height, b.width, b.height)
+	}
+	fmt.Fprintf(w, "a: %s (seed %s, %s threads)\n", a.name, a.header["seed"], a.header["threads"])
+	fmt.Fprintf(w, "b: %s (seed %s, %s threads)\n", b.name, b.header["seed"], b.header["threads"])
+	if a.header["seed"] != b.header["seed"] {
+		fmt.Fprintln(w, "warning: seeds differ, so the runs are not expected to match")
+	}
+
+	for {
+		chrononA, rowsA, errA := a.next()
+		chrononB, rowsB, errB := b.next()
+		if errA == io.EOF || errB == io.EOF {
+			switch {
+			case errA == io.EOF && errB == io.EOF:
+				fmt.Fprintln(w, "no divergence: recordings are identical")
+				return false, nil
+			case errA == io.EOF:
+				fmt.Fprintf(w, "no divergence while both ran; a ends before chronon %d of b\n", chrononB)
+			default:
+				fmt.Fprintf(w, "no divergence while both ran; b ends before chronon %d of a\n", chrononA)
+			}
+			return false, nil
+		}
+		if errA != nil {
+			return false, errA
+		}
+		if errB != nil {
+			return false, errB
+		}
+		if chrononA != chrononB {
+			return false, fmt.Errorf("chronon numbers out of step: %d vs %d", chrononA, chrononB)
+		}
+
+		diffs := diffRows(rowsA, rowsB)
+		if len(diffs) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "first divergence at chronon %d: %d cells differ\n", chrononA, len(diffs))
+		for i, d := range diffs {
+			if i == maxCells {
+				fmt.Fprintf(w, "  ... %d more\n", len(diffs)-maxCells)
+				break
+			}
+			fmt.Fprintf(w, "  (%d, %d): %c vs %c\n", d.x, d.y, d.a, d.b)
+		}
+		return true, nil
+	}
+}
+
+// diffRows returns every cell that differs between two grids of equal size, in row-major order.
+func diffRows(a, b []string) []cellDiff {
+	var diffs []cellDiff
+	for y := range a {
+		for x := 0; x < len(a[y]); x++ {
+			if a[y][x] != b[y][x] {
+				diffs = append(diffs, cellDiff{x: x, y: y, a: a[y][x], b: b[y][x]})
+			}
+		}
+	}
+	return diffs
+}
+
+// openSnapshot opens a snapshot file and parses its header.
+func openSnapshot(name string) (*snapshot, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &snapshot{name: name, header: map[string]string{}, scanner: bufio.NewScanner(file)}
+	if !s.scanner.Scan() || !strings.HasPrefix(s.scanner.Text(), "wator-snapshot ") {
+		return nil, fmt.Errorf("%s: not a Wa-Tor snapshot", name)
+	}
+	s.header["version"] = strings.TrimPrefix(s.scanner.Text(), "wator-snapshot ")
+
+	// Header fields run until the first chronon block.
+	for s.scanner.Scan() {
+		key, value, _ := strings.Cut(s.scanner.Text(), " ")
+		if key == "chronon" {
+			s.pending = s.scanner.Text()
+			break
+		}
+		s.header[key] = value
+	}
+	if err := s.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	if _, err := fmt.Sscanf(s.header["grid"], "%d %d", &s.width, &s.height); err != nil {
+		return nil, fmt.Errorf("%s: invalid grid header %q", name, s.header["grid"])
+	}
+	return s, nil
+}
+
+// next reads the following chronon block, returning io.EOF once the file is exhausted.
+func (s *snapshot) next() (int, []string, error) {
+	line := s.pending
+	s.pending = ""
+	if line == "" {
+		if !s.scanner.Scan() {
+			if err := s.scanner.Err(); err != nil {
+				return 0, nil, fmt.Errorf("%s: %w", s.name, err)
+			}
+			return 0, nil, io.EOF
+		}
+		line = s.scanner.Text()
+	}
+
+	key, value, _ := strings.Cut(line, " ")
+	chronon, err := strconv.Atoi(value)
+	if key != "chronon" || err != nil {
+		return 0, nil, fmt.Errorf("%s: expected chronon line, got %q", s.name, line)
+	}
+
+	rows := make([]string, s.height)
+	for y := range rows {
+		if !s.scanner.Scan() {
+			// A run interrupted mid-write leaves a partial block; treat it as the end of the recording.
+			return 0, nil, io.EOF
+		}
+		rows[y] = s.scanner.Text()
+		if len(rows[y]) != s.width {
+			return 0, nil, fmt.Errorf("%s: chronon %d row %d has %d cells, want %d", s.name, chronon, y, len(rows[y]), s.width)
+		}
+	}
+	return chronon, rows, nil
+}
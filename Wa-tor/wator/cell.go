@@ -0,0 +1,92 @@
+package wator
+
+// Cell is the contents of one grid cell.
+type Cell uint8
+
+// The kinds of cell. The values match the cell kinds written to replay logs.
+const (
+	Empty Cell = iota // Open water.
+	Fish              // A fish.
+	Shark             // A shark.
+	Land              // An impassable cell that neither fish nor sharks may enter.
+)
+
+// String returns the name of the cell kind.
+func (c Cell) String() string {
+	switch c {
+	case Empty:
+		return "empty"
+	case Fish:
+		return "fish"
+	case Shark:
+		return "shark"
+	case Land:
+		return "land"
+	}
+	return "unknown"
+}
+
+// Snapshot is a copy of the grid at the end of a chronon.
+// It does not share memory with the simulation, so it can be kept or modified freely.
+type Snapshot struct {
+	Chronon int    // Number of chronons simulated when the snapshot was taken.
+	Width   int    // Number of cells in the x direction.
+	Height  int    // Number of cells in the y direction.
+	Cells   []Cell // Cell contents row by row, indexed y*Width + x.
+
+	// Infected marks the cells holding infected fish, indexed like Cells. It is nil unless the disease is enabled,
+	// and is not saved by the snapshot, replay or scenario files.
+	Infected []bool
+}
+
+// NewSnapshot returns an all-empty snapshot of the given size, for building a Config.Layout.
+func NewSnapshot(width, height int) *Snapshot {
+	return &Snapshot{Width: width, Height: height, Cells: make([]Cell, width*height)}
+}
+
+// At returns the contents of the cell at (x, y).
+func (s Snapshot) At(x, y int) Cell {
+	return s.Cells[y*s.Width+x]
+}
+
+// Set changes the contents of the cell at (x, y).
+func (s Snapshot) Set(x, y int, c Cell) {
+	s.Cells[y*s.Width+x] = c
+}
+
+// Count returns the number of cells holding c.
+func (s Snapshot) Count(c Cell) int {
+	n := 0
+	for _, cell := range s.Cells {
+		if cell == c {
+			n++
+		}
+	}
+	return n
+}
+
+// Occupancy counts how many chronons each cell held a fish or a shark, revealing spatial structure such as fronts and
+// waves that the population totals miss. Fill it with Simulation.AddOccupancy after every chronon.
+type Occupancy struct {
+	Chronons int   // Number of chronons counted.
+	Width    int   // Number of cells in the x direction.
+	Height   int   // Number of cells in the y direction.
+	Fish     []int // Chronons each cell held a fish, row by row, indexed y*Width + x.
+	Sharks   []int // Chronons each cell held a shark, indexed like Fish.
+}
+
+// NewOccupancy returns an occupancy with no chronons counted for a grid of the given size.
+func NewOccupancy(width, height int) *Occupancy {
+	return &Occupancy{Width: width, Height: height, Fish: make([]int, width*height), Sharks: make([]int, width*height)}
+}
+
+// CreatureInfo describes one creature, as returned by Simulation.Inspect.
+type CreatureInfo struct {
+	Kind           Cell // Fish, Shark or Land.
+	X, Y           int  // Position on the grid.
+	BreedTimer     int  // Moves made since the creature last bred.
+	BreedThreshold int  // Moves the creature makes before it breeds; its own inherited threshold when Config.Mutation is set.
+	Starve         int  // Moves a shark has made since it last ate; always 0 for fish.
+	Age            int  // Chronons the creature has lived through since it was born or placed.
+	Infected       bool // Whether a fish has caught the disease.
+}
@@ -0,0 +1,107 @@
+package wator
+
+import (
+	"fmt" // Formats the description of a broken invariant.
+)
+
+// InvariantError describes a broken invariant found by Simulation.Check.
+type InvariantError struct {
+	Chronon int    // The chronon after which the invariant was broken.
+	X, Y    int    // The cell where the problem was found.
+	Problem string // What is wrong.
+}
+
+// Error describes the problem and where it was found.
+func (e *InvariantError) Error() string {
+	return fmt.Sprintf("chronon %d: cell (%d, %d): %s", e.Chronon, e.X, e.Y, e.Problem)
+}
+
+// Check verifies the simulation's invariants and returns an *InvariantError describing the first one broken, or nil.
+// It scans every cell and creature, so it is meant for debugging runs rather than for every production chronon.
+//
+// Functionality:
+//  1. Every fish and shark listed by a partition is alive, of the right kind, inside that partition and on the grid
+//     at its own position, so no cell holds two creatures and none has been overwritten.
+//  2. Every creature on the grid records the cell it is in, and the grid holds exactly as many fish and sharks as
+//     the lists, so none is listed twice or missing from its list.
+//  3. Breed timers and starve counters are below the largest threshold in effect since the simulation started, or
+//     breed timers below the creature's own threshold when traits evolve.
+//     Counters are only checked against a threshold when a creature moves, so lowering one with SetParams leaves
+//     creatures that could not move with counters above it.
+//  4. Only fish are infected, and none has outlived the disease's lifetime.
+func (s *Simulation) Check() error {
+	fail := func(x, y int, format string, args ...any) error {
+		return &InvariantError{Chronon: s.chronon, X: x, Y: y, Problem: fmt.Sprintf(format, args...)}
+	}
+
+	listed := map[Cell]int{}
+	for i, p := range s.partitions {
+		for _, list := range []struct {
+			kind      Cell
+			creatures []*creature
+		}{{Fish, p.fish}, {Shark, p.sharks}} {
+			for _, c := range list.creatures {
+				switch {
+				case c.kind != list.kind:
+					return fail(c.x, c.y, "%s in partition %d's %s list", c.kind, i, list.kind)
+				case c.dead:
+					return fail(c.x, c.y, "dead %s still listed by partition %d", c.kind, i)
+				case c.x < 0 || c.x >= s.cfg.Width || c.y < 0 || c.y >= s.cfg.Height:
+					return fail(c.x, c.y, "%s outside the grid", c.kind)
+				case !p.contains(c.x, c.y):
+					return fail(c.x, c.y, "%s listed by partition %d, which does not contain it", c.kind, i)
+				case s.grid[c.x][c.y] != c:
+					return fail(c.x, c.y, "%s listed here but the cell holds %s", c.kind, s.cellKind(c.x, c.y))
+				case c.breedTimer < 0 || c.breedTimer >= s.breedLimit(c):
+					return fail(c.x, c.y, "%s breed timer %d outside [0, %d)", c.kind, c.breedTimer, s.breedLimit(c))
+				case s.cfg.Mutation > 0 && c.breedAt < 1:
+					return fail(c.x, c.y, "%s inherited breed threshold %d below 1", c.kind, c.breedAt)
+				case c.infected && (c.kind != Fish || c.infectedAt > s.chronon || s.chronon-c.infectedAt >= s.cfg.Disease.Lifetime):
+					return fail(c.x, c.y, "%s infected at chronon %d with a lifetime of %d", c.kind, c.infectedAt, s.cfg.Disease.Lifetime)
+				case c.kind == Shark && (c.starve < 0 || c.starve >= s.maxParams.SharkStarve):
+					return fail(c.x, c.y, "shark starve counter %d outside [0, %d)", c.starve, s.maxParams.SharkStarve)
+				}
+				listed[c.kind]++
+			}
+		}
+	}
+
+	onGrid := map[Cell]int{}
+	for x, column := range s.grid {
+		for y, c := range column {
+			if c == nil || c.kind == Land {
+				continue
+			}
+			if c.x != x || c.y != y {
+				return fail(x, y, "%s on the grid records its position as (%d, %d)", c.kind, c.x, c.y)
+			}
+			onGrid[c.kind]++
+		}
+	}
+	for _, kind := range []Cell{Fish, Shark} {
+		if listed[kind] != onGrid[kind] {
+			return fail(0, 0, "%d %s listed but %d on the grid", listed[kind], kind, onGrid[kind])
+		}
+	}
+	return nil
+}
+
+// cellKind returns the kind of creature in the cell (x, y), or Empty.
+func (s *Simulation) cellKind(x, y int) Cell {
+	if c := s.grid[x][y]; c != nil {
+		return c.kind
+	}
+	return Empty
+}
+
+// breedLimit returns the breed threshold c's breed timer must stay below: its own when traits evolve, otherwise the
+// largest for its kind in effect since the simulation started.
+func (s *Simulation) breedLimit(c *creature) int {
+	if s.cfg.Mutation > 0 {
+		return c.breedAt
+	}
+	if c.kind == Fish {
+		return s.maxParams.FishBreed
+	}
+	return s.maxParams.SharkBreed
+}
@@ -0,0 +1,55 @@
+package wator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckFindsCorruption(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(s *Simulation)
+	}{
+		{"two creatures in a cell", func(s *Simulation) {
+			f := s.partitions[0].fish[0]
+			s.grid[f.x][f.y] = &creature{kind: Shark, x: f.x, y: f.y}
+		}},
+		{"listed twice", func(s *Simulation) {
+			p := s.partitions[0]
+			p.fish = append(p.fish, p.fish[0])
+		}},
+		{"missing from its list", func(s *Simulation) {
+			p := s.partitions[0]
+			p.sharks = p.sharks[1:]
+		}},
+		{"starve counter too high", func(s *Simulation) {
+			s.partitions[0].sharks[0].starve = s.cfg.SharkStarve
+		}},
+		{"dead but listed", func(s *Simulation) {
+			s.partitions[0].fish[0].dead = true
+		}},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Seed = 1
+		s, _ := New(cfg)
+		s.Step()
+		tt.corrupt(s)
+		var invariant *InvariantError
+		if err := s.Check(); !errors.As(err, &invariant) || invariant.Chronon != 1 {
+			t.Errorf("%s: Check returned %v", tt.name, err)
+		}
+	}
+
+	// Lowering a threshold leaves counters above it until the creatures next move, which is not corruption.
+	cfg := DefaultConfig()
+	cfg.Seed = 1
+	s, _ := New(cfg)
+	for i := 0; i < 3; i++ {
+		s.Step()
+	}
+	s.SetParams(Params{FishBreed: 1, SharkBreed: 1, SharkStarve: 1})
+	if err := s.Check(); err != nil {
+		t.Errorf("after lowering the thresholds: %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package wator
+
+import (
+	"fmt" // Formats configuration errors.
+)
+
+// MaxSightRadius is the largest SightRadius a Config accepts. Larger radii scan thousands of cells per shark.
+const MaxSightRadius = 10
+
+// MaxCrowding is the largest Crowding a Config accepts. A cell a shark moves to has the shark's own cell as one of its
+// four neighbours, so at most three other sharks can surround it.
+const MaxCrowding = 3
+
+// Params holds the breeding and starvation thresholds, measured in chronons.
+// They can be changed while a simulation runs with Simulation.SetParams.
+type Params struct {
+	FishBreed   int // Moves a fish makes before it breeds.
+	SharkBreed  int // Moves a shark makes before it breeds.
+	SharkStarve int // Moves a shark can make without eating before it starves.
+}
+
+// Validate reports an error if any threshold is below one.
+func (p Params) Validate() error {
+	if p.FishBreed < 1 || p.SharkBreed < 1 || p.SharkStarve < 1 {
+		return fmt.Errorf("breed and starve times must be at least 1, got fish breed %d, shark breed %d, shark starve %d",
+			p.FishBreed, p.SharkBreed, p.SharkStarve)
+	}
+	return nil
+}
+
+// Config describes a simulation.
+type Config struct {
+	Width   int // Number of cells in the x direction.
+	Height  int // Number of cells in the y direction.
+	Threads int // Number of partitions stepped concurrently; 1 runs everything on the calling goroutine.
+
+	Params // Breeding and starvation thresholds.
+
+	SightRadius int   // How far sharks can see fish; sharks hunt the nearest visible fish when it is 2 or more.
+	Crowding    int   // Makes sharks territorial: a shark moving to an empty cell tries cells next to this many other sharks last; 0 disables it.
+	Seed        int64 // Seeds the starting population and the partitions' moves; 0 picks a seed from the clock, which Simulation.Config reports.
+
+	// Deterministic steps the partitions one after another in a fixed order instead of concurrently,
+	// so that a run is reproducible from its Config even when Threads is above one. See the package documentation.
+	Deterministic bool
+
+	// InstrumentLocks makes every boundary mutex a barrier.Mutex, which times how long each is held and waited for, so
+	// Stats.Boundaries shows which boundaries the partitions fight over. It reads the clock on every boundary lock, so
+	// leave it off when measuring the frame rate.
+	InstrumentLocks bool
+
+	Engine Engine // How the partitions run concurrently when Threads is above one; the zero value is PersistentWorkers.
+
+	Order Order // Whether fish or sharks move first each chronon, or both in a random order; the zero value is FishFirst.
+
+	Disease Disease // Optional infection among the fish; off unless Disease.Lifetime is set.
+
+	// Mutation makes the breed thresholds heritable, turning the world into a simple evolution sandbox: each newborn's
+	// threshold is its parent's changed by a random amount of up to Mutation chronons either way, and never below one.
+	// The starting creatures take FishBreed and SharkBreed, and SetParams no longer changes their thresholds.
+	// 0 disables evolution.
+	Mutation int
+
+	// The random starting population, used when Layout is nil. DefaultConfig sets the densities of the original
+	// versions, about 6% of cells starting with a fish and 1% with a shark; a Config built without it starts empty.
+	Distribution Distribution // Where the creatures are placed.
+	FishDensity  float64      // Average fraction of cells that start with a fish.
+	SharkDensity float64      // Average fraction of cells that start with a shark.
+
+	Layout *Snapshot // Optional starting layout; when set it replaces the random population and must match Width and Height.
+}
+
+// DefaultConfig returns the configuration of the original single-threaded version: a 50x50 grid with every
+// threshold at 5 chronons.
+func DefaultConfig() Config {
+	return Config{
+		Width:   50,
+		Height:  50,
+		Threads: 1,
+		Params:  Params{FishBreed: 5, SharkBreed: 5, SharkStarve: 5},
+
+		FishDensity:  0.06,
+		SharkDensity: 0.01,
+	}
+}
+
+// Validate reports an error if the configuration cannot be simulated.
+//
+// Functionality:
+// Besides checking every value is in range, Validate makes sure the grid can be split into cfg.Threads partitions
+// that are at least two cells wide (and, when the grid is also split horizontally, two cells tall), so each partition's
+// boundary cells are distinct from its neighbours'.
+func (c Config) Validate() error {
+	if c.Width < 1 || c.Height < 1 {
+		return fmt.Errorf("grid size must be at least 1x1, got %dx%d", c.Width, c.Height)
+	}
+	if c.Threads < 1 {
+		return fmt.Errorf("thread count must be at least 1, got %d", c.Threads)
+	}
+	cols, rows := partitionLayout(c.Threads)
+	if (cols > 1 && c.Width < 2*cols) || (rows > 1 && c.Height < 2*rows) {
+		return fmt.Errorf("a %dx%d grid is too small for %d threads (%dx%d partitions of at least 2x2 cells)",
+			c.Width, c.Height, c.Threads, cols, rows)
+	}
+	if err := c.Params.Validate(); err != nil {
+		return err
+	}
+	if c.SightRadius < 0 || c.SightRadius > MaxSightRadius {
+		return fmt.Errorf("sight radius must be between 0 and %d, got %d", MaxSightRadius, c.SightRadius)
+	}
+	if c.Mutation < 0 {
+		return fmt.Errorf("mutation must not be negative, got %d", c.Mutation)
+	}
+	if err := c.Disease.Validate(); err != nil {
+		return err
+	}
+	if c.Crowding < 0 || c.Crowding > MaxCrowding {
+		return fmt.Errorf("crowding must be between 0 and %d, got %d", MaxCrowding, c.Crowding)
+	}
+	if c.Engine < PersistentWorkers || c.Engine > WorkStealing {
+		return fmt.Errorf("unknown engine %v", c.Engine)
+	}
+	if c.Order < FishFirst || c.Order > Interleaved {
+		return fmt.Errorf("unknown order %v", c.Order)
+	}
+	if c.Distribution < Uniform || c.Distribution > Colony {
+		return fmt.Errorf("unknown distribution %v", c.Distribution)
+	}
+	if c.FishDensity < 0 || c.SharkDensity < 0 || c.FishDensity+c.SharkDensity > 1 {
+		return fmt.Errorf("densities must not be negative or add up to more than 1, got fish %g and sharks %g", c.FishDensity, c.SharkDensity)
+	}
+	if c.Layout != nil && (c.Layout.Width != c.Width || c.Layout.Height != c.Height) {
+		return fmt.Errorf("layout is %dx%d but the grid is %dx%d", c.Layout.Width, c.Layout.Height, c.Width, c.Height)
+	}
+	return nil
+}
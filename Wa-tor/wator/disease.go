@@ -0,0 +1,100 @@
+package wator
+
+import (
+	"fmt"       // Formats errors for invalid disease settings.
+	"math/rand" // Picks the fish infected at the start.
+)
+
+// Disease describes an optional infection among the fish. Infected fish pass it to the fish next to them and die a
+// fixed number of chronons after catching it. Sharks neither catch it nor carry it, and newborn fish start healthy.
+// The zero value, with Lifetime 0, turns the disease off.
+type Disease struct {
+	Fraction float64 // Fraction of the starting fish that are infected.
+	Spread   float64 // Chance that an infected fish infects each healthy fish next to it every chronon.
+	Lifetime int     // Chronons an infected fish lives after catching the infection; 0 turns the disease off.
+}
+
+// Enabled reports whether the disease is switched on.
+func (d Disease) Enabled() bool {
+	return d.Lifetime > 0
+}
+
+// Validate reports an error if the disease settings are out of range.
+func (d Disease) Validate() error {
+	if d.Lifetime < 0 {
+		return fmt.Errorf("infection lifetime must not be negative, got %d", d.Lifetime)
+	}
+	if d.Fraction < 0 || d.Fraction > 1 || d.Spread < 0 || d.Spread > 1 {
+		return fmt.Errorf("infected fraction and spread must be between 0 and 1, got %g and %g", d.Fraction, d.Spread)
+	}
+	return nil
+}
+
+// infectStart infects each starting fish with probability Disease.Fraction, drawing from rng.
+func (s *Simulation) infectStart(rng *rand.Rand) {
+	for _, p := range s.partitions {
+		for _, fish := range p.fish {
+			if rng.Float64() < s.cfg.Disease.Fraction {
+				fish.infected = true
+			}
+		}
+	}
+}
+
+// sicken runs a chronon of illness for an infected fish before it moves, and reports whether the fish died of it.
+//
+// Input:
+//   - p (*partition): The partition stepping the fish; its random stream decides which neighbours catch the infection.
+//   - fish (*creature): An infected fish.
+//
+// Output:
+//   - bool: Whether the fish died, in which case its cell has been cleared, or was eaten while it was being stepped.
+//
+// Functionality:
+//  1. Fish infected during this chronon are left alone until the next one, so the infection spreads at most one cell
+//     per chronon whatever order the fish are stepped in.
+//  2. A fish that has been ill for Disease.Lifetime chronons dies, clearing its cell under the mutexes guarding it.
+//  3. Otherwise each healthy fish next to it catches the infection with probability Disease.Spread. Looking at a cell
+//     on a partition boundary holds the mutexes guarding it, as a move would.
+func (s *Simulation) sicken(p *partition, fish *creature) bool {
+	if fish.infectedAt > s.chronon {
+		return false
+	}
+	if s.chronon+1-fish.infectedAt >= s.cfg.Disease.Lifetime {
+		var locks cellLocks
+		p.guard(&locks, fish.x, fish.y)
+		p.lock(&locks)
+		if !fish.dead { // Otherwise a shark in a neighbouring partition has just eaten it and taken its cell.
+			fish.dead = true
+			s.grid[fish.x][fish.y] = nil
+			p.diseaseDeaths++
+		}
+		locks.unlock()
+		return true
+	}
+	for direction := north; direction <= west; direction++ {
+		x, y := s.neighbour(fish.x, fish.y, direction)
+		locks := p.locksFor(direction, fish.x, fish.y, x, y)
+		p.lock(&locks)
+		if c := s.grid[x][y]; c != nil && c.kind == Fish && !c.infected && p.rng.Float64() < s.cfg.Disease.Spread {
+			c.infected = true
+			c.infectedAt = s.chronon + 1
+		}
+		locks.unlock()
+	}
+	return false
+}
+
+// Infected returns the number of living infected fish.
+// Like Population it does not allocate, so it can be called after every chronon to follow an epidemic.
+func (s *Simulation) Infected() int {
+	infected := 0
+	for _, p := range s.partitions {
+		for _, fish := range p.fish {
+			if fish.infected {
+				infected++
+			}
+		}
+	}
+	return infected
+}
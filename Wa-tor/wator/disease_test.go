@@ -0,0 +1,61 @@
+package wator
+
+import "testing"
+
+func TestDiseaseKillsInfectedFish(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Seed = 1
+	cfg.SharkDensity = 0
+	cfg.Disease = Disease{Fraction: 1, Lifetime: 3}
+	s, _ := New(cfg)
+	fish, _ := s.Population()
+	if s.Infected() != fish || s.Snapshot().Count(Fish) != fish {
+		t.Fatalf("%d of %d starting fish infected, want all", s.Infected(), fish)
+	}
+	for i := 0; i < 3; i++ {
+		s.Step()
+		if err := s.Check(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Without spread the newborns stay healthy, and every starting fish has died of the disease.
+	if stats := s.Stats(); stats.Infected != 0 || stats.DiseaseDeaths != int64(fish) {
+		t.Errorf("after the lifetime %d fish infected and %d died of the disease, want 0 and %d", stats.Infected, stats.DiseaseDeaths, fish)
+	}
+}
+
+func TestDiseaseSpreads(t *testing.T) {
+	// A row of fish walled in by land, so none can move and the infection passes along the row one fish per chronon.
+	layout := NewSnapshot(6, 3)
+	for x := 0; x < 6; x++ {
+		layout.Set(x, 0, Land)
+		layout.Set(x, 1, Fish)
+		layout.Set(x, 2, Land)
+	}
+	layout.Set(5, 1, Land)
+	cfg := DefaultConfig()
+	cfg.Width, cfg.Height = 6, 3
+	cfg.Layout = layout
+	cfg.Disease = Disease{Spread: 1, Lifetime: 10}
+	s, _ := New(cfg)
+	s.grid[0][1].infected = true
+
+	for chronon := 1; chronon <= 4; chronon++ {
+		s.Step()
+		if err := s.Check(); err != nil {
+			t.Fatal(err)
+		}
+		if got := s.Infected(); got != chronon+1 {
+			t.Fatalf("chronon %d: %d fish infected, want %d", chronon, got, chronon+1)
+		}
+	}
+	snap := s.Snapshot()
+	for x := 0; x < 5; x++ {
+		if !snap.Infected[snap.Width+x] {
+			t.Errorf("fish at (%d, 1) is not marked infected in the snapshot", x)
+		}
+	}
+	if info, ok := s.Inspect(4, 1); !ok || !info.Infected {
+		t.Errorf("Inspect(4, 1) = %+v, want an infected fish", info)
+	}
+}
@@ -0,0 +1,120 @@
+package wator
+
+import (
+	"fmt"       // Formats errors for unknown distribution names.
+	"math"      // Provides the square root and pi for disc radii.
+	"math/rand" // Places the cluster centres.
+	"strings"   // Lists the distribution names in errors.
+)
+
+// Distribution chooses where the starting fish and sharks are placed when Config.Layout is not set.
+// The starting arrangement strongly affects how a Wa-Tor world develops: a uniform scatter settles quickly into
+// small local cycles, whereas separated populations produce travelling fronts and waves.
+//
+// Every distribution places about Config.FishDensity fish and Config.SharkDensity sharks per cell over the grid as a
+// whole, concentrating them where the distribution puts them. Where that would exceed one creature per cell the
+// densities there are scaled down, so very dense configurations place fewer creatures than asked.
+type Distribution int
+
+// The starting distributions.
+const (
+	Uniform  Distribution = iota // Every cell independently, as in the original versions.
+	Clusters                     // clusterCount round blobs covering a quarter of the grid, at random positions.
+	Stripes                      // Alternating vertical bands of fish and sharks, stripeCount of each.
+	Gradient                     // Density rising from nothing at the left edge to double at the right.
+	Colony                       // A single disc in the centre covering a sixteenth of the grid, left to spread.
+)
+
+// Shape constants for the distributions.
+const (
+	clusterCount    = 5  // Number of blobs placed by Clusters.
+	clusterFraction = 4  // Clusters cover one cell in clusterFraction.
+	stripeCount     = 5  // Number of fish stripes, and of shark stripes, placed by Stripes.
+	colonyFraction  = 16 // The Colony disc covers one cell in colonyFraction.
+)
+
+// distributionNames lists the names accepted by ParseDistribution, indexed by Distribution.
+var distributionNames = []string{"uniform", "clusters", "stripes", "gradient", "colony"}
+
+// String returns the name of the distribution, as accepted by ParseDistribution.
+func (d Distribution) String() string {
+	if d >= 0 && int(d) < len(distributionNames) {
+		return distributionNames[d]
+	}
+	return fmt.Sprintf("Distribution(%d)", int(d))
+}
+
+// ParseDistribution returns the distribution with the given name.
+func ParseDistribution(name string) (Distribution, error) {
+	for i, n := range distributionNames {
+		if n == name {
+			return Distribution(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown distribution %q (want one of %s)", name, strings.Join(distributionNames, ", "))
+}
+
+// weights returns a function giving how strongly fish and sharks are concentrated at each cell of a width by height
+// grid, relative to the configured densities. Each weight averages about one over the grid.
+// rng places the cluster centres, so it must be the source that then populates the grid.
+func (d Distribution) weights(width, height int, rng *rand.Rand) func(x, y int) (fish, sharks float64) {
+	switch d {
+	case Clusters:
+		radius := discRadius(width, height, clusterFraction*clusterCount)
+		centres := make([][2]int, clusterCount)
+		for i := range centres {
+			centres[i] = [2]int{rng.Intn(width), rng.Intn(height)}
+		}
+		return func(x, y int) (float64, float64) {
+			for _, c := range centres {
+				if torusDistance(x, y, c[0], c[1], width, height) <= radius {
+					return clusterFraction, clusterFraction
+				}
+			}
+			return 0, 0
+		}
+	case Stripes:
+		return func(x, y int) (float64, float64) {
+			if x*2*stripeCount/width%2 == 0 {
+				return 2, 0
+			}
+			return 0, 2
+		}
+	case Gradient:
+		return func(x, y int) (float64, float64) {
+			w := 2 * (float64(x) + 0.5) / float64(width)
+			return w, w
+		}
+	case Colony:
+		radius := discRadius(width, height, colonyFraction)
+		return func(x, y int) (float64, float64) {
+			if torusDistance(x, y, width/2, height/2, width, height) <= radius {
+				return colonyFraction, colonyFraction
+			}
+			return 0, 0
+		}
+	}
+	return func(x, y int) (float64, float64) { return 1, 1 }
+}
+
+// discRadius returns the radius of a disc covering one cell in fraction of a width by height grid, at least one cell.
+func discRadius(width, height, fraction int) float64 {
+	return max(1, math.Sqrt(float64(width*height)/float64(fraction)/math.Pi))
+}
+
+// torusDistance returns the straight-line distance between two cells, wrapping around the edges of the grid.
+func torusDistance(x1, y1, x2, y2, width, height int) float64 {
+	dx := abs(x1 - x2)
+	dy := abs(y1 - y2)
+	dx = min(dx, width-dx)
+	dy = min(dy, height-dy)
+	return math.Hypot(float64(dx), float64(dy))
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,73 @@
+package wator
+
+import "testing"
+
+func TestDistributions(t *testing.T) {
+	newSnapshot := func(d Distribution) Snapshot {
+		cfg := DefaultConfig()
+		cfg.Width, cfg.Height = 200, 100
+		cfg.Seed = 1
+		cfg.Distribution = d
+		s, err := New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s.Snapshot()
+	}
+	// countIn returns the number of cells holding c among those for which in is true.
+	countIn := func(snap Snapshot, c Cell, in func(x, y int) bool) int {
+		n := 0
+		for y := 0; y < snap.Height; y++ {
+			for x := 0; x < snap.Width; x++ {
+				if snap.At(x, y) == c && in(x, y) {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	all := func(x, y int) bool { return true }
+
+	if fish := newSnapshot(Uniform).Count(Fish); fish < 1000 || fish > 1400 {
+		t.Errorf("uniform: %d fish, want about 1200", fish)
+	}
+
+	stripes := newSnapshot(Stripes)
+	fishStripe := func(x, y int) bool { return x/20%2 == 0 }
+	if n := countIn(stripes, Shark, fishStripe); n != 0 {
+		t.Errorf("stripes: %d sharks in the fish stripes", n)
+	}
+	if n := countIn(stripes, Fish, all) - countIn(stripes, Fish, fishStripe); n != 0 {
+		t.Errorf("stripes: %d fish in the shark stripes", n)
+	}
+
+	gradient := newSnapshot(Gradient)
+	left, right := countIn(gradient, Fish, func(x, y int) bool { return x < 100 }), countIn(gradient, Fish, func(x, y int) bool { return x >= 100 })
+	if 2*left > right {
+		t.Errorf("gradient: %d fish in the left half and %d in the right, want about three times as many on the right", left, right)
+	}
+
+	colony := newSnapshot(Colony)
+	outside := func(x, y int) bool {
+		return torusDistance(x, y, 100, 50, 200, 100) > discRadius(200, 100, colonyFraction)
+	}
+	if n := countIn(colony, Fish, outside) + countIn(colony, Shark, outside); n != 0 {
+		t.Errorf("colony: %d creatures outside the disc", n)
+	}
+	if n := colony.Count(Fish); n < 1000 {
+		t.Errorf("colony: only %d fish", n)
+	}
+
+	if n := newSnapshot(Clusters).Count(Fish); n < 600 {
+		t.Errorf("clusters: only %d fish", n)
+	}
+
+	for d := Uniform; d <= Colony; d++ {
+		if parsed, err := ParseDistribution(d.String()); err != nil || parsed != d {
+			t.Errorf("ParseDistribution(%q) = %v, %v", d.String(), parsed, err)
+		}
+	}
+	if _, err := ParseDistribution("spiral"); err == nil {
+		t.Error("an unknown distribution name was accepted")
+	}
+}
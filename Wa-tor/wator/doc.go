@@ -0,0 +1,52 @@
+// Package wator simulates Wa-Tor, the predator-prey world of fish and sharks on a toroidal ocean.
+//
+// The package has no graphics dependency, so it can be embedded in tools, tests and headless batch runs.
+// Rendering lives in the wator/render subpackage.
+//
+// A simulation is created from a Config and advanced one chronon at a time:
+//
+//	cfg := wator.DefaultConfig()
+//	cfg.Width, cfg.Height, cfg.Threads = 200, 200, 4
+//	sim, err := wator.New(cfg)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer sim.Close() // Stops the goroutines stepping the partitions.
+//	for i := 0; i < 1000; i++ {
+//		sim.Step()
+//	}
+//	stats := sim.Stats()
+//	fmt.Println(stats.Chronon, stats.Fish, stats.Sharks)
+//
+// With Threads above one the grid is split into partitions that are stepped concurrently. Entities that move across
+// a partition boundary lock a mutex shared by the two partitions, as in the original multi-threaded versions, and so
+// does every move, birth, meal or infection touching a cell on a boundary, so a newborn never appears in a cell a
+// neighbouring partition is moving into. Hunting and territorial sharks look further than the cells next to them, into
+// cells a neighbouring partition writes without locks, so they read a copy of the grid recorded at the end of the
+// previous chronon instead; no partition reads a cell while another is writing it, which running the package's tests
+// with -race checks. The time spent waiting for the locks is reported in Stats. Each partition owns the fish and
+// sharks inside it, so the births, deaths and moves between partitions are merged by the partitions themselves, also
+// concurrently.
+// Stats.Buckets reports how many creatures each partition owns and how many it has handed to its neighbours. Each
+// partition is stepped by a worker goroutine kept for the life of the simulation, and the workers meet at a barrier
+// between the phases of a chronon; Close stops them.
+//
+// # Determinism
+//
+// Every random choice is drawn from a source seeded by Config.Seed: the starting population from Seed itself, and
+// the moves made in partition i from its own stream seeded with Seed+i. Partitions never share a source, so the
+// goroutines do not contend for one, and the numbers a partition draws do not depend on how the others are scheduled.
+// A simulation's Config (seed chosen from the clock included) is reported by Simulation.Config.
+//
+// The guarantees are:
+//   - With Threads set to one, the same Config always produces the same sequence of grids.
+//   - With Deterministic set, the same also holds for any number of threads: partitions are stepped one after another
+//     in index order, so a run with Threads set to 4 is reproducible, although it is no faster than one thread and
+//     its grids differ from a Threads=1 run because the partitions draw from different streams.
+//   - Otherwise partitions run concurrently. A creature moving across a boundary may find its target cell taken or
+//     free depending on which side reached it first, so two runs with the same Config can diverge from the first
+//     contested boundary move onwards. Each partition's stream is still reproducible, so runs agree until then.
+//
+// The exported API (Config, Params, New, Simulation and its methods, Cell, Snapshot and Stats) is stable:
+// fields and methods may be added, but existing ones keep their meaning.
+package wator
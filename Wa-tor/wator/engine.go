@@ -0,0 +1,43 @@
+package wator
+
+import (
+	"fmt"     // Formats errors for unknown engine names.
+	"strings" // Lists the engine names in errors.
+)
+
+// Engine chooses how a simulation with more than one thread runs its partitions concurrently. Both give the same kind
+// of world; neither is reproducible, which needs Config.Deterministic, under which the engine is not used.
+type Engine int
+
+// The execution engines.
+const (
+	// PersistentWorkers gives each partition its own goroutine, started by the first Step and kept until Close,
+	// meeting the others at a barrier between the phases of a chronon.
+	PersistentWorkers Engine = iota
+	// WorkStealing runs every phase of every partition as a task on a pool of Threads workers from Barrier/pool, each
+	// with its own deque of tasks, which steal from one another when their own runs out. Each worker is dealt one
+	// partition a phase, so it only steals when another is slow to take its partition, such as when the other's
+	// thread has been descheduled.
+	WorkStealing
+)
+
+// engineNames lists the names accepted by ParseEngine, indexed by Engine.
+var engineNames = []string{"workers", "pool"}
+
+// String returns the name of the engine, as accepted by ParseEngine.
+func (e Engine) String() string {
+	if e >= 0 && int(e) < len(engineNames) {
+		return engineNames[e]
+	}
+	return fmt.Sprintf("Engine(%d)", int(e))
+}
+
+// ParseEngine returns the engine with the given name.
+func ParseEngine(name string) (Engine, error) {
+	for i, n := range engineNames {
+		if n == name {
+			return Engine(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown engine %q (want one of %s)", name, strings.Join(engineNames, ", "))
+}
@@ -0,0 +1,51 @@
+package wator
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestEngine(t *testing.T) {
+	for _, threads := range []int{2, 4, 8} {
+		before := runtime.NumGoroutine()
+		cfg := DefaultConfig()
+		cfg.Threads = threads
+		cfg.Seed = 5
+		cfg.Engine = WorkStealing
+		s, err := New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 100; i++ {
+			s.Step()
+			if err := s.Check(); err != nil {
+				t.Fatalf("pool, %d threads, chronon %d: %v", threads, i+1, err)
+			}
+		}
+		if s.chronons != nil {
+			t.Errorf("pool, %d threads: the persistent workers were started too", threads)
+		}
+		s.Close()
+		s.Close()
+		for deadline := time.Now().Add(5 * time.Second); runtime.NumGoroutine() > before; time.Sleep(time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("pool, %d threads: %d goroutines after Close, want %d", threads, runtime.NumGoroutine(), before)
+			}
+		}
+	}
+
+	for _, name := range engineNames {
+		if engine, err := ParseEngine(name); err != nil || engine.String() != name {
+			t.Errorf("ParseEngine(%q) = %v, %v", name, engine, err)
+		}
+	}
+	if _, err := ParseEngine("threads"); err == nil {
+		t.Error("an unknown engine was accepted")
+	}
+	cfg := DefaultConfig()
+	cfg.Engine = Engine(7)
+	if err := cfg.Validate(); err == nil {
+		t.Error("a config with an unknown engine was accepted")
+	}
+}
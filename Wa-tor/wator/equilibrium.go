@@ -0,0 +1,103 @@
+package wator
+
+import (
+	"fmt"  // Formats errors for invalid detector settings.
+	"math" // Provides the square root for standard deviations.
+)
+
+// EquilibriumDetector reports when the fish and shark populations have settled, so that long runs can stop once
+// nothing new is happening and the time taken to settle can be compared between configurations.
+//
+// A population counts as settled when, over the last Window chronons, either
+//   - it barely varies: its standard deviation is below Threshold times its mean (a steady state, including extinction), or
+//   - it oscillates regularly: the mean and standard deviation of the first and second halves of the window differ by
+//     less than Threshold times the mean (a limit cycle). Halves must each span at least one full cycle for this to
+//     be recognised, so the window should be at least twice the longest period expected.
+//
+// Equilibrium is reached when both populations have settled.
+type EquilibriumDetector struct {
+	window    int       // Number of chronons examined.
+	threshold float64   // Largest variation allowed, relative to the mean population.
+	fish      []float64 // The fish population over the last window chronons, as a ring buffer.
+	sharks    []float64 // The shark population over the last window chronons, as a ring buffer.
+	count     int       // Number of chronons observed.
+	reached   int       // First chronon of the window in which equilibrium was detected, or -1.
+}
+
+// NewEquilibriumDetector returns a detector examining the last window chronons.
+// The threshold is relative to the mean population: 0.05 allows variations of about 5%.
+func NewEquilibriumDetector(window int, threshold float64) (*EquilibriumDetector, error) {
+	if window < 4 {
+		return nil, fmt.Errorf("equilibrium window must be at least 4 chronons, got %d", window)
+	}
+	if threshold <= 0 {
+		return nil, fmt.Errorf("equilibrium threshold must be positive, got %g", threshold)
+	}
+	return &EquilibriumDetector{
+		window:    window,
+		threshold: threshold,
+		fish:      make([]float64, window),
+		sharks:    make([]float64, window),
+		reached:   -1,
+	}, nil
+}
+
+// Observe records the populations at the end of a chronon and reports whether equilibrium has been reached.
+// Once reached it stays reached, so Observe can be called every chronon and checked cheaply.
+func (d *EquilibriumDetector) Observe(chronon, fish, sharks int) bool {
+	if d.reached >= 0 {
+		return true
+	}
+	i := d.count % d.window
+	d.fish[i] = float64(fish)
+	d.sharks[i] = float64(sharks)
+	d.count++
+	if d.count < d.window {
+		return false
+	}
+
+	oldest := d.count % d.window
+	if d.settled(d.fish, oldest) && d.settled(d.sharks, oldest) {
+		d.reached = chronon - d.window + 1
+		return true
+	}
+	return false
+}
+
+// Reached returns the chronon by which the populations had settled, which is the first chronon of the window in
+// which equilibrium was detected, and whether equilibrium has been reached at all.
+func (d *EquilibriumDetector) Reached() (int, bool) {
+	return d.reached, d.reached >= 0
+}
+
+// settled reports whether the population in the ring buffer, whose oldest value is at index oldest, has settled.
+func (d *EquilibriumDetector) settled(ring []float64, oldest int) bool {
+	half := d.window / 2
+	mean, std := meanStd(ring, oldest, d.window)
+	if mean == 0 {
+		return true // Populations cannot be negative, so the species has been extinct for the whole window.
+	}
+	if std < d.threshold*mean {
+		return true
+	}
+
+	mean1, std1 := meanStd(ring, oldest, half)
+	mean2, std2 := meanStd(ring, oldest+d.window-half, half) // The most recent half; the middle value is skipped for odd windows.
+	return math.Abs(mean1-mean2) < d.threshold*mean && math.Abs(std1-std2) < d.threshold*mean
+}
+
+// meanStd returns the mean and standard deviation of the n values of the ring buffer starting at index start.
+func meanStd(ring []float64, start, n int) (float64, float64) {
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		sum += ring[(start+i)%len(ring)]
+	}
+	mean := sum / float64(n)
+
+	squares := 0.0
+	for i := 0; i < n; i++ {
+		diff := ring[(start+i)%len(ring)] - mean
+		squares += diff * diff
+	}
+	return mean, math.Sqrt(squares / float64(n))
+}
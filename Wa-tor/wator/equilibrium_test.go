@@ -0,0 +1,40 @@
+package wator
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEquilibriumDetector(t *testing.T) {
+	tests := []struct {
+		name    string
+		fish    func(chronon int) int
+		settles bool
+	}{
+		{"steady", func(c int) int { return 1000 + c%3 }, true},
+		{"extinct", func(c int) int { return 0 }, true},
+		{"cycle", func(c int) int { return int(1000 + 400*math.Sin(float64(c)*2*math.Pi/25)) }, true},
+		{"growing", func(c int) int { return 100 + 5*c }, false},
+		{"widening", func(c int) int { return int(1000 + 2*float64(c)*math.Sin(float64(c)*2*math.Pi/25)) }, false},
+	}
+	for _, tt := range tests {
+		d, err := NewEquilibriumDetector(100, 0.05)
+		if err != nil {
+			t.Fatal(err)
+		}
+		settled := false
+		for c := 1; c <= 150; c++ {
+			settled = d.Observe(c, tt.fish(c), 200)
+		}
+		if settled != tt.settles {
+			t.Errorf("%s: settled = %v, want %v", tt.name, settled, tt.settles)
+		}
+		if at, ok := d.Reached(); ok && tt.settles && at != 1 {
+			t.Errorf("%s: settled by chronon %d, want 1", tt.name, at)
+		}
+	}
+
+	if _, err := NewEquilibriumDetector(2, 0.05); err == nil {
+		t.Error("a 2 chronon window was accepted")
+	}
+}
@@ -0,0 +1,351 @@
+package wator
+
+// Directions a creature can move in.
+const (
+	north = iota
+	south
+	east
+	west
+)
+
+// neighbour returns the cell one step from (x, y) in direction, wrapping around the edges of the grid.
+func (s *Simulation) neighbour(x, y, direction int) (int, int) {
+	switch direction {
+	case north:
+		return x, (y - 1 + s.cfg.Height) % s.cfg.Height
+	case south:
+		return x, (y + 1) % s.cfg.Height
+	case east:
+		return (x + 1) % s.cfg.Width, y
+	}
+	return (x - 1 + s.cfg.Width) % s.cfg.Width, y
+}
+
+// shuffledDirections returns the four directions in random order, so each neighbour is tried exactly once
+// with no bias towards any of them. It is rng.Perm(4) without allocating a slice for every creature.
+func (p *partition) shuffledDirections() [4]int {
+	directions := [4]int{north, south, east, west}
+	p.rng.Shuffle(len(directions), func(i, j int) {
+		directions[i], directions[j] = directions[j], directions[i]
+	})
+	return directions
+}
+
+// runPartition moves every fish and shark that was in partition p at the start of the chronon, in the order set by
+// Config.Order: every fish and then every shark, as in the original versions, the sharks first, or the two mixed in a
+// random order.
+//
+// Input:
+//   - p (*partition): The partition being stepped; newborns are collected in it.
+//   - fishList, sharkList ([]*creature): The partition's fish and sharks.
+func (s *Simulation) runPartition(p *partition, fishList, sharkList []*creature) {
+	switch s.cfg.Order {
+	case SharksFirst:
+		for _, shark := range sharkList {
+			s.moveShark(p, shark)
+		}
+		for _, fish := range fishList {
+			s.moveFish(p, fish)
+		}
+	case Interleaved:
+		for _, c := range p.interleave(fishList, sharkList) {
+			if c.kind == Fish {
+				s.moveFish(p, c)
+			} else {
+				s.moveShark(p, c)
+			}
+		}
+	default:
+		for _, fish := range fishList {
+			s.moveFish(p, fish)
+		}
+		for _, shark := range sharkList {
+			s.moveShark(p, shark)
+		}
+	}
+}
+
+// moveFish runs a fish's turn.
+//
+// Functionality:
+//  1. A fish eaten earlier in the chronon is skipped. An infected fish first dies of the disease or passes it on to
+//     the fish next to it.
+//  2. The fish then tries its four neighbours in random order and moves to the first empty one, leaving a newborn fish
+//     behind when its breed timer is due.
+//  3. A move that touches a cell on a partition boundary, whether to leave the partition, to enter a cell on its
+//     edge or to leave a newborn behind in one, holds the boundary mutexes guarding both cells, so neighbouring
+//     partitions never read a cell while it is being emptied or filled.
+func (s *Simulation) moveFish(p *partition, fish *creature) {
+	if p.eaten(fish) {
+		return // Eaten by a shark earlier in the chronon.
+	}
+	fish.age++
+	if fish.infected && s.sicken(p, fish) {
+		return
+	}
+	for _, direction := range p.shuffledDirections() {
+		x, y := fish.x, fish.y
+		newX, newY := s.neighbour(x, y, direction)
+		locks := p.locksFor(direction, x, y, newX, newY)
+		p.lock(&locks)
+
+		eaten := fish.dead // By a shark in a neighbouring partition since the fish's turn began.
+		moved := !eaten && s.grid[newX][newY] == nil
+		if moved {
+			s.moveTo(fish, newX, newY)
+			fish.breedTimer++
+			if fish.breedTimer >= s.breedThreshold(fish) {
+				fish.breedTimer = 0
+				p.fishBorn = append(p.fishBorn, s.spawn(p, fish, x, y))
+			}
+		}
+
+		locks.unlock()
+		if moved || eaten {
+			return
+		}
+	}
+}
+
+// eaten reports whether a shark has eaten fish, reading its dead flag under the mutexes guarding its cell, which a shark
+// in a neighbouring partition holds while it eats a fish on the boundary. A fish away from every boundary can only be
+// eaten by a shark of p, so no lock is taken for it.
+func (p *partition) eaten(fish *creature) bool {
+	var locks cellLocks
+	p.guard(&locks, fish.x, fish.y)
+	p.lock(&locks)
+	dead := fish.dead
+	locks.unlock()
+	return dead
+}
+
+// moveShark runs a shark's turn.
+//
+// Functionality:
+//  1. The shark first tries its neighbours for a fish to eat.
+//  2. Only if there is none does it move to an empty cell, heading towards the nearest visible fish first when hunting
+//     is enabled and avoiding crowds of sharks when they are territorial.
+//  3. A shark that moves without eating starves once its starve counter reaches the threshold; otherwise it leaves a
+//     newborn behind when its breed timer is due. Boundary cells are locked as for fish.
+func (s *Simulation) moveShark(p *partition, shark *creature) {
+	shark.age++
+	if s.hunt(p, shark) {
+		return
+	}
+	for _, direction := range s.huntingDirections(p, shark.x, shark.y) {
+		x, y := shark.x, shark.y
+		newX, newY := s.neighbour(x, y, direction)
+		locks := p.locksFor(direction, x, y, newX, newY)
+		p.lock(&locks)
+
+		moved := s.grid[newX][newY] == nil
+		if moved {
+			s.moveTo(shark, newX, newY)
+			shark.starve++
+			if shark.starve >= s.cfg.SharkStarve {
+				shark.dead = true
+				s.grid[newX][newY] = nil
+				p.flows.SharksStarved++
+			} else {
+				shark.breedTimer++
+				if shark.breedTimer >= s.breedThreshold(shark) {
+					shark.breedTimer = 0
+					p.sharksBorn = append(p.sharksBorn, s.spawn(p, shark, x, y))
+				}
+			}
+		}
+
+		locks.unlock()
+		if moved {
+			return
+		}
+	}
+}
+
+// hunt moves the shark onto a neighbouring fish and eats it, trying the neighbours in random order.
+// It reports whether the shark ate.
+func (s *Simulation) hunt(p *partition, shark *creature) bool {
+	for _, direction := range p.shuffledDirections() {
+		x, y := shark.x, shark.y
+		newX, newY := s.neighbour(x, y, direction)
+		locks := p.locksFor(direction, x, y, newX, newY)
+		p.lock(&locks)
+
+		prey := s.grid[newX][newY]
+		ate := prey != nil && prey.kind == Fish
+		if ate {
+			prey.dead = true
+			p.flows.FishEaten++
+			s.moveTo(shark, newX, newY)
+			shark.starve = 0
+			shark.breedTimer++
+			if shark.breedTimer >= s.breedThreshold(shark) {
+				shark.breedTimer = 0
+				p.sharksBorn = append(p.sharksBorn, s.spawn(p, shark, x, y))
+			}
+		}
+
+		locks.unlock()
+		if ate {
+			return true
+		}
+	}
+	return false
+}
+
+// moveTo moves c from its cell to (x, y), which the caller has checked is free or holds prey.
+func (s *Simulation) moveTo(c *creature, x, y int) {
+	s.grid[c.x][c.y] = nil
+	c.x, c.y = x, y
+	s.grid[x][y] = c
+}
+
+// spawn places a newborn like parent at (x, y), the cell its parent has just left, inheriting its breed threshold
+// when traits evolve, and counts the birth in the partition's flows.
+func (s *Simulation) spawn(p *partition, parent *creature, x, y int) *creature {
+	c := &creature{kind: parent.kind, x: x, y: y, breedAt: s.inherit(p, parent)}
+	s.grid[x][y] = c
+	if c.kind == Fish {
+		p.flows.FishBorn++
+	} else {
+		p.flows.SharksBorn++
+	}
+	return c
+}
+
+// huntingDirections returns the order in which a shark that found no adjacent fish tries the empty neighbouring cells.
+//
+// Functionality:
+//  1. With hunting disabled (a sight radius below 2) the directions are simply shuffled. Otherwise the directions that
+//     bring the shark closer to the nearest visible fish come first, keeping the shuffled order within each group, so
+//     the shark heads towards its prey whenever one of those cells is free and otherwise moves as it would without
+//     hunting.
+//  2. With territorial sharks (Crowding above 0) the directions leading to crowded cells are then moved to the end,
+//     so a shark only joins a crowd of sharks when every other neighbouring cell is taken.
+func (s *Simulation) huntingDirections(p *partition, x, y int) [4]int {
+	directions := p.shuffledDirections()
+	if s.cfg.SightRadius >= 2 { // Adjacent fish are already eaten before the shark looks for an empty cell.
+		if dx, dy, ok := s.nearestVisibleFish(p, x, y); ok {
+			directions = towards(directions, dx, dy)
+		}
+	}
+	if s.cfg.Crowding > 0 {
+		directions = s.avoidCrowds(p, directions, x, y)
+	}
+	return directions
+}
+
+// towards reorders directions so those that reduce the offset (dx, dy) come first, keeping the order within each group.
+func towards(directions [4]int, dx, dy int) [4]int {
+	closer := func(direction int) bool {
+		switch direction {
+		case north:
+			return dy < 0
+		case south:
+			return dy > 0
+		case east:
+			return dx > 0
+		}
+		return dx < 0
+	}
+
+	var ordered [4]int
+	n := 0
+	for _, direction := range directions {
+		if closer(direction) {
+			ordered[n] = direction
+			n++
+		}
+	}
+	for _, direction := range directions {
+		if !closer(direction) {
+			ordered[n] = direction
+			n++
+		}
+	}
+	return ordered
+}
+
+// avoidCrowds moves the directions from (x, y) that lead to crowded cells to the end, keeping the order within each
+// group and counting each crowded cell the shark turns away from in the partition's statistics. Like crowded, it judges
+// whether a cell is empty from s.view.
+func (s *Simulation) avoidCrowds(p *partition, directions [4]int, x, y int) [4]int {
+	var ordered, crowded [4]int
+	n, m := 0, 0
+	for _, direction := range directions {
+		newX, newY := s.neighbour(x, y, direction)
+		if s.view[newY*s.cfg.Width+newX] == Empty && s.crowded(newX, newY, x, y) {
+			crowded[m] = direction
+			m++
+			p.crowdedCells++
+		} else {
+			ordered[n] = direction
+			n++
+		}
+	}
+	copy(ordered[n:], crowded[:m])
+	return ordered
+}
+
+// crowded reports whether the cell (x, y) is next to at least Crowding sharks other than the one at (fromX, fromY)
+// that is deciding whether to move there.
+//
+// Functionality:
+// The neighbours of a cell just across a partition boundary belong to a partition that may be moving them at the same
+// moment, so like the sight scan it counts the sharks in s.view, the grid as it was at the end of the last chronon.
+// A shark may therefore avoid a crowd that has since moved away, but never reads a cell while it is being written.
+func (s *Simulation) crowded(x, y, fromX, fromY int) bool {
+	sharks := 0
+	for direction := north; direction <= west; direction++ {
+		nx, ny := s.neighbour(x, y, direction)
+		if nx == fromX && ny == fromY {
+			continue
+		}
+		if s.view[ny*s.cfg.Width+nx] == Shark {
+			sharks++
+		}
+	}
+	return sharks >= s.cfg.Crowding
+}
+
+// nearestVisibleFish finds the closest fish within the shark's sight radius, measuring Manhattan distance on the torus.
+//
+// Functionality:
+// The cells at distance d form a diamond-shaped ring of 4*d cells. The rings are scanned outwards from d = 2 (d = 1 is
+// the shark's own neighbourhood) up to the sight radius, so the first fish found is one of the nearest and a shark with
+// a fish close by looks at only a handful of cells. Each ring starts at a random position so that no direction is
+// favoured when several fish are equally close.
+//
+// The rings reach into the neighbouring partitions, whose cells away from the boundary are written without locks while
+// they move their creatures, so the scan reads s.view, the grid as it was at the end of the last chronon, rather than
+// the grid itself. A shark may therefore head for a fish that has since moved or been eaten.
+func (s *Simulation) nearestVisibleFish(p *partition, x, y int) (dx, dy int, ok bool) {
+	width, height := s.cfg.Width, s.cfg.Height
+	radius := min(s.cfg.SightRadius, width/2, height/2) // Beyond half the grid a ring wraps onto cells that are closer the other way.
+
+	for d := 2; d <= radius; d++ {
+		cells := 4 * d
+		start := p.rng.Intn(cells)
+		for i := 0; i < cells; i++ {
+			dx, dy := ringOffset(d, (start+i)%cells)
+			if s.view[(y+dy+height)%height*width+(x+dx+width)%width] == Fish {
+				return dx, dy, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// ringOffset returns the i-th of the 4*d offsets at Manhattan distance d, walking the diamond clockwise from due east.
+func ringOffset(d, i int) (int, int) {
+	side, k := i/d, i%d
+	switch side {
+	case 0:
+		return d - k, k // East to south.
+	case 1:
+		return -k, d - k // South to west.
+	case 2:
+		return -d + k, -k // West to north.
+	}
+	return k, -d + k // North to east.
+}
@@ -0,0 +1,49 @@
+package wator
+
+import (
+	"fmt"     // Formats errors for unknown order names.
+	"strings" // Lists the order names in errors.
+)
+
+// Order chooses the order in which the fish and sharks of a partition take their turns each chronon.
+// The order changes how a world develops, since fish that move before the sharks can escape them and fish that move
+// after can be eaten first, so comparing with another Wa-Tor implementation needs the order it uses.
+type Order int
+
+// The update orders.
+const (
+	FishFirst   Order = iota // Every fish and then every shark, as in the original versions.
+	SharksFirst              // Every shark and then every fish.
+	Interleaved              // Fish and sharks mixed together in a random order, drawn afresh every chronon.
+)
+
+// orderNames lists the names accepted by ParseOrder, indexed by Order.
+var orderNames = []string{"fish-first", "sharks-first", "interleaved"}
+
+// String returns the name of the order, as accepted by ParseOrder.
+func (o Order) String() string {
+	if o >= 0 && int(o) < len(orderNames) {
+		return orderNames[o]
+	}
+	return fmt.Sprintf("Order(%d)", int(o))
+}
+
+// ParseOrder returns the order with the given name.
+func ParseOrder(name string) (Order, error) {
+	for i, n := range orderNames {
+		if n == name {
+			return Order(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown order %q (want one of %s)", name, strings.Join(orderNames, ", "))
+}
+
+// interleave returns the partition's fish and sharks together in a random order drawn from its stream.
+// The slice is reused from one chronon to the next, so it is only valid until the partition's next turn.
+func (p *partition) interleave(fishList, sharkList []*creature) []*creature {
+	p.turns = append(append(p.turns[:0], fishList...), sharkList...)
+	p.rng.Shuffle(len(p.turns), func(i, j int) {
+		p.turns[i], p.turns[j] = p.turns[j], p.turns[i]
+	})
+	return p.turns
+}
@@ -0,0 +1,57 @@
+package wator
+
+import "testing"
+
+func TestOrder(t *testing.T) {
+	// A fish next to a shark, walled in by land so its only move is away from the shark. Moving first it escapes;
+	// moving after the shark it is eaten.
+	layout := NewSnapshot(7, 7)
+	layout.Set(2, 3, Shark)
+	layout.Set(3, 3, Fish)
+	layout.Set(3, 2, Land)
+	layout.Set(3, 4, Land)
+	for _, tc := range []struct {
+		order Order
+		fish  int
+	}{{FishFirst, 1}, {SharksFirst, 0}} {
+		cfg := DefaultConfig()
+		cfg.Width, cfg.Height = 7, 7
+		cfg.Layout = layout
+		cfg.Order = tc.order
+		s, err := New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Step()
+		if fish, _ := s.Population(); fish != tc.fish {
+			t.Errorf("%v: %d fish after one chronon, want %d", tc.order, fish, tc.fish)
+		}
+	}
+
+	for _, threads := range []int{1, 4} {
+		cfg := DefaultConfig()
+		cfg.Threads = threads
+		cfg.Seed = 3
+		cfg.Order = Interleaved
+		s, err := New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(s.Close)
+		for i := 0; i < 100; i++ {
+			s.Step()
+			if err := s.Check(); err != nil {
+				t.Fatalf("interleaved, %d threads: %v", threads, err)
+			}
+		}
+	}
+
+	for _, name := range orderNames {
+		if order, err := ParseOrder(name); err != nil || order.String() != name {
+			t.Errorf("ParseOrder(%q) = %v, %v", name, order, err)
+		}
+	}
+	if _, err := ParseOrder("random"); err == nil {
+		t.Error("an unknown order was accepted")
+	}
+}
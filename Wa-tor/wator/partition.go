@@ -0,0 +1,295 @@
+package wator
+
+import (
+	"math/rand" // Gives each partition its own random number stream.
+	"sync"      // Provides the boundary mutexes.
+	"time"      // Measures how long a partition waits for a boundary lock.
+
+	"Barrier/barrier" // Provides the instrumented boundary mutexes.
+)
+
+// partition is a rectangular region of the grid stepped by one goroutine.
+type partition struct {
+	startX, endX int // Columns covered, inclusive.
+	startY, endY int // Rows covered, inclusive.
+
+	// Boundary mutexes shared with the neighbouring partitions, including across the wrap-around edges.
+	// A mutex is nil when the grid is not split in that direction, since every move then stays inside the partition.
+	left, right, top, bottom *boundaryMutex
+
+	adjacent [4]*partition // The neighbouring partition in each direction, indexed by north, south, east and west.
+
+	rng           *rand.Rand  // Shuffles directions and picks sight scan starts; seeded with the simulation seed plus the partition index.
+	locks         LockStats   // How often and how long this partition waited on its neighbours; only updated by its own goroutine.
+	crowdedCells  int64       // Empty cells its sharks tried last because other sharks crowded them; only updated by its own goroutine.
+	diseaseDeaths int64       // Fish in this partition that died of the disease; only updated by its own goroutine.
+	handedOff     int64       // Creatures that moved out of this partition and were handed to another; only updated by its own goroutine.
+	flows         Flows       // Births and deaths in this partition; only updated by its own goroutine.
+	fish          []*creature // Living fish in this partition at the start of the chronon; reused between chronons.
+	sharks        []*creature // Living sharks in this partition at the start of the chronon; reused between chronons.
+	fishBorn      []*creature // Fish born in this partition during the current chronon.
+	sharksBorn    []*creature // Sharks born in this partition during the current chronon.
+	turns         []*creature // Fish and sharks in the order they move when Config.Order is Interleaved; reused between chronons.
+
+	// Living creatures that ended the chronon in another partition, indexed by that partition.
+	// Only this partition appends to them and only the receiving partition empties its own entry.
+	fishOut, sharksOut [][]*creature
+}
+
+// partitionLayout splits count partitions into as square an arrangement of columns and rows as possible,
+// with at least as many columns as rows: 2 threads give 2x1, 4 give 2x2 and 8 give 4x2, as in the original versions.
+func partitionLayout(count int) (cols, rows int) {
+	rows = 1
+	for r := 2; r*r <= count; r++ {
+		if count%r == 0 {
+			rows = r
+		}
+	}
+	return count / rows, rows
+}
+
+// newPartitions divides a width by height grid into count partitions and creates the boundary mutexes between them.
+// Columns and rows are spread as evenly as possible, so partitions differ in size by at most one cell.
+// Partition i draws its random numbers from its own stream seeded with seed+i, so no two goroutines share a source.
+// When instrument is set the boundary mutexes are barrier.Mutexes, which are also returned in lock order so their
+// statistics can be read; otherwise they are plain sync.Mutexes and none are returned.
+func newPartitions(width, height, count int, seed int64, instrument bool) ([]*partition, []*barrier.Mutex) {
+	cols, rows := partitionLayout(count)
+
+	// vertical[r][c] guards the boundary on the right of column c in row r; horizontal[c][r] the boundary below row r in column c.
+	// The mutexes are numbered in the order they are created, which is the order they are locked in.
+	var instrumented []*barrier.Mutex
+	order := 0
+	newMutex := func() *boundaryMutex {
+		mu := &boundaryMutex{locker: &sync.Mutex{}, order: order}
+		order++
+		if instrument {
+			m := &barrier.Mutex{}
+			mu.locker = m
+			instrumented = append(instrumented, m)
+		}
+		return mu
+	}
+	vertical := make([][]*boundaryMutex, rows)
+	for r := range vertical {
+		vertical[r] = make([]*boundaryMutex, cols)
+		for c := range vertical[r] {
+			if cols > 1 {
+				vertical[r][c] = newMutex()
+			}
+		}
+	}
+	horizontal := make([][]*boundaryMutex, cols)
+	for c := range horizontal {
+		horizontal[c] = make([]*boundaryMutex, rows)
+		for r := range horizontal[c] {
+			if rows > 1 {
+				horizontal[c][r] = newMutex()
+			}
+		}
+	}
+
+	partitions := make([]*partition, 0, count)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			partitions = append(partitions, &partition{
+				startX: c * width / cols,
+				endX:   (c+1)*width/cols - 1,
+				startY: r * height / rows,
+				endY:   (r+1)*height/rows - 1,
+				left:   vertical[r][(c-1+cols)%cols],
+				right:  vertical[r][c],
+				top:    horizontal[c][(r-1+rows)%rows],
+				bottom: horizontal[c][r],
+				rng:    rand.New(rand.NewSource(seed + int64(len(partitions)))),
+
+				fishOut:   make([][]*creature, count),
+				sharksOut: make([][]*creature, count),
+			})
+		}
+	}
+	for i, p := range partitions {
+		r, c := i/cols, i%cols
+		p.adjacent[north] = partitions[(r-1+rows)%rows*cols+c]
+		p.adjacent[south] = partitions[(r+1)%rows*cols+c]
+		p.adjacent[east] = partitions[r*cols+(c+1)%cols]
+		p.adjacent[west] = partitions[r*cols+(c-1+cols)%cols]
+	}
+	return partitions, instrumented
+}
+
+// contains reports whether the cell (x, y) lies inside the partition.
+func (p *partition) contains(x, y int) bool {
+	return x >= p.startX && x <= p.endX && y >= p.startY && y <= p.endY
+}
+
+// boundaryMutex guards the cells on either side of the boundary between two partitions.
+type boundaryMutex struct {
+	locker     // A *sync.Mutex, or a *barrier.Mutex when Config.InstrumentLocks is set.
+	order  int // Position in the lock order: a partition needing several boundary mutexes locks them in increasing order.
+}
+
+// locker is the part of sync.Mutex the boundary mutexes use, which barrier.Mutex also provides.
+type locker interface {
+	Lock()
+	TryLock() bool
+	Unlock()
+}
+
+// cellLocks is the set of boundary mutexes held while a creature reads or writes two neighbouring cells: at most two
+// guard the cell it is in, when that cell is a corner of its partition, and two the cell next to it.
+type cellLocks struct {
+	mutexes [4]*boundaryMutex // Sorted by lock order.
+	n       int
+}
+
+// add adds mu to the set, keeping it sorted by lock order. Nil and repeated mutexes are ignored.
+func (l *cellLocks) add(mu *boundaryMutex) {
+	if mu == nil {
+		return
+	}
+	i := 0
+	for i < l.n && l.mutexes[i].order < mu.order {
+		i++
+	}
+	if i < l.n && l.mutexes[i] == mu {
+		return
+	}
+	copy(l.mutexes[i+1:l.n+1], l.mutexes[i:l.n])
+	l.mutexes[i] = mu
+	l.n++
+}
+
+// unlock unlocks every mutex in the set.
+func (l *cellLocks) unlock() {
+	for _, mu := range l.mutexes[:l.n] {
+		mu.Unlock()
+	}
+}
+
+// guard adds to l the mutexes guarding the cell (x, y) of p: those of the boundaries the cell lies on, which are the
+// ones a neighbouring partition holds when it moves a creature into or out of the cell. A cell away from every
+// boundary needs none, since only p's goroutine touches it.
+func (p *partition) guard(l *cellLocks, x, y int) {
+	if x == p.startX {
+		l.add(p.left)
+	}
+	if x == p.endX {
+		l.add(p.right)
+	}
+	if y == p.startY {
+		l.add(p.top)
+	}
+	if y == p.endY {
+		l.add(p.bottom)
+	}
+}
+
+// locksFor returns the boundary mutexes to hold while a creature of p at (x, y) moves to, eats or infects whatever is
+// in the neighbouring cell (newX, newY) in direction. They guard both cells, since a creature that moves empties its
+// cell and may leave a newborn in it. A neighbouring cell outside p is guarded by the partition it belongs to.
+func (p *partition) locksFor(direction, x, y, newX, newY int) cellLocks {
+	var l cellLocks
+	p.guard(&l, x, y)
+	if p.contains(newX, newY) {
+		p.guard(&l, newX, newY)
+	} else {
+		p.adjacent[direction].guard(&l, newX, newY)
+	}
+	return l
+}
+
+// lock locks every mutex in l in lock order, so two partitions locking overlapping sets cannot deadlock, recording the
+// acquisitions and any time spent waiting in the partition's statistics. An uncontended lock costs a single TryLock;
+// the clock is only read when a neighbour already holds the lock.
+func (p *partition) lock(l *cellLocks) {
+	for _, mu := range l.mutexes[:l.n] {
+		p.locks.Acquisitions++
+		if mu.TryLock() {
+			continue
+		}
+		start := time.Now()
+		mu.Lock()
+		p.locks.Contended++
+		p.locks.Wait += time.Since(start)
+	}
+}
+
+// settle prepares partition i's lists for the next chronon, the first half of consolidation.
+// Its fish and sharks that died are dropped, its newborns are added, and creatures that moved into another partition
+// are handed to that partition's entry in fishOut and sharksOut, and its cells are recorded in the view sharks look at
+// during the next chronon. Every partition settles at the same time, since each only writes its own lists and cells.
+func (s *Simulation) settle(i int, p *partition) {
+	s.record(p)
+	p.fish = s.keepResidents(p, p.fish, p.fishBorn, p.fishOut)
+	p.sharks = s.keepResidents(p, p.sharks, p.sharksBorn, p.sharksOut)
+	clear(p.fishBorn)
+	clear(p.sharksBorn)
+	p.fishBorn = p.fishBorn[:0]
+	p.sharksBorn = p.sharksBorn[:0]
+}
+
+// keepResidents filters list in place, keeping the living creatures still inside p followed by the living newborns
+// inside p, and appends the living creatures that are now outside p to out, indexed by the partition they are in.
+// Newborns are added before filtering because a fish can be born and eaten in the same chronon.
+func (s *Simulation) keepResidents(p *partition, list, born []*creature, out [][]*creature) []*creature {
+	n := len(list)
+	kept := list[:0]
+	for _, group := range [2][]*creature{list, born} {
+		for _, c := range group {
+			switch {
+			case c.dead:
+			case p.contains(c.x, c.y):
+				kept = append(kept, c)
+			default:
+				j := s.partitionIndex(c.x, c.y)
+				out[j] = append(out[j], c)
+				p.handedOff++
+			}
+		}
+	}
+	if len(kept) < n {
+		clear(list[len(kept):n]) // Drop references to creatures that died or left, past the end of the list.
+	}
+	return kept
+}
+
+// record copies the contents of p's cells into s.view, if there is one. It is called once the grid has stopped changing
+// for the chronon, so the view can be read during the next one without locks while the partitions move their creatures.
+func (s *Simulation) record(p *partition) {
+	if s.view == nil {
+		return
+	}
+	for x := p.startX; x <= p.endX; x++ {
+		for y := p.startY; y <= p.endY; y++ {
+			kind := Empty
+			if c := s.grid[x][y]; c != nil {
+				kind = c.kind
+			}
+			s.view[y*s.cfg.Width+x] = kind
+		}
+	}
+}
+
+// collect adds the creatures that moved into partition i from the other partitions, the second half of consolidation.
+// Partitions are collected from in index order so deterministic runs stay reproducible.
+func (s *Simulation) collect(i int, p *partition) {
+	for _, from := range s.partitions {
+		p.fish = append(p.fish, from.fishOut[i]...)
+		p.sharks = append(p.sharks, from.sharksOut[i]...)
+		clear(from.fishOut[i])
+		clear(from.sharksOut[i])
+		from.fishOut[i] = from.fishOut[i][:0]
+		from.sharksOut[i] = from.sharksOut[i][:0]
+	}
+}
+
+// partitionIndex returns the index of the partition containing the cell (x, y).
+func (s *Simulation) partitionIndex(x, y int) int {
+	for i, p := range s.partitions {
+		if p.contains(x, y) {
+			return i
+		}
+	}
+	panic("wator: cell outside every partition") // newPartitions covers the whole grid, so this cannot happen.
+}
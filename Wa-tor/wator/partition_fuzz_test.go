@@ -0,0 +1,185 @@
+package wator
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// FuzzRunPartition builds random grids, partition layouts and populations and checks that moving entities
+// across partition boundaries and around the edges of the torus never loses, duplicates or misplaces an entity.
+//
+// The simulation runs in deterministic mode, so the partitions are stepped one after another rather than concurrently:
+// a failure is caused by the boundary and wrap-around logic rather than by goroutine scheduling, and it reproduces
+// exactly from the fuzzer's inputs.
+//
+// Run it with:
+//
+//	go test ./wator -run x -fuzz FuzzRunPartition -fuzztime 30s
+func FuzzRunPartition(f *testing.F) {
+	f.Add(int64(1), uint8(50), uint8(50), uint8(1), uint8(10), uint8(5))
+	f.Add(int64(2), uint8(50), uint8(50), uint8(2), uint8(30), uint8(3))
+	f.Add(int64(3), uint8(40), uint8(40), uint8(8), uint8(60), uint8(1))
+	f.Add(int64(4), uint8(4), uint8(4), uint8(4), uint8(90), uint8(2))
+	f.Add(int64(5), uint8(9), uint8(1), uint8(3), uint8(50), uint8(4))
+
+	f.Fuzz(func(t *testing.T, seed int64, width, height, threads, density, params uint8) {
+		cfg := DefaultConfig()
+		cfg.Width = int(width%64) + 1
+		cfg.Height = int(height%64) + 1
+		cfg.Threads = int(threads%12) + 1
+		cfg.Seed = seed
+		cfg.Deterministic = true
+		cfg.Params = Params{FishBreed: int(params%5) + 1, SharkBreed: int(params/5%5) + 1, SharkStarve: int(params/25%5) + 1}
+		cfg.SightRadius = int(params % 4)
+		if cfg.Validate() != nil {
+			return // Too small for the number of partitions.
+		}
+		cfg.Layout = randomLayout(rand.New(rand.NewSource(seed)), cfg.Width, cfg.Height, int(density%101))
+
+		s, err := New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(s.Close)
+		checkPartitionCover(t, s)
+		checkBoundaryMutexes(t, s)
+		for i := 0; i < 20; i++ {
+			s.Step()
+			checkGrid(t, s)
+		}
+	})
+}
+
+// randomLayout fills a layout so that roughly density percent of the cells hold something, mostly fish.
+func randomLayout(rng *rand.Rand, width, height, density int) *Snapshot {
+	layout := NewSnapshot(width, height)
+	for i := range layout.Cells {
+		if rng.Intn(100) >= density {
+			continue
+		}
+		switch n := rng.Intn(10); {
+		case n < 6:
+			layout.Cells[i] = Fish
+		case n < 9:
+			layout.Cells[i] = Shark
+		default:
+			layout.Cells[i] = Land
+		}
+	}
+	return layout
+}
+
+// checkPartitionCover fails the test unless every cell belongs to exactly one partition.
+func checkPartitionCover(t *testing.T, s *Simulation) {
+	t.Helper()
+	for x := 0; x < s.cfg.Width; x++ {
+		for y := 0; y < s.cfg.Height; y++ {
+			owners := 0
+			for _, p := range s.partitions {
+				if p.contains(x, y) {
+					owners++
+				}
+			}
+			if owners != 1 {
+				t.Fatalf("cell (%d, %d) belongs to %d partitions", x, y, owners)
+			}
+		}
+	}
+}
+
+// checkBoundaryMutexes fails the test unless every move holds, in lock order, exactly the mutexes guarding the two
+// cells it touches: for each cell, the mutexes a neighbouring partition holds when it moves into or out of that cell.
+// Moves between cells away from every boundary take no lock.
+func checkBoundaryMutexes(t *testing.T, s *Simulation) {
+	t.Helper()
+	// guards returns the mutexes used by the partitions next to the one owning (x, y) to reach it.
+	guards := func(x, y int) map[*boundaryMutex]bool {
+		owner := s.partitions[s.partitionIndex(x, y)]
+		set := map[*boundaryMutex]bool{}
+		for direction := north; direction <= west; direction++ {
+			nx, ny := s.neighbour(x, y, direction)
+			if owner.contains(nx, ny) {
+				continue
+			}
+			q := s.partitions[s.partitionIndex(nx, ny)]
+			switch direction {
+			case north:
+				set[q.bottom] = true
+			case south:
+				set[q.top] = true
+			case east:
+				set[q.left] = true
+			case west:
+				set[q.right] = true
+			}
+		}
+		return set
+	}
+
+	for _, p := range s.partitions {
+		for x := p.startX; x <= p.endX; x++ {
+			for y := p.startY; y <= p.endY; y++ {
+				for direction := north; direction <= west; direction++ {
+					newX, newY := s.neighbour(x, y, direction)
+					if newX < 0 || newX >= s.cfg.Width || newY < 0 || newY >= s.cfg.Height {
+						t.Fatalf("moving %d from (%d, %d) leaves the grid at (%d, %d)", direction, x, y, newX, newY)
+					}
+					want := guards(x, y)
+					for mu := range guards(newX, newY) {
+						want[mu] = true
+					}
+					locks := p.locksFor(direction, x, y, newX, newY)
+					held := locks.mutexes[:locks.n]
+					for i, mu := range held {
+						if !want[mu] || (i > 0 && held[i-1].order >= mu.order) {
+							t.Fatalf("move %d from (%d, %d) to (%d, %d) holds %d mutexes, want %d in lock order",
+								direction, x, y, newX, newY, locks.n, len(want))
+						}
+					}
+					if locks.n != len(want) {
+						t.Fatalf("move %d from (%d, %d) to (%d, %d) holds %d mutexes, want %d",
+							direction, x, y, newX, newY, locks.n, len(want))
+					}
+				}
+			}
+		}
+	}
+}
+
+// creatureLists returns the fish and shark lists of every partition.
+func (s *Simulation) creatureLists() [][]*creature {
+	var lists [][]*creature
+	for _, p := range s.partitions {
+		lists = append(lists, p.fish, p.sharks)
+	}
+	return lists
+}
+
+// checkGrid fails the test if an entity is out of bounds, shares its cell, or is missing from the grid or its list.
+func checkGrid(t *testing.T, s *Simulation) {
+	t.Helper()
+	listed := 0
+	for _, list := range s.creatureLists() {
+		for _, c := range list {
+			if c.x < 0 || c.x >= s.cfg.Width || c.y < 0 || c.y >= s.cfg.Height {
+				t.Fatalf("chronon %d: %s moved out of bounds to (%d, %d)", s.chronon, c.kind, c.x, c.y)
+			}
+			if s.grid[c.x][c.y] != c {
+				t.Fatalf("chronon %d: %s at (%d, %d) shares its cell or was overwritten", s.chronon, c.kind, c.x, c.y)
+			}
+			listed++
+		}
+	}
+
+	onGrid := 0
+	for x := range s.grid {
+		for _, c := range s.grid[x] {
+			if c != nil && c.kind != Land {
+				onGrid++
+			}
+		}
+	}
+	if onGrid != listed {
+		t.Fatalf("chronon %d: %d entities on the grid but %d in the fish and shark lists", s.chronon, onGrid, listed)
+	}
+}
@@ -0,0 +1,76 @@
+package wator
+
+import (
+	"fmt"     // Formats the error for an unknown preset.
+	"strings" // Lists the preset names in that error.
+)
+
+// presets are the named configurations returned by Preset, in the order PresetNames lists them.
+// Each has a fixed seed so the dynamics it is named after can be seen straight away.
+var presets = []struct {
+	name        string
+	description string
+	config      func() Config
+}{
+	{"classic", "Dewdney's original 80x23 ocean with 200 fish and 20 sharks: populations that rise and fall in turn", func() Config {
+		cfg := DefaultConfig()
+		cfg.Width, cfg.Height = 80, 23
+		cfg.Params = Params{FishBreed: 3, SharkBreed: 10, SharkStarve: 3}
+		cfg.FishDensity, cfg.SharkDensity = 200.0/(80*23), 20.0/(80*23)
+		cfg.Seed = 1
+		return cfg
+	}},
+	{"fish-heavy", "fast-breeding fish and slow-breeding sharks: fish fill the ocean and hold several times as many as the sharks", func() Config {
+		cfg := DefaultConfig()
+		cfg.Width, cfg.Height = 100, 100
+		cfg.Params = Params{FishBreed: 2, SharkBreed: 12, SharkStarve: 3}
+		cfg.FishDensity, cfg.SharkDensity = 0.3, 0.005
+		cfg.Seed = 1
+		return cfg
+	}},
+	{"predator-collapse", "as many sharks as fish, breeding fast and starving quickly: they eat the fish down and starve out within 100 chronons", func() Config {
+		cfg := DefaultConfig()
+		cfg.Width, cfg.Height = 100, 100
+		cfg.Params = Params{FishBreed: 6, SharkBreed: 2, SharkStarve: 2}
+		cfg.FishDensity, cfg.SharkDensity = 0.2, 0.2
+		cfg.Seed = 1
+		return cfg
+	}},
+	{"large-bench", "the original thresholds on a 1000x1000 grid split into 8 partitions, for measuring speed", func() Config {
+		cfg := DefaultConfig()
+		cfg.Width, cfg.Height = 1000, 1000
+		cfg.Threads = 8
+		cfg.Seed = 1
+		return cfg
+	}},
+}
+
+// Preset returns the configuration of a named preset: "classic", "fish-heavy", "predator-collapse" or "large-bench".
+// The configuration is complete, seed included, and can be changed like any other before it is passed to New.
+func Preset(name string) (Config, error) {
+	for _, preset := range presets {
+		if preset.name == name {
+			return preset.config(), nil
+		}
+	}
+	return Config{}, fmt.Errorf("unknown preset %q (want %s)", name, strings.Join(PresetNames(), ", "))
+}
+
+// PresetNames returns the names of the presets.
+func PresetNames() []string {
+	names := make([]string, len(presets))
+	for i, preset := range presets {
+		names[i] = preset.name
+	}
+	return names
+}
+
+// PresetDescription returns a one-line description of the named preset, or "" if there is no such preset.
+func PresetDescription(name string) string {
+	for _, preset := range presets {
+		if preset.name == name {
+			return preset.description
+		}
+	}
+	return ""
+}
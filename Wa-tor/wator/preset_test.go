@@ -0,0 +1,34 @@
+package wator
+
+import "testing"
+
+func TestPresets(t *testing.T) {
+	for _, name := range PresetNames() {
+		cfg, err := Preset(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if cfg.Seed == 0 || PresetDescription(name) == "" {
+			t.Errorf("%s has no fixed seed or no description", name)
+		}
+	}
+	if _, err := Preset("nonexistent"); err == nil {
+		t.Error("an unknown preset was accepted")
+	}
+
+	// The preset must live up to its name.
+	cfg, _ := Preset("predator-collapse")
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		s.Step()
+	}
+	if fish, sharks := s.Population(); sharks != 0 || fish == 0 {
+		t.Errorf("predator-collapse has %d fish and %d sharks after 100 chronons, want the sharks gone", fish, sharks)
+	}
+}
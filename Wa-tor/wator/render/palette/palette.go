@@ -0,0 +1,35 @@
+// Package palette holds the colours wator simulations are drawn in, shared by the render package's window and images
+// and the tui package's terminal view so both look alike.
+//
+// It depends on nothing but the standard library and the wator package, so the terminal view builds without Ebiten,
+// cgo or a display.
+package palette
+
+import (
+	"image/color" // Provides the colours used for each kind of cell.
+
+	"Wator/wator" // Provides the kinds of cell.
+)
+
+// Colors used for each kind of cell, matching the original versions and the PNG scenario palette.
+var (
+	EmptyColor = color.RGBA{0, 0, 0, 255}      // Black for open water.
+	FishColor  = color.RGBA{0, 221, 255, 255}  // Light blue for fish.
+	SharkColor = color.RGBA{190, 44, 190, 255} // Purple for sharks.
+	LandColor  = color.RGBA{120, 90, 40, 255}  // Brown for land.
+
+	InfectedColor = color.RGBA{140, 220, 40, 255} // Sickly green for fish that have caught the disease.
+)
+
+// Color returns the colour used to draw a cell.
+func Color(c wator.Cell) color.RGBA {
+	switch c {
+	case wator.Fish:
+		return FishColor
+	case wator.Shark:
+		return SharkColor
+	case wator.Land:
+		return LandColor
+	}
+	return EmptyColor
+}
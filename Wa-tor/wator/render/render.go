@@ -0,0 +1,142 @@
+// Package render draws wator simulations with Ebiten.
+//
+// It is kept apart from the wator package so programs that only need the simulation do not depend on Ebiten.
+// To watch a simulation in a window:
+//
+//	sim, _ := wator.New(wator.DefaultConfig())
+//	ebiten.SetWindowSize(800, 800)
+//	if err := ebiten.RunGame(render.NewGame(sim, 800, 800)); err != nil {
+//		log.Fatal(err)
+//	}
+package render
+
+import (
+	"image"       // Provides the RGBA image returned by Image.
+	"image/color" // Provides the colour type of each kind of cell and of heatmaps.
+
+	"Wator/wator"                // The simulation being drawn.
+	"Wator/wator/render/palette" // Provides the colours used for each kind of cell.
+
+	"github.com/hajimehoshi/ebiten/v2" // Provides the images and draw options used to render the grid.
+)
+
+// Colors used for each kind of cell, those of the palette package, which the tui package shares.
+var (
+	EmptyColor = palette.EmptyColor // Black for open water.
+	FishColor  = palette.FishColor  // Light blue for fish.
+	SharkColor = palette.SharkColor // Purple for sharks.
+	LandColor  = palette.LandColor  // Brown for land.
+
+	InfectedColor = palette.InfectedColor // Sickly green for fish that have caught the disease.
+)
+
+// Color returns the colour used to draw a cell.
+func Color(c wator.Cell) color.RGBA {
+	return palette.Color(c)
+}
+
+// Image returns the snapshot as an image with one pixel per cell, for saving to PNG or further processing.
+func Image(snap wator.Snapshot) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, snap.Width, snap.Height))
+	writePixels(img.Pix, snap)
+	return img
+}
+
+// Heatmap returns counts, one per cell row by row, as a width by height image shading each cell from black for a count
+// of zero to full for the largest count. Pass an Occupancy's Fish or Sharks counts with FishColor or SharkColor.
+func Heatmap(counts []int, width, height int, full color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	largest := 0
+	for _, n := range counts {
+		largest = max(largest, n)
+	}
+	for i, n := range counts {
+		shade := 0.0
+		if largest > 0 {
+			shade = float64(n) / float64(largest)
+		}
+		img.Pix[4*i] = uint8(shade * float64(full.R))
+		img.Pix[4*i+1] = uint8(shade * float64(full.G))
+		img.Pix[4*i+2] = uint8(shade * float64(full.B))
+		img.Pix[4*i+3] = 255
+	}
+	return img
+}
+
+// SnapshotColor returns the colour used to draw cell i of snap, indexed like snap.Cells: Color of the cell, except
+// that infected fish are drawn in InfectedColor.
+func SnapshotColor(snap wator.Snapshot, i int) color.RGBA {
+	if snap.Infected != nil && snap.Infected[i] {
+		return InfectedColor
+	}
+	return Color(snap.Cells[i])
+}
+
+// writePixels fills pix with the RGBA colour of every cell of snap, row by row.
+func writePixels(pix []byte, snap wator.Snapshot) {
+	for i := range snap.Cells {
+		rgba := SnapshotColor(snap, i)
+		pix[4*i] = rgba.R
+		pix[4*i+1] = rgba.G
+		pix[4*i+2] = rgba.B
+		pix[4*i+3] = rgba.A
+	}
+}
+
+// Renderer draws snapshots with a single DrawImage call.
+//
+// Drawing every cell as its own rectangle costs one draw call per cell, which dominated frame time on large grids.
+// Instead the grid is written into a small image with one pixel per cell, and that image is scaled up to fill the
+// destination. The default nearest-neighbour filter keeps the cell edges sharp. The zero value is ready to use.
+type Renderer struct {
+	image  *ebiten.Image // One pixel per grid cell; recreated whenever the grid size changes.
+	pixels []byte        // RGBA pixel data uploaded to image every frame.
+}
+
+// Draw renders snap scaled to fill dst, which may be a sub-image of the screen such as one panel of a split view.
+func (r *Renderer) Draw(dst *ebiten.Image, snap wator.Snapshot) {
+	if r.image == nil || r.image.Bounds().Dx() != snap.Width || r.image.Bounds().Dy() != snap.Height {
+		r.image = ebiten.NewImage(snap.Width, snap.Height)
+		r.pixels = make([]byte, 4*snap.Width*snap.Height)
+	}
+	writePixels(r.pixels, snap)
+	r.image.WritePixels(r.pixels)
+
+	var op ebiten.DrawImageOptions
+	bounds := dst.Bounds()
+	op.GeoM.Scale(float64(bounds.Dx())/float64(snap.Width), float64(bounds.Dy())/float64(snap.Height))
+	op.GeoM.Translate(float64(bounds.Min.X), float64(bounds.Min.Y)) // Sub-images keep the screen's coordinates.
+	dst.DrawImage(r.image, &op)
+}
+
+// Game shows a simulation in an Ebiten window, advancing it by one chronon per frame.
+type Game struct {
+	Sim      *wator.Simulation // The simulation being shown.
+	Paused   bool              // Stops Update from stepping the simulation.
+	width    int               // Logical screen width in pixels.
+	height   int               // Logical screen height in pixels.
+	renderer Renderer          // Draws the grid.
+}
+
+// NewGame returns an ebiten.Game that runs sim on a width by height pixel screen.
+func NewGame(sim *wator.Simulation, width, height int) *Game {
+	return &Game{Sim: sim, width: width, height: height}
+}
+
+// Update advances the simulation by one chronon unless the game is paused.
+func (g *Game) Update() error {
+	if !g.Paused {
+		g.Sim.Step()
+	}
+	return nil
+}
+
+// Draw renders the current grid.
+func (g *Game) Draw(screen *ebiten.Image) {
+	g.renderer.Draw(screen, g.Sim.Snapshot())
+}
+
+// Layout returns the fixed screen size given to NewGame.
+func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return g.width, g.height
+}
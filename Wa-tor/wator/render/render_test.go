@@ -0,0 +1,102 @@
+package render
+
+import (
+	"image/color"
+	"testing"
+
+	"Wator/wator"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+func TestImage(t *testing.T) {
+	snap := wator.NewSnapshot(3, 2)
+	snap.Set(0, 0, wator.Fish)
+	snap.Set(2, 1, wator.Shark)
+	snap.Set(1, 1, wator.Land)
+
+	img := Image(*snap)
+	for y := 0; y < snap.Height; y++ {
+		for x := 0; x < snap.Width; x++ {
+			if got, want := img.RGBAAt(x, y), Color(snap.At(x, y)); got != want {
+				t.Errorf("pixel (%d, %d) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestImageShowsInfectedFish(t *testing.T) {
+	snap := wator.NewSnapshot(2, 1)
+	snap.Set(0, 0, wator.Fish)
+	snap.Set(1, 0, wator.Fish)
+	snap.Infected = []bool{false, true}
+
+	img := Image(*snap)
+	if got := img.RGBAAt(0, 0); got != FishColor {
+		t.Errorf("healthy fish drawn as %v, want %v", got, FishColor)
+	}
+	if got := img.RGBAAt(1, 0); got != InfectedColor {
+		t.Errorf("infected fish drawn as %v, want %v", got, InfectedColor)
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	img := Heatmap([]int{0, 2, 4, 4}, 2, 2, color.RGBA{200, 100, 50, 255})
+	want := []color.RGBA{{0, 0, 0, 255}, {100, 50, 25, 255}, {200, 100, 50, 255}, {200, 100, 50, 255}}
+	for i, w := range want {
+		if got := img.RGBAAt(i%2, i/2); got != w {
+			t.Errorf("pixel %d = %v, want %v", i, got, w)
+		}
+	}
+}
+
+// newBenchmarkSnapshot returns the starting grid of the default 50x50 simulation.
+func newBenchmarkSnapshot(b *testing.B) wator.Snapshot {
+	cfg := wator.DefaultConfig()
+	cfg.Seed = 1
+	sim, err := wator.New(cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return sim.Snapshot()
+}
+
+// drawCellsAsRects draws the grid the way the original versions did, with one rectangle per cell.
+// It is kept only so BenchmarkDrawRects can be compared with BenchmarkDraw.
+func drawCellsAsRects(screen *ebiten.Image, snap wator.Snapshot) {
+	cellXSize, cellYSize := screen.Bounds().Dx()/snap.Width, screen.Bounds().Dy()/snap.Height
+	for x := 0; x < snap.Width; x++ {
+		for y := 0; y < snap.Height; y++ {
+			var c color.Color = color.RGBA{0, 0, 0, 0}
+			if cell := snap.At(x, y); cell != wator.Empty {
+				c = Color(cell)
+			}
+			ebitenutil.DrawRect(screen, float64(x*cellXSize), float64(y*cellYSize), float64(cellXSize), float64(cellYSize), c)
+		}
+	}
+}
+
+// BenchmarkDraw measures the cost of drawing one frame of the grid with Renderer.
+// Ebiten needs a graphics context, so the draw benchmarks must be run on a machine with a display.
+func BenchmarkDraw(b *testing.B) {
+	snap := newBenchmarkSnapshot(b)
+	screen := ebiten.NewImage(800, 800)
+	var r Renderer
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Draw(screen, snap)
+	}
+}
+
+// BenchmarkDrawRects measures the cost of drawing one frame of the grid with one rectangle per cell.
+func BenchmarkDrawRects(b *testing.B) {
+	snap := newBenchmarkSnapshot(b)
+	screen := ebiten.NewImage(800, 800)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		drawCellsAsRects(screen, snap)
+	}
+}
@@ -0,0 +1,155 @@
+// Package tui draws wator simulations in a terminal with ANSI escape sequences, so a simulation can be watched over
+// SSH on a server with no display.
+//
+// Each character shows two grid rows, using an upper half block coloured with the top cell as its foreground and the
+// bottom cell as its background, in the palette package's colours, as the render package uses, infected fish included.
+// Grids larger than the terminal are shrunk by showing each block of cells as its most notable cell, so scattered
+// sharks stay visible. The terminal must support 24-bit colour, as most modern terminals and SSH clients do.
+//
+// It does not import the render package, so it builds without Ebiten, cgo or a display.
+//
+//	r := tui.NewRenderer(os.Stdout, cols, rows)
+//	r.Start()
+//	defer r.Stop()
+//	for {
+//		sim.Step()
+//		r.Draw(sim.Snapshot(), "")
+//	}
+package tui
+
+import (
+	"bytes"       // Builds each frame before writing it in one go.
+	"fmt"         // Formats the colour escape sequences.
+	"image/color" // Provides the cell colours.
+	"io"          // Provides the terminal writer.
+	"strings"     // Pads the status line.
+
+	"Wator/wator"                // Provides the grid being drawn.
+	"Wator/wator/render/palette" // Provides the cell colours, so both views look alike.
+)
+
+// ANSI escape sequences used to manage the screen.
+const (
+	enterAltScreen = "\x1b[?1049h" // Switch to the alternate screen, keeping the shell's output underneath.
+	leaveAltScreen = "\x1b[?1049l" // Return to the shell's screen.
+	hideCursor     = "\x1b[?25l"
+	showCursor     = "\x1b[?25h"
+	cursorHome     = "\x1b[H"  // Move to the top-left corner, so each frame overwrites the last without flicker.
+	clearScreen    = "\x1b[2J" // Blank the screen.
+	resetColours   = "\x1b[0m"
+	upperHalfBlock = "▀"
+)
+
+// Renderer draws snapshots to a terminal of a fixed size.
+type Renderer struct {
+	out        io.Writer    // The terminal.
+	cols, rows int          // Terminal size in characters; the bottom row holds the status line.
+	frame      bytes.Buffer // The frame being built; reused between frames.
+}
+
+// NewRenderer returns a renderer drawing to out, a terminal cols characters wide and rows tall.
+func NewRenderer(out io.Writer, cols, rows int) *Renderer {
+	return &Renderer{out: out, cols: max(cols, 1), rows: max(rows, 2)}
+}
+
+// Start switches to the alternate screen and hides the cursor. Call Stop to restore the terminal.
+func (r *Renderer) Start() error {
+	_, err := io.WriteString(r.out, enterAltScreen+hideCursor+clearScreen)
+	return err
+}
+
+// Stop restores the cursor and the screen as they were before Start.
+func (r *Renderer) Stop() error {
+	_, err := io.WriteString(r.out, resetColours+showCursor+leaveAltScreen)
+	return err
+}
+
+// Scale returns how many grid cells each terminal pixel (half a character) covers in each direction for a width by
+// height grid, the smallest whole number that fits the grid into the terminal above the status line.
+func (r *Renderer) Scale(width, height int) int {
+	pixelsAcross, pixelsDown := r.cols, 2*(r.rows-1)
+	return max(1, (width+pixelsAcross-1)/pixelsAcross, (height+pixelsDown-1)/pixelsDown)
+}
+
+// Draw writes snap to the terminal with status on the bottom row.
+//
+// Input:
+//   - snap (wator.Snapshot): The grid to draw.
+//   - status (string): A line of text shown below the grid, cut to the terminal width.
+//
+// Output:
+//   - error: Returns an error if the terminal cannot be written to.
+//
+// Functionality:
+// The frame is built in memory and written with a single call, so a slow SSH connection shows whole frames.
+// Colour escape sequences are only written when the colour changes, since neighbouring cells are often alike.
+func (r *Renderer) Draw(snap wator.Snapshot, status string) error {
+	scale := r.Scale(snap.Width, snap.Height)
+	across := (snap.Width + scale - 1) / scale
+	down := (snap.Height + scale - 1) / scale
+
+	r.frame.Reset()
+	r.frame.WriteString(cursorHome)
+	var fg, bg color.RGBA
+	first := true
+	for row := 0; row < (down+1)/2; row++ {
+		for col := 0; col < across; col++ {
+			top := blockColor(snap, col*scale, 2*row*scale, scale)
+			bottom := palette.EmptyColor
+			if 2*row+1 < down {
+				bottom = blockColor(snap, col*scale, (2*row+1)*scale, scale)
+			}
+			if first || top != fg {
+				fmt.Fprintf(&r.frame, "\x1b[38;2;%d;%d;%dm", top.R, top.G, top.B)
+			}
+			if first || bottom != bg {
+				fmt.Fprintf(&r.frame, "\x1b[48;2;%d;%d;%dm", bottom.R, bottom.G, bottom.B)
+			}
+			fg, bg, first = top, bottom, false
+			r.frame.WriteString(upperHalfBlock)
+		}
+		r.frame.WriteString(resetColours + "\x1b[K\r\n") // Clear the rest of the line in case the grid got narrower.
+		first = true
+	}
+
+	if len(status) > r.cols {
+		status = status[:r.cols]
+	}
+	fmt.Fprintf(&r.frame, "\x1b[%d;1H%s%s", r.rows, status, strings.Repeat(" ", r.cols-len(status)))
+	_, err := r.out.Write(r.frame.Bytes())
+	return err
+}
+
+// blockColor returns the colour of the scale by scale block of snap whose top-left corner is (x, y): the colour of its
+// most notable cell, with a block of fish shown as infected when any of its fish is.
+func blockColor(snap wator.Snapshot, x, y, scale int) color.RGBA {
+	cell := notableCell(snap, x, y, scale)
+	if cell != wator.Fish || snap.Infected == nil {
+		return palette.Color(cell)
+	}
+	for dy := 0; dy < scale && y+dy < snap.Height; dy++ {
+		for dx := 0; dx < scale && x+dx < snap.Width; dx++ {
+			if snap.Infected[(y+dy)*snap.Width+x+dx] {
+				return palette.InfectedColor
+			}
+		}
+	}
+	return palette.FishColor
+}
+
+// notableCell returns the most notable cell in the scale by scale block of snap whose top-left corner is (x, y):
+// a shark if there is one, otherwise a fish, otherwise land, otherwise empty water.
+func notableCell(snap wator.Snapshot, x, y, scale int) wator.Cell {
+	best := wator.Empty
+	for dy := 0; dy < scale && y+dy < snap.Height; dy++ {
+		for dx := 0; dx < scale && x+dx < snap.Width; dx++ {
+			switch c := snap.At(x+dx, y+dy); {
+			case c == wator.Shark:
+				return c
+			case c == wator.Fish, c == wator.Land && best == wator.Empty:
+				best = c
+			}
+		}
+	}
+	return best
+}
@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"Wator/wator"
+	"Wator/wator/render/palette"
+)
+
+func TestDraw(t *testing.T) {
+	snap := wator.NewSnapshot(4, 4)
+	snap.Set(0, 0, wator.Fish)
+	snap.Set(1, 1, wator.Shark)
+	snap.Set(3, 3, wator.Land)
+
+	var out bytes.Buffer
+	r := NewRenderer(&out, 80, 24)
+	if got := r.Scale(4, 4); got != 1 {
+		t.Errorf("Scale(4, 4) = %d, want 1", got)
+	}
+	if err := r.Draw(*snap, "status"); err != nil {
+		t.Fatal(err)
+	}
+	frame := out.String()
+	if n := strings.Count(frame, upperHalfBlock); n != 8 {
+		t.Errorf("drew %d half blocks, want 8 for a 4x4 grid", n)
+	}
+	fish, shark := palette.FishColor, palette.SharkColor
+	for _, want := range []string{
+		fmt.Sprintf("38;2;%d;%d;%dm", fish.R, fish.G, fish.B),    // The fish at (0, 0) is the top half of the first character.
+		fmt.Sprintf("48;2;%d;%d;%dm", shark.R, shark.G, shark.B), // The shark at (1, 1) is the bottom half of the second.
+		"status",
+	} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("frame does not contain %q", want)
+		}
+	}
+}
+
+func TestScaleAndNotableCell(t *testing.T) {
+	r := NewRenderer(&bytes.Buffer{}, 80, 25)
+	if got := r.Scale(400, 100); got != 5 {
+		t.Errorf("Scale(400, 100) = %d, want 5", got)
+	}
+
+	snap := wator.NewSnapshot(3, 3)
+	snap.Set(0, 0, wator.Land)
+	snap.Set(1, 1, wator.Fish)
+	if got := notableCell(*snap, 0, 0, 3); got != wator.Fish {
+		t.Errorf("notable cell is %v, want fish", got)
+	}
+	snap.Infected = make([]bool, 9)
+	snap.Infected[4] = true
+	if got := blockColor(*snap, 0, 0, 3); got != palette.InfectedColor {
+		t.Errorf("block with an infected fish is %v, want %v", got, palette.InfectedColor)
+	}
+	snap.Set(2, 2, wator.Shark)
+	if got := notableCell(*snap, 0, 0, 3); got != wator.Shark {
+		t.Errorf("notable cell is %v, want shark", got)
+	}
+}
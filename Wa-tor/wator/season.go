@@ -0,0 +1,129 @@
+package wator
+
+import (
+	"fmt"     // Formats errors for malformed schedules.
+	"strconv" // Parses the season lengths and parameter values.
+	"strings" // Splits the schedule into seasons and fields.
+)
+
+// Season is one part of a Schedule: a named stretch of chronons with its own breeding and starvation thresholds.
+type Season struct {
+	Name   string // Name used in logs and CSV files, such as "summer".
+	Length int    // Number of chronons the season lasts.
+	Params Params // Thresholds in effect during the season.
+}
+
+// Schedule is a cycle of seasons, repeated for as long as a simulation runs, for simulating how the balance between
+// fish and sharks shifts with the seasons. A Simulation does not follow a schedule by itself: call Begins before each
+// Step and pass the season to Simulation.SetParams when one begins.
+type Schedule []Season
+
+// scheduleParams names the parameters a season may set, as they are written in a schedule.
+var scheduleParams = []struct {
+	name  string
+	value func(p *Params) *int
+}{
+	{"fish-breed", func(p *Params) *int { return &p.FishBreed }},
+	{"shark-breed", func(p *Params) *int { return &p.SharkBreed }},
+	{"shark-starve", func(p *Params) *int { return &p.SharkStarve }},
+}
+
+// ParseSchedule parses a schedule written one season per line or separated by semicolons, each season being its
+// name, its length in chronons and the parameters it changes, for example
+//
+//	summer 200 fish-breed=3 shark-breed=4; winter 200 fish-breed=8 shark-starve=3
+//
+// Input:
+//   - text (string): The schedule. Blank lines and anything after a "#" are ignored.
+//   - base (Params): The thresholds a season starts from; each season only overrides those it names.
+//
+// Output:
+//   - Schedule: The seasons in order.
+//   - error: Returns an error naming the season at fault if the schedule is empty or a season is malformed.
+func ParseSchedule(text string, base Params) (Schedule, error) {
+	var schedule Schedule
+	for _, line := range strings.Split(text, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		for _, entry := range strings.Split(line, ";") {
+			fields := strings.Fields(entry)
+			if len(fields) == 0 {
+				continue
+			}
+			season, err := parseSeason(fields, base)
+			if err != nil {
+				return nil, fmt.Errorf("season %d (%q): %w", len(schedule)+1, strings.TrimSpace(entry), err)
+			}
+			schedule = append(schedule, season)
+		}
+	}
+	if len(schedule) == 0 {
+		return nil, fmt.Errorf("schedule has no seasons")
+	}
+	return schedule, nil
+}
+
+// parseSeason parses the fields of one season: its name, its length and its name=value parameters.
+func parseSeason(fields []string, base Params) (Season, error) {
+	if len(fields) < 2 {
+		return Season{}, fmt.Errorf("want a name and a length in chronons")
+	}
+	length, err := strconv.Atoi(fields[1])
+	if err != nil || length < 1 {
+		return Season{}, fmt.Errorf("length must be a whole number of chronons above 0, got %q", fields[1])
+	}
+
+	season := Season{Name: fields[0], Length: length, Params: base}
+	for _, field := range fields[2:] {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return Season{}, fmt.Errorf("want name=value, got %q", field)
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return Season{}, fmt.Errorf("%s must be a whole number, got %q", name, value)
+		}
+		found := false
+		for _, param := range scheduleParams {
+			if param.name == name {
+				*param.value(&season.Params) = n
+				found = true
+			}
+		}
+		if !found {
+			return Season{}, fmt.Errorf("unknown parameter %q (want fish-breed, shark-breed or shark-starve)", name)
+		}
+	}
+	if err := season.Params.Validate(); err != nil {
+		return Season{}, err
+	}
+	return season, nil
+}
+
+// Period returns the number of chronons in one cycle of the schedule.
+func (s Schedule) Period() int {
+	period := 0
+	for _, season := range s {
+		period += season.Length
+	}
+	return period
+}
+
+// At returns the index of the season in effect after chronon chronons have run, that is for the next Step, and how
+// many chronons of it have already run.
+func (s Schedule) At(chronon int) (index, into int) {
+	offset := chronon % s.Period()
+	for i, season := range s {
+		if offset < season.Length {
+			return i, offset
+		}
+		offset -= season.Length
+	}
+	return len(s) - 1, offset // Unreachable, since the offset is below the period.
+}
+
+// Begins reports whether a season begins with the next Step after chronon chronons have run, and which.
+// It is true before the very first chronon, so the first season's parameters are applied from the start.
+func (s Schedule) Begins(chronon int) (int, bool) {
+	season, into := s.At(chronon)
+	return season, into == 0
+}
@@ -0,0 +1,43 @@
+package wator
+
+import "testing"
+
+func TestParseSchedule(t *testing.T) {
+	base := Params{FishBreed: 5, SharkBreed: 5, SharkStarve: 5}
+	schedule, err := ParseSchedule("summer 3 fish-breed=2 shark-breed=4 # plenty of food\nwinter 2 shark-starve=3;", base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Schedule{
+		{"summer", 3, Params{FishBreed: 2, SharkBreed: 4, SharkStarve: 5}},
+		{"winter", 2, Params{FishBreed: 5, SharkBreed: 5, SharkStarve: 3}},
+	}
+	if len(schedule) != len(want) || schedule[0] != want[0] || schedule[1] != want[1] {
+		t.Fatalf("ParseSchedule = %+v, want %+v", schedule, want)
+	}
+
+	for _, bad := range []string{"", "summer", "summer 0", "summer 3 fish-breed", "summer 3 fish-breed=x", "summer 3 sharks=2", "summer 3 shark-starve=0"} {
+		if _, err := ParseSchedule(bad, base); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestScheduleBegins(t *testing.T) {
+	schedule := Schedule{{Name: "summer", Length: 3}, {Name: "winter", Length: 2}}
+	if schedule.Period() != 5 {
+		t.Fatalf("Period = %d, want 5", schedule.Period())
+	}
+	// Seasons for the chronons after 0 to 10 have run, with a capital where a season begins.
+	want := "SssWwSssWwS"
+	for chronon, w := range want {
+		season, begins := schedule.Begins(chronon)
+		got := "sw"[season]
+		if begins {
+			got -= 'a' - 'A'
+		}
+		if rune(got) != w {
+			t.Errorf("after chronon %d: got %c, want %c", chronon, got, w)
+		}
+	}
+}
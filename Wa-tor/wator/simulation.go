@@ -0,0 +1,460 @@
+package wator
+
+import (
+	"fmt"       // Formats errors for invalid parameters and mismatched occupancy grids.
+	"math/rand" // Generates the starting population.
+	"slices"    // Removes creatures from their partition's list.
+	"time"      // Seeds from the clock and measures step time.
+
+	"Barrier/barrier" // Provides the instrumented boundary mutexes and the barriers the partitions' workers meet at.
+	"Barrier/pool"    // Runs the partitions on a work-stealing pool with Config.Engine WorkStealing.
+)
+
+// creature is a fish, a shark or a land cell on the grid.
+type creature struct {
+	kind       Cell // Fish, Shark or Land.
+	x, y       int  // Position on the grid.
+	breedTimer int  // Moves made since the creature last bred.
+	breedAt    int  // The creature's own breed threshold, inherited from its parent, when Config.Mutation is set.
+	starve     int  // Moves a shark has made since it last ate.
+	age        int  // Chronons the creature has lived through since it was born or placed.
+	infected   bool // Whether a fish has caught the disease.
+	infectedAt int  // The chronon during which an infected fish caught the disease; 0 for fish infected at the start.
+	dead       bool // Set when a fish is eaten or a shark starves; the creature is dropped from its list after the chronon.
+}
+
+// land is shared by every land cell, since land never moves or changes.
+var land = &creature{kind: Land}
+
+// Simulation is a running Wa-Tor world.
+//
+// A Simulation is not safe for concurrent use: Step, SetParams, Snapshot and Stats must be called from one goroutine
+// (Step uses its own goroutines internally when Config.Threads is above one). Close a simulation with more than one
+// thread once it is no longer needed, to stop those goroutines.
+type Simulation struct {
+	cfg        Config           // The configuration, with the seed actually used.
+	grid       [][]*creature    // grid[x][y] holds the creature in each cell, or nil for open water.
+	view       []Cell           // The grid as it was at the end of the last chronon, row by row, for sight and crowding; nil without either.
+	partitions []*partition     // Regions of the grid stepped concurrently, each owning the creatures inside it.
+	boundaries []*barrier.Mutex // The boundary mutexes in lock order when Config.InstrumentLocks is set; otherwise nil.
+	chronons   *barrier.Barrier // Where Step and the workers meet to start and finish a chronon; nil until the workers start.
+	phases     *barrier.Barrier // Where the workers meet between the phases of a chronon.
+	pool       *pool.Pool       // Runs the phases of the partitions with Config.Engine WorkStealing; nil until the first Step.
+	closed     bool             // Set by Close, which stops the workers.
+	maxParams  Params           // The largest of each threshold in effect since the simulation started, for Check.
+	chronon    int              // Number of chronons simulated.
+	elapsed    time.Duration    // Total time spent in Step.
+}
+
+// New creates a simulation from cfg.
+//
+// Input:
+//   - cfg (Config): The grid size, thread count, thresholds and optional starting layout.
+//
+// Output:
+//   - *Simulation: A simulation at chronon 0.
+//   - error: Returns an error if cfg is invalid.
+//
+// Functionality:
+// Without a Layout the grid is filled at random, with cfg.FishDensity and cfg.SharkDensity creatures per cell
+// spread over the grid as cfg.Distribution describes. With the disease enabled, cfg.Disease.Fraction of the starting
+// fish are then infected at random.
+func New(cfg Config) (*Simulation, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+
+	s := &Simulation{cfg: cfg, maxParams: cfg.Params}
+	s.partitions, s.boundaries = newPartitions(cfg.Width, cfg.Height, cfg.Threads, cfg.Seed, cfg.InstrumentLocks)
+	cells := make([]*creature, cfg.Width*cfg.Height)
+	s.grid = make([][]*creature, cfg.Width)
+	for x := range s.grid {
+		s.grid[x] = cells[x*cfg.Height : (x+1)*cfg.Height : (x+1)*cfg.Height]
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	if cfg.Layout != nil {
+		for y := 0; y < cfg.Height; y++ {
+			for x := 0; x < cfg.Width; x++ {
+				s.place(cfg.Layout.At(x, y), x, y)
+			}
+		}
+	} else {
+		s.populate(rng)
+	}
+	if cfg.Disease.Enabled() {
+		s.infectStart(rng)
+	}
+	if cfg.SightRadius >= 2 || cfg.Crowding > 0 {
+		s.view = make([]Cell, cfg.Width*cfg.Height)
+		for _, p := range s.partitions {
+			s.record(p)
+		}
+	}
+
+	return s, nil
+}
+
+// populate places the random starting population, drawing from rng.
+// Each cell holds a fish with probability FishDensity and a shark with probability SharkDensity, both multiplied by
+// the distribution's weight for the cell and scaled down together wherever they would add up to more than one.
+func (s *Simulation) populate(rng *rand.Rand) {
+	weights := s.cfg.Distribution.weights(s.cfg.Width, s.cfg.Height, rng)
+	for x := 0; x < s.cfg.Width; x++ {
+		for y := 0; y < s.cfg.Height; y++ {
+			fishWeight, sharkWeight := weights(x, y)
+			fish, sharks := s.cfg.FishDensity*fishWeight, s.cfg.SharkDensity*sharkWeight
+			if total := fish + sharks; total > 1 {
+				fish, sharks = fish/total, sharks/total
+			}
+			switch r := rng.Float64(); {
+			case r < fish:
+				s.place(Fish, x, y)
+			case r < fish+sharks:
+				s.place(Shark, x, y)
+			}
+		}
+	}
+}
+
+// place puts a new creature of the given kind at (x, y) and adds it to the list of the partition containing it.
+func (s *Simulation) place(kind Cell, x, y int) {
+	switch kind {
+	case Fish:
+		fish := &creature{kind: Fish, x: x, y: y, breedAt: s.cfg.FishBreed}
+		s.grid[x][y] = fish
+		p := s.partitions[s.partitionIndex(x, y)]
+		p.fish = append(p.fish, fish)
+	case Shark:
+		shark := &creature{kind: Shark, x: x, y: y, breedAt: s.cfg.SharkBreed}
+		s.grid[x][y] = shark
+		p := s.partitions[s.partitionIndex(x, y)]
+		p.sharks = append(p.sharks, shark)
+	case Land:
+		s.grid[x][y] = land
+	}
+}
+
+// Config returns the simulation's configuration, including the seed chosen when Config.Seed was zero
+// and any parameters changed with SetParams.
+func (s *Simulation) Config() Config {
+	return s.cfg
+}
+
+// SetParams changes the breeding and starvation thresholds from the next chronon on.
+func (s *Simulation) SetParams(p Params) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("invalid parameters: %w", err)
+	}
+	s.cfg.Params = p
+	s.maxParams = Params{
+		FishBreed:   max(s.maxParams.FishBreed, p.FishBreed),
+		SharkBreed:  max(s.maxParams.SharkBreed, p.SharkBreed),
+		SharkStarve: max(s.maxParams.SharkStarve, p.SharkStarve),
+	}
+	return nil
+}
+
+// Step advances the simulation by one chronon.
+//
+// Functionality:
+//  1. Steps every partition over the fish and sharks it owns.
+//  2. Has every partition drop its dead, add its newborns and hand the creatures that crossed a boundary to the
+//     partition they are now in.
+//  3. Has every partition collect the creatures handed to it.
+//
+// When there is more than one partition and Config.Deterministic is off, each partition is stepped by its own worker
+// goroutine, started by the first Step and kept until Close, so the consolidation of births, deaths and migrations is
+// spread over the threads as well as the movement. The workers meet at a barrier between the phases, and Step meets
+// them at another to start the chronon and again once it is done. With Config.Engine WorkStealing, each phase of each
+// partition is instead submitted to a work-stealing pool, also started by the first Step, and Step waits for the pool
+// to finish the phase before submitting the next. Otherwise the partitions are stepped one at a time in index order on
+// the calling goroutine. Step panics if called after Close.
+func (s *Simulation) Step() {
+	start := time.Now()
+	switch {
+	case s.closed:
+		panic("wator: Step after Close")
+	case len(s.partitions) == 1 || s.cfg.Deterministic:
+		for i, p := range s.partitions {
+			s.move(i, p)
+		}
+		for i, p := range s.partitions {
+			s.settle(i, p)
+		}
+		for i, p := range s.partitions {
+			s.collect(i, p)
+		}
+	case s.cfg.Engine == WorkStealing:
+		if s.pool == nil {
+			s.pool = pool.New(len(s.partitions))
+		}
+		for _, phase := range []func(int, *partition){s.move, s.settle, s.collect} {
+			for i, p := range s.partitions {
+				s.pool.Submit(func() { phase(i, p) })
+			}
+			s.pool.Wait() // Every partition has finished the phase before any starts the next, as at the barriers.
+		}
+	default:
+		if s.chronons == nil {
+			s.startWorkers()
+		}
+		s.chronons.Wait() // Start the workers on the chronon,
+		s.chronons.Wait() // and wait for them to finish it.
+	}
+	s.chronon++
+	s.elapsed += time.Since(start)
+}
+
+// move steps partition i over the fish and sharks it owns, the movement phase of a chronon.
+func (s *Simulation) move(i int, p *partition) {
+	s.runPartition(p, p.fish, p.sharks)
+}
+
+// startWorkers starts a worker goroutine for every partition, replacing the goroutines and sync.WaitGroup that each
+// phase used to start and wait for. Step and the workers meet at s.chronons, of one party more than there are
+// workers, to start and finish each chronon, and the workers meet at s.phases between the phases.
+func (s *Simulation) startWorkers() {
+	s.chronons = barrier.New(len(s.partitions) + 1)
+	s.phases = barrier.New(len(s.partitions))
+	for i, p := range s.partitions {
+		go s.work(i, p)
+	}
+}
+
+// work steps partition i through every chronon until the simulation is closed. Nothing can break the barriers, so
+// their errors are not checked.
+func (s *Simulation) work(i int, p *partition) {
+	for {
+		s.chronons.Wait()
+		if s.closed {
+			return
+		}
+		s.move(i, p)
+		s.phases.Wait() // Every partition has moved, so none is still adding to the lists settle reads.
+		s.settle(i, p)
+		s.phases.Wait() // Every partition has settled, so the creatures handed to i are all in fishOut and sharksOut.
+		s.collect(i, p)
+		s.chronons.Wait()
+	}
+}
+
+// Close stops the worker goroutines that step the partitions, or the pool that runs them, which a simulation with more
+// than one thread keeps from one Step to the next. Step must not be called after Close. It does nothing if no workers
+// were started, or if they have already been stopped, so a simulation that may not have been stepped concurrently can
+// still be closed. A second Close returns before writing closed, which the workers released by the first one read.
+func (s *Simulation) Close() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	if s.pool != nil {
+		s.pool.Close()
+	}
+	if s.chronons != nil {
+		s.chronons.Wait() // Releases the workers waiting to start a chronon, which see closed and return.
+	}
+}
+
+// Snapshot returns a copy of the grid.
+func (s *Simulation) Snapshot() Snapshot {
+	snap := Snapshot{Chronon: s.chronon, Width: s.cfg.Width, Height: s.cfg.Height, Cells: make([]Cell, s.cfg.Width*s.cfg.Height)}
+	if s.cfg.Disease.Enabled() {
+		snap.Infected = make([]bool, len(snap.Cells))
+	}
+	for x, column := range s.grid {
+		for y, c := range column {
+			if c != nil {
+				snap.Cells[y*s.cfg.Width+x] = c.kind
+				if c.infected {
+					snap.Infected[y*s.cfg.Width+x] = true
+				}
+			}
+		}
+	}
+	return snap
+}
+
+// Inspect describes the creature in the cell (x, y), for debugging individual behaviour.
+// It reports false if the cell is empty or outside the grid.
+func (s *Simulation) Inspect(x, y int) (CreatureInfo, bool) {
+	if x < 0 || x >= s.cfg.Width || y < 0 || y >= s.cfg.Height || s.grid[x][y] == nil {
+		return CreatureInfo{}, false
+	}
+	c := s.grid[x][y]
+	if c.kind == Land {
+		return CreatureInfo{Kind: Land, X: x, Y: y}, true // Land is shared between cells, so its position is not stored.
+	}
+	return CreatureInfo{Kind: c.kind, X: c.x, Y: c.y, BreedTimer: c.breedTimer, BreedThreshold: s.breedThreshold(c), Starve: c.starve, Age: c.age, Infected: c.infected}, true
+}
+
+// Remove takes whatever is in the cell (x, y) off the grid, a fish or shark from its partition's list too, and
+// describes it as Inspect does. It reports false if the cell is empty or outside the grid. With Place it moves creatures
+// between simulations, such as the strips of a distributed run, between chronons.
+func (s *Simulation) Remove(x, y int) (CreatureInfo, bool) {
+	info, ok := s.Inspect(x, y)
+	if !ok {
+		return CreatureInfo{}, false
+	}
+	if c := s.grid[x][y]; c.kind != Land {
+		p := s.partitions[s.partitionIndex(x, y)]
+		list := &p.fish
+		if c.kind == Shark {
+			list = &p.sharks
+		}
+		i := slices.Index(*list, c)
+		*list = slices.Delete(*list, i, i+1)
+	}
+	s.grid[x][y] = nil
+	if s.view != nil {
+		s.view[y*s.cfg.Width+x] = Empty
+	}
+	return info, true
+}
+
+// Place puts what info describes into the open water at (info.X, info.Y), between chronons. A fish or shark keeps the
+// breed timer, starve counter and age in info, and its breed threshold when Config.Mutation is set; an infected fish is
+// placed as if it had caught the disease during the last chronon. Land only needs its position.
+// It returns an error if the cell is outside the grid or not open water, or info.Kind is Empty.
+func (s *Simulation) Place(info CreatureInfo) error {
+	x, y := info.X, info.Y
+	switch {
+	case x < 0 || x >= s.cfg.Width || y < 0 || y >= s.cfg.Height:
+		return fmt.Errorf("cell (%d, %d) is outside the %dx%d grid", x, y, s.cfg.Width, s.cfg.Height)
+	case s.grid[x][y] != nil:
+		return fmt.Errorf("cell (%d, %d) already holds %s", x, y, s.grid[x][y].kind)
+	case info.Kind != Fish && info.Kind != Shark && info.Kind != Land:
+		return fmt.Errorf("cannot place %s", info.Kind)
+	}
+	s.place(info.Kind, x, y)
+	if c := s.grid[x][y]; c.kind != Land {
+		c.breedTimer, c.starve, c.age = info.BreedTimer, info.Starve, info.Age
+		if s.cfg.Mutation > 0 {
+			c.breedAt = info.BreedThreshold
+		}
+		if info.Infected && c.kind == Fish {
+			c.infected, c.infectedAt = true, s.chronon
+		}
+	}
+	if s.view != nil {
+		s.view[y*s.cfg.Width+x] = info.Kind
+	}
+	return nil
+}
+
+// AddOccupancy counts the current chronon in o, which must be the size of the grid.
+// It reads the grid directly rather than through a Snapshot, so it can be called after every chronon without allocating.
+func (s *Simulation) AddOccupancy(o *Occupancy) error {
+	if o.Width != s.cfg.Width || o.Height != s.cfg.Height {
+		return fmt.Errorf("occupancy is %dx%d but the grid is %dx%d", o.Width, o.Height, s.cfg.Width, s.cfg.Height)
+	}
+	for x, column := range s.grid {
+		for y, c := range column {
+			if c == nil {
+				continue
+			}
+			switch c.kind {
+			case Fish:
+				o.Fish[y*s.cfg.Width+x]++
+			case Shark:
+				o.Sharks[y*s.cfg.Width+x]++
+			}
+		}
+	}
+	o.Chronons++
+	return nil
+}
+
+// LockStats counts how often and how long partitions waited on the boundary locks shared with their neighbours.
+type LockStats struct {
+	Acquisitions int64         // Boundary locks taken.
+	Contended    int64         // Acquisitions that had to wait because a neighbour held the lock.
+	Wait         time.Duration // Total time spent waiting for contended locks.
+}
+
+// BucketStats describes the creatures owned by one partition, which it keeps in its own lists and hands to its
+// neighbours when they cross a boundary.
+type BucketStats struct {
+	Fish      int   // Living fish in the partition.
+	Sharks    int   // Living sharks in the partition.
+	HandedOff int64 // Creatures handed to a neighbouring partition after moving into it.
+}
+
+// Flows counts the births and deaths since a simulation started. Together with Stats.DiseaseDeaths they account for
+// every change in the populations, so comparing them with the populations checks that no creature was lost or
+// duplicated.
+type Flows struct {
+	FishBorn      int64 // Fish born.
+	FishEaten     int64 // Fish eaten by sharks.
+	SharksBorn    int64 // Sharks born.
+	SharksStarved int64 // Sharks that starved.
+}
+
+// Stats summarises the state of a simulation.
+type Stats struct {
+	Chronon        int                  // Number of chronons simulated.
+	Fish           int                  // Number of living fish.
+	Sharks         int                  // Number of living sharks.
+	Elapsed        time.Duration        // Total time spent in Step.
+	Locks          LockStats            // Boundary lock statistics summed over every partition.
+	PartitionLocks []LockStats          // Boundary lock statistics of each partition.
+	Buckets        []BucketStats        // The creatures owned by each partition, in the same order as PartitionLocks.
+	Boundaries     []barrier.MutexStats // How each boundary mutex was used, in lock order, when Config.InstrumentLocks is set; otherwise nil.
+	CrowdedCells   int64                // Empty cells territorial sharks tried last because other sharks crowded them.
+	Infected       int                  // Number of living infected fish.
+	DiseaseDeaths  int64                // Fish that have died of the disease.
+	Flows          Flows                // Births and deaths since the simulation started.
+}
+
+// Chronon returns the number of chronons simulated.
+func (s *Simulation) Chronon() int {
+	return s.chronon
+}
+
+// Population returns the number of living fish and sharks.
+// Unlike Stats it does not allocate, so it can be called after every chronon.
+func (s *Simulation) Population() (fish, sharks int) {
+	for _, p := range s.partitions {
+		fish += len(p.fish)
+		sharks += len(p.sharks)
+	}
+	return fish, sharks
+}
+
+// Stats returns the current populations and the accumulated timing, lock, handoff, crowding, disease and birth and
+// death statistics.
+func (s *Simulation) Stats() Stats {
+	fish, sharks := s.Population()
+	st := Stats{
+		Chronon:        s.chronon,
+		Fish:           fish,
+		Sharks:         sharks,
+		Infected:       s.Infected(),
+		Elapsed:        s.elapsed,
+		PartitionLocks: make([]LockStats, len(s.partitions)),
+		Buckets:        make([]BucketStats, len(s.partitions)),
+	}
+	for i, p := range s.partitions {
+		st.PartitionLocks[i] = p.locks
+		st.Buckets[i] = BucketStats{Fish: len(p.fish), Sharks: len(p.sharks), HandedOff: p.handedOff}
+		st.Locks.Acquisitions += p.locks.Acquisitions
+		st.Locks.Contended += p.locks.Contended
+		st.Locks.Wait += p.locks.Wait
+		st.CrowdedCells += p.crowdedCells
+		st.DiseaseDeaths += p.diseaseDeaths
+		st.Flows.FishBorn += p.flows.FishBorn
+		st.Flows.FishEaten += p.flows.FishEaten
+		st.Flows.SharksBorn += p.flows.SharksBorn
+		st.Flows.SharksStarved += p.flows.SharksStarved
+	}
+	if s.boundaries != nil {
+		st.Boundaries = make([]barrier.MutexStats, len(s.boundaries))
+		for i, mu := range s.boundaries {
+			st.Boundaries[i] = mu.Stats()
+		}
+	}
+	return st
+}
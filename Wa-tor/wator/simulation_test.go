@@ -0,0 +1,626 @@
+package wator
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"Barrier/barrier"
+)
+
+// checkConsistent fails the test if the fish and shark lists disagree with the grid.
+func checkConsistent(t *testing.T, s *Simulation) {
+	t.Helper()
+	snap := s.Snapshot()
+	stats := s.Stats()
+	if got := snap.Count(Fish); got != stats.Fish {
+		t.Fatalf("chronon %d: %d fish on the grid but %d in the list", stats.Chronon, got, stats.Fish)
+	}
+	if got := snap.Count(Shark); got != stats.Sharks {
+		t.Fatalf("chronon %d: %d sharks on the grid but %d in the list", stats.Chronon, got, stats.Sharks)
+	}
+	for i, p := range s.partitions {
+		for _, list := range [][]*creature{p.fish, p.sharks} {
+			for _, c := range list {
+				if s.grid[c.x][c.y] != c {
+					t.Fatalf("chronon %d: %s at (%d, %d) is not on the grid", stats.Chronon, c.kind, c.x, c.y)
+				}
+				if !p.contains(c.x, c.y) {
+					t.Fatalf("chronon %d: %s at (%d, %d) is listed by partition %d, which does not contain it", stats.Chronon, c.kind, c.x, c.y, i)
+				}
+			}
+		}
+	}
+}
+
+func TestStepKeepsListsAndGridInSync(t *testing.T) {
+	for _, threads := range []int{1, 2, 4, 8} {
+		cfg := DefaultConfig()
+		cfg.Threads = threads
+		cfg.Seed = 1
+		cfg.SightRadius = 3
+		s, err := New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(s.Close)
+		for i := 0; i < 200; i++ {
+			s.Step()
+			checkConsistent(t, s)
+			if err := s.Check(); err != nil {
+				t.Fatalf("%d threads: %v", threads, err)
+			}
+		}
+
+		stats := s.Stats()
+		fish, sharks, handedOff := 0, 0, int64(0)
+		for _, bucket := range stats.Buckets {
+			fish += bucket.Fish
+			sharks += bucket.Sharks
+			handedOff += bucket.HandedOff
+		}
+		if len(stats.Buckets) != threads || fish != stats.Fish || sharks != stats.Sharks {
+			t.Errorf("%d threads: %d buckets holding %d fish and %d sharks, want %d holding %d and %d",
+				threads, len(stats.Buckets), fish, sharks, threads, stats.Fish, stats.Sharks)
+		}
+		if (handedOff > 0) != (threads > 1) {
+			t.Errorf("%d threads: %d creatures handed between partitions", threads, handedOff)
+		}
+	}
+}
+
+func TestBreedingAtBoundaries(t *testing.T) {
+	// 4x4 partitions, where every cell but four in each partition lies on a boundary, and creatures breeding
+	// every chronon, so most births happen in cells a neighbouring partition is moving into at the same time.
+	cfg := DefaultConfig()
+	cfg.Width, cfg.Height = 16, 8
+	cfg.Threads = 8
+	cfg.Seed = 3
+	cfg.Params = Params{FishBreed: 1, SharkBreed: 1, SharkStarve: 2}
+	cfg.FishDensity, cfg.SharkDensity = 0.3, 0.1
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Close)
+	for i := 0; i < 500; i++ {
+		s.Step()
+		checkConsistent(t, s)
+		if err := s.Check(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestBoundariesRace steps small partitions with every behaviour that touches a neighbouring partition's cells switched
+// on: breeding, eating, the disease, hunting and territorial sharks. It only finds unguarded reads and writes when run
+// under the race detector, with go test -race ./wator.
+func TestBoundariesRace(t *testing.T) {
+	for _, engine := range []Engine{PersistentWorkers, WorkStealing} {
+		cfg := DefaultConfig()
+		cfg.Width, cfg.Height = 16, 8
+		cfg.Threads = 8
+		cfg.Engine = engine
+		cfg.Order = Interleaved
+		cfg.Seed = 4
+		cfg.Params = Params{FishBreed: 1, SharkBreed: 2, SharkStarve: 3}
+		cfg.FishDensity, cfg.SharkDensity = 0.3, 0.1
+		cfg.SightRadius = 3
+		cfg.Crowding = 1
+		cfg.Disease = Disease{Fraction: 0.2, Spread: 0.3, Lifetime: 5}
+		s, err := New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(s.Close)
+		for i := 0; i < 200; i++ {
+			s.Step()
+			if err := s.Check(); err != nil {
+				t.Fatalf("%v: %v", engine, err)
+			}
+		}
+	}
+}
+
+func TestFlowsBalance(t *testing.T) {
+	for _, threads := range []int{1, 4} {
+		cfg := DefaultConfig()
+		cfg.Threads = threads
+		cfg.Seed = 2
+		cfg.Disease = Disease{Fraction: 0.2, Spread: 0.3, Lifetime: 5}
+		s, err := New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(s.Close)
+		last := s.Stats()
+		for i := 0; i < 200; i++ {
+			s.Step()
+			stats := s.Stats()
+			born, eaten, diseased := stats.Flows.FishBorn-last.Flows.FishBorn, stats.Flows.FishEaten-last.Flows.FishEaten, stats.DiseaseDeaths-last.DiseaseDeaths
+			if got, want := int64(stats.Fish-last.Fish), born-eaten-diseased; got != want {
+				t.Fatalf("%d threads, chronon %d: fish changed by %d, but %d were born, %d eaten and %d died of the disease",
+					threads, stats.Chronon, got, born, eaten, diseased)
+			}
+			born, starved := stats.Flows.SharksBorn-last.Flows.SharksBorn, stats.Flows.SharksStarved-last.Flows.SharksStarved
+			if got, want := int64(stats.Sharks-last.Sharks), born-starved; got != want {
+				t.Fatalf("%d threads, chronon %d: sharks changed by %d, but %d were born and %d starved",
+					threads, stats.Chronon, got, born, starved)
+			}
+			last = stats
+		}
+		if last.Flows.FishBorn == 0 || last.Flows.FishEaten == 0 || last.Flows.SharksBorn == 0 || last.Flows.SharksStarved == 0 {
+			t.Errorf("%d threads: flows %+v are missing births or deaths", threads, last.Flows)
+		}
+	}
+}
+
+func TestSameSeedGivesSameStart(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Seed = 42
+	a, _ := New(cfg)
+	b, _ := New(cfg)
+	if sa, sb := a.Snapshot(), b.Snapshot(); string(cellBytes(sa)) != string(cellBytes(sb)) {
+		t.Fatal("two simulations with the same seed started differently")
+	}
+}
+
+func TestSameConfigGivesSameRun(t *testing.T) {
+	for _, threads := range []int{1, 4} {
+		cfg := DefaultConfig()
+		cfg.Threads = threads
+		cfg.Seed = 7
+		cfg.SightRadius = 3
+		cfg.Deterministic = threads > 1
+		a, _ := New(cfg)
+		b, _ := New(cfg)
+		t.Cleanup(a.Close)
+		t.Cleanup(b.Close)
+		for i := 0; i < 100; i++ {
+			a.Step()
+			b.Step()
+			if string(cellBytes(a.Snapshot())) != string(cellBytes(b.Snapshot())) {
+				t.Fatalf("%d threads: runs with the same config diverged at chronon %d", threads, i+1)
+			}
+		}
+	}
+}
+
+func cellBytes(s Snapshot) []byte {
+	b := make([]byte, len(s.Cells))
+	for i, c := range s.Cells {
+		b[i] = byte(c)
+	}
+	return b
+}
+
+func TestOccupancy(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Seed = 3
+	s, _ := New(cfg)
+	o := NewOccupancy(cfg.Width, cfg.Height)
+	wantFish, wantSharks := 0, 0
+	for i := 0; i < 50; i++ {
+		s.Step()
+		if err := s.AddOccupancy(o); err != nil {
+			t.Fatal(err)
+		}
+		fish, sharks := s.Population()
+		wantFish += fish
+		wantSharks += sharks
+	}
+	if o.Chronons != 50 || sum(o.Fish) != wantFish || sum(o.Sharks) != wantSharks {
+		t.Fatalf("counted %d chronons, %d fish and %d sharks, want 50, %d and %d", o.Chronons, sum(o.Fish), sum(o.Sharks), wantFish, wantSharks)
+	}
+	if err := s.AddOccupancy(NewOccupancy(10, 10)); err == nil {
+		t.Fatal("an occupancy of the wrong size was accepted")
+	}
+}
+
+// sum returns the total of counts.
+func sum(counts []int) int {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return total
+}
+
+func TestInspect(t *testing.T) {
+	layout := NewSnapshot(10, 10)
+	layout.Set(2, 3, Shark)
+	layout.Set(7, 7, Land)
+	cfg := DefaultConfig()
+	cfg.Width, cfg.Height = 10, 10
+	cfg.Layout = layout
+	s, _ := New(cfg)
+	for i := 0; i < 3; i++ {
+		s.Step()
+	}
+
+	var shark CreatureInfo
+	found := false
+	for x := 0; x < 10 && !found; x++ {
+		for y := 0; y < 10 && !found; y++ {
+			if info, ok := s.Inspect(x, y); ok && info.Kind == Shark {
+				shark, found = info, true
+			}
+		}
+	}
+	if !found || shark.Age != 3 || shark.Starve != 3 || shark.BreedTimer != 3 {
+		t.Errorf("shark after 3 chronons without food: %+v, found %v", shark, found)
+	}
+	if info, ok := s.Inspect(7, 7); !ok || info.Kind != Land {
+		t.Errorf("Inspect(7, 7) = %+v, %v, want land", info, ok)
+	}
+	if _, ok := s.Inspect(10, 0); ok {
+		t.Error("a cell outside the grid was inspected")
+	}
+}
+
+func TestPlaceAndRemove(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Width, cfg.Height, cfg.Threads = 10, 10, 4
+	cfg.Layout = NewSnapshot(10, 10)
+	s, _ := New(cfg)
+	t.Cleanup(s.Close)
+	shark := CreatureInfo{Kind: Shark, X: 7, Y: 8, BreedTimer: 2, BreedThreshold: 5, Starve: 3, Age: 9}
+	for _, info := range []CreatureInfo{shark, {Kind: Fish, X: 1, Y: 1, BreedThreshold: 5}, {Kind: Land, X: 4, Y: 4}} {
+		if err := s.Place(info); err != nil {
+			t.Fatalf("Place(%+v): %v", info, err)
+		}
+	}
+	for _, bad := range []CreatureInfo{{Kind: Fish, X: 7, Y: 8}, {Kind: Fish, X: 10, Y: 0}, {Kind: Empty, X: 0, Y: 0}} {
+		if err := s.Place(bad); err == nil {
+			t.Errorf("Place(%+v) succeeded", bad)
+		}
+	}
+	if fish, sharks := s.Population(); fish != 1 || sharks != 1 {
+		t.Fatalf("%d fish and %d sharks placed, want 1 and 1", fish, sharks)
+	}
+	if err := s.Check(); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, ok := s.Remove(7, 8); !ok || info != shark {
+		t.Errorf("Remove(7, 8) = %+v, %v, want %+v", info, ok, shark)
+	}
+	if info, ok := s.Remove(4, 4); !ok || info.Kind != Land {
+		t.Errorf("Remove(4, 4) = %+v, %v, want land", info, ok)
+	}
+	if _, ok := s.Remove(4, 4); ok {
+		t.Error("an empty cell was removed")
+	}
+	if fish, sharks := s.Population(); fish != 1 || sharks != 0 {
+		t.Fatalf("%d fish and %d sharks after removing the shark, want 1 and 0", fish, sharks)
+	}
+	if err := s.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCrowding(t *testing.T) {
+	// The cell north of the shark at (3, 3) is next to two other sharks, the cell east of it to one.
+	layout := NewSnapshot(7, 7)
+	layout.Set(3, 3, Shark)
+	layout.Set(2, 2, Shark)
+	layout.Set(4, 2, Shark)
+	cfg := DefaultConfig()
+	cfg.Width, cfg.Height = 7, 7
+	cfg.Layout = layout
+	cfg.Crowding = 2
+	s, _ := New(cfg)
+	if !s.crowded(3, 2, 3, 3) || s.crowded(4, 3, 3, 3) {
+		t.Fatal("crowded cells misjudged")
+	}
+	for i := 0; i < 20; i++ {
+		if directions := s.huntingDirections(s.partitions[0], 3, 3); directions[3] != north {
+			t.Fatalf("directions %v do not try the crowded cell last", directions)
+		}
+	}
+	if got := s.Stats().CrowdedCells; got != 20 {
+		t.Errorf("CrowdedCells = %d, want 20", got)
+	}
+
+	for _, threads := range []int{1, 4} {
+		cfg := DefaultConfig()
+		cfg.Threads = threads
+		cfg.Seed = 5
+		cfg.SharkDensity = 0.1
+		cfg.Crowding = 1
+		s, _ := New(cfg)
+		t.Cleanup(s.Close)
+		for i := 0; i < 100; i++ {
+			s.Step()
+			if err := s.Check(); err != nil {
+				t.Fatalf("%d threads: %v", threads, err)
+			}
+		}
+		if s.Stats().CrowdedCells == 0 {
+			t.Errorf("%d threads: no shark avoided a crowd", threads)
+		}
+	}
+}
+
+func TestLayout(t *testing.T) {
+	layout := NewSnapshot(10, 8)
+	layout.Set(1, 1, Fish)
+	layout.Set(5, 5, Shark)
+	layout.Set(9, 7, Land)
+
+	cfg := DefaultConfig()
+	cfg.Width, cfg.Height = 10, 8
+	cfg.Layout = layout
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := s.Stats(); stats.Fish != 1 || stats.Sharks != 1 {
+		t.Fatalf("got %d fish and %d sharks, want 1 and 1", stats.Fish, stats.Sharks)
+	}
+	for i := 0; i < 20; i++ {
+		s.Step()
+		if s.Snapshot().At(9, 7) != Land {
+			t.Fatal("land cell was overwritten")
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(*Config)
+	}{
+		{"empty grid", func(c *Config) { c.Width = 0 }},
+		{"no threads", func(c *Config) { c.Threads = 0 }},
+		{"too narrow for threads", func(c *Config) { c.Width, c.Threads = 3, 4 }},
+		{"zero breed time", func(c *Config) { c.FishBreed = 0 }},
+		{"sight too far", func(c *Config) { c.SightRadius = MaxSightRadius + 1 }},
+		{"crowding too high", func(c *Config) { c.Crowding = MaxCrowding + 1 }},
+		{"infection spread above 1", func(c *Config) { c.Disease = Disease{Spread: 2, Lifetime: 5} }},
+		{"layout size", func(c *Config) { c.Layout = NewSnapshot(3, 3) }},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		tt.edit(&cfg)
+		if _, err := New(cfg); err == nil {
+			t.Errorf("%s: New accepted an invalid config", tt.name)
+		}
+	}
+}
+
+func TestPartitionLayout(t *testing.T) {
+	for _, tt := range []struct{ count, cols, rows int }{{1, 1, 1}, {2, 2, 1}, {4, 2, 2}, {8, 4, 2}, {6, 3, 2}, {7, 7, 1}} {
+		if cols, rows := partitionLayout(tt.count); cols != tt.cols || rows != tt.rows {
+			t.Errorf("partitionLayout(%d) = %dx%d, want %dx%d", tt.count, cols, rows, tt.cols, tt.rows)
+		}
+	}
+}
+
+// TestInstrumentLocks checks that instrumented boundary mutexes count every boundary lock the partitions take, and do
+// not change the run.
+func TestInstrumentLocks(t *testing.T) {
+	for _, tt := range []struct{ threads, boundaries int }{{2, 2}, {4, 8}, {8, 16}} {
+		cfg := DefaultConfig()
+		cfg.Threads = tt.threads
+		cfg.Seed = 1
+		cfg.Deterministic = true
+		plain, _ := New(cfg)
+		cfg.InstrumentLocks = true
+		instrumented, _ := New(cfg)
+		t.Cleanup(plain.Close)
+		t.Cleanup(instrumented.Close)
+		for range 50 {
+			plain.Step()
+			instrumented.Step()
+		}
+
+		stats := instrumented.Stats()
+		if plain.Stats().Boundaries != nil {
+			t.Errorf("%d threads: Boundaries set without InstrumentLocks", tt.threads)
+		}
+		if len(stats.Boundaries) != tt.boundaries {
+			t.Fatalf("%d threads: %d boundaries, want %d", tt.threads, len(stats.Boundaries), tt.boundaries)
+		}
+		var total barrier.MutexStats
+		for _, b := range stats.Boundaries {
+			total = total.Add(b)
+		}
+		if total.Acquisitions != stats.Locks.Acquisitions || total.Acquisitions == 0 {
+			t.Errorf("%d threads: boundaries locked %d times, partitions counted %d", tt.threads, total.Acquisitions, stats.Locks.Acquisitions)
+		}
+		if plain.Snapshot().Count(Fish) != stats.Fish || plain.Snapshot().Count(Shark) != stats.Sharks {
+			t.Errorf("%d threads: instrumenting the locks changed the run", tt.threads)
+		}
+	}
+}
+
+// BenchmarkStep measures the time and allocations needed to advance a freshly populated 50x50 grid by one chronon.
+// The simulation is recreated every 100 chronons so the population does not die out or fill the grid during long runs.
+func BenchmarkStep(b *testing.B) {
+	for _, threads := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("threads=%d", threads), func(b *testing.B) {
+			cfg := DefaultConfig()
+			cfg.Threads = threads
+			cfg.Seed = 1
+			s, _ := New(cfg)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if i%100 == 99 {
+					b.StopTimer()
+					s.Close()
+					s, _ = New(cfg)
+					b.StartTimer()
+				}
+				s.Step()
+			}
+			b.StopTimer()
+			s.Close()
+		})
+	}
+}
+
+// BenchmarkStepLargePopulation measures a 400x400 grid that starts half full of fish with one shark in every ten
+// cells, so the per-chronon sorting of creatures into partitions and the merging of births and deaths dominate.
+// The simulation is recreated every 20 chronons so the population stays large.
+func BenchmarkStepLargePopulation(b *testing.B) {
+	layout := NewSnapshot(400, 400)
+	for i := range layout.Cells {
+		switch {
+		case i%10 == 0:
+			layout.Cells[i] = Shark
+		case i%2 == 1:
+			layout.Cells[i] = Fish
+		}
+	}
+	for _, threads := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("threads=%d", threads), func(b *testing.B) {
+			cfg := DefaultConfig()
+			cfg.Width, cfg.Height = layout.Width, layout.Height
+			cfg.Threads = threads
+			cfg.Seed = 1
+			cfg.Layout = layout
+			s, _ := New(cfg)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if i%20 == 19 {
+					b.StopTimer()
+					s.Close()
+					s, _ = New(cfg)
+					b.StartTimer()
+				}
+				s.Step()
+			}
+			b.StopTimer()
+			s.Close()
+		})
+	}
+}
+
+// benchmarkStep measures advancing a width by height grid with the default densities by one chronon, for every
+// partitioning strategy: 1x1, 2x1, 2x2 and 4x2 partitions. The simulation is recreated from the same seed every
+// restart chronons, outside the timer, so every run of the benchmark steps through the same populations and ns/op
+// can be compared across commits. Runs with several partitions are concurrent, so their grids may differ slightly
+// from run to run as described in the package documentation.
+func benchmarkStep(b *testing.B, width, height, restart int) {
+	for _, threads := range []int{1, 2, 4, 8} {
+		cols, rows := partitionLayout(threads)
+		b.Run(fmt.Sprintf("partitions=%dx%d", cols, rows), func(b *testing.B) {
+			cfg := DefaultConfig()
+			cfg.Width, cfg.Height = width, height
+			cfg.Threads = threads
+			cfg.Seed = 1
+			s, err := New(cfg)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if i%restart == restart-1 {
+					b.StopTimer()
+					s.Close()
+					s, _ = New(cfg)
+					b.StartTimer()
+				}
+				s.Step()
+			}
+			b.StopTimer()
+			s.Close()
+		})
+	}
+}
+
+// BenchmarkStepSmall steps the default 50x50 grid, where the per-chronon overhead of the partitions shows most.
+func BenchmarkStepSmall(b *testing.B) { benchmarkStep(b, 50, 50, 100) }
+
+// BenchmarkStepMedium steps a 200x200 grid, the size used for the thread comparisons in the results files.
+func BenchmarkStepMedium(b *testing.B) { benchmarkStep(b, 200, 200, 100) }
+
+// BenchmarkStepLarge steps a 600x600 grid, where the work per partition outweighs its overhead.
+func BenchmarkStepLarge(b *testing.B) { benchmarkStep(b, 600, 600, 50) }
+
+// TestClose checks that Close stops the workers a concurrent simulation starts, and that a closed simulation can still
+// be read but not stepped.
+func TestClose(t *testing.T) {
+	before := runtime.NumGoroutine()
+	cfg := DefaultConfig()
+	cfg.Threads = 8
+	cfg.Seed = 1
+	s, _ := New(cfg)
+	s.Step()
+	if got := runtime.NumGoroutine(); got < before+8 {
+		t.Errorf("%d goroutines while stepping, want at least %d: one worker per partition", got, before+8)
+	}
+	s.Close()
+	s.Close() // Closing again does nothing.
+	for deadline := time.Now().Add(5 * time.Second); runtime.NumGoroutine() > before; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines after Close, want %d", runtime.NumGoroutine(), before)
+		}
+	}
+	if got := s.Stats().Chronon; got != 1 {
+		t.Errorf("Chronon = %d after Close, want 1", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Step after Close did not panic")
+		}
+	}()
+	s.Step()
+}
+
+// stepSpawning advances s by one chronon as Step did before the partitions had workers, starting a goroutine for each
+// partition in each phase and waiting for them with a sync.WaitGroup, for BenchmarkWorkers to compare against.
+func stepSpawning(s *Simulation) {
+	for _, phase := range []func(int, *partition){s.move, s.settle, s.collect} {
+		var wg sync.WaitGroup
+		wg.Add(len(s.partitions))
+		for i, p := range s.partitions {
+			go func() {
+				defer wg.Done()
+				phase(i, p)
+			}()
+		}
+		wg.Wait()
+	}
+	s.chronon++
+}
+
+// BenchmarkWorkers compares stepping a 50x50 grid with persistent workers meeting at barriers, as Step does, against
+// the work-stealing pool of Config.Engine WorkStealing and against starting goroutines for every phase of every
+// chronon. The grid is small so the synchronisation, rather than the moves, dominates.
+func BenchmarkWorkers(b *testing.B) {
+	for _, threads := range []int{2, 4, 8} {
+		for _, bench := range []struct {
+			name   string
+			step   func(*Simulation)
+			engine Engine
+		}{{"workers", (*Simulation).Step, PersistentWorkers}, {"pool", (*Simulation).Step, WorkStealing}, {"spawning", stepSpawning, PersistentWorkers}} {
+			b.Run(fmt.Sprintf("threads=%d/%s", threads, bench.name), func(b *testing.B) {
+				cfg := DefaultConfig()
+				cfg.Threads = threads
+				cfg.Engine = bench.engine
+				cfg.Seed = 1
+				s, _ := New(cfg)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if i%100 == 99 {
+						b.StopTimer()
+						s.Close()
+						s, _ = New(cfg)
+						b.StartTimer()
+					}
+					bench.step(s)
+				}
+				b.StopTimer()
+				s.Close()
+			})
+		}
+	}
+}
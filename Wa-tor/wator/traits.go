@@ -0,0 +1,40 @@
+package wator
+
+// breedThreshold returns the number of moves c makes before it breeds: its own inherited threshold when traits
+// evolve, otherwise the current threshold for its kind.
+func (s *Simulation) breedThreshold(c *creature) int {
+	if s.cfg.Mutation > 0 {
+		return c.breedAt
+	}
+	if c.kind == Fish {
+		return s.cfg.FishBreed
+	}
+	return s.cfg.SharkBreed
+}
+
+// inherit returns the breed threshold of a newborn of parent: the parent's own changed by a random amount of up to
+// Config.Mutation either way, drawn from p's stream and never below one. Without evolution nothing is drawn, so runs
+// without it are unchanged.
+func (s *Simulation) inherit(p *partition, parent *creature) int {
+	m := s.cfg.Mutation
+	if m == 0 {
+		return parent.breedAt
+	}
+	return max(1, parent.breedAt+p.rng.Intn(2*m+1)-m)
+}
+
+// BreedThresholds counts the living creatures of kind, Fish or Shark, by breed threshold, to follow how the
+// thresholds evolve when Config.Mutation is set. Without evolution every creature has the current threshold.
+func (s *Simulation) BreedThresholds(kind Cell) map[int]int {
+	counts := map[int]int{}
+	for _, p := range s.partitions {
+		list := p.fish
+		if kind == Shark {
+			list = p.sharks
+		}
+		for _, c := range list {
+			counts[s.breedThreshold(c)]++
+		}
+	}
+	return counts
+}
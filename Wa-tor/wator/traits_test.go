@@ -0,0 +1,62 @@
+package wator
+
+import "testing"
+
+func TestInherit(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Seed = 1
+	cfg.Mutation = 2
+	s, _ := New(cfg)
+	p := s.partitions[0]
+
+	seen := map[int]bool{}
+	for i := 0; i < 1000; i++ {
+		seen[s.inherit(p, &creature{kind: Fish, breedAt: 5})] = true
+		if got := s.inherit(p, &creature{kind: Fish, breedAt: 1}); got < 1 || got > 3 {
+			t.Fatalf("threshold 1 inherited as %d", got)
+		}
+	}
+	for threshold := 3; threshold <= 7; threshold++ {
+		if !seen[threshold] {
+			t.Errorf("threshold 5 never mutated to %d", threshold)
+		}
+	}
+	if len(seen) != 5 {
+		t.Errorf("threshold 5 mutated to %v, want 3 to 7", seen)
+	}
+}
+
+func TestBreedThresholdsEvolve(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Seed = 2
+	cfg.Threads = 4
+	cfg.Mutation = 1
+	cfg.SharkDensity = 0 // Sharks can wipe out the fish on a small grid.
+	s, _ := New(cfg)
+	t.Cleanup(s.Close)
+	for i := 0; i < 100; i++ {
+		s.Step()
+		if err := s.Check(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fish, _ := s.Population()
+	counts, total := s.BreedThresholds(Fish), 0
+	for threshold, n := range counts {
+		if threshold < 1 {
+			t.Errorf("%d fish have threshold %d", n, threshold)
+		}
+		total += n
+	}
+	if total != fish || len(counts) < 2 {
+		t.Errorf("fish thresholds %v after 100 chronons, want %d fish spread over several thresholds", counts, fish)
+	}
+
+	cfg.Mutation, cfg.SharkDensity = 0, 0.01
+	s, _ = New(cfg)
+	t.Cleanup(s.Close)
+	s.Step()
+	if counts := s.BreedThresholds(Shark); len(counts) != 1 || counts[cfg.SharkBreed] == 0 {
+		t.Errorf("without evolution shark thresholds are %v, want all %d", counts, cfg.SharkBreed)
+	}
+}
@@ -0,0 +1,112 @@
+//go:build js && wasm
+
+// Command web runs the Wa-Tor simulation in a web page, compiled to WebAssembly.
+//
+// Ebiten draws the grid on a canvas it adds to the page, so the demo needs nothing installed beyond a browser.
+// Build it and serve this directory with any static web server:
+//
+//	GOOS=js GOARCH=wasm go build -o web/wator.wasm ./web
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" web/
+//	python3 -m http.server -d web 8000
+//
+// The simulation is configured from the page's query string, using the names of the wator command's flags, for
+// example index.html?width=200&height=200&threads=4&fish-breed=3. Space pauses and resumes it.
+package main
+
+import (
+	"flag"       // Parses the settings in the query string.
+	"fmt"        // Formats the population line.
+	"log"        // Reports errors on the browser console.
+	"net/url"    // Decodes the query string.
+	"syscall/js" // Reads the page's address.
+
+	"Wator/wator"        // Runs the simulation.
+	"Wator/wator/render" // Draws the grid.
+
+	"github.com/hajimehoshi/ebiten/v2"            // Runs the game loop on the page's canvas.
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil" // Draws the population line.
+	"github.com/hajimehoshi/ebiten/v2/inpututil"  // Detects the pause key.
+)
+
+// screenSize is the width and height of the canvas in pixels; the grid is scaled to fill it.
+const screenSize = 800
+
+// page shows a simulation with its populations, pausing when space is pressed.
+type page struct {
+	*render.Game
+}
+
+func main() {
+	cfg, err := configFromQuery(js.Global().Get("location").Get("search").String())
+	if err != nil {
+		log.Fatal(err)
+	}
+	sim, err := wator.New(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ebiten.SetWindowTitle("Ebiten Wa-Tor World")
+	if err := ebiten.RunGame(&page{render.NewGame(sim, screenSize, screenSize)}); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// configFromQuery returns the configuration described by a query string such as "?width=200&threads=4".
+// Settings left out take their defaults from wator.DefaultConfig.
+func configFromQuery(query string) (wator.Config, error) {
+	cfg := wator.DefaultConfig()
+	fs := flag.NewFlagSet("web", flag.ContinueOnError)
+	fs.IntVar(&cfg.Width, "width", cfg.Width, "number of cells in the x direction")
+	fs.IntVar(&cfg.Height, "height", cfg.Height, "number of cells in the y direction")
+	fs.IntVar(&cfg.Threads, "threads", cfg.Threads, "number of partitions")
+	fs.Int64Var(&cfg.Seed, "seed", 0, "random seed; 0 picks one from the clock")
+	distribution := fs.String("distribution", cfg.Distribution.String(), "where the starting population is placed")
+	fs.Float64Var(&cfg.FishDensity, "fish-density", cfg.FishDensity, "average fraction of cells that start with a fish")
+	fs.Float64Var(&cfg.SharkDensity, "shark-density", cfg.SharkDensity, "average fraction of cells that start with a shark")
+	order := fs.String("order", cfg.Order.String(), "which creatures move first each chronon")
+	fs.IntVar(&cfg.SightRadius, "sight", cfg.SightRadius, "shark sight radius in cells")
+	fs.IntVar(&cfg.Crowding, "crowding", cfg.Crowding, "number of neighbouring sharks that makes a cell crowded")
+	fs.IntVar(&cfg.FishBreed, "fish-breed", cfg.FishBreed, "chronons a fish must survive before breeding")
+	fs.IntVar(&cfg.SharkBreed, "shark-breed", cfg.SharkBreed, "chronons a shark must survive before breeding")
+	fs.IntVar(&cfg.SharkStarve, "shark-starve", cfg.SharkStarve, "chronons a shark can go without eating before it starves")
+
+	values, err := url.ParseQuery(query[min(len(query), 1):]) // Drop the leading "?".
+	if err != nil {
+		return cfg, err
+	}
+	var args []string
+	for name, list := range values {
+		for _, value := range list {
+			args = append(args, "-"+name+"="+value)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+	if cfg.Distribution, err = wator.ParseDistribution(*distribution); err != nil {
+		return cfg, err
+	}
+	if cfg.Order, err = wator.ParseOrder(*order); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// Update pauses or resumes the simulation when space is pressed, then steps it unless it is paused.
+func (p *page) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		p.Paused = !p.Paused
+	}
+	return p.Game.Update()
+}
+
+// Draw draws the grid with the chronon and populations in the top-left corner.
+func (p *page) Draw(screen *ebiten.Image) {
+	p.Game.Draw(screen)
+	fish, sharks := p.Sim.Population()
+	status := fmt.Sprintf("Chronon %d: %d fish, %d sharks", p.Sim.Chronon(), fish, sharks)
+	if p.Paused {
+		status += " (paused, space to resume)"
+	}
+	ebitenutil.DebugPrint(screen, status)
+}
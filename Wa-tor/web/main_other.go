@@ -0,0 +1,14 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"fmt" // Prints how to build the demo.
+	"os"  // Sets the exit status.
+)
+
+// main explains that the web demo only runs in a browser.
+func main() {
+	fmt.Fprintln(os.Stderr, "web: the web demo runs in a browser; build it with GOOS=js GOARCH=wasm go build -o web/wator.wasm ./web")
+	os.Exit(2)
+}